@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/garuda-labs-1/pmii-be/config"
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
 	// "github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/handlers"
+	"github.com/garuda-labs-1/pmii-be/internal/middleware"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 	"github.com/garuda-labs-1/pmii-be/internal/routes"
 	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/archive"
 	"github.com/garuda-labs-1/pmii-be/pkg/cloudinary"
 	"github.com/garuda-labs-1/pmii-be/pkg/database"
+	"github.com/garuda-labs-1/pmii-be/pkg/geoip"
 	"github.com/garuda-labs-1/pmii-be/pkg/logger"
+	"github.com/garuda-labs-1/pmii-be/pkg/mailer"
+	"github.com/garuda-labs-1/pmii-be/pkg/oidc"
 	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"github.com/garuda-labs-1/pmii-be/pkg/webhook"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,6 +35,13 @@ func main() {
 	if err != nil {
 		logger.Error.Fatalf("Failed to load configuration: %v", err)
 	}
+	overriddenPolicies := make([]string, 0, len(cfg.RateLimit.Overrides))
+	for name := range cfg.RateLimit.Overrides {
+		overriddenPolicies = append(overriddenPolicies, name)
+	}
+	if err := middleware.ValidateRateLimitPolicyNames(overriddenPolicies); err != nil {
+		logger.Error.Fatalf("Invalid RATE_LIMIT_POLICIES: %v", err)
+	}
 
 	config.InitDB(cfg)
 	// config.DB.AutoMigrate(
@@ -36,12 +54,33 @@ func main() {
 	logger.Info.Printf("Environment: %s", cfg.Server.Environment)
 
 	// 3. Initialize JWT Secret
-	utils.InitJWT(cfg.JWT.Secret, cfg.JWT.ExpirationHours)
-	logger.Info.Printf("✅ JWT initialized (expiration: %d hours)", cfg.JWT.ExpirationHours)
+	utils.InitJWT(
+		cfg.JWT.Secret,
+		time.Duration(cfg.JWT.AccessTokenMinutes)*time.Minute,
+		time.Duration(cfg.JWT.RefreshTokenDays)*24*time.Hour,
+	)
+	if err := utils.InitJWTSigningKeys(cfg.JWT.SigningKeyFile, cfg.JWT.VerificationKeyFiles); err != nil {
+		logger.Error.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+	if kid := utils.SigningKeyID(); kid != "" {
+		logger.Info.Printf("✅ JWT signing key loaded (kid: %s, HS256 accepted: %t)", kid, cfg.JWT.Secret != "")
+	}
+	logger.Info.Printf("✅ JWT initialized (access token: %d minutes, refresh token: %d days)", cfg.JWT.AccessTokenMinutes, cfg.JWT.RefreshTokenDays)
 
-	// 3a. Initialize Token Blacklist
-	utils.InitBlacklist()
-	logger.Info.Println("✅ Token blacklist initialized")
+	// Mode auth cookie untuk SPA admin (session cookie HttpOnly + CSRF double-submit)
+	sameSite, err := utils.ParseSameSite(cfg.AuthCookie.SameSite)
+	if err != nil {
+		logger.Error.Fatalf("Invalid auth cookie configuration: %v", err)
+	}
+	utils.InitSessionCookies(utils.SessionCookieConfig{
+		Enabled:  cfg.AuthCookie.Enabled,
+		Domain:   cfg.AuthCookie.Domain,
+		Secure:   cfg.AuthCookie.Secure,
+		SameSite: sameSite,
+	})
+	if cfg.AuthCookie.Enabled {
+		logger.Info.Printf("✅ Cookie auth mode enabled (domain: %q, secure: %t, samesite: %s)", cfg.AuthCookie.Domain, cfg.AuthCookie.Secure, cfg.AuthCookie.SameSite)
+	}
 
 	// 4. Initialize Database Connection
 	dbConfig := database.Config{
@@ -63,11 +102,40 @@ func main() {
 		logger.Error.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// 4b-1. Audit log otomatis (create/update/delete model konten) lewat GORM callback
+	// Dipasang di kedua koneksi karena sebagian repository masih memakai config.DB
+	auditPlugin := audit.Default()
+	if err := config.DB.Use(auditPlugin); err != nil {
+		logger.Error.Fatalf("Failed to register audit plugin: %v", err)
+	}
+	if err := db.Use(auditPlugin); err != nil {
+		logger.Error.Fatalf("Failed to register audit plugin: %v", err)
+	}
+	logger.Info.Println("✅ Audit log plugin registered")
+
+	// 4b-2. Key untuk menandatangani checkpoint hash chain activity log (opsional)
+	var checkpointSigner *audit.CheckpointSigner
+	if cfg.Audit.CheckpointKeyFile != "" {
+		checkpointSigner, err = audit.LoadCheckpointSigner(cfg.Audit.CheckpointKeyFile)
+		if err != nil {
+			logger.Error.Fatalf("Failed to load audit checkpoint key: %v", err)
+		}
+		logger.Info.Printf("✅ Audit checkpoint key loaded (key id: %s, interval: %s)", checkpointSigner.KeyID(), cfg.Audit.CheckpointInterval)
+	}
+
 	// 4c. Seed Default Users (Auto-run on startup)
 	if err := database.SeedDefaultUsers(db); err != nil {
 		logger.Error.Fatalf("Failed to seed default users: %v", err)
 	}
 
+	// 4d. Initialize Token Revocation Store (Postgres + in-memory cache)
+	utils.InitRevocationStore(repository.NewTokenRevocationRepository(db))
+	logger.Info.Println("✅ Token revocation store initialized")
+
+	// 4e. Initialize Permission Store (role & permission dari database + in-memory cache)
+	utils.InitPermissionStore(repository.NewRoleRepository(db))
+	logger.Info.Println("✅ Permission store initialized")
+
 	// 5. Initialize Cloudinary Service
 	cloudinaryService, err := cloudinary.NewService(cfg.Cloudinary.URL)
 	if err != nil {
@@ -78,6 +146,17 @@ func main() {
 	// Set Cloudinary service to config for use in routes
 	config.InitCloudinary(cloudinaryService)
 
+	// 5a. Initialize Mailer Service (SMTP)
+	mailerService := mailer.NewService(mailer.Config{
+		Host:     cfg.Mail.SMTPHost,
+		Port:     cfg.Mail.SMTPPort,
+		Username: cfg.Mail.SMTPUsername,
+		Password: cfg.Mail.SMTPPassword,
+		From:     cfg.Mail.From,
+		LogBody:  cfg.Server.Environment == "development",
+	})
+	logger.Info.Println("✅ Mailer service initialized")
+
 	// Content seeding (members, testimonials, documents, settings) dijalankan manual
 	// Jalankan dengan: go run cmd/seed/main.go
 	// if cfg.Server.Environment == "development" {
@@ -99,24 +178,109 @@ func main() {
 	dashboardRepo := repository.NewDashboardRepository(db)
 	activityLogRepo := repository.NewActivityLogRepository()
 	visitorRepo := repository.NewVisitorRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	passwordResetRepo := repository.NewPasswordResetRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	auditChainRepo := repository.NewAuditChainRepository(db)
+	activityLogArchiveRepo := repository.NewActivityLogArchiveRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	cspReportRepo := repository.NewCSPReportRepository(db)
 
 	// 7. Initialize Services (Business Logic Layer)
-	authService := service.NewAuthService(userRepo, activityLogRepo)
-	userService := service.NewUserService(userRepo, cloudinaryService, activityLogRepo)
-	testimonialService := service.NewTestimonialService(testimonialRepo, cloudinaryService, activityLogRepo)
-	memberService := service.NewMemberService(memberRepo, cloudinaryService, activityLogRepo)
+	// Webhook service dibuat lebih dulu karena dipakai service konten untuk mengirim event
+	webhookService := service.NewWebhookService(webhookRepo, webhook.NewHTTPClient(cfg.Webhook.Timeout, cfg.Webhook.AllowPrivateTargets), service.WebhookPolicy{
+		MaxAttempts:         cfg.Webhook.MaxAttempts,
+		AllowPrivateTargets: cfg.Webhook.AllowPrivateTargets,
+	})
+	accountLockoutService := service.NewAccountLockoutService(userRepo, loginAttemptRepo, activityLogRepo, mailerService, service.LockoutPolicy{
+		MaxFailedAttempts: cfg.Lockout.MaxFailedAttempts,
+		LockoutDuration:   time.Duration(cfg.Lockout.LockoutMinutes) * time.Minute,
+		MaxFailedPerIP:    cfg.Lockout.MaxFailedPerIP,
+		IPWindow:          time.Duration(cfg.Lockout.IPWindowMinutes) * time.Minute,
+		AttemptRetention:  time.Duration(cfg.Lockout.AttemptRetentionDays) * 24 * time.Hour,
+	})
+	go runLoginAttemptPurge(accountLockoutService)
+	authService := service.NewAuthService(userRepo, sessionRepo, twoFactorRepo, siteSettingRepo, accountLockoutService, activityLogRepo)
+	oidcProviders := make([]service.OIDCProvider, 0, len(cfg.OIDC))
+	for _, provider := range cfg.OIDC {
+		oidcProviders = append(oidcProviders, oidc.NewProvider(oidc.Config{
+			Name:           provider.Name,
+			IssuerURL:      provider.IssuerURL,
+			ClientID:       provider.ClientID,
+			ClientSecret:   provider.ClientSecret,
+			RedirectURL:    provider.RedirectURL,
+			Scopes:         provider.Scopes,
+			AllowedDomains: provider.AllowedDomains,
+			AutoProvision:  provider.AutoProvision,
+		}))
+		logger.Info.Printf("✅ SSO provider enabled: %s (%s)", provider.Name, provider.IssuerURL)
+	}
+	oidcService := service.NewOIDCService(oidcProviders, oidcRepo, userRepo, authService, activityLogRepo)
+	passwordResetService := service.NewPasswordResetService(userRepo, passwordResetRepo, sessionRepo, activityLogRepo, mailerService, cfg.Server.FrontendURL)
+	userService := service.NewUserService(userRepo, roleRepo, apiKeyRepo, cloudinaryService)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, roleRepo, cloudinaryService, activityLogRepo, mailerService, cfg.Server.FrontendURL)
+	testimonialService := service.NewTestimonialService(testimonialRepo, cloudinaryService)
+	memberService := service.NewMemberService(memberRepo, cloudinaryService, webhookService)
 	aboutService := service.NewAboutService(aboutRepo)
-	siteSettingService := service.NewSiteSettingService(siteSettingRepo, cloudinaryService)
+	siteSettingService := service.NewSiteSettingService(siteSettingRepo, cloudinaryService, webhookService)
 	contactService := service.NewContactService(contactRepo)
 	publicAboutService := service.NewPublicAboutService(aboutRepo, memberRepo, contactRepo, cloudinaryService)
 	publicHomeService := service.NewPublicHomeService(homeRepo, testimonialRepo, cloudinaryService)
-	documentService := service.NewDocumentService(documentRepo, cloudinaryService, activityLogRepo)
+	documentService := service.NewDocumentService(documentRepo, cloudinaryService, webhookService)
 	publicDocumentService := service.NewPublicDocumentService(documentRepo, cloudinaryService)
 	dashboardService := service.NewDashboardService(dashboardRepo)
 	publicSiteSettingService := service.NewPublicSiteSettingService(siteSettingRepo, cloudinaryService)
+	auditChainService := service.NewAuditChainService(auditChainRepo, checkpointSigner)
+
+	// 7a. Checkpoint berkala hash chain activity log
+	if checkpointSigner != nil {
+		go runAuditCheckpoints(auditChainService, cfg.Audit.CheckpointInterval)
+	}
+
+	// 7b. Retensi activity log: arsipkan entry lama ke disk/Cloudinary lalu hapus dari database
+	var archiveStorage archive.Storage = archive.NewDiskStorage(cfg.Audit.ArchiveDir)
+	if cfg.Audit.ArchiveStorage == "cloudinary" {
+		archiveStorage = archive.NewCloudinaryStorage(cloudinaryService, "archives/activity-logs")
+	}
+	activityLogArchiveService := service.NewActivityLogArchiveService(activityLogArchiveRepo, activityLogRepo, auditChainService, archiveStorage, cfg.Audit.RetentionMonths)
+	if cfg.Audit.RetentionMonths > 0 {
+		logger.Info.Printf("✅ Activity log retention enabled (%d months, storage: %s)", cfg.Audit.RetentionMonths, archiveStorage.Name())
+		go runActivityLogArchive(activityLogArchiveService, cfg.Audit.ArchiveInterval)
+	}
+
+	// 7c. Worker pengiriman webhook (antrian di database, retry dengan backoff eksponensial)
+	go webhookService.Run(context.Background(), webhookPollInterval)
+
+	// 7d. Visitor analytics: IP di-hash dengan salt harian, data mentah dikosongkan setelah masa retensi
+	visitorPrivacyService := service.NewVisitorPrivacyService(visitorRepo, cfg.Analytics.RetentionDays)
+	logger.Info.Printf("✅ Visitor analytics retention: %d days", cfg.Analytics.RetentionDays)
+	go runVisitorDataPurge(visitorPrivacyService, cfg.Analytics.PurgeInterval)
+
+	// 7e. Laporan CSP dari endpoint publik: dibatasi jumlahnya dan dihapus setelah masa retensi
+	cspReportService := service.NewCSPReportService(cspReportRepo, cfg.Security.CSPReportRetentionDays, cfg.Security.CSPReportMaxStored)
+	go runCSPReportPurge(cspReportService)
+
+	// 7f. Geolokasi offline (opsional): tanpa file database, kolom lokasi visitor dibiarkan kosong
+	var geoLocator service.GeoLocator
+	if cfg.Analytics.GeoIPDatabasePath != "" {
+		geoReader, err := geoip.Open(cfg.Analytics.GeoIPDatabasePath)
+		if err != nil {
+			logger.Error.Printf("⚠️ GeoIP database unavailable, visitor geolocation disabled: %v", err)
+		} else {
+			geoLocator = geoReader
+			logger.Info.Printf("✅ GeoIP database loaded (%s)", geoReader.DatabaseType())
+		}
+	} else {
+		logger.Info.Println("Visitor geolocation disabled (ANALYTICS_GEOIP_DATABASE not set)")
+	}
 
 	// 8. Initialize Handlers (Transport Layer)
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, passwordResetService)
 	adminHandler := handlers.NewAdminHandler(userService)
 	userHandler := handlers.NewUserHandler(userService)
 	testimonialHandler := handlers.NewTestimonialHandler(testimonialService)
@@ -130,6 +294,13 @@ func main() {
 	publicDocumentHandler := handlers.NewPublicDocumentHandler(publicDocumentService)
 	dashboardHandler := handlers.NewDashboardHandler(dashboardService)
 	publicSiteSettingHandler := handlers.NewPublicSiteSettingHandler(publicSiteSettingService)
+	accountLockoutHandler := handlers.NewAccountLockoutHandler(accountLockoutService)
+	oidcHandler := handlers.NewOIDCHandler(oidcService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	auditChainHandler := handlers.NewAuditChainHandler(auditChainService)
+	activityLogArchiveHandler := handlers.NewActivityLogArchiveHandler(activityLogArchiveService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	cspReportHandler := handlers.NewCSPReportHandler(cspReportService)
 
 	// 9. Setup Gin Router
 	if cfg.Server.Environment == "production" {
@@ -137,11 +308,17 @@ func main() {
 	}
 	r := gin.Default()
 
+	// IP klien (allowlist admin, rate limit, lockout, geolokasi) hanya diambil dari header proxy yang dipercaya
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Error.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.TrustedPlatform = cfg.Server.TrustedPlatform
+
 	// Set max multipart memory to 20MB (untuk file upload besar)
 	r.MaxMultipartMemory = 20 << 20 // 20 MB
 
 	// 10. Setup Routes (dari internal/routes)
-	routes.SetupRoutes(r, authHandler, adminHandler, userHandler, testimonialHandler, memberHandler, aboutHandler, siteSettingHandler, contactHandler, publicAboutHandler, publicHomeHandler, documentHandler, publicDocumentHandler, dashboardHandler, publicSiteSettingHandler, visitorRepo, cfg.Server.AllowedOrigins, cfg.Server.Environment)
+	routes.SetupRoutes(r, authHandler, adminHandler, userHandler, testimonialHandler, memberHandler, aboutHandler, siteSettingHandler, contactHandler, publicAboutHandler, publicHomeHandler, documentHandler, publicDocumentHandler, dashboardHandler, publicSiteSettingHandler, accountLockoutHandler, oidcHandler, invitationHandler, auditChainHandler, activityLogArchiveHandler, webhookHandler, cspReportHandler, webhookService, visitorRepo, visitorPrivacyService, geoLocator, cfg.Security, cfg.RateLimit, cfg.Server.AllowedOrigins, cfg.Server.Environment)
 
 	// 11. Start Server
 	serverAddr := ":" + cfg.Server.Port
@@ -150,3 +327,93 @@ func main() {
 		logger.Error.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// webhookPollInterval jarak pengecekan retry webhook yang jatuh tempo, event baru langsung dikirim tanpa menunggu
+const webhookPollInterval = 15 * time.Second
+
+// runAuditCheckpoints membuat checkpoint activity log setiap interval
+// Checkpoint tidak dibuat jika chain rusak, sehingga head yang sudah dimanipulasi tidak ikut ditandatangani
+// Berjalan di setiap replica, advisory lock di CreateCheckpoint memastikan hanya satu yang membuat checkpoint
+func runAuditCheckpoints(auditChainService service.AuditChainService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		checkpoint, err := auditChainService.CreateCheckpoint()
+		if errors.Is(err, service.ErrAuditCheckpointBusy) {
+			continue // Replica lain sedang membuat checkpoint
+		}
+		if err != nil {
+			logger.Error.Printf("Failed to create audit checkpoint: %v", err)
+			continue
+		}
+		if checkpoint != nil {
+			logger.Info.Printf("✅ Audit checkpoint created (last log id: %d, entries: %d)", checkpoint.LastLogID, checkpoint.EntryCount)
+		}
+	}
+}
+
+// runActivityLogArchive menjalankan retensi activity log saat startup lalu setiap interval
+// Run tercatat di activity log tanpa user (job sistem)
+func runActivityLogArchive(archiveService service.ActivityLogArchiveService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := archiveService.Run(context.Background())
+		if err != nil {
+			logger.Error.Printf("Failed to archive activity logs: %v", err)
+		} else if archived != nil {
+			logger.Info.Printf("✅ Archived %d activity logs (id %d-%d) to %s", archived.EntryCount, archived.FromLogID, archived.ToLogID, archived.Location)
+		}
+		<-ticker.C
+	}
+}
+
+// runLoginAttemptPurge menghapus riwayat percobaan login yang melewati masa retensi saat startup lalu setiap jam
+func runLoginAttemptPurge(accountLockoutService service.AccountLockoutService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := accountLockoutService.PurgeAttempts()
+		if err != nil {
+			logger.Error.Printf("Failed to purge login attempts: %v", err)
+		} else if deleted > 0 {
+			logger.Info.Printf("✅ Deleted %d login attempts past retention", deleted)
+		}
+		<-ticker.C
+	}
+}
+
+// runVisitorDataPurge menjalankan retensi data visitor saat startup lalu setiap interval
+func runVisitorDataPurge(visitorPrivacyService service.VisitorPrivacyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := visitorPrivacyService.Purge()
+		if err != nil {
+			logger.Error.Printf("Failed to purge visitor data: %v", err)
+		} else if result.Visitors > 0 || result.PostViews > 0 {
+			logger.Info.Printf("✅ Anonymized %d visitors and %d post views past retention", result.Visitors, result.PostViews)
+		}
+		<-ticker.C
+	}
+}
+
+// runCSPReportPurge menghapus laporan CSP yang melewati masa retensi atau kuota saat startup lalu setiap jam
+func runCSPReportPurge(cspReportService service.CSPReportService) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := cspReportService.Purge()
+		if err != nil {
+			logger.Error.Printf("Failed to purge CSP reports: %v", err)
+		} else if deleted > 0 {
+			logger.Info.Printf("✅ Deleted %d CSP reports past retention", deleted)
+		}
+		<-ticker.C
+	}
+}
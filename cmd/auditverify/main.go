@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/garuda-labs-1/pmii-be/config"
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/database"
+)
+
+func main() {
+	// Parse command line flags
+	checkpointsFile := flag.String("checkpoints", "", "Verify against an exported checkpoint file instead of checkpoints in the database")
+	createCheckpoint := flag.Bool("checkpoint", false, "Create a signed checkpoint of the current chain head")
+	exportFile := flag.String("export", "", "Export signed checkpoints to a file (use - for stdout)")
+	flag.Usage = func() {
+		fmt.Println("Usage: go run cmd/auditverify/main.go [options]")
+		fmt.Println()
+		fmt.Println("Without options the activity log hash chain is verified against the checkpoints in the database.")
+		fmt.Println("Exit code 1 means the chain is broken, 2 means verification could not run.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -checkpoints FILE  Verify against an exported checkpoint file")
+		fmt.Println("  -checkpoint        Create a signed checkpoint (requires AUDIT_CHECKPOINT_KEY_FILE)")
+		fmt.Println("  -export FILE       Export signed checkpoints (use - for stdout)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  go run cmd/auditverify/main.go")
+		fmt.Println("  go run cmd/auditverify/main.go -export checkpoints.json")
+		fmt.Println("  go run cmd/auditverify/main.go -checkpoints /mnt/offsite/checkpoints.json")
+	}
+	flag.Parse()
+
+	// Log ke stderr agar hasil -export - ke stdout tetap JSON yang valid
+	log.SetOutput(os.Stderr)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Printf("Failed to load configuration: %v", err)
+		os.Exit(2)
+	}
+
+	// Initialize database
+	db, err := database.InitDB(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+	})
+	if err != nil {
+		log.Printf("Failed to connect to database: %v", err)
+		os.Exit(2)
+	}
+
+	var signer *audit.CheckpointSigner
+	if cfg.Audit.CheckpointKeyFile != "" {
+		if signer, err = audit.LoadCheckpointSigner(cfg.Audit.CheckpointKeyFile); err != nil {
+			log.Printf("Failed to load audit checkpoint key: %v", err)
+			os.Exit(2)
+		}
+	}
+	auditChainService := service.NewAuditChainService(repository.NewAuditChainRepository(db), signer)
+
+	switch {
+	case *createCheckpoint:
+		checkpoint, err := auditChainService.CreateCheckpoint()
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		if checkpoint == nil {
+			log.Println("✅ No new activity log entries since the last checkpoint")
+			return
+		}
+		log.Printf("✅ Checkpoint #%d created (last log id: %d, entries: %d)", checkpoint.ID, checkpoint.LastLogID, checkpoint.EntryCount)
+
+	case *exportFile != "":
+		export, err := auditChainService.ExportCheckpoints()
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(2)
+		}
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			log.Printf("❌ Failed to encode checkpoints: %v", err)
+			os.Exit(2)
+		}
+		if *exportFile == "-" {
+			fmt.Println(string(data))
+			return
+		}
+		if err := os.WriteFile(*exportFile, data, 0o600); err != nil {
+			log.Printf("❌ Failed to write %s: %v", *exportFile, err)
+			os.Exit(2)
+		}
+		log.Printf("✅ %d checkpoints exported to %s", len(export.Checkpoints), *exportFile)
+
+	default:
+		var result *responses.AuditChainVerificationResponse
+		if *checkpointsFile != "" {
+			data, err := os.ReadFile(*checkpointsFile)
+			if err != nil {
+				log.Printf("❌ Failed to read %s: %v", *checkpointsFile, err)
+				os.Exit(2)
+			}
+			var export responses.AuditCheckpointExportResponse
+			if err := json.Unmarshal(data, &export); err != nil {
+				log.Printf("❌ Failed to parse %s: %v", *checkpointsFile, err)
+				os.Exit(2)
+			}
+			result, err = auditChainService.VerifyExport(&export)
+		} else {
+			result, err = auditChainService.Verify()
+		}
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(2)
+		}
+		report(result)
+	}
+}
+
+// report mencetak hasil verifikasi, exit code 1 jika chain rusak
+func report(result *responses.AuditChainVerificationResponse) {
+	log.Printf("Checked entries: %d (unchained legacy entries: %d)", result.CheckedEntries, result.UnchainedEntries)
+	log.Printf("Checked checkpoints: %d (signature not verified: %d)", result.CheckedCheckpoints, result.UnverifiedCheckpoints)
+
+	if !result.Valid {
+		broken := result.BrokenLink
+		if broken.LogID != nil {
+			log.Printf("First broken link at activity log #%d", *broken.LogID)
+		}
+		if broken.CheckpointID != nil {
+			log.Printf("Checkpoint involved: #%d", *broken.CheckpointID)
+		}
+		log.Printf("❌ Hash chain broken: %s", broken.Reason)
+		os.Exit(1)
+	}
+
+	if result.HeadLogID != nil {
+		log.Printf("Head: activity log #%d (%s)", *result.HeadLogID, result.HeadHash)
+	}
+	log.Println("✅ Activity log hash chain is intact")
+}
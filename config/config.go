@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/garuda-labs-1/pmii-be/pkg/cloudinary"
 	"github.com/spf13/viper"
@@ -19,6 +22,15 @@ type Config struct {
 	JWT        JWTConfig
 	Server     ServerConfig
 	Cloudinary CloudinaryConfig
+	Mail       MailConfig
+	Lockout    LockoutConfig
+	AuthCookie AuthCookieConfig
+	Security   SecurityConfig
+	RateLimit  RateLimitConfig
+	Audit      AuditConfig
+	Webhook    WebhookConfig
+	Analytics  AnalyticsConfig
+	OIDC       []OIDCProviderConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -32,8 +44,11 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret               string   // HS256, tetap diterima selama migrasi ke key asimetris
+	SigningKeyFile       string   // Private key RSA/Ed25519 (PEM) untuk menandatangani token baru
+	VerificationKeyFiles []string // Public key lama yang masih diterima selama rotasi
+	AccessTokenMinutes   int
+	RefreshTokenDays     int
 }
 
 // ServerConfig holds server configuration
@@ -41,6 +56,10 @@ type ServerConfig struct {
 	Port           string
 	AllowedOrigins string
 	Environment    string
+	FrontendURL    string
+
+	TrustedProxies  []string // IP/CIDR reverse proxy yang boleh mengisi X-Forwarded-For, kosong = IP koneksi langsung
+	TrustedPlatform string   // Header IP klien dari platform (mis. CF-Connecting-IP), kosong = tidak dipakai
 }
 
 // CloudinaryConfig holds Cloudinary configuration
@@ -48,6 +67,98 @@ type CloudinaryConfig struct {
 	URL string
 }
 
+// MailConfig holds SMTP configuration (opsional, kosong = email hanya dicatat ke log)
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+}
+
+// LockoutConfig holds brute-force login protection configuration
+type LockoutConfig struct {
+	MaxFailedAttempts int
+	LockoutMinutes    int
+	MaxFailedPerIP    int
+	IPWindowMinutes   int
+	// AttemptRetentionDays lama riwayat login_attempts disimpan sebelum dihapus
+	AttemptRetentionDays int
+}
+
+// AuthCookieConfig holds konfigurasi mode auth cookie (session cookie HttpOnly + CSRF double-submit)
+type AuthCookieConfig struct {
+	Enabled  bool
+	Domain   string
+	Secure   bool
+	SameSite string // lax, strict atau none
+}
+
+// AuditConfig holds konfigurasi checkpoint hash chain dan retensi activity log
+type AuditConfig struct {
+	CheckpointKeyFile  string        // Private key Ed25519 (PEM) untuk menandatangani checkpoint, kosong = checkpoint nonaktif
+	CheckpointInterval time.Duration // Jarak antar checkpoint otomatis
+
+	RetentionMonths int           // Activity log lebih tua dari ini diarsipkan lalu dihapus, 0 = disimpan selamanya
+	ArchiveStorage  string        // disk atau cloudinary
+	ArchiveDir      string        // Direktori arsip untuk storage disk
+	ArchiveInterval time.Duration // Jarak antar pengecekan retensi otomatis
+}
+
+// WebhookConfig holds konfigurasi pengiriman webhook keluar
+type WebhookConfig struct {
+	MaxAttempts         int           // Total percobaan kirim sebelum delivery dianggap gagal
+	Timeout             time.Duration // Timeout satu request ke endpoint penerima
+	AllowPrivateTargets bool          // Izinkan URL ke IP privat/loopback (hanya untuk development)
+}
+
+// AnalyticsConfig holds konfigurasi retensi data mentah visitor analytics
+type AnalyticsConfig struct {
+	RetentionDays int           // Hash IP dan user agent visitor/post view dikosongkan setelah sekian hari
+	PurgeInterval time.Duration // Jarak antar purge otomatis
+
+	GeoIPDatabasePath string // File MMDB (GeoLite2/DB-IP City) untuk lokasi visitor, kosong = geolokasi nonaktif
+}
+
+// SecurityConfig holds konfigurasi header keamanan (HSTS & Content-Security-Policy)
+type SecurityConfig struct {
+	HSTSMaxAge    int    // Detik, 0 = Strict-Transport-Security tidak dikirim
+	CSP           string // Policy untuk route publik
+	AdminCSP      string // Policy lebih ketat untuk /v1/admin, /v1/auth dan /v1/user
+	CSPReportOnly bool   // Kirim sebagai Content-Security-Policy-Report-Only (hanya melaporkan, tidak memblokir)
+	CSPReportURI  string // Endpoint penerima laporan pelanggaran, kosong = tanpa report-uri
+
+	CSPReportRetentionDays int // Laporan CSP lebih lama dari ini dihapus
+	CSPReportMaxStored     int // Batas jumlah laporan CSP tersimpan, laporan baru dibuang saat penuh
+
+	AdminIPAllowlistBypass bool // Darurat: matikan allowlist IP admin jika semua admin terkunci
+}
+
+// RateLimitConfig holds konfigurasi rate limit per policy
+type RateLimitConfig struct {
+	Store     string                   // memory (default, per proses) atau postgres (bersama antar replica)
+	Overrides map[string]RateLimitRule // Menimpa policy bawaan, key = nama policy (mis. public-read)
+}
+
+// RateLimitRule batas request per window untuk satu policy
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// OIDCProviderConfig holds konfigurasi satu provider SSO OpenID Connect
+// Diaktifkan lewat OIDC_PROVIDERS=google,mock lalu OIDC_<NAMA>_* per provider
+type OIDCProviderConfig struct {
+	Name           string
+	IssuerURL      string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	Scopes         []string
+	AllowedDomains []string
+	AutoProvision  bool
+}
+
 // Load loads configuration from .env file using Viper
 func Load() (*Config, error) {
 	// Set config file
@@ -57,6 +168,26 @@ func Load() (*Config, error) {
 	// Auto bind environment variables
 	viper.AutomaticEnv()
 
+	// Default values untuk config opsional
+	viper.SetDefault("JWT_REFRESH_TOKEN_DAYS", 30)
+	viper.SetDefault("FRONTEND_URL", "http://localhost:3000")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("MAIL_FROM", "PMII <no-reply@pmii.id>")
+	viper.SetDefault("LOGIN_MAX_FAILED_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("LOGIN_MAX_FAILED_PER_IP", 20)
+	viper.SetDefault("LOGIN_IP_WINDOW_MINUTES", 15)
+	viper.SetDefault("LOGIN_ATTEMPT_RETENTION_DAYS", 90)
+	viper.SetDefault("AUTH_COOKIE_SAMESITE", "lax")
+	viper.SetDefault("SECURITY_CSP_REPORT_URI", "/v1/csp-report")
+	viper.SetDefault("SECURITY_CSP_REPORT_RETENTION_DAYS", 30)
+	viper.SetDefault("SECURITY_CSP_REPORT_MAX_STORED", 10000)
+	viper.SetDefault("RATE_LIMIT_STORE", "memory")
+	viper.SetDefault("AUDIT_CHECKPOINT_INTERVAL", "1h")
+	viper.SetDefault("AUDIT_ARCHIVE_STORAGE", "disk")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("ANALYTICS_RETENTION_DAYS", 30)
+
 	// Read config file (optional - akan fallback ke env vars jika file tidak ada)
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Warning: .env file not found, using environment variables: %v", err)
@@ -65,7 +196,6 @@ func Load() (*Config, error) {
 	// Validate required configs
 	requiredKeys := []string{
 		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME",
-		"JWT_SECRET", "JWT_EXPIRATION_HOURS",
 		"PORT", "ENV", "ALLOWED_ORIGINS",
 		"CLOUDINARY_URL",
 	}
@@ -75,6 +205,11 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// Minimal salah satu: JWT_SECRET (HS256) atau JWT_SIGNING_KEY_FILE (RS256/EdDSA)
+	if viper.GetString("JWT_SECRET") == "" && viper.GetString("JWT_SIGNING_KEY_FILE") == "" {
+		return nil, fmt.Errorf("required configuration JWT_SECRET or JWT_SIGNING_KEY_FILE is not set")
+	}
+
 	config := &Config{
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -84,23 +219,358 @@ func Load() (*Config, error) {
 			DBName:   viper.GetString("DB_NAME"),
 		},
 		JWT: JWTConfig{
-			Secret:          viper.GetString("JWT_SECRET"),
-			ExpirationHours: viper.GetInt("JWT_EXPIRATION_HOURS"),
+			Secret:               viper.GetString("JWT_SECRET"),
+			SigningKeyFile:       viper.GetString("JWT_SIGNING_KEY_FILE"),
+			VerificationKeyFiles: splitList(viper.GetString("JWT_VERIFICATION_KEY_FILES")),
+			AccessTokenMinutes:   loadAccessTokenMinutes(),
+			RefreshTokenDays:     viper.GetInt("JWT_REFRESH_TOKEN_DAYS"),
 		},
 		Server: ServerConfig{
 			Port:           viper.GetString("PORT"),
 			AllowedOrigins: viper.GetString("ALLOWED_ORIGINS"),
 			Environment:    viper.GetString("ENV"),
+			FrontendURL:    viper.GetString("FRONTEND_URL"),
+
+			TrustedProxies:  splitList(viper.GetString("TRUSTED_PROXIES")),
+			TrustedPlatform: strings.TrimSpace(viper.GetString("TRUSTED_PLATFORM")),
 		},
 		Cloudinary: CloudinaryConfig{
 			URL: viper.GetString("CLOUDINARY_URL"),
 		},
+		Mail: MailConfig{
+			SMTPHost:     viper.GetString("SMTP_HOST"),
+			SMTPPort:     viper.GetString("SMTP_PORT"),
+			SMTPUsername: viper.GetString("SMTP_USERNAME"),
+			SMTPPassword: viper.GetString("SMTP_PASSWORD"),
+			From:         viper.GetString("MAIL_FROM"),
+		},
+		Lockout: LockoutConfig{
+			MaxFailedAttempts: viper.GetInt("LOGIN_MAX_FAILED_ATTEMPTS"),
+			LockoutMinutes:    viper.GetInt("LOGIN_LOCKOUT_MINUTES"),
+			MaxFailedPerIP:    viper.GetInt("LOGIN_MAX_FAILED_PER_IP"),
+			IPWindowMinutes:   viper.GetInt("LOGIN_IP_WINDOW_MINUTES"),
+
+			AttemptRetentionDays: viper.GetInt("LOGIN_ATTEMPT_RETENTION_DAYS"),
+		},
+	}
+
+	authCookie, err := loadAuthCookie(config.Server.Environment)
+	if err != nil {
+		return nil, err
 	}
+	config.AuthCookie = authCookie
+	config.Security = loadSecurity(config.Server.Environment)
+
+	rateLimit, err := loadRateLimit()
+	if err != nil {
+		return nil, err
+	}
+	config.RateLimit = rateLimit
+
+	audit, err := loadAudit()
+	if err != nil {
+		return nil, err
+	}
+	config.Audit = audit
+
+	webhook, err := loadWebhook()
+	if err != nil {
+		return nil, err
+	}
+	config.Webhook = webhook
+
+	analytics, err := loadAnalytics()
+	if err != nil {
+		return nil, err
+	}
+	config.Analytics = analytics
+
+	oidcProviders, err := loadOIDCProviders()
+	if err != nil {
+		return nil, err
+	}
+	config.OIDC = oidcProviders
 
 	log.Println("✅ Configuration loaded successfully")
 	return config, nil
 }
 
+// loadAuthCookie membaca konfigurasi cookie session dari AUTH_COOKIE_*
+// Secure default true kecuali di development (localhost tanpa HTTPS)
+func loadAuthCookie(environment string) (AuthCookieConfig, error) {
+	cfg := AuthCookieConfig{
+		Enabled:  viper.GetBool("AUTH_COOKIE_ENABLED"),
+		Domain:   strings.TrimSpace(viper.GetString("AUTH_COOKIE_DOMAIN")),
+		Secure:   environment != "development",
+		SameSite: strings.ToLower(strings.TrimSpace(viper.GetString("AUTH_COOKIE_SAMESITE"))),
+	}
+	if viper.IsSet("AUTH_COOKIE_SECURE") && viper.GetString("AUTH_COOKIE_SECURE") != "" {
+		cfg.Secure = viper.GetBool("AUTH_COOKIE_SECURE")
+	}
+
+	switch cfg.SameSite {
+	case "lax", "strict":
+	case "none":
+		// Browser menolak cookie SameSite=None tanpa Secure
+		if !cfg.Secure {
+			return cfg, fmt.Errorf("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+		}
+	default:
+		return cfg, fmt.Errorf("invalid AUTH_COOKIE_SAMESITE %q (use lax, strict or none)", cfg.SameSite)
+	}
+
+	return cfg, nil
+}
+
+// defaultAccessTokenMinutes masa berlaku access token jika JWT_ACCESS_TOKEN_MINUTES dan JWT_EXPIRATION_HOURS kosong
+const defaultAccessTokenMinutes = 15
+
+// loadAccessTokenMinutes membaca JWT_ACCESS_TOKEN_MINUTES
+// JWT_EXPIRATION_HOURS (deprecated) masih dipakai sebagai fallback agar deployment lama tidak diam-diam berubah TTL-nya
+func loadAccessTokenMinutes() int {
+	legacyHours := viper.GetString("JWT_EXPIRATION_HOURS")
+	if viper.GetString("JWT_ACCESS_TOKEN_MINUTES") != "" {
+		if legacyHours != "" {
+			log.Printf("Warning: JWT_EXPIRATION_HOURS is deprecated and ignored because JWT_ACCESS_TOKEN_MINUTES is set")
+		}
+		return viper.GetInt("JWT_ACCESS_TOKEN_MINUTES")
+	}
+	if legacyHours != "" {
+		minutes := viper.GetInt("JWT_EXPIRATION_HOURS") * 60
+		log.Printf("Warning: JWT_EXPIRATION_HOURS is deprecated, use JWT_ACCESS_TOKEN_MINUTES=%d instead", minutes)
+		return minutes
+	}
+	return defaultAccessTokenMinutes
+}
+
+// Policy CSP bawaan, API hanya mengembalikan JSON sehingga policy admin bisa menolak semua resource
+const (
+	defaultCSP      = "default-src 'self'; img-src 'self' data: https:; style-src 'self' 'unsafe-inline'; frame-ancestors 'self'; base-uri 'self'"
+	defaultAdminCSP = "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+)
+
+// loadSecurity membaca konfigurasi header keamanan dari SECURITY_*
+// HSTS tidak dikirim di development agar browser tidak memaksa HTTPS ke localhost
+func loadSecurity(environment string) SecurityConfig {
+	cfg := SecurityConfig{
+		HSTSMaxAge:    31536000, // 1 tahun
+		CSP:           strings.TrimSpace(viper.GetString("SECURITY_CSP")),
+		AdminCSP:      strings.TrimSpace(viper.GetString("SECURITY_ADMIN_CSP")),
+		CSPReportOnly: viper.GetBool("SECURITY_CSP_REPORT_ONLY"),
+		CSPReportURI:  strings.TrimSpace(viper.GetString("SECURITY_CSP_REPORT_URI")),
+
+		CSPReportRetentionDays: viper.GetInt("SECURITY_CSP_REPORT_RETENTION_DAYS"),
+		CSPReportMaxStored:     viper.GetInt("SECURITY_CSP_REPORT_MAX_STORED"),
+
+		AdminIPAllowlistBypass: viper.GetBool("ADMIN_IP_ALLOWLIST_BYPASS"),
+	}
+	if cfg.CSP == "" {
+		cfg.CSP = defaultCSP
+	}
+	if cfg.AdminCSP == "" {
+		cfg.AdminCSP = defaultAdminCSP
+	}
+	if environment == "development" {
+		cfg.HSTSMaxAge = 0
+	}
+	if viper.GetString("SECURITY_HSTS_MAX_AGE") != "" {
+		cfg.HSTSMaxAge = viper.GetInt("SECURITY_HSTS_MAX_AGE")
+	}
+	if cfg.HSTSMaxAge < 0 {
+		cfg.HSTSMaxAge = 0
+	}
+	if cfg.CSPReportRetentionDays < 1 {
+		cfg.CSPReportRetentionDays = 30
+	}
+	if cfg.CSPReportMaxStored < 1 {
+		cfg.CSPReportMaxStored = 10000
+	}
+	return cfg
+}
+
+// loadRateLimit membaca RATE_LIMIT_STORE dan RATE_LIMIT_POLICIES (format: nama=limit/window, mis. public-read=300/1m,upload=10/1m)
+func loadRateLimit() (RateLimitConfig, error) {
+	cfg := RateLimitConfig{
+		Store:     strings.ToLower(strings.TrimSpace(viper.GetString("RATE_LIMIT_STORE"))),
+		Overrides: make(map[string]RateLimitRule),
+	}
+	if cfg.Store != "memory" && cfg.Store != "postgres" {
+		return cfg, fmt.Errorf("invalid RATE_LIMIT_STORE %q (use memory or postgres)", cfg.Store)
+	}
+
+	for _, item := range splitList(viper.GetString("RATE_LIMIT_POLICIES")) {
+		name, value, ok := strings.Cut(item, "=")
+		limitStr, windowStr, okRule := strings.Cut(value, "/")
+		if !ok || !okRule {
+			return cfg, fmt.Errorf("invalid RATE_LIMIT_POLICIES entry %q (use name=limit/window, e.g. public-read=300/1m)", item)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit <= 0 {
+			return cfg, fmt.Errorf("invalid RATE_LIMIT_POLICIES limit in %q", item)
+		}
+		window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+		if err != nil || window < time.Second {
+			return cfg, fmt.Errorf("invalid RATE_LIMIT_POLICIES window in %q (minimum 1s)", item)
+		}
+
+		cfg.Overrides[strings.TrimSpace(name)] = RateLimitRule{Limit: limit, Window: window}
+	}
+
+	return cfg, nil
+}
+
+// Nilai bawaan audit jika env kosong
+const (
+	defaultAuditCheckpointInterval = time.Hour
+	defaultAuditArchiveDir         = "./archives/activity-logs"
+	defaultAuditArchiveInterval    = 24 * time.Hour
+)
+
+// loadAudit membaca AUDIT_CHECKPOINT_* dan AUDIT_RETENTION_MONTHS / AUDIT_ARCHIVE_* (durasi dalam format Go, mis. 30m, 1h)
+func loadAudit() (AuditConfig, error) {
+	cfg := AuditConfig{
+		CheckpointKeyFile:  strings.TrimSpace(viper.GetString("AUDIT_CHECKPOINT_KEY_FILE")),
+		CheckpointInterval: defaultAuditCheckpointInterval,
+		RetentionMonths:    viper.GetInt("AUDIT_RETENTION_MONTHS"),
+		ArchiveStorage:     strings.ToLower(strings.TrimSpace(viper.GetString("AUDIT_ARCHIVE_STORAGE"))),
+		ArchiveDir:         strings.TrimSpace(viper.GetString("AUDIT_ARCHIVE_DIR")),
+		ArchiveInterval:    defaultAuditArchiveInterval,
+	}
+	if cfg.RetentionMonths < 0 {
+		return cfg, fmt.Errorf("invalid AUDIT_RETENTION_MONTHS %d (use 0 to keep activity logs forever)", cfg.RetentionMonths)
+	}
+	if cfg.ArchiveStorage == "" {
+		cfg.ArchiveStorage = "disk"
+	}
+	if cfg.ArchiveStorage != "disk" && cfg.ArchiveStorage != "cloudinary" {
+		return cfg, fmt.Errorf("invalid AUDIT_ARCHIVE_STORAGE %q (use disk or cloudinary)", cfg.ArchiveStorage)
+	}
+	if cfg.ArchiveDir == "" {
+		cfg.ArchiveDir = defaultAuditArchiveDir
+	}
+
+	if value := strings.TrimSpace(viper.GetString("AUDIT_CHECKPOINT_INTERVAL")); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Minute {
+			return cfg, fmt.Errorf("invalid AUDIT_CHECKPOINT_INTERVAL %q (minimum 1m)", value)
+		}
+		cfg.CheckpointInterval = interval
+	}
+	if value := strings.TrimSpace(viper.GetString("AUDIT_ARCHIVE_INTERVAL")); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Minute {
+			return cfg, fmt.Errorf("invalid AUDIT_ARCHIVE_INTERVAL %q (minimum 1m)", value)
+		}
+		cfg.ArchiveInterval = interval
+	}
+
+	return cfg, nil
+}
+
+// Nilai bawaan webhook jika env kosong
+const (
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookTimeout     = 10 * time.Second
+)
+
+// loadWebhook membaca WEBHOOK_* (timeout dalam format durasi Go, mis. 10s)
+func loadWebhook() (WebhookConfig, error) {
+	cfg := WebhookConfig{
+		MaxAttempts:         viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		Timeout:             defaultWebhookTimeout,
+		AllowPrivateTargets: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_TARGETS"),
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if cfg.MaxAttempts < 1 || cfg.MaxAttempts > 20 {
+		return cfg, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS %d (use 1-20)", cfg.MaxAttempts)
+	}
+
+	if value := strings.TrimSpace(viper.GetString("WEBHOOK_TIMEOUT")); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < time.Second || timeout > time.Minute {
+			return cfg, fmt.Errorf("invalid WEBHOOK_TIMEOUT %q (use 1s-1m)", value)
+		}
+		cfg.Timeout = timeout
+	}
+
+	return cfg, nil
+}
+
+// Nilai bawaan retensi analytics jika env kosong
+const (
+	defaultAnalyticsPurgeInterval = time.Hour
+	maxAnalyticsRetentionDays     = 365
+)
+
+// loadAnalytics membaca ANALYTICS_* (interval dalam format durasi Go, mis. 1h)
+// Retensi wajib diisi 1-365 hari, data mentah visitor tidak boleh disimpan selamanya
+func loadAnalytics() (AnalyticsConfig, error) {
+	cfg := AnalyticsConfig{
+		RetentionDays: viper.GetInt("ANALYTICS_RETENTION_DAYS"),
+		PurgeInterval: defaultAnalyticsPurgeInterval,
+
+		GeoIPDatabasePath: strings.TrimSpace(viper.GetString("ANALYTICS_GEOIP_DATABASE")),
+	}
+	if cfg.RetentionDays < 1 || cfg.RetentionDays > maxAnalyticsRetentionDays {
+		return cfg, fmt.Errorf("invalid ANALYTICS_RETENTION_DAYS %d (use 1-%d)", cfg.RetentionDays, maxAnalyticsRetentionDays)
+	}
+
+	if value := strings.TrimSpace(viper.GetString("ANALYTICS_PURGE_INTERVAL")); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Minute {
+			return cfg, fmt.Errorf("invalid ANALYTICS_PURGE_INTERVAL %q (minimum 1m)", value)
+		}
+		cfg.PurgeInterval = interval
+	}
+
+	return cfg, nil
+}
+
+// loadOIDCProviders membaca konfigurasi provider SSO dari OIDC_PROVIDERS dan OIDC_<NAMA>_*
+func loadOIDCProviders() ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range splitList(viper.GetString("OIDC_PROVIDERS")) {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		provider := OIDCProviderConfig{
+			Name:           name,
+			IssuerURL:      viper.GetString(prefix + "ISSUER_URL"),
+			ClientID:       viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret:   viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:    viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:         strings.Fields(viper.GetString(prefix + "SCOPES")),
+			AllowedDomains: splitList(viper.GetString(prefix + "ALLOWED_DOMAINS")),
+			AutoProvision:  viper.GetBool(prefix + "AUTO_PROVISION"),
+		}
+
+		if provider.IssuerURL == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("OIDC provider %s requires %sISSUER_URL, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		// Auto-provisioning tanpa batas domain berarti siapa pun bisa membuat akun author
+		if provider.AutoProvision && len(provider.AllowedDomains) == 0 {
+			return nil, fmt.Errorf("OIDC provider %s: %sAUTO_PROVISION requires %sALLOWED_DOMAINS", name, prefix, prefix)
+		}
+
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// splitList memecah nilai config dipisah koma, mengabaikan elemen kosong
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
 // Panggil fungsi ini di main.go setelah Load config
 func InitDB(cfg *Config) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
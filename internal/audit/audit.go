@@ -0,0 +1,498 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// RedactedValue pengganti nilai field sensitif di activity log
+const RedactedValue = "[REDACTED]"
+
+// defaultRedactedFields kolom yang selalu disamarkan di semua model
+var defaultRedactedFields = []string{"password_hash", "token_hash", "key_hash", "secret"}
+
+// ignoredDiffFields kolom yang berubah di setiap update sehingga tidak dianggap perubahan
+var ignoredDiffFields = map[string]bool{"updated_at": true}
+
+// oldRowsKey key instance statement untuk menyimpan row sebelum update/delete
+const oldRowsKey = "audit:old_rows"
+
+// Model konfigurasi audit untuk satu model GORM
+type Model struct {
+	Module     domain.ActivityModuleType // Module di activity log
+	Entity     string                    // Nama entity di deskripsi log, contoh: "kategori"
+	LabelField string                    // Kolom yang ditampilkan di deskripsi log, contoh: "name"
+	Redact     []string                  // Kolom tambahan yang disamarkan selain defaultRedactedFields
+}
+
+// Plugin GORM yang mencatat create/update/delete model terdaftar ke activity log
+// Actor, IP dan user agent diambil dari context request (db.WithContext), tanpa actor tidak ada log
+type Plugin struct {
+	models map[reflect.Type]Model
+}
+
+// New membuat plugin audit tanpa model terdaftar
+func New() *Plugin {
+	return &Plugin{models: make(map[reflect.Type]Model)}
+}
+
+// Register mendaftarkan model yang perubahannya dicatat
+func (p *Plugin) Register(model any, cfg Model) *Plugin {
+	modelType := reflect.Indirect(reflect.ValueOf(model)).Type()
+	p.models[modelType] = cfg
+	return p
+}
+
+// Name implementasi gorm.Plugin
+func (p *Plugin) Name() string {
+	return "audit"
+}
+
+// Initialize implementasi gorm.Plugin, mendaftarkan callback create/update/delete
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	// Semua callback dijalankan sebelum commit agar activity log masuk transaksi yang sama dengan perubahan datanya
+	const commit = "gorm:commit_or_rollback_transaction"
+
+	if err := db.Callback().Create().After("gorm:create").Before(commit).Register("audit:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("audit:before_update", p.captureOldRows); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Before(commit).Register("audit:after_update", p.afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("audit:before_delete", p.captureOldRows); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", p.afterDelete)
+}
+
+// lookup mengambil konfigurasi model statement, false jika model tidak diaudit atau tidak ada actor
+func (p *Plugin) lookup(db *gorm.DB) (Model, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return Model{}, false
+	}
+	cfg, ok := p.models[db.Statement.Schema.ModelType]
+	if !ok {
+		return Model{}, false
+	}
+	if _, ok := utils.GetUserID(db.Statement.Context); !ok {
+		return Model{}, false // Skip if no user in context
+	}
+	return cfg, true
+}
+
+func (p *Plugin) afterCreate(db *gorm.DB) {
+	cfg, ok := p.lookup(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+
+	for _, row := range rowsOf(db.Statement.ReflectValue) {
+		newValue := Snapshot(db.Statement.Context, db.Statement.Schema, cfg, row)
+		p.write(db, cfg, domain.ActionCreate, "Membuat "+cfg.Entity, nil, newValue)
+	}
+}
+
+// captureOldRows membaca row yang akan diubah/dihapus sebelum query dijalankan
+func (p *Plugin) captureOldRows(db *gorm.DB) {
+	if _, ok := p.lookup(db); !ok {
+		return
+	}
+
+	rows, err := p.loadRows(db, db.Statement.Unscoped)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: gagal membaca data lama: %w", err))
+		return
+	}
+	db.InstanceSet(oldRowsKey, rows)
+}
+
+func (p *Plugin) afterUpdate(db *gorm.DB) {
+	cfg, ok := p.lookup(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	oldRows, ok := p.oldRows(db)
+	if !ok || len(oldRows) == 0 {
+		return
+	}
+
+	// Dibaca ulang tanpa scope soft delete agar update deleted_at tetap tercatat
+	newRows, err := p.queryByPrimaryKeys(db, oldRows)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: gagal membaca data baru: %w", err))
+		return
+	}
+
+	ctx, sch := db.Statement.Context, db.Statement.Schema
+	newByKey := make(map[string]map[string]any, len(newRows))
+	for _, row := range newRows {
+		snapshot := Snapshot(ctx, sch, cfg, row)
+		newByKey[primaryKeyString(ctx, sch, row)] = snapshot
+	}
+
+	for _, row := range oldRows {
+		after, found := newByKey[primaryKeyString(ctx, sch, row)]
+		if !found {
+			continue
+		}
+		oldValue, newValue := Diff(Snapshot(ctx, sch, cfg, row), after)
+		if len(newValue) == 0 && len(oldValue) == 0 {
+			continue // Tidak ada perubahan nyata
+		}
+		action, verb := domain.ActionUpdate, "Mengupdate "
+		if isSoftDelete(oldValue, newValue) {
+			action, verb = domain.ActionDelete, "Menghapus "
+		}
+		p.writeChange(db, cfg, action, verb+cfg.Entity, row, after, oldValue, newValue)
+	}
+}
+
+func (p *Plugin) afterDelete(db *gorm.DB) {
+	cfg, ok := p.lookup(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	oldRows, ok := p.oldRows(db)
+	if !ok {
+		return
+	}
+
+	for _, row := range oldRows {
+		oldValue := Snapshot(db.Statement.Context, db.Statement.Schema, cfg, row)
+		p.write(db, cfg, domain.ActionDelete, "Menghapus "+cfg.Entity, oldValue, nil)
+	}
+}
+
+func (p *Plugin) oldRows(db *gorm.DB) ([]reflect.Value, bool) {
+	value, ok := db.InstanceGet(oldRowsKey)
+	if !ok {
+		return nil, false
+	}
+	rows, ok := value.([]reflect.Value)
+	return rows, ok
+}
+
+// loadRows membaca row yang cocok dengan kondisi WHERE statement dan primary key pada dest
+func (p *Plugin) loadRows(db *gorm.DB, unscoped bool) ([]reflect.Value, error) {
+	stmt := db.Statement
+	tx := p.session(db)
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+
+	hasCondition := false
+	if where, ok := stmt.Clauses["WHERE"]; ok && where.Expression != nil {
+		tx = tx.Clauses(where.Expression)
+		hasCondition = true
+	}
+	if pf := stmt.Schema.PrioritizedPrimaryField; pf != nil && stmt.ReflectValue.IsValid() {
+		var keys []any
+		for _, row := range rowsOf(stmt.ReflectValue) {
+			if value, isZero := pf.ValueOf(stmt.Context, row); !isZero {
+				keys = append(keys, value)
+			}
+		}
+		if len(keys) > 0 {
+			tx = tx.Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pf.DBName}, Values: keys})
+			hasCondition = true
+		}
+	}
+	if !hasCondition {
+		return nil, nil // Update/delete global diblokir GORM, tidak ada yang perlu dibaca
+	}
+
+	return p.find(tx, stmt.Schema)
+}
+
+// queryByPrimaryKeys membaca ulang row berdasarkan primary key row lama
+func (p *Plugin) queryByPrimaryKeys(db *gorm.DB, rows []reflect.Value) ([]reflect.Value, error) {
+	pf := db.Statement.Schema.PrioritizedPrimaryField
+	if pf == nil {
+		return nil, nil
+	}
+
+	keys := make([]any, 0, len(rows))
+	for _, row := range rows {
+		value, _ := pf.ValueOf(db.Statement.Context, row)
+		keys = append(keys, value)
+	}
+
+	tx := p.session(db).Unscoped().Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pf.DBName}, Values: keys})
+	return p.find(tx, db.Statement.Schema)
+}
+
+// session membuat query baru di koneksi/transaksi yang sama tanpa hooks model
+func (p *Plugin) session(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+}
+
+func (p *Plugin) find(tx *gorm.DB, sch *schema.Schema) ([]reflect.Value, error) {
+	result := reflect.New(reflect.SliceOf(sch.ModelType))
+	if err := tx.Model(reflect.New(sch.ModelType).Interface()).Find(result.Interface()).Error; err != nil {
+		return nil, err
+	}
+	return rowsOf(result.Elem()), nil
+}
+
+// writeChange mencatat update dengan label dari data terbaru
+func (p *Plugin) writeChange(db *gorm.DB, cfg Model, action domain.ActivityActionType, description string, row reflect.Value, after, oldValue, newValue map[string]any) {
+	targetID := primaryKeyInt(db.Statement.Context, db.Statement.Schema, row)
+	p.create(db, cfg, action, withLabel(description, cfg, after), targetID, oldValue, newValue)
+}
+
+// write mencatat create/delete, snapshot lengkap disimpan di old/new value
+func (p *Plugin) write(db *gorm.DB, cfg Model, action domain.ActivityActionType, description string, oldValue, newValue map[string]any) {
+	snapshot := newValue
+	if snapshot == nil {
+		snapshot = oldValue
+	}
+
+	var targetID *int
+	if pf := db.Statement.Schema.PrioritizedPrimaryField; pf != nil {
+		if id, ok := toInt(snapshot[pf.DBName]); ok {
+			targetID = &id
+		}
+	}
+	p.create(db, cfg, action, withLabel(description, cfg, snapshot), targetID, publicValues(oldValue), publicValues(newValue))
+}
+
+// create menyimpan activity log di transaksi yang sama dengan perubahan datanya
+// Gagal mencatat = perubahan ikut dibatalkan, sehingga tidak ada perubahan tanpa jejak audit
+func (p *Plugin) create(db *gorm.DB, cfg Model, action domain.ActivityActionType, description string, targetID *int, oldValue, newValue map[string]any) {
+	log := NewActivityLog(db.Statement.Context, Event{
+		Action:      action,
+		Module:      cfg.Module,
+		Description: description,
+		TargetID:    targetID,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+	})
+
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if err := Append(tx, log); err != nil {
+		db.AddError(fmt.Errorf("audit: gagal menyimpan activity log: %w", err))
+	}
+}
+
+// Event satu kejadian yang dicatat ke activity log di luar perubahan model (login, revoke sesi, arsip, dll)
+type Event struct {
+	UserID      int // 0 berarti actor diambil dari context, tanpa actor dicatat sebagai user_id NULL
+	Action      domain.ActivityActionType
+	Module      domain.ActivityModuleType
+	Description string
+	TargetID    *int
+	OldValue    map[string]any
+	NewValue    map[string]any
+}
+
+// NewActivityLog membuat activity log dari event, IP, user agent, API key dan impersonator diambil dari context request
+func NewActivityLog(ctx context.Context, event Event) *domain.ActivityLog {
+	userID := event.UserID
+	if userID == 0 {
+		userID, _ = utils.GetUserID(ctx)
+	}
+
+	var ipPtr, uaPtr *string
+	if ipAddress := utils.GetIPAddress(ctx); ipAddress != "" {
+		ipPtr = &ipAddress
+	}
+	if userAgent := utils.GetUserAgent(ctx); userAgent != "" {
+		uaPtr = &userAgent
+	}
+
+	description := event.Description
+	return &domain.ActivityLog{
+		UserID:         userID,
+		ActionType:     event.Action,
+		Module:         event.Module,
+		Description:    &description,
+		TargetID:       event.TargetID,
+		OldValue:       event.OldValue,
+		NewValue:       event.NewValue,
+		IPAddress:      ipPtr,
+		UserAgent:      uaPtr,
+		APIKeyID:       utils.GetAPIKeyID(ctx),
+		ImpersonatorID: utils.GetImpersonatorID(ctx),
+	}
+}
+
+// Snapshot mengubah satu row menjadi map kolom -> nilai (format JSON), field sensitif disamarkan
+// Field read-only (gorm:"->") tidak disertakan karena tidak pernah diubah lewat model
+func Snapshot(ctx context.Context, sch *schema.Schema, cfg Model, row reflect.Value) map[string]any {
+	redacted := make(map[string]bool, len(defaultRedactedFields)+len(cfg.Redact))
+	for _, name := range defaultRedactedFields {
+		redacted[name] = true
+	}
+	for _, name := range cfg.Redact {
+		redacted[name] = true
+	}
+
+	values := make(map[string]any, len(sch.DBNames))
+	for _, name := range sch.DBNames {
+		field := sch.FieldsByDBName[name]
+		if field == nil || (!field.Creatable && !field.Updatable) {
+			continue
+		}
+
+		// Nilai field langsung (bukan ValueOf) agar field dengan serializer tidak ikut dibungkus
+		value := field.ReflectValueOf(ctx, row).Interface()
+		if redacted[name] {
+			// Nilai asli tetap dipakai untuk diff, hanya disamarkan di output
+			values[name] = redactedValue{value: normalize(value)}
+			continue
+		}
+		values[name] = normalize(value)
+	}
+	return values
+}
+
+// Diff mengembalikan kolom yang berubah antara dua snapshot (nilai lama dan nilai baru)
+func Diff(before, after map[string]any) (map[string]any, map[string]any) {
+	oldValue := make(map[string]any)
+	newValue := make(map[string]any)
+	for name, value := range after {
+		if ignoredDiffFields[name] {
+			continue
+		}
+		if previous, ok := before[name]; ok && reflect.DeepEqual(previous, value) {
+			continue
+		}
+		oldValue[name] = publicValue(before[name])
+		newValue[name] = publicValue(value)
+	}
+	return oldValue, newValue
+}
+
+// redactedValue menyimpan nilai field sensitif untuk perbandingan diff tanpa pernah ditulis ke log
+type redactedValue struct {
+	value any
+}
+
+// MarshalJSON memastikan nilai field sensitif tidak pernah keluar dari snapshot
+func (redactedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// publicValues mengganti semua redactedValue di snapshot dengan RedactedValue
+func publicValues(snapshot map[string]any) map[string]any {
+	if snapshot == nil {
+		return nil
+	}
+	values := make(map[string]any, len(snapshot))
+	for name, value := range snapshot {
+		values[name] = publicValue(value)
+	}
+	return values
+}
+
+// publicValue mengganti redactedValue dengan RedactedValue untuk disimpan di log
+func publicValue(value any) any {
+	if _, ok := value.(redactedValue); ok {
+		return RedactedValue
+	}
+	return value
+}
+
+// normalize mengubah nilai Go menjadi bentuk JSON (string, float64, bool, map, slice, nil)
+// agar perbandingan dan nilai yang disimpan konsisten dengan kolom jsonb
+func normalize(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return string(data)
+	}
+	return out
+}
+
+// isSoftDelete mengecek update yang mengisi deleted_at (soft delete manual tanpa gorm.DeletedAt)
+func isSoftDelete(oldValue, newValue map[string]any) bool {
+	deletedAt, changed := newValue["deleted_at"]
+	return changed && isNull(oldValue["deleted_at"]) && !isNull(deletedAt)
+}
+
+// isNull mengecek nilai kosong hasil normalize, termasuk sql.Null* yang di-encode sebagai {"Valid": false}
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+	if nullable, ok := value.(map[string]any); ok {
+		valid, _ := nullable["Valid"].(bool)
+		return !valid
+	}
+	return false
+}
+
+// withLabel menambahkan label entity (contoh: nama kategori) ke deskripsi log
+func withLabel(description string, cfg Model, snapshot map[string]any) string {
+	if cfg.LabelField == "" {
+		return description
+	}
+	if label, ok := snapshot[cfg.LabelField].(string); ok && label != "" {
+		return description + ": " + label
+	}
+	return description
+}
+
+// rowsOf mengembalikan struct row dari ReflectValue (struct tunggal atau slice)
+func rowsOf(value reflect.Value) []reflect.Value {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		rows := make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if row := reflect.Indirect(value.Index(i)); row.Kind() == reflect.Struct {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	case reflect.Struct:
+		return []reflect.Value{value}
+	}
+	return nil
+}
+
+func primaryKeyString(ctx context.Context, sch *schema.Schema, row reflect.Value) string {
+	if sch.PrioritizedPrimaryField == nil {
+		return ""
+	}
+	value, _ := sch.PrioritizedPrimaryField.ValueOf(ctx, row)
+	return fmt.Sprint(value)
+}
+
+func primaryKeyInt(ctx context.Context, sch *schema.Schema, row reflect.Value) *int {
+	if sch.PrioritizedPrimaryField == nil {
+		return nil
+	}
+	value, _ := sch.PrioritizedPrimaryField.ValueOf(ctx, row)
+	if id, ok := toInt(normalize(value)); ok {
+		return &id
+	}
+	return nil
+}
+
+// toInt mengubah nilai primary key hasil normalize menjadi int
+func toInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case string:
+		id, err := strconv.Atoi(v)
+		return id, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm/schema"
+)
+
+func parseSchema(t *testing.T, model any) *schema.Schema {
+	t.Helper()
+	sch, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	return sch
+}
+
+func TestSnapshot_RedactsSensitiveFieldsAndSkipsReadOnly(t *testing.T) {
+	sch := parseSchema(t, &domain.User{})
+	user := domain.User{ID: 7, FullName: "Admin", Email: "admin@pmii.id", PasswordHash: "$2a$10$secret", FailedLoginCount: 3}
+
+	snapshot := Snapshot(context.Background(), sch, Model{}, reflect.ValueOf(user))
+
+	if snapshot["full_name"] != "Admin" || snapshot["id"] != float64(7) {
+		t.Errorf("unexpected snapshot values: %+v", snapshot)
+	}
+	if _, ok := snapshot["failed_login_count"]; ok {
+		t.Error("expected read-only field to be skipped")
+	}
+
+	data, err := json.Marshal(publicValues(snapshot))
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("password hash leaked into snapshot: %s", data)
+	}
+	if !strings.Contains(string(data), `"password_hash":"[REDACTED]"`) {
+		t.Errorf("expected password hash to be redacted, got %s", data)
+	}
+}
+
+func TestSnapshot_CustomRedactedField(t *testing.T) {
+	sch := parseSchema(t, &domain.Contact{})
+	phone := "08123456789"
+
+	snapshot := Snapshot(context.Background(), sch, Model{Redact: []string{"phone"}}, reflect.ValueOf(domain.Contact{ID: 1, Phone: &phone}))
+
+	if publicValue(snapshot["phone"]) != RedactedValue {
+		t.Errorf("expected phone to be redacted, got %v", snapshot["phone"])
+	}
+}
+
+func TestDiff_OnlyChangedFields(t *testing.T) {
+	sch := parseSchema(t, &domain.User{})
+	ctx := context.Background()
+	before := domain.User{ID: 7, FullName: "Lama", Email: "a@pmii.id", PasswordHash: "hash-lama", IsActive: true}
+	after := before
+	after.FullName = "Baru"
+	after.PasswordHash = "hash-baru"
+	after.UpdatedAt = before.UpdatedAt.AddDate(0, 0, 1)
+
+	oldValue, newValue := Diff(
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(before)),
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(after)),
+	)
+
+	if len(newValue) != 2 || len(oldValue) != 2 {
+		t.Fatalf("expected full_name and password_hash only, got old=%v new=%v", oldValue, newValue)
+	}
+	if oldValue["full_name"] != "Lama" || newValue["full_name"] != "Baru" {
+		t.Errorf("unexpected full_name diff: old=%v new=%v", oldValue["full_name"], newValue["full_name"])
+	}
+	// Perubahan password tetap tercatat, tapi nilainya disamarkan
+	if oldValue["password_hash"] != RedactedValue || newValue["password_hash"] != RedactedValue {
+		t.Errorf("expected redacted password diff, got old=%v new=%v", oldValue["password_hash"], newValue["password_hash"])
+	}
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	sch := parseSchema(t, &domain.Category{})
+	category := domain.Category{ID: 1, Name: "Opini", Slug: "opini"}
+	snapshot := Snapshot(context.Background(), sch, Model{}, reflect.ValueOf(category))
+
+	oldValue, newValue := Diff(snapshot, snapshot)
+	if len(oldValue) != 0 || len(newValue) != 0 {
+		t.Errorf("expected no diff, got old=%v new=%v", oldValue, newValue)
+	}
+}
+
+func TestWithLabel(t *testing.T) {
+	cfg := Model{Entity: "kategori", LabelField: "name"}
+
+	if got := withLabel("Membuat kategori", cfg, map[string]any{"name": "Opini"}); got != "Membuat kategori: Opini" {
+		t.Errorf("unexpected description: %q", got)
+	}
+	if got := withLabel("Membuat kategori", cfg, map[string]any{"name": nil}); got != "Membuat kategori" {
+		t.Errorf("expected description without label, got %q", got)
+	}
+}
+
+func TestIsSoftDelete(t *testing.T) {
+	sch := parseSchema(t, &domain.Document{})
+	ctx := context.Background()
+	before := domain.Document{ID: 4, Name: "AD/ART"}
+	after := before
+	after.DeletedAt.Valid = true
+	after.DeletedAt.Time = time.Now()
+
+	oldValue, newValue := Diff(
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(before)),
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(after)),
+	)
+	if !isSoftDelete(oldValue, newValue) {
+		t.Errorf("expected deleted_at update to count as soft delete, got old=%v new=%v", oldValue, newValue)
+	}
+
+	after = before
+	after.Name = "AD/ART 2025"
+	oldValue, newValue = Diff(
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(before)),
+		Snapshot(ctx, sch, Model{}, reflect.ValueOf(after)),
+	)
+	if isSoftDelete(oldValue, newValue) {
+		t.Error("expected regular update not to count as soft delete")
+	}
+}
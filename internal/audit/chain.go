@@ -0,0 +1,169 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GenesisHash adalah prev_hash untuk entry pertama di hash chain
+var GenesisHash = strings.Repeat("0", 64)
+
+// createdAtLayout format created_at yang di-hash: jam dinding tanpa zona waktu, presisi mikrodetik
+// Kolom created_at bertipe timestamp (tanpa zona waktu), sehingga hanya jam dindingnya yang tersimpan dan dibaca kembali
+const createdAtLayout = "2006-01-02T15:04:05.000000"
+
+// chainLockKey adalah key pg_advisory_xact_lock agar entry ditambahkan ke chain satu per satu
+// Lock dilepas saat transaksi selesai, sehingga urutan id selalu sama dengan urutan chain
+const chainLockKey = 440_044
+
+// canonicalEntry menentukan isi entry yang di-hash, urutan field tetap
+// ID tidak ikut karena baru diketahui setelah insert, urutan dijaga oleh prev_hash
+type canonicalEntry struct {
+	PrevHash       string                    `json:"prev_hash"`
+	UserID         int                       `json:"user_id"`
+	ActionType     domain.ActivityActionType `json:"action_type"`
+	Module         domain.ActivityModuleType `json:"module"`
+	Description    *string                   `json:"description"`
+	TargetID       *int                      `json:"target_id"`
+	OldValue       map[string]any            `json:"old_value"`
+	NewValue       map[string]any            `json:"new_value"`
+	IPAddress      *string                   `json:"ip_address"`
+	UserAgent      *string                   `json:"user_agent"`
+	APIKeyID       *int                      `json:"api_key_id"`
+	ImpersonatorID *int                      `json:"impersonator_id"`
+	CreatedAt      string                    `json:"created_at"`
+}
+
+// Append menambahkan activity log ke hash chain lalu menyimpannya
+// Jika db sedang dalam transaksi (mis. dari callback audit), entry ikut di-commit/rollback bersama transaksi tersebut
+func Append(db *gorm.DB, log *domain.ActivityLog) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := LockChain(tx); err != nil {
+			return err
+		}
+
+		prevHash, err := chainHead(tx)
+		if err != nil {
+			return err
+		}
+		if err := Seal(log, prevHash); err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Create(log).Error
+	})
+}
+
+// LockChain mengunci hash chain sampai transaksi tx selesai
+// Dipakai juga saat entry lama diarsipkan agar tidak ada entry baru yang menunjuk entry yang sedang dihapus
+func LockChain(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error
+}
+
+// chainHead mengambil entry_hash terakhir di chain
+// Jika semua entry sudah diarsipkan, chain dilanjutkan dari hash entry terakhir di arsip
+func chainHead(tx *gorm.DB) (string, error) {
+	var last []string
+	if err := tx.Model(&domain.ActivityLog{}).
+		Where("entry_hash IS NOT NULL").
+		Order("id DESC").
+		Limit(1).
+		Pluck("entry_hash", &last).Error; err != nil {
+		return "", err
+	}
+	if len(last) > 0 {
+		return last[0], nil
+	}
+
+	if err := tx.Model(&domain.ActivityLogArchive{}).
+		Where("last_entry_hash IS NOT NULL").
+		Order("to_log_id DESC").
+		Limit(1).
+		Pluck("last_entry_hash", &last).Error; err != nil {
+		return "", err
+	}
+	if len(last) > 0 {
+		return last[0], nil
+	}
+	return GenesisHash, nil
+}
+
+// Seal mengisi prev_hash dan entry_hash log
+// old_value/new_value dinormalisasi agar sama persis dengan yang dibaca kembali dari database, created_at tidak diubah
+func Seal(log *domain.ActivityLog, prevHash string) error {
+	oldValue, err := normalizeMap(log.OldValue)
+	if err != nil {
+		return err
+	}
+	newValue, err := normalizeMap(log.NewValue)
+	if err != nil {
+		return err
+	}
+	log.OldValue = oldValue
+	log.NewValue = newValue
+
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	log.PrevHash = &prevHash
+
+	hash, err := EntryHash(log)
+	if err != nil {
+		return err
+	}
+	log.EntryHash = &hash
+	return nil
+}
+
+// EntryHash menghitung SHA-256 (hex) dari isi kanonik entry termasuk prev_hash
+func EntryHash(log *domain.ActivityLog) (string, error) {
+	entry := canonicalEntry{
+		UserID:         log.UserID,
+		ActionType:     log.ActionType,
+		Module:         log.Module,
+		Description:    log.Description,
+		TargetID:       log.TargetID,
+		OldValue:       log.OldValue,
+		NewValue:       log.NewValue,
+		IPAddress:      log.IPAddress,
+		UserAgent:      log.UserAgent,
+		APIKeyID:       log.APIKeyID,
+		ImpersonatorID: log.ImpersonatorID,
+		CreatedAt:      log.CreatedAt.Truncate(time.Microsecond).Format(createdAtLayout),
+	}
+	if log.PrevHash != nil {
+		entry.PrevHash = *log.PrevHash
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", fmt.Errorf("audit: gagal membuat hash activity log: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeMap menyamakan tipe nilai dengan hasil decode JSONB (angka jadi float64, struct jadi map)
+func normalizeMap(value map[string]any) (map[string]any, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("audit: gagal menormalisasi nilai activity log: %w", err)
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("audit: gagal menormalisasi nilai activity log: %w", err)
+	}
+	return normalized, nil
+}
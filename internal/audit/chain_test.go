@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+)
+
+func newChainTestLog() *domain.ActivityLog {
+	description := "Mengupdate kategori: Opini"
+	targetID := 3
+	return &domain.ActivityLog{
+		UserID:      1,
+		ActionType:  domain.ActionUpdate,
+		Module:      domain.ModuleCategory,
+		Description: &description,
+		TargetID:    &targetID,
+		OldValue:    map[string]any{"name": "Opini", "sort": 1},
+		NewValue:    map[string]any{"name": "Opini & Esai", "sort": 2},
+		CreatedAt:   time.Date(2026, 10, 18, 9, 30, 0, 123456789, time.FixedZone("WIB", 7*3600)),
+	}
+}
+
+func TestSeal_HashMatchesStoredRow(t *testing.T) {
+	log := newChainTestLog()
+	if err := Seal(log, GenesisHash); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.PrevHash == nil || *log.PrevHash != GenesisHash || log.EntryHash == nil || len(*log.EntryHash) != 64 {
+		t.Fatalf("unexpected hashes: prev=%v entry=%v", log.PrevHash, log.EntryHash)
+	}
+	if want := newChainTestLog().CreatedAt; !log.CreatedAt.Equal(want) || log.CreatedAt.Location().String() != want.Location().String() {
+		t.Errorf("expected created_at to be left unchanged, got %v", log.CreatedAt)
+	}
+
+	// Simulasi row yang dibaca kembali dari database: JSONB di-decode ulang,
+	// timestamp tanpa zona waktu kembali dengan jam dinding yang sama dalam UTC dan presisi mikrodetik
+	var stored domain.ActivityLog
+	data, _ := json.Marshal(log)
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("failed to decode log: %v", err)
+	}
+	wall := log.CreatedAt.Truncate(time.Microsecond)
+	stored.CreatedAt = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+	hash, err := EntryHash(&stored)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != *log.EntryHash {
+		t.Errorf("expected hash of stored row to match, got %s want %s", hash, *log.EntryHash)
+	}
+}
+
+func TestEntryHash_DetectsChanges(t *testing.T) {
+	log := newChainTestLog()
+	if err := Seal(log, GenesisHash); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original := *log.EntryHash
+
+	log.NewValue["name"] = "Opini"
+	if hash, _ := EntryHash(log); hash == original {
+		t.Error("expected modified new_value to change the hash")
+	}
+
+	log = newChainTestLog()
+	prevHash := "ab" + GenesisHash[2:]
+	if err := Seal(log, prevHash); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *log.EntryHash == original {
+		t.Error("expected different prev_hash to change the hash")
+	}
+}
+
+func TestCheckpointSigner_SignAndVerify(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer := NewCheckpointSigner(private)
+
+	checkpoint := domain.ActivityLogCheckpoint{LastLogID: 42, EntryHash: GenesisHash, EntryCount: 40, CreatedAt: time.Now()}
+	signer.Sign(&checkpoint)
+	if checkpoint.KeyID != signer.KeyID() || checkpoint.Signature == "" {
+		t.Fatalf("expected checkpoint to be signed, got %+v", checkpoint)
+	}
+	if !signer.Verify(checkpoint) {
+		t.Error("expected signature to be valid")
+	}
+
+	// Verifikasi di luar aplikasi hanya dengan public key hasil ekspor
+	publicKey, err := signer.PublicKeyPEM()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	verifier, err := ParseCheckpointVerifier(publicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if verifier.KeyID() != signer.KeyID() || !verifier.Verify(checkpoint) {
+		t.Error("expected exported public key to verify the checkpoint")
+	}
+
+	tampered := checkpoint
+	tampered.EntryCount = 39
+	if verifier.Verify(tampered) {
+		t.Error("expected tampered checkpoint to fail verification")
+	}
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// CheckpointVerifier memverifikasi signature checkpoint dengan public key Ed25519
+type CheckpointVerifier struct {
+	keyID  string
+	public ed25519.PublicKey
+}
+
+// CheckpointSigner menandatangani checkpoint hash chain dengan private key Ed25519
+// Public key ikut diekspor sehingga checkpoint bisa diverifikasi di luar aplikasi tanpa akses ke database
+type CheckpointSigner struct {
+	CheckpointVerifier
+	private ed25519.PrivateKey
+}
+
+// LoadCheckpointSigner membaca private key Ed25519 (PEM PKCS#8) dari file
+func LoadCheckpointSigner(path string) (*CheckpointSigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit checkpoint key: %w", err)
+	}
+
+	private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("audit checkpoint key %s must be an Ed25519 private key in PEM format", path)
+	}
+	signer, ok := private.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("unsupported audit checkpoint key type")
+	}
+	return NewCheckpointSigner(signer), nil
+}
+
+// NewCheckpointSigner membuat signer dari private key Ed25519
+func NewCheckpointSigner(private ed25519.PrivateKey) *CheckpointSigner {
+	return &CheckpointSigner{
+		CheckpointVerifier: newCheckpointVerifier(private.Public().(ed25519.PublicKey)),
+		private:            private,
+	}
+}
+
+// ParseCheckpointVerifier membaca public key Ed25519 (PEM PKIX), mis. dari berkas ekspor checkpoint
+func ParseCheckpointVerifier(publicKeyPEM string) (*CheckpointVerifier, error) {
+	public, err := jwt.ParseEdPublicKeyFromPEM([]byte(publicKeyPEM))
+	if err != nil {
+		return nil, errors.New("audit checkpoint public key must be an Ed25519 public key in PEM format")
+	}
+	key, ok := public.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("unsupported audit checkpoint public key type")
+	}
+	verifier := newCheckpointVerifier(key)
+	return &verifier, nil
+}
+
+func newCheckpointVerifier(public ed25519.PublicKey) CheckpointVerifier {
+	sum := sha256.Sum256(public)
+	return CheckpointVerifier{keyID: hex.EncodeToString(sum[:8]), public: public}
+}
+
+// KeyID adalah fingerprint public key (8 byte pertama SHA-256, hex)
+func (v *CheckpointVerifier) KeyID() string {
+	return v.keyID
+}
+
+// PublicKeyPEM mengembalikan public key dalam format PEM (PKIX) untuk disimpan bersama hasil ekspor
+func (v *CheckpointVerifier) PublicKeyPEM() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(v.public)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// Sign mengisi key_id dan signature checkpoint, created_at tidak diubah
+func (s *CheckpointSigner) Sign(checkpoint *domain.ActivityLogCheckpoint) {
+	checkpoint.KeyID = s.keyID
+	signature := ed25519.Sign(s.private, []byte(CheckpointPayload(checkpoint)))
+	checkpoint.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+// Verify mengecek signature checkpoint yang ditandatangani dengan key ini
+func (v *CheckpointVerifier) Verify(checkpoint domain.ActivityLogCheckpoint) bool {
+	if checkpoint.KeyID != v.keyID {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(checkpoint.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(v.public, []byte(CheckpointPayload(&checkpoint)), signature)
+}
+
+// CheckpointPayload adalah pesan yang ditandatangani, dipakai juga untuk verifikasi di luar aplikasi
+// created_at memakai format jam dinding yang sama dengan hash entry (lihat createdAtLayout)
+func CheckpointPayload(checkpoint *domain.ActivityLogCheckpoint) string {
+	return fmt.Sprintf("pmii-audit-checkpoint:v1:%d:%s:%d:%s",
+		checkpoint.LastLogID,
+		checkpoint.EntryHash,
+		checkpoint.EntryCount,
+		checkpoint.CreatedAt.Truncate(time.Microsecond).Format(createdAtLayout),
+	)
+}
@@ -0,0 +1,21 @@
+package audit
+
+import "github.com/garuda-labs-1/pmii-be/internal/domain"
+
+// Default membuat plugin audit dengan semua model konten yang dikelola lewat admin panel
+// Event non-CRUD (login, sesi, 2FA, impersonasi, dll) tetap dicatat manual oleh service masing-masing
+func Default() *Plugin {
+	return New().
+		Register(&domain.Post{}, Model{Module: domain.ModulePost, Entity: "post", LabelField: "title"}).
+		Register(&domain.Category{}, Model{Module: domain.ModuleCategory, Entity: "kategori", LabelField: "name"}).
+		Register(&domain.Tag{}, Model{Module: domain.ModuleTags, Entity: "tag", LabelField: "name"}).
+		Register(&domain.Member{}, Model{Module: domain.ModuleMembers, Entity: "member", LabelField: "full_name"}).
+		Register(&domain.Testimonial{}, Model{Module: domain.ModuleTestimoni, Entity: "testimonial", LabelField: "name"}).
+		Register(&domain.Document{}, Model{Module: domain.ModuleDokumen, Entity: "dokumen", LabelField: "name"}).
+		Register(&domain.Ad{}, Model{Module: domain.ModuleAds, Entity: "iklan", LabelField: "page"}).
+		Register(&domain.User{}, Model{Module: domain.ModuleUser, Entity: "user", LabelField: "full_name"}).
+		Register(&domain.About{}, Model{Module: domain.ModuleSettings, Entity: "halaman about", LabelField: "title"}).
+		Register(&domain.SiteSetting{}, Model{Module: domain.ModuleSettings, Entity: "pengaturan situs", LabelField: "site_name"}).
+		Register(&domain.Contact{}, Model{Module: domain.ModuleSettings, Entity: "kontak", LabelField: "email"}).
+		Register(&domain.Webhook{}, Model{Module: domain.ModuleWebhooks, Entity: "webhook", LabelField: "name"})
+}
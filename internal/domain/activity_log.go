@@ -6,46 +6,59 @@ import "time"
 type ActivityActionType string
 
 const (
-	ActionCreate ActivityActionType = "create"
-	ActionUpdate ActivityActionType = "update"
-	ActionDelete ActivityActionType = "delete"
-	ActionLogin  ActivityActionType = "login"
-	ActionLogout ActivityActionType = "logout"
+	ActionCreate       ActivityActionType = "create"
+	ActionUpdate       ActivityActionType = "update"
+	ActionDelete       ActivityActionType = "delete"
+	ActionLogin        ActivityActionType = "login"
+	ActionLogout       ActivityActionType = "logout"
+	ActionLoginFailed  ActivityActionType = "login_failed"
+	ActionImpersonate  ActivityActionType = "impersonate"
+	ActionAccessDenied ActivityActionType = "access_denied"
+	ActionArchive      ActivityActionType = "archive"
 )
 
 // ActivityModuleType represents the module where the action was performed
 type ActivityModuleType string
 
 const (
-	ModuleUser      ActivityModuleType = "user"
-	ModulePost      ActivityModuleType = "post"
-	ModuleCategory  ActivityModuleType = "category"
-	ModuleTags      ActivityModuleType = "tags"
-	ModuleTestimoni ActivityModuleType = "testimoni"
-	ModuleMembers   ActivityModuleType = "members"
-	ModuleTeams     ActivityModuleType = "teams"
-	ModuleDokumen   ActivityModuleType = "dokumen"
-	ModuleSettings  ActivityModuleType = "settings"
-	ModuleAuth      ActivityModuleType = "auth"
-	ModuleAds       ActivityModuleType = "ads"
+	ModuleUser         ActivityModuleType = "user"
+	ModulePost         ActivityModuleType = "post"
+	ModuleCategory     ActivityModuleType = "category"
+	ModuleTags         ActivityModuleType = "tags"
+	ModuleTestimoni    ActivityModuleType = "testimoni"
+	ModuleMembers      ActivityModuleType = "members"
+	ModuleTeams        ActivityModuleType = "teams"
+	ModuleDokumen      ActivityModuleType = "dokumen"
+	ModuleSettings     ActivityModuleType = "settings"
+	ModuleAuth         ActivityModuleType = "auth"
+	ModuleAds          ActivityModuleType = "ads"
+	ModuleSecurity     ActivityModuleType = "security"
+	ModuleActivityLogs ActivityModuleType = "activity_logs"
+	ModuleWebhooks     ActivityModuleType = "webhooks"
 )
 
 // ActivityLog represents a log entry for user activities
 type ActivityLog struct {
-	ID          int                `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID      int                `gorm:"not null" json:"user_id"`
-	ActionType  ActivityActionType `gorm:"type:activity_action_type;not null" json:"action_type"`
-	Module      ActivityModuleType `gorm:"type:activity_module_type;not null" json:"module"`
-	Description *string            `gorm:"type:text" json:"description,omitempty"`
-	TargetID    *int               `json:"target_id,omitempty"`
-	OldValue    map[string]any     `gorm:"type:jsonb;serializer:json" json:"old_value,omitempty"`
-	NewValue    map[string]any     `gorm:"type:jsonb;serializer:json" json:"new_value,omitempty"`
-	IPAddress   *string            `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
-	UserAgent   *string            `gorm:"type:text" json:"user_agent,omitempty"`
-	CreatedAt   time.Time          `gorm:"default:now()" json:"created_at"`
+	ID             int                `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID         int                `gorm:"default:null" json:"user_id"` // 0 (NULL) untuk job sistem, mis. arsip terjadwal
+	ActionType     ActivityActionType `gorm:"type:activity_action_type;not null" json:"action_type"`
+	Module         ActivityModuleType `gorm:"type:activity_module_type;not null" json:"module"`
+	Description    *string            `gorm:"type:text" json:"description,omitempty"`
+	TargetID       *int               `json:"target_id,omitempty"`
+	OldValue       map[string]any     `gorm:"type:jsonb;serializer:json" json:"old_value,omitempty"`
+	NewValue       map[string]any     `gorm:"type:jsonb;serializer:json" json:"new_value,omitempty"`
+	IPAddress      *string            `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	UserAgent      *string            `gorm:"type:text" json:"user_agent,omitempty"`
+	APIKeyID       *int               `gorm:"column:api_key_id" json:"api_key_id,omitempty"` // Diisi jika aksi dilakukan dengan API key
+	ImpersonatorID *int               `json:"impersonator_id,omitempty"`                     // Diisi dengan admin yang sebenarnya jika aksi dilakukan saat impersonasi
+	CreatedAt      time.Time          `gorm:"default:now()" json:"created_at"`
+	PrevHash       *string            `gorm:"type:varchar(64)" json:"prev_hash,omitempty"`  // entry_hash entry sebelumnya di hash chain
+	EntryHash      *string            `gorm:"type:varchar(64)" json:"entry_hash,omitempty"` // NULL untuk entry sebelum hash chain aktif
 
 	// Relationship
-	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	User         User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	APIKey       *APIKey `gorm:"foreignKey:APIKeyID" json:"api_key,omitempty"`
+	Impersonator *User   `gorm:"foreignKey:ImpersonatorID" json:"impersonator,omitempty"`
 }
 
 // TableName specifies the table name for ActivityLog
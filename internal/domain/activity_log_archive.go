@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ActivityLogArchive mencatat satu batch activity log lama yang sudah diarsipkan lalu dihapus dari tabel
+// LastEntryHash menjadi prev_hash yang diharapkan untuk entry pertama yang tersisa di hash chain
+type ActivityLogArchive struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Storage       string    `gorm:"type:varchar(20);not null" json:"storage"` // disk atau cloudinary
+	Location      string    `gorm:"type:text;not null" json:"location"`
+	FromLogID     int       `gorm:"not null" json:"from_log_id"`
+	ToLogID       int       `gorm:"not null" json:"to_log_id"`
+	EntryCount    int64     `gorm:"not null" json:"entry_count"`
+	ChainedCount  int64     `gorm:"not null" json:"chained_count"` // Entry ber-hash di arsip ini
+	LastEntryHash *string   `gorm:"type:varchar(64)" json:"last_entry_hash,omitempty"`
+	Cutoff        time.Time `gorm:"not null" json:"cutoff"` // Entry sebelum waktu ini diarsipkan
+	SHA256        string    `gorm:"column:sha256;type:varchar(64);not null" json:"sha256"`
+	SizeBytes     int64     `gorm:"not null" json:"size_bytes"`
+	CreatedBy     *int      `json:"created_by,omitempty"` // NULL jika dijalankan oleh job terjadwal
+	CreatedAt     time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for ActivityLogArchive
+func (ActivityLogArchive) TableName() string {
+	return "activity_log_archives"
+}
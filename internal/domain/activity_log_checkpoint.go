@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// ActivityLogCheckpoint adalah snapshot head hash chain activity log yang ditandatangani (Ed25519)
+// Checkpoint diekspor dan disimpan di luar database agar penghapusan entry terakhir tetap terdeteksi
+type ActivityLogCheckpoint struct {
+	ID         int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	LastLogID  int       `gorm:"not null" json:"last_log_id"`
+	EntryHash  string    `gorm:"type:varchar(64);not null" json:"entry_hash"`
+	EntryCount int64     `gorm:"not null" json:"entry_count"` // Jumlah entry ber-hash sampai LastLogID
+	KeyID      string    `gorm:"type:varchar(64);not null" json:"key_id"`
+	Signature  string    `gorm:"type:text;not null" json:"signature"` // Signature Ed25519 (base64) atas CheckpointPayload
+	CreatedAt  time.Time `gorm:"not null" json:"created_at"`
+}
+
+// TableName specifies the table name for ActivityLogCheckpoint
+func (ActivityLogCheckpoint) TableName() string {
+	return "activity_log_checkpoints"
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"net"
+	"time"
+)
+
+// AdminIPAllowlistEntry represents an IP range allowed to access the admin API
+// An empty allowlist means the admin API is reachable from any IP
+type AdminIPAllowlistEntry struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	CIDR        string    `gorm:"column:cidr;type:varchar(64);uniqueIndex;not null" json:"cidr"` // IP tunggal disimpan sebagai /32 atau /128
+	Description *string   `gorm:"type:varchar(255)" json:"description,omitempty"`
+	CreatedBy   *int      `json:"created_by,omitempty"`
+	CreatedAt   time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for AdminIPAllowlistEntry
+func (AdminIPAllowlistEntry) TableName() string {
+	return "admin_ip_allowlist"
+}
+
+// Contains checks whether the IP is inside the entry's range
+func (e *AdminIPAllowlistEntry) Contains(ip net.IP) bool {
+	_, network, err := net.ParseCIDR(e.CIDR)
+	return err == nil && network.Contains(ip)
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"net"
+	"time"
+)
+
+// Scope yang bisa diberikan ke API key
+const (
+	ScopeNewsRead      = "news:read"
+	ScopePostsWrite    = "posts:write"
+	ScopeDocumentsRead = "documents:read"
+)
+
+// APIKeyScopes daftar semua scope yang valid
+var APIKeyScopes = []string{ScopeNewsRead, ScopePostsWrite, ScopeDocumentsRead}
+
+// APIKeyScopePermissions permission yang harus masih dimiliki pembuat key agar scope bisa dipakai
+var APIKeyScopePermissions = map[string][]string{
+	ScopeNewsRead:      {PermPostsManageAny},
+	ScopePostsWrite:    {PermPostsCreate, PermPostsUpdate, PermPostsDelete},
+	ScopeDocumentsRead: {PermDocumentsManage},
+}
+
+// APIKey represents a machine-to-machine credential created by an admin
+// Only the SHA-256 hash of the key is stored, Prefix identifies the key without revealing it
+type APIKey struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);uniqueIndex;not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Scopes     []string   `gorm:"type:jsonb;serializer:json;not null" json:"scopes"`
+	AllowedIPs []string   `gorm:"type:jsonb;serializer:json;not null" json:"allowed_ips"` // IP atau CIDR, kosong = semua IP
+	CreatedBy  int        `gorm:"not null" json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP *string    `gorm:"type:varchar(45)" json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive checks if the key is not revoked and not expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// HasScope checks if the key was granted the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsIP checks the client IP against the allowlist (IP atau CIDR)
+// An empty allowlist allows every IP
+func (k *APIKey) AllowsIP(ipAddress string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+
+	for _, allowed := range k.AllowedIPs {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
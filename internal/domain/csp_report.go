@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// CSPReport represents a Content Security Policy violation reported by a browser
+type CSPReport struct {
+	ID                 int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	DocumentURI        string    `gorm:"type:text" json:"document_uri"`
+	Referrer           string    `gorm:"type:text" json:"referrer,omitempty"`
+	ViolatedDirective  string    `gorm:"type:varchar(255)" json:"violated_directive"`
+	EffectiveDirective string    `gorm:"type:varchar(255)" json:"effective_directive"`
+	BlockedURI         string    `gorm:"type:text" json:"blocked_uri"`
+	SourceFile         string    `gorm:"type:text" json:"source_file,omitempty"`
+	LineNumber         *int      `json:"line_number,omitempty"`
+	ColumnNumber       *int      `json:"column_number,omitempty"`
+	StatusCode         *int      `json:"status_code,omitempty"`
+	Sample             string    `gorm:"type:text" json:"sample,omitempty"`
+	Disposition        string    `gorm:"type:varchar(20)" json:"disposition"` // enforce atau report
+	OriginalPolicy     string    `gorm:"type:text" json:"original_policy"`
+	IPAddress          *string   `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	UserAgent          *string   `gorm:"type:text" json:"user_agent,omitempty"`
+	CreatedAt          time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for CSPReport
+func (CSPReport) TableName() string {
+	return "csp_reports"
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// LoginAttempt represents a single login attempt, used to detect brute-force per account and per IP
+// UserID is nil when the email is not registered
+type LoginAttempt struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email     string    `gorm:"type:varchar(100);not null" json:"email"`
+	UserID    *int      `json:"user_id,omitempty"`
+	IPAddress *string   `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	Success   bool      `gorm:"not null;default:false" json:"success"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for LoginAttempt
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
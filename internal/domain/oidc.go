@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// OIDCLoginState represents a pending OpenID Connect login (authorization code + PKCE)
+// Only the SHA-256 hash of the state is stored; the row is deleted when consumed
+type OIDCLoginState struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Provider     string    `gorm:"type:varchar(50);not null" json:"provider"`
+	StateHash    string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Nonce        string    `gorm:"type:varchar(64);not null" json:"-"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt    time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for OIDCLoginState
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// UserIdentity links an external identity (provider + subject) to a user account
+type UserIdentity struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      int        `gorm:"not null;index" json:"user_id"`
+	Provider    string     `gorm:"type:varchar(50);not null" json:"provider"`
+	Subject     string     `gorm:"type:varchar(255);not null" json:"subject"`
+	Email       string     `gorm:"type:varchar(100);not null" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for UserIdentity
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
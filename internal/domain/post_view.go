@@ -4,11 +4,14 @@ import "time"
 
 // PostView tracks individual views of a post
 type PostView struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	PostID    int       `gorm:"not null;index" json:"post_id"`
-	IPAddress *string   `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
-	UserAgent *string   `gorm:"type:text" json:"user_agent,omitempty"`
-	ViewedAt  time.Time `gorm:"default:now()" json:"viewed_at"`
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PostID      int       `gorm:"not null;index" json:"post_id"`
+	VisitorHash *string   `gorm:"type:varchar(64)" json:"-"` // Hash IP dengan salt harian, bukan IP mentah
+	UserAgent   *string   `gorm:"type:text" json:"user_agent,omitempty"`
+	Country     *string   `gorm:"type:varchar(2)" json:"country,omitempty"` // ISO 3166-1 alpha-2
+	Region      *string   `gorm:"type:varchar(100)" json:"region,omitempty"`
+	City        *string   `gorm:"type:varchar(100)" json:"city,omitempty"`
+	ViewedAt    time.Time `gorm:"default:now()" json:"viewed_at"`
 
 	// Relationships
 	Post Post `gorm:"foreignKey:PostID" json:"post,omitempty"`
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// RevokedToken represents an access token revoked before its expiry (e.g. logout)
+// Rows can be deleted once ExpiresAt has passed
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;type:varchar(64);primaryKey" json:"jti"`
+	UserID    int       `gorm:"not null" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	RevokedAt time.Time `gorm:"default:now()" json:"revoked_at"`
+}
+
+// TableName specifies the table name for RevokedToken
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
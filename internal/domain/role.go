@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// Role bawaan, ID-nya sama dengan nilai users.role sebelum role disimpan di database
+const (
+	RoleAdminID  = 1
+	RoleAuthorID = 2
+)
+
+// Permission keys yang dicek oleh middleware RequirePermission
+const (
+	PermDashboardView      = "dashboard.view"
+	PermActivityLogsView   = "activity_logs.view"
+	PermPostsCreate        = "posts.create"
+	PermPostsUpdate        = "posts.update"
+	PermPostsDelete        = "posts.delete"
+	PermPostsPublish       = "posts.publish"
+	PermPostsManageAny     = "posts.manage_any" // Kelola post milik user lain
+	PermCategoriesManage   = "categories.manage"
+	PermTagsManage         = "tags.manage"
+	PermTestimonialsManage = "testimonials.manage"
+	PermMembersManage      = "members.manage"
+	PermAboutManage        = "about.manage"
+	PermContactManage      = "contact.manage"
+	PermSettingsManage     = "settings.manage"
+	PermDocumentsManage    = "documents.manage"
+	PermAdsManage          = "ads.manage"
+	PermUsersManage        = "users.manage"
+	PermUsersImpersonate   = "users.impersonate" // Masuk sebagai user lain untuk troubleshooting
+	PermRolesManage        = "roles.manage"
+	PermAPIKeysManage      = "api_keys.manage"
+	PermSecurityManage     = "security.manage" // Allowlist IP admin
+	PermWebhooksManage     = "webhooks.manage"
+)
+
+// AdminPermissions permission tingkat admin: role yang memiliki salah satunya diperlakukan
+// sebagai admin, termasuk role custom (mis. wajib 2FA jika pengaturan situs mewajibkannya)
+var AdminPermissions = []string{
+	PermUsersManage,
+	PermUsersImpersonate,
+	PermRolesManage,
+	PermAPIKeysManage,
+	PermSecurityManage,
+	PermSettingsManage,
+	PermWebhooksManage,
+}
+
+// Role represents a named set of permissions assigned to users
+type Role struct {
+	ID          int          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"key"`
+	Name        string       `gorm:"type:varchar(100);not null" json:"name"`
+	Description *string      `gorm:"type:text" json:"description,omitempty"`
+	IsSystem    bool         `gorm:"not null;default:false" json:"is_system"` // Role bawaan, tidak bisa dihapus
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
+	CreatedAt   time.Time    `gorm:"default:now()" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for Role
+func (Role) TableName() string {
+	return "roles"
+}
+
+// PermissionKeys returns the keys of all permissions granted to the role
+func (r *Role) PermissionKeys() []string {
+	keys := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		keys = append(keys, permission.Key)
+	}
+	return keys
+}
+
+// Permission represents a single fine-grained action, e.g. posts.publish
+type Permission struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"key"`
+	Description *string   `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for Permission
+func (Permission) TableName() string {
+	return "permissions"
+}
@@ -17,6 +17,7 @@ type SiteSetting struct {
 	InstagramURL    *string   `gorm:"type:varchar(255)" json:"instagram_url,omitempty"`
 	YoutubeURL      *string   `gorm:"type:varchar(255)" json:"youtube_url,omitempty"`
 	GithubURL       *string   `gorm:"type:varchar(255)" json:"github_url,omitempty"`
+	RequireAdmin2FA bool      `gorm:"column:require_admin_2fa;default:false" json:"require_admin_2fa"`
 	UpdatedAt       time.Time `gorm:"default:now()" json:"updated_at"`
 }
 
@@ -9,7 +9,7 @@ import (
 // User represents a user account in the system
 type User struct {
 	ID           int            `gorm:"primaryKey;autoIncrement" json:"id"`
-	Role         int            `gorm:"not null;default:2" json:"role"` // 1=Admin, 2=Author
+	Role         int            `gorm:"not null;default:2" json:"role"` // FK ke roles.id (1=Admin, 2=Author bawaan)
 	FullName     string         `gorm:"type:varchar(100);not null" json:"full_name"`
 	Email        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
 	PasswordHash string         `gorm:"type:varchar(255);not null" json:"-"`
@@ -18,6 +18,15 @@ type User struct {
 	CreatedAt    time.Time      `gorm:"default:now()" json:"created_at"`
 	UpdatedAt    time.Time      `gorm:"default:now()" json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// TokensValidAfter: token yang terbit sebelum waktu ini tidak valid
+	// Read-only agar Save() tidak menimpa watermark yang diset oleh revocation store
+	TokensValidAfter *time.Time `gorm:"->" json:"-"`
+
+	// Status lockout brute-force, hanya diubah lewat LoginAttemptRepository
+	FailedLoginCount  int        `gorm:"->" json:"-"`
+	LastFailedLoginAt *time.Time `gorm:"->" json:"-"`
+	LockedUntil       *time.Time `gorm:"->" json:"-"`
 }
 
 // TableName specifies the table name for User
@@ -27,5 +36,10 @@ func (User) TableName() string {
 
 // IsAdmin checks if user has admin role
 func (u *User) IsAdmin() bool {
-	return u.Role == 1
+	return u.Role == RoleAdminID
+}
+
+// IsLocked checks if the account is temporarily locked after too many failed logins
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
 }
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// UserInvitation represents an emailed invitation for a new user account
+// Only the SHA-256 hash of the invitation token is stored
+type UserInvitation struct {
+	ID             int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email          string     `gorm:"type:varchar(100);not null" json:"email"`
+	RoleID         int        `gorm:"not null" json:"role_id"`
+	TokenHash      string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	InvitedBy      int        `gorm:"not null" json:"invited_by"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	LastSentAt     time.Time  `gorm:"not null" json:"last_sent_at"`
+	SendCount      int        `gorm:"not null;default:1" json:"send_count"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *int       `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"default:now()" json:"updated_at"`
+
+	// Relationship
+	Role    Role `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+	Inviter User `gorm:"foreignKey:InvitedBy" json:"inviter,omitempty"`
+}
+
+// TableName specifies the table name for UserInvitation
+func (UserInvitation) TableName() string {
+	return "user_invitations"
+}
+
+// IsOpen checks if the invitation has not been accepted or revoked
+func (i *UserInvitation) IsOpen() bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil
+}
+
+// IsUsable checks if the invitation link can still be used to create an account
+func (i *UserInvitation) IsUsable(now time.Time) bool {
+	return i.IsOpen() && now.Before(i.ExpiresAt)
+}
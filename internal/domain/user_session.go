@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// UserSession represents a refresh token issued to a user on a device
+// Every rotation creates a new row in the same family; the family is the logical session
+type UserSession struct {
+	ID               int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID           int        `gorm:"not null;index" json:"user_id"`
+	FamilyID         string     `gorm:"type:varchar(64);not null;index" json:"family_id"`
+	RefreshTokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	Device           *string    `gorm:"type:varchar(100)" json:"device,omitempty"`
+	IPAddress        *string    `gorm:"type:varchar(45)" json:"ip_address,omitempty"`
+	UserAgent        *string    `gorm:"type:text" json:"user_agent,omitempty"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RotatedAt        *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for UserSession
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsActive checks if the refresh token can still be exchanged
+func (s *UserSession) IsActive() bool {
+	return s.RotatedAt == nil && s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
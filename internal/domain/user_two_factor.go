@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// UserTwoFactor represents the TOTP secret of a user
+// The row is created on enrollment and only becomes active once EnabledAt is set
+type UserTwoFactor struct {
+	UserID       int        `gorm:"primaryKey" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null" json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep *int64     `json:"-"` // Time step TOTP terakhir yang dipakai (anti replay)
+	CreatedAt    time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for UserTwoFactor
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// IsEnabled checks if enrollment has been confirmed
+func (t *UserTwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+// UserRecoveryCode represents a single-use 2FA recovery code
+// Only the SHA-256 hash of the code is stored
+type UserRecoveryCode struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
+
+// TableName specifies the table name for UserRecoveryCode
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
@@ -2,12 +2,28 @@ package domain
 
 import "time"
 
-// Visitor tracks unique daily visitors by IP address
-// Each IP is recorded only once per day
+// Visitor tracks unique daily visitors by salted IP hash
+// Each visitor is recorded only once per day, hash dikosongkan setelah masa retensi
 type Visitor struct {
-	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	IPAddress string    `gorm:"type:varchar(50);not null" json:"ip_address"`
-	Platform  *string   `gorm:"type:varchar(255)" json:"platform,omitempty"`
+	ID          int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	VisitorHash *string `gorm:"type:varchar(64)" json:"-"`
+
+	// Sumber kunjungan pertama hari itu, dikirim frontend lewat beacon
+	ReferrerHost *string `gorm:"type:varchar(255)" json:"referrer_host,omitempty"`
+	UTMSource    *string `gorm:"column:utm_source;type:varchar(100)" json:"utm_source,omitempty"`
+	UTMMedium    *string `gorm:"column:utm_medium;type:varchar(100)" json:"utm_medium,omitempty"`
+	UTMCampaign  *string `gorm:"column:utm_campaign;type:varchar(100)" json:"utm_campaign,omitempty"`
+
+	// Klasifikasi user agent (pkg/useragent), user agent lengkap tidak disimpan
+	Browser    *string `gorm:"type:varchar(50)" json:"browser,omitempty"`
+	OS         *string `gorm:"column:os;type:varchar(50)" json:"os,omitempty"`
+	DeviceType *string `gorm:"type:varchar(20)" json:"device_type,omitempty"`
+
+	// Lokasi perkiraan dari database geolokasi offline
+	Country *string `gorm:"type:varchar(2)" json:"country,omitempty"` // ISO 3166-1 alpha-2
+	Region  *string `gorm:"type:varchar(100)" json:"region,omitempty"`
+	City    *string `gorm:"type:varchar(100)" json:"city,omitempty"`
+
 	VisitedAt time.Time `gorm:"default:now()" json:"visited_at"`
 }
 
@@ -15,3 +31,15 @@ type Visitor struct {
 func (Visitor) TableName() string {
 	return "visitors"
 }
+
+// VisitorSalt salt acak untuk hash IP visitor pada satu hari
+type VisitorSalt struct {
+	Day       time.Time `gorm:"type:date;primaryKey"`
+	Salt      []byte    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for VisitorSalt
+func (VisitorSalt) TableName() string {
+	return "visitor_salts"
+}
@@ -0,0 +1,89 @@
+package domain
+
+import "time"
+
+// Event yang bisa dilanggan oleh webhook, dikirim dari service layer setelah perubahan tersimpan
+const (
+	WebhookEventPostPublished   = "post.published"
+	WebhookEventPostUpdated     = "post.updated"
+	WebhookEventPostDeleted     = "post.deleted"
+	WebhookEventDocumentCreated = "document.created"
+	WebhookEventDocumentUpdated = "document.updated"
+	WebhookEventDocumentDeleted = "document.deleted"
+	WebhookEventMemberCreated   = "member.created"
+	WebhookEventMemberUpdated   = "member.updated"
+	WebhookEventMemberDeleted   = "member.deleted"
+	WebhookEventSettingsUpdated = "settings.updated"
+	WebhookEventWebhookTest     = "webhook.test" // Dikirim manual dari admin untuk mengetes endpoint
+)
+
+// WebhookEvents daftar semua event yang valid untuk langganan
+var WebhookEvents = []string{
+	WebhookEventPostPublished, WebhookEventPostUpdated, WebhookEventPostDeleted,
+	WebhookEventDocumentCreated, WebhookEventDocumentUpdated, WebhookEventDocumentDeleted,
+	WebhookEventMemberCreated, WebhookEventMemberUpdated, WebhookEventMemberDeleted,
+	WebhookEventSettingsUpdated,
+}
+
+// Webhook adalah langganan event ke URL eksternal (bot Telegram, situs cabang, dll)
+// Secret dipakai untuk menandatangani payload dan tidak pernah dikirim di response list
+type Webhook struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	URL       string    `gorm:"type:text;not null" json:"url"`
+	Secret    string    `gorm:"type:varchar(128);not null" json:"-"`
+	Events    []string  `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	IsActive  bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedBy *int      `json:"created_by,omitempty"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for Webhook
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribes mengecek apakah webhook berlangganan event tertentu
+func (w *Webhook) Subscribes(event string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus status pengiriman satu event ke satu webhook
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending" // Menunggu dikirim atau dikirim ulang
+	WebhookDeliverySuccess WebhookDeliveryStatus = "success"
+	WebhookDeliveryFailed  WebhookDeliveryStatus = "failed" // Semua percobaan gagal, bisa di-replay manual
+)
+
+// WebhookDelivery adalah antrian sekaligus log pengiriman event ke webhook
+// EventID sama untuk semua webhook yang menerima event yang sama dan untuk replay, dipakai penerima untuk deduplikasi
+type WebhookDelivery struct {
+	ID             int64                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID      int                   `gorm:"not null" json:"webhook_id"`
+	Webhook        *Webhook              `gorm:"foreignKey:WebhookID" json:"-"`
+	EventID        string                `gorm:"type:varchar(64);not null" json:"event_id"`
+	Event          string                `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        string                `gorm:"type:jsonb;not null" json:"-"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:pending" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	ResponseStatus *int                  `json:"response_status,omitempty"`
+	LastError      *string               `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	ReplayOf       *int64                `json:"replay_of,omitempty"`
+	CreatedAt      time.Time             `gorm:"default:now()" json:"created_at"`
+	UpdatedAt      time.Time             `gorm:"default:now()" json:"updated_at"`
+}
+
+// TableName specifies the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
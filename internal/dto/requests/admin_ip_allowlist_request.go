@@ -0,0 +1,7 @@
+package requests
+
+// CreateAdminIPAllowlistRequest adalah DTO untuk menambah IP atau CIDR ke allowlist admin
+type CreateAdminIPAllowlistRequest struct {
+	CIDR        string `json:"cidr" binding:"required,max=64"` // IP tunggal (203.0.113.7) atau CIDR (203.0.113.0/24)
+	Description string `json:"description" binding:"max=255"`
+}
@@ -0,0 +1,12 @@
+package requests
+
+import "time"
+
+// CreateAPIKeyRequest adalah DTO untuk membuat API key integrasi
+// AllowedIPs berisi IP atau CIDR (mis. 203.0.113.0/24), kosong = semua IP
+type CreateAPIKeyRequest struct {
+	Name       string     `json:"name" binding:"required,min=2,max=100"`
+	Scopes     []string   `json:"scopes" binding:"required,min=1"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
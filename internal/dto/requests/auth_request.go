@@ -4,9 +4,53 @@ package requests
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	Mode     string `json:"mode" binding:"omitempty,oneof=bearer cookie"`
+}
+
+// Mode auth setelah login: bearer (default, token di response body) atau cookie (session cookie HttpOnly + CSRF)
+const (
+	AuthModeBearer = "bearer"
+	AuthModeCookie = "cookie"
+)
+
+// RefreshTokenRequest adalah DTO untuk request refresh token
+// Pada mode cookie refresh token diambil dari cookie sehingga body boleh kosong
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 type ChangePasswordRequest struct {
-	OldPassword string `json:"old_password" binding:"required"`
+	OldPassword         string `json:"old_password" binding:"required"`
+	NewPassword         string `json:"new_password" binding:"required,min=8,containsany=!@#$%^&*"`
+	RevokeOtherSessions bool   `json:"revoke_other_sessions"` // true: perangkat ini tetap login dengan session baru, false: semua perangkat login ulang
+}
+
+// ForgotPasswordRequest adalah DTO untuk request lupa password
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest adalah DTO untuk reset password dengan token dari email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=8,containsany=!@#$%^&*"`
 }
+
+// TwoFactorChallengeRequest adalah DTO untuk memulai setup 2FA saat login (admin wajib 2FA)
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+// VerifyTwoFactorRequest adalah DTO untuk langkah kedua login dengan kode TOTP atau recovery code
+type VerifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	Mode           string `json:"mode" binding:"omitempty,oneof=bearer cookie"`
+}
+
+// OIDCCallbackRequest adalah DTO untuk menyelesaikan login SSO dengan code dan state dari provider
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+	Mode  string `json:"mode" binding:"omitempty,oneof=bearer cookie"`
+}
@@ -0,0 +1,20 @@
+package requests
+
+// CreateInvitationRequest adalah DTO untuk mengundang user baru (Admin only)
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+	Role  int    `json:"role" binding:"required,min=1"` // ID role di tabel roles
+}
+
+// VerifyInvitationRequest adalah DTO untuk mengecek link undangan sebelum form pendaftaran ditampilkan
+type VerifyInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// AcceptInvitationRequest adalah DTO untuk menerima undangan dengan password dan profil sendiri
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" form:"token" binding:"required"`
+	FullName string `json:"full_name" form:"full_name" binding:"required,min=2,max=100"`
+	Password string `json:"password" form:"password" binding:"required,min=8"`
+	// Photo opsional dihandle terpisah menggunakan c.FormFile("photo")
+}
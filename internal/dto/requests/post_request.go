@@ -21,6 +21,11 @@ type PostUpdateRequest struct {
 	Image      *multipart.FileHeader `form:"image"`
 }
 
+// TransferPostOwnershipRequest adalah DTO untuk memindahkan kepemilikan post (Admin only)
+type TransferPostOwnershipRequest struct {
+	UserID int `json:"user_id" binding:"required,min=1"`
+}
+
 func (r *PostCreateRequest) GetSlug() string {
 	return strings.ToLower(strings.ReplaceAll(r.Title, " ", "-"))
 }
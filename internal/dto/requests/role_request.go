@@ -0,0 +1,18 @@
+package requests
+
+// CreateRoleRequest adalah DTO untuk membuat role baru
+// Key dipakai sebagai identitas role yang stabil (mis. "editor"), Permissions berisi key permission
+type CreateRoleRequest struct {
+	Key         string   `json:"key" binding:"required,min=2,max=50"`
+	Name        string   `json:"name" binding:"required,min=2,max=100"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest adalah DTO untuk mengubah role
+// Semua field opsional, Permissions (jika dikirim) mengganti seluruh permission role
+type UpdateRoleRequest struct {
+	Name        *string   `json:"name" binding:"omitempty,min=2,max=100"`
+	Description *string   `json:"description"`
+	Permissions *[]string `json:"permissions"`
+}
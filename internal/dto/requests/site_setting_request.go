@@ -11,5 +11,6 @@ type UpdateSiteSettingRequest struct {
 	InstagramURL    *string `form:"instagram_url"`
 	YoutubeURL      *string `form:"youtube_url"`
 	GithubURL       *string `form:"github_url"`
+	RequireAdmin2FA *bool   `form:"require_admin_2fa"` // Wajibkan 2FA untuk semua admin
 	// Images handled separately: favicon, logo_header, logo_big
 }
@@ -0,0 +1,13 @@
+package requests
+
+// TwoFactorCodeRequest adalah DTO untuk konfirmasi enrollment dan generate ulang recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest adalah DTO untuk menonaktifkan 2FA
+// Code boleh berupa kode TOTP atau recovery code
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
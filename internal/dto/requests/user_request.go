@@ -13,7 +13,7 @@ type CreateUserRequest struct {
 type UpdateUserRequest struct {
 	FullName *string `json:"full_name" form:"full_name" binding:"omitempty,min=2,max=100"`
 	Email    *string `json:"email" form:"email" binding:"omitempty,email"`
-	Role     *int    `json:"role" form:"role" binding:"omitempty,oneof=1 2"`
+	Role     *int    `json:"role" form:"role" binding:"omitempty,min=1"` // ID role di tabel roles
 	IsActive *bool   `json:"is_active" form:"is_active"`
 	Password *string `json:"password,omitempty" form:"password" binding:"omitempty,min=8"`
 	// PhotoURI akan dihandle terpisah menggunakan c.FormFile("photo")
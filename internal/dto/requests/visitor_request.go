@@ -0,0 +1,10 @@
+package requests
+
+// VisitorBeaconRequest adalah DTO beacon kunjungan dari frontend
+// Referrer diisi document.referrer, parameter UTM diambil dari URL halaman pertama
+type VisitorBeaconRequest struct {
+	Referrer    string `json:"referrer" binding:"omitempty,max=2048"`
+	UTMSource   string `json:"utm_source" binding:"omitempty,max=255"`
+	UTMMedium   string `json:"utm_medium" binding:"omitempty,max=255"`
+	UTMCampaign string `json:"utm_campaign" binding:"omitempty,max=255"`
+}
@@ -0,0 +1,22 @@
+package requests
+
+// CreateWebhookRequest adalah DTO untuk mendaftarkan webhook baru
+// Secret kosong = dibuat otomatis oleh server
+type CreateWebhookRequest struct {
+	Name     string   `json:"name" binding:"required,min=2,max=100"`
+	URL      string   `json:"url" binding:"required,max=2000"`
+	Secret   string   `json:"secret" binding:"omitempty,min=16,max=128"`
+	Events   []string `json:"events" binding:"required,min=1"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// UpdateWebhookRequest adalah DTO untuk mengubah webhook, field kosong tidak diubah
+// RotateSecret = true membuat secret baru yang dikembalikan sekali di response
+type UpdateWebhookRequest struct {
+	Name         *string  `json:"name" binding:"omitempty,min=2,max=100"`
+	URL          *string  `json:"url" binding:"omitempty,max=2000"`
+	Secret       *string  `json:"secret" binding:"omitempty,min=16,max=128"`
+	RotateSecret bool     `json:"rotate_secret"`
+	Events       []string `json:"events" binding:"omitempty,min=1"`
+	IsActive     *bool    `json:"is_active"`
+}
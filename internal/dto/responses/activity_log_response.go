@@ -13,17 +13,46 @@ type ActivityLogUserInfo struct {
 	Email    string `json:"email"`
 }
 
+// ActivityLogAPIKeyInfo menampilkan API key yang dipakai jika aktivitas dilakukan lewat integrasi
+type ActivityLogAPIKeyInfo struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+}
+
 type ActivityLogResponse struct {
-	ID          int                       `json:"id"`
-	UserID      int                       `json:"user_id"`
-	User        *ActivityLogUserInfo      `json:"user,omitempty"`
-	ActionType  domain.ActivityActionType `json:"action_type"`
-	Module      domain.ActivityModuleType `json:"module"`
-	Description *string                   `json:"description,omitempty"`
-	TargetID    *int                      `json:"target_id,omitempty"`
-	OldValue    map[string]any            `json:"old_value,omitempty"`
-	NewValue    map[string]any            `json:"new_value,omitempty"`
-	IPAddress   *string                   `json:"ip_address,omitempty"`
-	UserAgent   *string                   `json:"user_agent,omitempty"`
-	CreatedAt   time.Time                 `json:"created_at"`
+	ID           int                       `json:"id"`
+	UserID       int                       `json:"user_id"`
+	User         *ActivityLogUserInfo      `json:"user,omitempty"`
+	APIKey       *ActivityLogAPIKeyInfo    `json:"api_key,omitempty"`
+	Impersonator *ActivityLogUserInfo      `json:"impersonator,omitempty"` // Admin yang sebenarnya melakukan aksi saat impersonasi
+	ActionType   domain.ActivityActionType `json:"action_type"`
+	Module       domain.ActivityModuleType `json:"module"`
+	Description  *string                   `json:"description,omitempty"`
+	TargetID     *int                      `json:"target_id,omitempty"`
+	OldValue     map[string]any            `json:"old_value,omitempty"`
+	NewValue     map[string]any            `json:"new_value,omitempty"`
+	IPAddress    *string                   `json:"ip_address,omitempty"`
+	UserAgent    *string                   `json:"user_agent,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// EntityHistoryChange adalah perubahan satu field pada entity
+// Before kosong untuk create, After kosong untuk delete
+type EntityHistoryChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// EntityHistoryResponse adalah satu titik di timeline perubahan sebuah entity (post, member, dokumen, dll)
+type EntityHistoryResponse struct {
+	ID           int                       `json:"id"` // ID activity log
+	ActionType   domain.ActivityActionType `json:"action_type"`
+	Description  *string                   `json:"description,omitempty"`
+	Actor        *ActivityLogUserInfo      `json:"actor,omitempty"` // nil jika dilakukan oleh job sistem
+	Impersonator *ActivityLogUserInfo      `json:"impersonator,omitempty"`
+	APIKey       *ActivityLogAPIKeyInfo    `json:"api_key,omitempty"`
+	Changes      []EntityHistoryChange     `json:"changes"`
+	CreatedAt    time.Time                 `json:"created_at"`
 }
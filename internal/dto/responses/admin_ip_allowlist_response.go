@@ -0,0 +1,20 @@
+package responses
+
+import "time"
+
+// AdminIPAllowlistEntryResponse adalah DTO untuk satu entry allowlist IP admin
+type AdminIPAllowlistEntryResponse struct {
+	ID          int       `json:"id"`
+	CIDR        string    `json:"cidr"`
+	Description *string   `json:"description,omitempty"`
+	CreatedBy   *int      `json:"createdBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// AdminIPAllowlistResponse adalah DTO daftar allowlist beserta status penerapannya
+type AdminIPAllowlistResponse struct {
+	Entries   []AdminIPAllowlistEntryResponse `json:"entries"`
+	Enforced  bool                            `json:"enforced"`  // false jika allowlist kosong atau bypass darurat aktif
+	Bypassed  bool                            `json:"bypassed"`  // ADMIN_IP_ALLOWLIST_BYPASS aktif
+	CurrentIP string                          `json:"currentIp"` // IP request saat ini, untuk memastikan tidak terkunci
+}
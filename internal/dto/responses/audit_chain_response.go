@@ -0,0 +1,46 @@
+package responses
+
+import "time"
+
+// AuditChainBreakResponse menunjukkan titik pertama hash chain activity log yang rusak
+type AuditChainBreakResponse struct {
+	LogID        *int   `json:"logId,omitempty"`
+	CheckpointID *int   `json:"checkpointId,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+// AuditChainVerificationResponse adalah hasil penelusuran hash chain activity log
+type AuditChainVerificationResponse struct {
+	Valid                 bool                     `json:"valid"`
+	CheckedEntries        int64                    `json:"checkedEntries"`
+	UnchainedEntries      int64                    `json:"unchainedEntries"`      // Entry lama sebelum hash chain aktif
+	ArchivedEntries       int64                    `json:"archivedEntries"`       // Entry ber-hash yang sudah diarsipkan, penelusuran dimulai setelahnya
+	CheckedCheckpoints    int                      `json:"checkedCheckpoints"`    // Checkpoint yang cocok dengan entry di chain
+	UnverifiedCheckpoints int                      `json:"unverifiedCheckpoints"` // Signature tidak dicek karena ditandatangani key lain atau key belum dikonfigurasi
+	HeadLogID             *int                     `json:"headLogId,omitempty"`
+	HeadHash              string                   `json:"headHash,omitempty"`
+	BrokenLink            *AuditChainBreakResponse `json:"brokenLink,omitempty"`
+	VerifiedAt            time.Time                `json:"verifiedAt"`
+}
+
+// AuditCheckpointResponse adalah DTO satu checkpoint bertanda tangan
+type AuditCheckpointResponse struct {
+	ID         int       `json:"id"`
+	LastLogID  int       `json:"lastLogId"`
+	EntryHash  string    `json:"entryHash"`
+	EntryCount int64     `json:"entryCount"`
+	KeyID      string    `json:"keyId"`
+	Payload    string    `json:"payload"`   // Pesan yang ditandatangani
+	Signature  string    `json:"signature"` // Ed25519, base64
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// AuditCheckpointExportResponse adalah berkas ekspor checkpoint untuk disimpan di luar database
+// Signature tiap checkpoint bisa diverifikasi dengan PublicKey tanpa akses ke aplikasi
+type AuditCheckpointExportResponse struct {
+	Algorithm   string                    `json:"algorithm"`
+	KeyID       string                    `json:"keyId,omitempty"`
+	PublicKey   string                    `json:"publicKey,omitempty"` // PEM, kosong jika key checkpoint belum dikonfigurasi
+	ExportedAt  time.Time                 `json:"exportedAt"`
+	Checkpoints []AuditCheckpointResponse `json:"checkpoints"`
+}
@@ -1,9 +1,21 @@
 package responses
 
 // LoginResponse adalah DTO untuk response login
+// Pada mode cookie token tidak dikirim di body, diganti csrfToken untuk header X-CSRF-Token
 type LoginResponse struct {
-	Token string  `json:"token"`
-	User  UserDTO `json:"user"`
+	Token        string  `json:"token,omitempty"`
+	RefreshToken string  `json:"refreshToken,omitempty"`
+	CSRFToken    string  `json:"csrfToken,omitempty"`
+	ExpiresIn    int     `json:"expiresIn"`
+	User         UserDTO `json:"user"`
+}
+
+// RefreshTokenResponse adalah DTO untuk response refresh token
+type RefreshTokenResponse struct {
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	CSRFToken    string `json:"csrfToken,omitempty"`
+	ExpiresIn    int    `json:"expiresIn"`
 }
 
 // UserDTO adalah DTO untuk data user (tanpa data sensitif)
@@ -13,3 +25,39 @@ type UserDTO struct {
 	Email    string `json:"email"`
 	Role     string `json:"role"`
 }
+
+// TwoFactorChallengeResponse adalah DTO untuk response login yang masih butuh verifikasi 2FA
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	SetupRequired     bool   `json:"setupRequired"` // Admin wajib 2FA tapi belum enrollment
+	ChallengeToken    string `json:"challengeToken"`
+	ExpiresIn         int    `json:"expiresIn"`
+}
+
+// TwoFactorSetupLoginResponse adalah DTO untuk response login setelah setup 2FA wajib
+type TwoFactorSetupLoginResponse struct {
+	LoginResponse
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// OIDCProvidersResponse adalah DTO untuk daftar provider SSO yang aktif
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// OIDCAuthorizationResponse adalah DTO untuk memulai login SSO
+// Frontend menyimpan state lalu redirect ke authorizationUrl
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+	ExpiresIn        int    `json:"expiresIn"`
+}
+
+// ImpersonationResponse adalah DTO untuk token impersonasi admin
+// Tidak ada refresh token, impersonasi berakhir saat token kadaluarsa atau logout
+type ImpersonationResponse struct {
+	Token          string  `json:"token"`
+	ExpiresIn      int     `json:"expiresIn"`
+	User           UserDTO `json:"user"`
+	ImpersonatorID int     `json:"impersonatorId"`
+}
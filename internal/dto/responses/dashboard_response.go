@@ -36,6 +36,37 @@ type TopArticle struct {
 	Views int64  `json:"views"`
 }
 
+// TrafficBreakdown jumlah visitor per domain perujuk atau kelompok perangkat
+type TrafficBreakdown struct {
+	Name       string  `json:"name"`
+	Visitors   int64   `json:"visitors"`
+	Percentage float64 `json:"percentage"` // Persentase dari seluruh kunjungan (perujuk: seluruh kunjungan dengan perujuk)
+}
+
+// TopCampaign kampanye UTM dengan visitor terbanyak
+type TopCampaign struct {
+	Campaign   string  `json:"campaign"`
+	Source     string  `json:"source"`
+	Medium     string  `json:"medium"`
+	Visitors   int64   `json:"visitors"`
+	Percentage float64 `json:"percentage"` // Persentase dari seluruh kunjungan kampanye periode ini
+}
+
+// DeviceMix distribusi visitor per jenis perangkat, browser dan sistem operasi
+type DeviceMix struct {
+	Devices          []TrafficBreakdown `json:"devices"` // desktop, mobile, tablet, bot, unknown
+	Browsers         []TrafficBreakdown `json:"browsers"`
+	OperatingSystems []TrafficBreakdown `json:"operating_systems"`
+}
+
+// RegionBreakdown jumlah visitor per negara dan provinsi
+type RegionBreakdown struct {
+	Country    string  `json:"country"` // Kode ISO 3166-1 alpha-2, "unknown" jika lokasi tidak diketahui
+	Region     string  `json:"region"`
+	Visitors   int64   `json:"visitors"`
+	Percentage float64 `json:"percentage"` // Persentase dari seluruh visitor periode ini
+}
+
 // ActivityLogItem item activity log untuk dashboard admin
 type ActivityLogItem struct {
 	Name  string `json:"name"`  // Nama user
@@ -51,6 +82,10 @@ type DashboardResponse struct {
 	VisitorsTrend        []VisitorsTrend        `json:"visitors_trend"`
 	CategoryDistribution []CategoryDistribution `json:"category_distribution"`
 	TopArticles          []TopArticle           `json:"top_articles"`
+	TopReferrers         []TrafficBreakdown     `json:"top_referrers"` // Persentase dari visitor yang punya perujuk
+	TopCampaigns         []TopCampaign          `json:"top_campaigns"`
+	DeviceMix            DeviceMix              `json:"device_mix"`
+	VisitorsByRegion     []RegionBreakdown      `json:"visitors_by_region"`
 	ActivityLogs         []ActivityLogItem      `json:"activity_logs,omitempty"` // Hanya untuk Admin
 }
 
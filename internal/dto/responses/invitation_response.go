@@ -0,0 +1,24 @@
+package responses
+
+import "time"
+
+// InvitationResponse adalah DTO untuk undangan user yang masih terbuka
+type InvitationResponse struct {
+	ID         int       `json:"id"`
+	Email      string    `json:"email"`
+	RoleID     int       `json:"roleId"`
+	Role       string    `json:"role"`
+	InvitedBy  string    `json:"invitedBy"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Expired    bool      `json:"expired"`
+	LastSentAt time.Time `json:"lastSentAt"`
+	SendCount  int       `json:"sendCount"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// InvitationPreviewResponse adalah DTO untuk form pendaftaran dari link undangan
+type InvitationPreviewResponse struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
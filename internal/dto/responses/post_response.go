@@ -33,6 +33,7 @@ type PostResponse struct {
 	Excerpt     string                `json:"excerpt"`
 	Content     string                `json:"content,omitempty"`
 	ImageUrl    string                `json:"imageUrl"`
+	Status      string                `json:"status"`
 	PublishedAt time.Time             `json:"publishedAt"`
 	Views       int                   `json:"views"`
 	CategoryId  CategoryShortResponse `json:"category"`
@@ -93,6 +94,7 @@ func FromDomainToPostResponse(post domain.Post) PostResponse {
 		Excerpt:     excerpt,
 		Content:     post.Content,
 		ImageUrl:    imageUrl,
+		Status:      string(post.Status),
 		PublishedAt: publishedAt,
 		Views:       post.ViewsCount,
 		CategoryId:  categoryData,
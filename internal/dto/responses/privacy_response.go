@@ -0,0 +1,20 @@
+package responses
+
+// DataProcessingItem adalah satu jenis data yang diproses beserta tujuan dan masa simpannya
+type DataProcessingItem struct {
+	Data      string `json:"data"`
+	Purpose   string `json:"purpose"`
+	Storage   string `json:"storage"`
+	Retention string `json:"retention"`
+}
+
+// DataProcessingNoticeResponse adalah DTO keterangan pemrosesan data visitor untuk kebijakan privasi
+type DataProcessingNoticeResponse struct {
+	Summary        string               `json:"summary"`
+	Items          []DataProcessingItem `json:"items"`
+	IPHashing      string               `json:"ipHashing"`
+	SaltRotation   string               `json:"saltRotation"`
+	RetentionDays  int                  `json:"retentionDays"`
+	ThirdParties   []string             `json:"thirdParties"`
+	TrackingCookie bool                 `json:"trackingCookie"`
+}
@@ -0,0 +1,17 @@
+package responses
+
+// RoleResponse adalah DTO untuk role beserta key permission-nya
+type RoleResponse struct {
+	ID          int      `json:"id"`
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	IsSystem    bool     `json:"isSystem"`
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionResponse adalah DTO untuk permission yang tersedia
+type PermissionResponse struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+}
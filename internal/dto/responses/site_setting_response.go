@@ -14,4 +14,5 @@ type SiteSettingResponse struct {
 	InstagramURL    *string `json:"instagramUrl,omitempty"`
 	YoutubeURL      *string `json:"youtubeUrl,omitempty"`
 	GithubURL       *string `json:"githubUrl,omitempty"`
+	RequireAdmin2FA bool    `json:"requireAdmin2fa"`
 }
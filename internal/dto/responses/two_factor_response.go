@@ -0,0 +1,23 @@
+package responses
+
+import "time"
+
+// TwoFactorStatusResponse adalah DTO untuk status 2FA user
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabledAt,omitempty"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int64      `json:"recoveryCodesRemaining"`
+}
+
+// TwoFactorEnrollmentResponse adalah DTO untuk hasil enrollment 2FA
+// OTPAuthURI dirender FE sebagai QR code, Secret untuk input manual
+type TwoFactorEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+// RecoveryCodesResponse adalah DTO untuk recovery code yang hanya ditampilkan sekali
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,41 @@
+package responses
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookResponse adalah DTO untuk webhook (tanpa secret)
+type WebhookResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"isActive"`
+	CreatedBy *int      `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WebhookSecretResponse adalah DTO webhook baru atau setelah rotasi secret, Secret hanya ditampilkan sekali
+type WebhookSecretResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryResponse adalah DTO satu entry log pengiriman webhook
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhookId"`
+	EventID        string          `json:"eventId"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"responseStatus,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	ReplayOf       *int64          `json:"replayOf,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AccountLockoutHandler handles HTTP requests untuk pengelolaan akun yang terkunci
+type AccountLockoutHandler struct {
+	lockoutService service.AccountLockoutService
+}
+
+// NewAccountLockoutHandler constructor untuk AccountLockoutHandler
+func NewAccountLockoutHandler(lockoutService service.AccountLockoutService) *AccountLockoutHandler {
+	return &AccountLockoutHandler{lockoutService: lockoutService}
+}
+
+// Unlock handles POST /v1/admin/users/:id/unlock (Admin Only)
+// Membuka kunci akun yang terkunci karena terlalu banyak percobaan login gagal
+func (h *AccountLockoutHandler) Unlock(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.lockoutService.Unlock(GetContextWithRequestInfo(c), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Kunci akun berhasil dibuka", nil))
+}
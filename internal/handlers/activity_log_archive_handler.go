@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityLogArchiveHandler handles HTTP requests untuk arsip (retensi) activity log
+type ActivityLogArchiveHandler struct {
+	archiveService service.ActivityLogArchiveService
+}
+
+// NewActivityLogArchiveHandler constructor untuk ActivityLogArchiveHandler
+func NewActivityLogArchiveHandler(archiveService service.ActivityLogArchiveService) *ActivityLogArchiveHandler {
+	return &ActivityLogArchiveHandler{archiveService: archiveService}
+}
+
+// GetArchives handles GET /v1/admin/activity-logs/archives
+// Query Params:
+//   - page: int (default: 1)
+//   - limit: int (default: 20)
+func (h *ActivityLogArchiveHandler) GetArchives(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	archives, lastPage, total, err := h.archiveService.GetArchives(page, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200, "Arsip activity log berhasil dimuat", archives, page, limit, total, lastPage,
+	))
+}
+
+// Run handles POST /v1/admin/activity-logs/archives
+// Menjalankan arsip sekarang tanpa menunggu jadwal berkala
+func (h *ActivityLogArchiveHandler) Run(c *gin.Context) {
+	archive, err := h.archiveService.Run(GetContextWithRequestInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if archive == nil {
+		c.JSON(http.StatusOK, responses.SuccessResponse(200, "Tidak ada activity log yang melewati masa retensi", nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Activity log berhasil diarsipkan", archive))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *ActivityLogArchiveHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrActivityLogRetentionDisabled):
+		c.JSON(http.StatusServiceUnavailable, responses.ErrorResponse(503, err.Error()))
+	case errors.Is(err, service.ErrActivityLogArchiveRunning),
+		errors.Is(err, service.ErrActivityLogArchiveConflict),
+		errors.Is(err, service.ErrAuditChainBroken):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
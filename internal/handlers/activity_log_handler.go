@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/service"
 	"github.com/gin-gonic/gin"
@@ -25,6 +30,7 @@ func NewActivityLogHandler(svc service.ActivityLogService) *ActivityLogHandler {
 //   - user_id: int (optional) - filter by user
 //   - module: string (optional) - filter by module (user, post, category, etc.)
 //   - action_type: string (optional) - filter by action type (create, update, delete, etc.)
+//   - target_id: int (optional) - filter by ID entity yang diubah
 //   - start_date: string (optional) - filter from date (format: 2006-01-02)
 //   - end_date: string (optional) - filter to date (format: 2006-01-02)
 //   - search: string (optional) - search in description
@@ -40,7 +46,125 @@ func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
 		limit = 30
 	}
 
-	// Build filter params
+	filter := parseActivityLogFilter(c)
+
+	// Call service
+	logs, lastPage, total, err := h.svc.GetActivityLogs(page, limit, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mengambil data activity logs"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200, "Activity logs berhasil dimuat", logs, page, limit, total, lastPage,
+	))
+}
+
+// ExportActivityLogs handles GET /v1/admin/activity-logs/export
+// Query Params:
+//   - format: string (csv atau ndjson, default: csv)
+//   - filter sama dengan GetActivityLogs (user_id, module, action_type, target_id, start_date, end_date, search)
+//
+// Response di-stream per batch sehingga export besar tidak dimuat sekaligus ke memori
+func (h *ActivityLogHandler) ExportActivityLogs(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "Format export harus csv atau ndjson"))
+		return
+	}
+	filter := parseActivityLogFilter(c)
+
+	filename := fmt.Sprintf("activity-logs-%s.%s", time.Now().Format("20060102-150405"), format)
+	var write func(logs []responses.ActivityLogResponse) error
+	if format == "csv" {
+		writer := csv.NewWriter(c.Writer)
+		write = func(logs []responses.ActivityLogResponse) error {
+			if !c.Writer.Written() {
+				c.Header("Content-Type", "text/csv; charset=utf-8")
+				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+				if err := writer.Write(activityLogCSVHeader); err != nil {
+					return err
+				}
+			}
+			for _, log := range logs {
+				if err := writer.Write(activityLogCSVRow(log)); err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			c.Writer.Flush()
+			return writer.Error()
+		}
+	} else {
+		encoder := json.NewEncoder(c.Writer)
+		write = func(logs []responses.ActivityLogResponse) error {
+			if !c.Writer.Written() {
+				c.Header("Content-Type", "application/x-ndjson")
+				c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			}
+			for _, log := range logs {
+				if err := encoder.Encode(log); err != nil {
+					return err
+				}
+			}
+			c.Writer.Flush()
+			return nil
+		}
+	}
+
+	err := h.svc.ExportActivityLogs(filter, write)
+	if err != nil {
+		// Setelah data mulai dikirim status tidak bisa diubah lagi, client akan menerima file yang terpotong
+		if !c.Writer.Written() {
+			c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mengekspor activity logs"))
+		}
+		return
+	}
+
+	// Tidak ada data yang cocok, tetap kirim file kosong (CSV hanya header)
+	if !c.Writer.Written() {
+		_ = write(nil)
+	}
+}
+
+// GetEntityHistory membuat handler GET /v1/admin/<entity>/:id/history untuk module tertentu
+// Query Params:
+//   - page: int (default: 1)
+//   - limit: int (default: 30)
+//
+// Entity yang sudah dihapus tetap bisa dilihat riwayatnya
+func (h *ActivityLogHandler) GetEntityHistory(module domain.ActivityModuleType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, err := strconv.Atoi(c.Param("id"))
+		if err != nil || targetID < 1 {
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+		if page < 1 {
+			page = 1
+		}
+		if limit < 1 || limit > 100 {
+			limit = 30
+		}
+
+		history, lastPage, total, err := h.svc.GetEntityHistory(module, targetID, page, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mengambil riwayat perubahan"))
+			return
+		}
+
+		c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+			200, "Riwayat perubahan berhasil dimuat", history, page, limit, total, lastPage,
+		))
+	}
+}
+
+// parseActivityLogFilter membaca query filter yang dipakai list dan export activity log
+// Nilai yang tidak valid diabaikan
+func parseActivityLogFilter(c *gin.Context) service.ActivityLogFilterParams {
 	filter := service.ActivityLogFilterParams{
 		Search: c.Query("search"),
 	}
@@ -62,6 +186,13 @@ func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
 		filter.ActionType = &actionType
 	}
 
+	// Parse target_id filter
+	if targetIDStr := c.Query("target_id"); targetIDStr != "" {
+		if targetID, err := strconv.Atoi(targetIDStr); err == nil {
+			filter.TargetID = &targetID
+		}
+	}
+
 	// Parse date range filters
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
 		if startDate, err := time.Parse("2006-01-02", startDateStr); err == nil {
@@ -77,14 +208,74 @@ func (h *ActivityLogHandler) GetActivityLogs(c *gin.Context) {
 		}
 	}
 
-	// Call service
-	logs, lastPage, total, err := h.svc.GetActivityLogs(page, limit, filter)
+	return filter
+}
+
+var activityLogCSVHeader = []string{
+	"id", "created_at", "user_id", "user_name", "user_email", "impersonator_id", "api_key_id",
+	"action_type", "module", "description", "target_id", "old_value", "new_value", "ip_address", "user_agent",
+}
+
+// activityLogCSVRow mengubah satu activity log menjadi baris CSV
+func activityLogCSVRow(log responses.ActivityLogResponse) []string {
+	var userName, userEmail, impersonatorID, apiKeyID, targetID string
+	if log.User != nil {
+		userName = log.User.FullName
+		userEmail = log.User.Email
+	}
+	if log.Impersonator != nil {
+		impersonatorID = strconv.Itoa(log.Impersonator.ID)
+	}
+	if log.APIKey != nil {
+		apiKeyID = strconv.Itoa(log.APIKey.ID)
+	}
+	if log.TargetID != nil {
+		targetID = strconv.Itoa(*log.TargetID)
+	}
+
+	return []string{
+		strconv.Itoa(log.ID),
+		log.CreatedAt.Format(time.RFC3339),
+		strconv.Itoa(log.UserID),
+		csvSafe(userName),
+		csvSafe(userEmail),
+		impersonatorID,
+		apiKeyID,
+		string(log.ActionType),
+		string(log.Module),
+		csvSafe(derefString(log.Description)),
+		targetID,
+		csvJSON(log.OldValue),
+		csvJSON(log.NewValue),
+		csvSafe(derefString(log.IPAddress)),
+		csvSafe(derefString(log.UserAgent)),
+	}
+}
+
+// csvJSON menulis old/new value sebagai JSON di satu kolom
+func csvJSON(value map[string]any) string {
+	if len(value) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(value)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mengambil data activity logs"))
-		return
+		return ""
 	}
+	return csvSafe(string(data))
+}
 
-	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
-		200, "Activity logs berhasil dimuat", logs, page, limit, total, lastPage,
-	))
+// csvSafe mencegah formula injection saat file dibuka di spreadsheet
+// Isi activity log bisa berasal dari input user (judul post, nama, user agent)
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
 }
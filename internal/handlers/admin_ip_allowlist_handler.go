@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminIPAllowlistHandler handles HTTP requests untuk allowlist IP admin API
+type AdminIPAllowlistHandler struct {
+	allowlistService service.AdminIPAllowlistService
+}
+
+// NewAdminIPAllowlistHandler constructor untuk AdminIPAllowlistHandler
+func NewAdminIPAllowlistHandler(allowlistService service.AdminIPAllowlistService) *AdminIPAllowlistHandler {
+	return &AdminIPAllowlistHandler{allowlistService: allowlistService}
+}
+
+// GetAll handles GET /v1/admin/ip-allowlist
+func (h *AdminIPAllowlistHandler) GetAll(c *gin.Context) {
+	entries, err := h.allowlistService.GetAll(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.AdminIPAllowlistEntryResponse, 0, len(entries))
+	for i := range entries {
+		result = append(result, toAdminIPAllowlistEntryResponse(&entries[i]))
+	}
+
+	bypassed := h.allowlistService.Bypassed()
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Allowlist IP admin berhasil diambil", responses.AdminIPAllowlistResponse{
+		Entries:   result,
+		Enforced:  len(result) > 0 && !bypassed,
+		Bypassed:  bypassed,
+		CurrentIP: c.ClientIP(),
+	}))
+}
+
+// Create handles POST /v1/admin/ip-allowlist
+func (h *AdminIPAllowlistHandler) Create(c *gin.Context) {
+	var req requests.CreateAdminIPAllowlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	entry, err := h.allowlistService.Create(GetContextWithRequestInfo(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "IP berhasil ditambahkan ke allowlist admin", toAdminIPAllowlistEntryResponse(entry)))
+}
+
+// Delete handles DELETE /v1/admin/ip-allowlist/:id
+func (h *AdminIPAllowlistHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.allowlistService.Delete(GetContextWithRequestInfo(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "IP berhasil dihapus dari allowlist admin", nil))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *AdminIPAllowlistHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAdminIPAllowlistEntryNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrInvalidAdminIPAllowlistCIDR):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrAdminIPAllowlistEntryExists), errors.Is(err, service.ErrAdminIPAllowlistLockout):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// toAdminIPAllowlistEntryResponse mengubah domain.AdminIPAllowlistEntry ke DTO
+func toAdminIPAllowlistEntryResponse(entry *domain.AdminIPAllowlistEntry) responses.AdminIPAllowlistEntryResponse {
+	return responses.AdminIPAllowlistEntryResponse{
+		ID:          entry.ID,
+		CIDR:        entry.CIDR,
+		Description: entry.Description,
+		CreatedBy:   entry.CreatedBy,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles HTTP requests untuk manajemen API key integrasi
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler constructor untuk APIKeyHandler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// GetAll handles GET /v1/admin/api-keys
+func (h *APIKeyHandler) GetAll(c *gin.Context) {
+	keys, err := h.apiKeyService.GetAll(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		result = append(result, toAPIKeyResponse(&keys[i]))
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar API key berhasil diambil", result))
+}
+
+// Create handles POST /v1/admin/api-keys
+// Key plaintext hanya dikembalikan di response ini, simpan segera
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req requests.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.Create(GetContextWithRequestInfo(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "API key berhasil dibuat, simpan key ini karena tidak akan ditampilkan lagi", responses.APIKeyCreatedResponse{
+		APIKeyResponse: toAPIKeyResponse(key),
+		Key:            rawKey,
+	}))
+}
+
+// Revoke handles DELETE /v1/admin/api-keys/:id
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.apiKeyService.Revoke(GetContextWithRequestInfo(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "API key berhasil dicabut", nil))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *APIKeyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrUnknownAPIKeyScope), errors.Is(err, service.ErrInvalidAllowedIP), errors.Is(err, service.ErrAPIKeyExpiryInPast):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrAPIKeyAlreadyRevoked):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// toAPIKeyResponse mengubah domain.APIKey ke DTO
+func toAPIKeyResponse(key *domain.APIKey) responses.APIKeyResponse {
+	return responses.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		RevokedAt:  key.RevokedAt,
+		Active:     key.IsActive(time.Now()),
+		CreatedAt:  key.CreatedAt,
+	}
+}
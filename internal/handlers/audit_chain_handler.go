@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditChainHandler handles HTTP requests untuk verifikasi hash chain activity log dan checkpoint
+type AuditChainHandler struct {
+	auditChainService service.AuditChainService
+}
+
+// NewAuditChainHandler constructor untuk AuditChainHandler
+func NewAuditChainHandler(auditChainService service.AuditChainService) *AuditChainHandler {
+	return &AuditChainHandler{auditChainService: auditChainService}
+}
+
+// Verify handles GET /v1/admin/activity-logs/verify
+// Chain rusak tetap 200 dengan valid=false dan brokenLink berisi entry pertama yang rusak
+func (h *AuditChainHandler) Verify(c *gin.Context) {
+	result, err := h.auditChainService.Verify()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	message := "Hash chain activity log valid"
+	if !result.Valid {
+		message = "Hash chain activity log rusak"
+	}
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, message, result))
+}
+
+// CreateCheckpoint handles POST /v1/admin/activity-logs/checkpoints
+// Membuat checkpoint sekarang tanpa menunggu jadwal berkala
+func (h *AuditChainHandler) CreateCheckpoint(c *gin.Context) {
+	checkpoint, err := h.auditChainService.CreateCheckpoint()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if checkpoint == nil {
+		c.JSON(http.StatusOK, responses.SuccessResponse(200, "Tidak ada activity log baru sejak checkpoint terakhir", nil))
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Checkpoint activity log berhasil dibuat", checkpoint))
+}
+
+// ExportCheckpoints handles GET /v1/admin/activity-logs/checkpoints/export
+// Dikirim sebagai file JSON untuk disimpan di luar database
+func (h *AuditChainHandler) ExportCheckpoints(c *gin.Context) {
+	export, err := h.auditChainService.ExportCheckpoints()
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("activity-log-checkpoints-%s.json", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.JSON(http.StatusOK, export)
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *AuditChainHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAuditCheckpointDisabled):
+		c.JSON(http.StatusServiceUnavailable, responses.ErrorResponse(503, err.Error()))
+	case errors.Is(err, service.ErrAuditChainBroken), errors.Is(err, service.ErrAuditCheckpointBusy):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
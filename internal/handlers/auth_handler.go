@@ -1,24 +1,35 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 // AuthHandler handles HTTP requests untuk authentication
 type AuthHandler struct {
-	authService service.AuthService
+	authService          service.AuthService
+	passwordResetService service.PasswordResetService
 }
 
 // NewAuthHandler constructor untuk AuthHandler
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService service.AuthService, passwordResetService service.PasswordResetService) *AuthHandler {
+	return &AuthHandler{
+		authService:          authService,
+		passwordResetService: passwordResetService,
+	}
 }
 
 // Login handles POST /auth/login
@@ -55,17 +66,228 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if rejectUnavailableAuthMode(c, req.Mode) {
+		return
+	}
+
 	// Call service layer with context for activity logging
 	ctx := GetContextWithRequestInfo(c)
-	user, token, err := h.authService.Login(ctx, req.Email, req.Password)
+	user, tokens, err := h.authService.Login(ctx, req.Email, req.Password)
 	if err != nil {
+		// Password benar tapi masih butuh langkah 2FA
+		if respondTwoFactorChallenge(c, err) {
+			return
+		}
+
+		// IP diblokir karena terlalu banyak percobaan gagal
+		// Akun terkunci sengaja dijawab sama seperti password salah (lihat authService.Login)
+		if errors.Is(err, service.ErrTooManyLoginAttempts) {
+			respondLoginThrottled(c, err)
+			return
+		}
+
 		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Email atau password salah"))
 		return
 	}
 
-	// Convert domain.User → dto.UserDTO
-	response := responses.LoginResponse{
-		Token: token,
+	respondLogin(c, "Login berhasil", req.Mode, user, tokens)
+}
+
+// VerifyTwoFactor handles POST /auth/2fa/verify
+// Langkah kedua login dengan kode TOTP atau recovery code (mode cookie dikirim ulang di langkah ini)
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req requests.VerifyTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Challenge token dan kode 2FA wajib diisi"},
+		}))
+		return
+	}
+
+	if rejectUnavailableAuthMode(c, req.Mode) {
+		return
+	}
+
+	user, tokens, err := h.authService.VerifyTwoFactor(GetContextWithRequestInfo(c), req.ChallengeToken, req.Code)
+	if err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+
+	respondLogin(c, "Login berhasil", req.Mode, user, tokens)
+}
+
+// BeginTwoFactorSetup handles POST /auth/2fa/setup
+// Enrollment 2FA saat login untuk admin yang diwajibkan 2FA
+func (h *AuthHandler) BeginTwoFactorSetup(c *gin.Context) {
+	var req requests.TwoFactorChallengeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"challenge_token": {"Challenge token wajib diisi"},
+		}))
+		return
+	}
+
+	enrollment, err := h.authService.BeginTwoFactorSetup(GetContextWithRequestInfo(c), req.ChallengeToken)
+	if err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Scan QR code dengan aplikasi authenticator", responses.TwoFactorEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.OTPAuthURI,
+	}))
+}
+
+// ConfirmTwoFactorSetup handles POST /auth/2fa/setup/confirm
+// Mengaktifkan 2FA lalu menyelesaikan login, recovery code hanya ditampilkan sekali
+func (h *AuthHandler) ConfirmTwoFactorSetup(c *gin.Context) {
+	var req requests.VerifyTwoFactorRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Challenge token dan kode 2FA wajib diisi"},
+		}))
+		return
+	}
+
+	if rejectUnavailableAuthMode(c, req.Mode) {
+		return
+	}
+
+	user, tokens, recoveryCodes, err := h.authService.ConfirmTwoFactorSetup(GetContextWithRequestInfo(c), req.ChallengeToken, req.Code)
+	if err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+
+	loginResponse, err := newLoginResponse(c, req.Mode, user, tokens)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal membuat session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "2FA berhasil diaktifkan, simpan recovery code di tempat aman", responses.TwoFactorSetupLoginResponse{
+		LoginResponse: loginResponse,
+		RecoveryCodes: recoveryCodes,
+	}))
+}
+
+// handleTwoFactorError memetakan error 2FA saat login ke HTTP response
+func (h *AuthHandler) handleTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTooManyLoginAttempts):
+		respondLoginThrottled(c, err)
+	case errors.Is(err, service.ErrInvalidChallengeToken), errors.Is(err, service.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled), errors.Is(err, service.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// respondTwoFactorChallenge mengirim challenge 2FA jika login masih butuh langkah kedua
+// Return false jika err bukan TwoFactorChallengeError
+func respondTwoFactorChallenge(c *gin.Context, err error) bool {
+	var challenge *service.TwoFactorChallengeError
+	if !errors.As(err, &challenge) {
+		return false
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Verifikasi 2FA diperlukan", responses.TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		SetupRequired:     challenge.SetupRequired,
+		ChallengeToken:    challenge.ChallengeToken,
+		ExpiresIn:         challenge.ExpiresIn,
+	}))
+	return true
+}
+
+// respondLoginThrottled mengirim 429 beserta header Retry-After
+func respondLoginThrottled(c *gin.Context, err error) {
+	message := err.Error()
+
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+
+		if throttled.Locked {
+			message = fmt.Sprintf("Akun dikunci sementara karena terlalu banyak percobaan login gagal. Coba lagi dalam %d menit", int(math.Ceil(throttled.RetryAfter.Minutes())))
+		} else {
+			message = fmt.Sprintf("Terlalu banyak percobaan login gagal. Coba lagi dalam %d detik", retryAfter)
+		}
+	}
+
+	c.JSON(http.StatusTooManyRequests, responses.ErrorResponse(429, message))
+}
+
+// rejectUnavailableAuthMode menolak login mode cookie jika belum diaktifkan di config
+// Return true jika response error sudah dikirim
+func rejectUnavailableAuthMode(c *gin.Context, mode string) bool {
+	if mode == requests.AuthModeCookie && !utils.SessionCookiesEnabled() {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "Mode login cookie tidak diaktifkan"))
+		return true
+	}
+	return false
+}
+
+// respondLogin mengirim response login sukses sesuai mode auth
+func respondLogin(c *gin.Context, message string, mode string, user *domain.User, tokens *service.AuthTokens) {
+	response, err := newLoginResponse(c, mode, user, tokens)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal membuat session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, message, response))
+}
+
+// newLoginResponse membuat response login sesuai mode auth
+// Mode cookie: token disimpan di cookie HttpOnly dan body hanya berisi token CSRF
+func newLoginResponse(c *gin.Context, mode string, user *domain.User, tokens *service.AuthTokens) (responses.LoginResponse, error) {
+	response := toLoginResponse(user, tokens)
+	if mode != requests.AuthModeCookie {
+		return response, nil
+	}
+
+	csrfToken, err := setSessionCookies(c, tokens)
+	if err != nil {
+		return response, err
+	}
+
+	response.Token = ""
+	response.RefreshToken = ""
+	response.CSRFToken = csrfToken
+	return response, nil
+}
+
+// setSessionCookies menyimpan access & refresh token di cookie HttpOnly beserta token CSRF baru
+func setSessionCookies(c *gin.Context, tokens *service.AuthTokens) (string, error) {
+	csrfToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cookie := range utils.NewSessionCookies(tokens.AccessToken, tokens.RefreshToken, csrfToken) {
+		http.SetCookie(c.Writer, cookie)
+	}
+	return csrfToken, nil
+}
+
+// toLoginResponse convert domain.User dan token ke dto.LoginResponse
+func toLoginResponse(user *domain.User, tokens *service.AuthTokens) responses.LoginResponse {
+	return responses.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 		User: responses.UserDTO{
 			ID:       user.ID,
 			FullName: user.FullName,
@@ -73,38 +295,146 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			Role:     getRoleName(user.Role),
 		},
 	}
+}
+
+// Refresh handles POST /auth/refresh
+// Menukar refresh token dengan access token + refresh token baru (rotation)
+// Mode cookie: body boleh kosong, refresh token diambil dari cookie dan wajib disertai header X-CSRF-Token
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req requests.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"refresh_token": {"Refresh token wajib diisi"},
+		}))
+		return
+	}
+
+	refreshToken := req.RefreshToken
+	fromCookie := false
+	if refreshToken == "" && utils.SessionCookiesEnabled() {
+		if cookie, err := c.Cookie(utils.RefreshCookieName); err == nil && cookie != "" {
+			refreshToken = cookie
+			fromCookie = true
+		}
+	}
 
-	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Login berhasil", response))
+	if refreshToken == "" {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"refresh_token": {"Refresh token wajib diisi"},
+		}))
+		return
+	}
+
+	if fromCookie {
+		csrfCookie, _ := c.Cookie(utils.CSRFCookieName)
+		if !utils.ValidCSRFToken(csrfCookie, c.GetHeader(utils.CSRFHeaderName)) {
+			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "CSRF token tidak valid"))
+			return
+		}
+	}
+
+	ctx := GetContextWithRequestInfo(c)
+	_, tokens, err := h.authService.Refresh(ctx, refreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRefreshToken), errors.Is(err, service.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal memperbarui token"))
+		}
+		return
+	}
+
+	response, err := newTokenResponse(c, tokens, fromCookie)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal memperbarui token"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Token berhasil diperbarui", response))
+}
+
+// newTokenResponse membuat response token baru (refresh / session baru setelah ganti password)
+// Mode cookie: token baru hanya di cookie, token CSRF ikut dirotasi
+func newTokenResponse(c *gin.Context, tokens *service.AuthTokens, cookieMode bool) (responses.RefreshTokenResponse, error) {
+	if !cookieMode {
+		return responses.RefreshTokenResponse{
+			Token:        tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn:    tokens.ExpiresIn,
+		}, nil
+	}
+
+	csrfToken, err := setSessionCookies(c, tokens)
+	if err != nil {
+		return responses.RefreshTokenResponse{}, err
+	}
+	return responses.RefreshTokenResponse{
+		CSRFToken: csrfToken,
+		ExpiresIn: tokens.ExpiresIn,
+	}, nil
+}
+
+// clearSessionCookies menghapus cookie session di browser (tidak berpengaruh untuk client mode bearer)
+func clearSessionCookies(c *gin.Context) {
+	if !utils.SessionCookiesEnabled() {
+		return
+	}
+	for _, cookie := range utils.ClearSessionCookies() {
+		http.SetCookie(c.Writer, cookie)
+	}
 }
 
 // Logout handles POST /auth/logout
+// Tidak butuh access token yang masih berlaku: cukup access token (header Authorization atau session cookie)
+// atau refresh token (body atau refresh cookie). Cookie session selalu dihapus apa pun hasilnya
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// Get token dari header Authorization
-	authHeader := c.GetHeader("Authorization")
-	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
+	clearSessionCookies(c)
+
+	var req requests.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "Format request tidak valid"))
 		return
 	}
 
-	// Parse token
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
-		return
+	var accessToken string
+	if authHeader := c.GetHeader("Authorization"); authHeader != "" {
+		// Parse token
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
+			return
+		}
+		accessToken = parts[1]
 	}
 
-	token := parts[1]
+	// Mode cookie: tanpa header maupun body, token diambil dari session cookie dan refresh cookie
+	refreshToken := req.RefreshToken
+	fromCookie := false
+	if accessToken == "" && refreshToken == "" && utils.SessionCookiesEnabled() {
+		accessToken, _ = c.Cookie(utils.SessionCookieName)
+		refreshToken, _ = c.Cookie(utils.RefreshCookieName)
+		fromCookie = true
+	}
 
-	// Get user ID from context (set by auth middleware)
-	userID, exists := c.Get("user_id")
-	if !exists {
+	if accessToken == "" && refreshToken == "" {
 		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
 		return
 	}
 
-	// Logout (blacklist token) with context for activity logging
+	// Token dari cookie terkirim otomatis oleh browser, wajib disertai CSRF seperti /refresh
+	if fromCookie {
+		csrfCookie, _ := c.Cookie(utils.CSRFCookieName)
+		if !utils.ValidCSRFToken(csrfCookie, c.GetHeader(utils.CSRFHeaderName)) {
+			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "CSRF token tidak valid"))
+			return
+		}
+	}
+
+	// Logout (revoke token) with context for activity logging
 	ctx := GetContextWithRequestInfo(c)
-	if err := h.authService.Logout(ctx, userID.(int), token); err != nil {
+	if err := h.authService.Logout(ctx, accessToken, refreshToken); err != nil {
 		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
 		return
 	}
@@ -154,12 +484,104 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	// Panggil service layer dengan userID langsung
-	err = h.authService.ChangePassword(userID.(int), req)
+	// Panggil service layer dengan userID langsung (context membawa session saat ini)
+	tokens, err := h.authService.ChangePassword(GetContextWithRequestInfo(c), userID.(int), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Password berhasil diubah", nil))
+	// Semua session dicabut: tanpa revoke_other_sessions, perangkat ini juga harus login ulang
+	cookieMode := c.GetHeader("Authorization") == "" && utils.SessionCookiesEnabled()
+	if tokens == nil {
+		if cookieMode {
+			clearSessionCookies(c)
+		}
+		c.JSON(http.StatusOK, responses.SuccessResponse(200, "Password berhasil diubah, silakan login kembali", nil))
+		return
+	}
+
+	response, err := newTokenResponse(c, tokens, cookieMode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal membuat session"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Password berhasil diubah", response))
+}
+
+// ForgotPassword handles POST /auth/forgot-password
+// Selalu mengembalikan 200 agar tidak membocorkan email mana yang terdaftar
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req requests.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := make(map[string][]string)
+
+		if validationErr, ok := err.(validator.ValidationErrors); ok {
+			for _, e := range validationErr {
+				fieldName := strings.ToLower(e.Field())
+				switch e.Tag() {
+				case "required":
+					validationErrors[fieldName] = append(validationErrors[fieldName], "Email wajib diisi")
+				case "email":
+					validationErrors[fieldName] = append(validationErrors[fieldName], "Format email tidak valid")
+				}
+			}
+		}
+
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(validationErrors))
+		return
+	}
+
+	// Error internal sengaja tidak diteruskan ke client
+	_ = h.passwordResetService.ForgotPassword(GetContextWithRequestInfo(c), req.Email)
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Jika email terdaftar, link reset password telah dikirim", nil))
+}
+
+// ResetPassword handles POST /auth/reset-password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req requests.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := make(map[string][]string)
+
+		if validationErr, ok := err.(validator.ValidationErrors); ok {
+			for _, e := range validationErr {
+				fieldName := strings.ToLower(e.Field())
+				var message string
+
+				switch e.Tag() {
+				case "required":
+					switch fieldName {
+					case "token":
+						message = "Token wajib diisi"
+					case "newpassword":
+						message = "Password baru wajib diisi"
+					}
+				case "min":
+					message = "Password baru minimal 8 karakter"
+				case "containsany":
+					message = "Password baru harus mengandung minimal satu karakter spesial (!@#$%^&*)"
+				}
+
+				validationErrors[fieldName] = append(validationErrors[fieldName], message)
+			}
+		}
+
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(validationErrors))
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(GetContextWithRequestInfo(c), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mereset password"))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Password berhasil direset, silakan login kembali", nil))
 }
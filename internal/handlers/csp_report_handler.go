@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCSPReportBodySize batas ukuran body laporan CSP (64 KB)
+const maxCSPReportBodySize = 64 << 10
+
+// CSPReportHandler handles HTTP requests untuk laporan pelanggaran Content Security Policy
+type CSPReportHandler struct {
+	cspReportService service.CSPReportService
+}
+
+// NewCSPReportHandler constructor untuk CSPReportHandler
+func NewCSPReportHandler(cspReportService service.CSPReportService) *CSPReportHandler {
+	return &CSPReportHandler{cspReportService: cspReportService}
+}
+
+// Report handles POST /v1/csp-report (public, dikirim otomatis oleh browser)
+// Menerima application/csp-report (report-uri) dan application/reports+json (Reporting API)
+func (h *CSPReportHandler) Report(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCSPReportBodySize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, responses.ErrorResponse(413, "Laporan CSP terlalu besar"))
+		return
+	}
+
+	if _, err := h.cspReportService.Record(GetContextWithRequestInfo(c), body); err != nil {
+		if errors.Is(err, service.ErrInvalidCSPReport) {
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAll handles GET /v1/admin/csp-reports
+// Query Params:
+//   - page: int (default: 1)
+//   - limit: int (default: 30)
+//   - directive: string (optional) - filter by effective directive (script-src, img-src, dll.)
+func (h *CSPReportHandler) GetAll(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "30"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 30
+	}
+
+	reports, lastPage, total, err := h.cspReportService.GetAll(c.Request.Context(), page, limit, c.Query("directive"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200, "Laporan CSP berhasil dimuat", reports, page, limit, total, lastPage,
+	))
+}
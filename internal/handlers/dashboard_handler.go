@@ -5,8 +5,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -44,13 +46,8 @@ func (h *DashboardHandler) GetDashboard(c *gin.Context) {
 		}
 	}
 
-	// Check if user is admin (role = "1")
-	isAdmin := false
-	if userRole, exists := c.Get("user_role"); exists {
-		if role, ok := userRole.(string); ok && role == "1" {
-			isAdmin = true
-		}
-	}
+	// Activity logs hanya untuk role dengan permission activity_logs.view
+	isAdmin := utils.HasPermission(c.GetString("user_role"), domain.PermActivityLogsView)
 
 	// Get dashboard data dari service
 	dashboard, err := h.dashboardService.GetDashboard(c.Request.Context(), year, month, isAdmin)
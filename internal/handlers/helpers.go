@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -21,6 +22,26 @@ func GetContextWithRequestInfo(c *gin.Context) context.Context {
 		}
 	}
 
+	// Add role if available (dipakai service untuk cek permission, mis. ownership post)
+	if role := c.GetString("user_role"); role != "" {
+		ctx = utils.WithUserRole(ctx, role)
+	}
+
+	// Add API key ID if request diautentikasi dengan API key (dicatat di activity log)
+	if apiKeyID := c.GetInt("api_key_id"); apiKeyID != 0 {
+		ctx = utils.WithAPIKeyID(ctx, apiKeyID)
+	}
+
+	// Add impersonator ID if request dilakukan admin dengan token impersonasi (dicatat di activity log)
+	if impersonatorID := c.GetInt("impersonator_id"); impersonatorID != 0 {
+		ctx = utils.WithImpersonatorID(ctx, impersonatorID)
+	}
+
+	// Add session ID if available (sid claim dari access token)
+	if sessionID := c.GetString("session_id"); sessionID != "" {
+		ctx = utils.WithSessionID(ctx, sessionID)
+	}
+
 	// Add IP address
 	if ip, exists := c.Get(string(utils.ContextKeyIPAddress)); exists {
 		if ipStr, ok := ip.(string); ok {
@@ -98,16 +119,16 @@ func getValidationMessage(fe validator.FieldError) string {
 	}
 }
 
-// getRoleName convert role code ke nama role
+// getRoleName convert role ID ke key role (mis. "admin", "author")
+// Fallback ke mapping lama jika role tidak bisa di-resolve
 func getRoleName(role int) string {
-	switch role {
-	case 1:
+	if key, ok := utils.GetRoleKey(role); ok {
+		return key
+	}
+	if role == domain.RoleAdminID {
 		return "admin"
-	case 2:
-		return "author"
-	default:
-		return "author"
 	}
+	return "author"
 }
 
 // getStatusName convert isActive bool ke nama status
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles HTTP requests untuk impersonasi user oleh admin
+type ImpersonationHandler struct {
+	impersonationService service.ImpersonationService
+}
+
+// NewImpersonationHandler constructor untuk ImpersonationHandler
+func NewImpersonationHandler(impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+// Impersonate handles POST /v1/admin/users/:id/impersonate
+// Mengembalikan access token berumur pendek atas nama user, akhiri dengan POST /v1/auth/logout
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	result, err := h.impersonationService.Impersonate(GetContextWithRequestInfo(c), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+		case errors.Is(err, service.ErrCannotImpersonateSelf):
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+		case errors.Is(err, service.ErrImpersonationTargetInactive), errors.Is(err, service.ErrImpersonationTargetPrivileged), errors.Is(err, service.ErrImpersonationNotAllowed):
+			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Impersonasi dimulai", responses.ImpersonationResponse{
+		Token:     result.AccessToken,
+		ExpiresIn: result.ExpiresIn,
+		User: responses.UserDTO{
+			ID:       result.User.ID,
+			FullName: result.User.FullName,
+			Email:    result.User.Email,
+			Role:     getRoleName(result.User.Role),
+		},
+		ImpersonatorID: c.GetInt("user_id"),
+	}))
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
@@ -12,16 +14,49 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
+type InboxHandler struct {
+	svc      service.InboxService
+	upgrader websocket.Upgrader
 }
 
-type InboxHandler struct {
-	svc service.InboxService
+// NewInboxHandler constructor untuk InboxHandler
+// allowedOrigins sama dengan ALLOWED_ORIGINS (CORS), hanya origin tersebut yang boleh membuka WebSocket
+func NewInboxHandler(svc service.InboxService, allowedOrigins []string) *InboxHandler {
+	return &InboxHandler{
+		svc:      svc,
+		upgrader: websocket.Upgrader{CheckOrigin: checkWebSocketOrigin(allowedOrigins)},
+	}
 }
 
-func NewInboxHandler(svc service.InboxService) *InboxHandler {
-	return &InboxHandler{svc: svc}
+// checkWebSocketOrigin menolak upgrade dari origin di luar ALLOWED_ORIGINS
+// Session cookie ikut terkirim saat halaman lain membuka WebSocket, tanpa cek origin koneksi bisa dibajak (CSWSH)
+// Dengan ALLOWED_ORIGINS=* hanya origin yang sama dengan host API yang diterima
+func checkWebSocketOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	allowed := make(map[string]bool, len(allowedOrigins))
+	allowAll := false
+	for _, origin := range allowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			allowAll = true
+		} else if origin != "" {
+			allowed[origin] = true
+		}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true // Bukan dari browser, tidak membawa cookie pihak ketiga
+		}
+		if allowed[origin] {
+			return true
+		}
+		if allowAll {
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+		return false
+	}
 }
 
 // WS /v1/chat/ws
@@ -35,7 +70,7 @@ func (h *InboxHandler) HandleWebSocket(c *gin.Context) {
 	senderID := senderIDVal.(int)
 
 	// 2. Upgrade HTTP ke WebSocket
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WS Upgrade Error: %v", err)
 		return
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationHandler handles HTTP requests untuk undangan user baru
+type InvitationHandler struct {
+	invitationService service.InvitationService
+}
+
+// NewInvitationHandler constructor untuk InvitationHandler
+func NewInvitationHandler(invitationService service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+// GetAll handles GET /admin/invitations - undangan yang belum diterima atau dicabut
+func (h *InvitationHandler) GetAll(c *gin.Context) {
+	invitations, err := h.invitationService.GetOpen(GetContextWithRequestInfo(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	now := time.Now()
+	result := make([]responses.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		result = append(result, toInvitationResponse(&invitations[i], now))
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar undangan berhasil diambil", result))
+}
+
+// Create handles POST /admin/invitations
+func (h *InvitationHandler) Create(c *gin.Context) {
+	var req requests.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	invitation, err := h.invitationService.Create(GetContextWithRequestInfo(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Undangan berhasil dikirim", toInvitationResponse(invitation, time.Now())))
+}
+
+// Resend handles POST /admin/invitations/:id/resend
+func (h *InvitationHandler) Resend(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	invitation, err := h.invitationService.Resend(GetContextWithRequestInfo(c), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Undangan berhasil dikirim ulang", toInvitationResponse(invitation, time.Now())))
+}
+
+// Revoke handles DELETE /admin/invitations/:id
+func (h *InvitationHandler) Revoke(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.invitationService.Revoke(GetContextWithRequestInfo(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Undangan berhasil dicabut", nil))
+}
+
+// Verify handles POST /auth/invitations/verify - cek link undangan (public)
+func (h *InvitationHandler) Verify(c *gin.Context) {
+	var req requests.VerifyInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"token": {"Token undangan wajib diisi"},
+		}))
+		return
+	}
+
+	invitation, err := h.invitationService.Verify(GetContextWithRequestInfo(c), req.Token)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Undangan valid", responses.InvitationPreviewResponse{
+		Email:     invitation.Email,
+		Role:      invitation.Role.Name,
+		ExpiresAt: invitation.ExpiresAt,
+	}))
+}
+
+// Accept handles POST /auth/invitations/accept - membuat akun dari undangan (public, JSON atau multipart dengan foto)
+func (h *InvitationHandler) Accept(c *gin.Context) {
+	var req requests.AcceptInvitationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	photoFile, _ := c.FormFile("photo")
+
+	user, err := h.invitationService.Accept(GetContextWithRequestInfo(c), &req, photoFile)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	photoUri := ""
+	if user.PhotoURI != nil {
+		photoUri = *user.PhotoURI
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Akun berhasil dibuat, silakan login", responses.UserProfileResponse{
+		ID:       user.ID,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Role:     getRoleName(user.Role),
+		Status:   getStatusName(user.IsActive),
+		PhotoUri: photoUri,
+	}))
+}
+
+// handleError memetakan error undangan ke HTTP response
+func (h *InvitationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrEmailAlreadyExists), errors.Is(err, service.ErrInvitationAlreadyPending), errors.Is(err, service.ErrInvitationClosed):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	case errors.Is(err, service.ErrInvalidInvitation), errors.Is(err, service.ErrRoleNotFound), errors.Is(err, service.ErrInvalidPassword):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrInvitationRoleForbidden):
+		c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal memproses undangan"))
+	}
+}
+
+// toInvitationResponse convert domain.UserInvitation ke InvitationResponse DTO
+func toInvitationResponse(invitation *domain.UserInvitation, now time.Time) responses.InvitationResponse {
+	return responses.InvitationResponse{
+		ID:         invitation.ID,
+		Email:      invitation.Email,
+		RoleID:     invitation.RoleID,
+		Role:       invitation.Role.Name,
+		InvitedBy:  invitation.Inviter.FullName,
+		ExpiresAt:  invitation.ExpiresAt,
+		Expired:    !now.Before(invitation.ExpiresAt),
+		LastSentAt: invitation.LastSentAt,
+		SendCount:  invitation.SendCount,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler handles HTTP requests untuk login SSO (OpenID Connect)
+type OIDCHandler struct {
+	oidcService service.OIDCService
+}
+
+// NewOIDCHandler constructor untuk OIDCHandler
+func NewOIDCHandler(oidcService service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// GetProviders handles GET /auth/oidc/providers
+func (h *OIDCHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar provider SSO", responses.OIDCProvidersResponse{
+		Providers: h.oidcService.Providers(),
+	}))
+}
+
+// Authorize handles POST /auth/oidc/:provider/authorize
+// Mengembalikan URL login provider, frontend menyimpan state lalu redirect user ke URL tersebut
+// Hash state juga dipasang di cookie HttpOnly, jadi frontend harus memanggil authorize dan callback dengan credentials
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	authorization, err := h.oidcService.BeginLogin(GetContextWithRequestInfo(c), c.Param("provider"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	http.SetCookie(c.Writer, utils.NewOIDCStateCookie(authorization.StateBinding, time.Duration(authorization.ExpiresIn)*time.Second))
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Lanjutkan login di provider SSO", responses.OIDCAuthorizationResponse{
+		AuthorizationURL: authorization.AuthorizationURL,
+		State:            authorization.State,
+		ExpiresIn:        authorization.ExpiresIn,
+	}))
+}
+
+// Callback handles POST /auth/oidc/:provider/callback
+// Frontend meneruskan code dan state dari redirect provider, response sama dengan login password
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	var req requests.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Code dan state wajib diisi"},
+		}))
+		return
+	}
+
+	if rejectUnavailableAuthMode(c, req.Mode) {
+		return
+	}
+
+	// Cookie state sekali pakai, dihapus apa pun hasil callback
+	stateBinding, _ := c.Cookie(utils.OIDCStateCookieName)
+	http.SetCookie(c.Writer, utils.ClearOIDCStateCookie())
+
+	user, tokens, err := h.oidcService.CompleteLogin(GetContextWithRequestInfo(c), c.Param("provider"), req.Code, req.State, stateBinding)
+	if err != nil {
+		if respondTwoFactorChallenge(c, err) {
+			return
+		}
+		h.handleError(c, err)
+		return
+	}
+
+	respondLogin(c, "Login berhasil", req.Mode, user, tokens)
+}
+
+// handleError memetakan error SSO ke HTTP response
+func (h *OIDCHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOIDCProviderNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrOIDCInvalidState):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrOIDCTokenInvalid):
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+	case errors.Is(err, service.ErrOIDCEmailNotVerified), errors.Is(err, service.ErrOIDCDomainNotAllowed), errors.Is(err, service.ErrOIDCAccountNotFound):
+		c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+	case errors.Is(err, service.ErrOIDCProviderUnavailable):
+		c.JSON(http.StatusBadGateway, responses.ErrorResponse(502, err.Error()))
+	case errors.Is(err, service.ErrOIDCLoginFailed):
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	default:
+		// Error dari AuthService.LoginExternal (mis. akun nonaktif)
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Login SSO gagal"))
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -62,7 +63,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 	ctx := GetContextWithRequestInfo(c)
 	res, err := h.svc.CreatePost(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal membuat berita"))
+		h.handleError(c, err, "Gagal membuat berita")
 		return
 	}
 
@@ -104,7 +105,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 
 	res, err := h.svc.UpdatePost(GetContextWithRequestInfo(c), id, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal memperbarui berita"))
+		h.handleError(c, err, "Gagal memperbarui berita")
 		return
 	}
 
@@ -116,9 +117,113 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	id := c.Param("id")
 
 	if err := h.svc.DeletePost(GetContextWithRequestInfo(c), id); err != nil {
-		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, "Berita tidak ditemukan atau gagal dihapus"))
+		h.handleError(c, err, "Berita tidak ditemukan atau gagal dihapus")
 		return
 	}
 
 	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Berita berhasil dihapus", nil))
 }
+
+// 6. GET MY POSTS - GET /v1/users/me/posts (termasuk draft)
+func (h *PostHandler) GetMyPosts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	data, lastPage, total, err := h.svc.GetMyPosts(GetContextWithRequestInfo(c), page, limit, search)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil data berita")
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200,
+		"List of my posts",
+		data,
+		page,
+		limit,
+		total,
+		lastPage,
+	))
+}
+
+// GetDrafts - GET /v1/admin/posts/drafts (posts.manage_any atau API key news:read)
+func (h *PostHandler) GetDrafts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	search := c.Query("search")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	data, lastPage, total, err := h.svc.GetDrafts(page, limit, search)
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil data draft berita")
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200,
+		"List of draft posts",
+		data,
+		page,
+		limit,
+		total,
+		lastPage,
+	))
+}
+
+// GetDraft - GET /v1/admin/posts/drafts/:id (posts.manage_any atau API key news:read)
+func (h *PostHandler) GetDraft(c *gin.Context) {
+	res, err := h.svc.GetDraft(c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Gagal mengambil draft berita")
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Draft post detail", res))
+}
+
+// 7. TRANSFER OWNERSHIP - PUT /v1/admin/posts/:id/owner (Admin Only)
+func (h *PostHandler) TransferOwnership(c *gin.Context) {
+	var req requests.TransferPostOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	res, err := h.svc.TransferOwnership(GetContextWithRequestInfo(c), c.Param("id"), req.UserID)
+	if err != nil {
+		h.handleError(c, err, "Gagal memindahkan kepemilikan berita")
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Kepemilikan berita berhasil dipindahkan", res))
+}
+
+// handleError memetakan error service ke HTTP response, fallback ke 500 dengan pesan default
+func (h *PostHandler) handleError(c *gin.Context, err error, fallbackMessage string) {
+	switch {
+	case errors.Is(err, service.ErrPostNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrPostForbidden):
+		c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+	case errors.Is(err, service.ErrPostAuthorRequired):
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+	case errors.Is(err, service.ErrPostOwnerInactive):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, fallbackMessage))
+	}
+}
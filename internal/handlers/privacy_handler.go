@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyHandler handles HTTP requests untuk keterangan privasi publik
+type PrivacyHandler struct {
+	visitorPrivacyService service.VisitorPrivacyService
+}
+
+// NewPrivacyHandler constructor untuk PrivacyHandler
+func NewPrivacyHandler(visitorPrivacyService service.VisitorPrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{visitorPrivacyService: visitorPrivacyService}
+}
+
+// GetDataProcessingNotice handles GET /v1/privacy/data-processing
+// Returns data visitor yang diproses, cara anonimisasi dan masa simpannya untuk halaman kebijakan privasi
+func (h *PrivacyHandler) GetDataProcessingNotice(c *gin.Context) {
+	result := h.visitorPrivacyService.GetDataProcessingNotice()
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Berhasil mengambil keterangan pemrosesan data", result))
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler handles HTTP requests untuk manajemen role dan permission
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+// NewRoleHandler constructor untuk RoleHandler
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// GetAll handles GET /v1/admin/roles
+func (h *RoleHandler) GetAll(c *gin.Context) {
+	roles, err := h.roleService.GetAll(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.RoleResponse, 0, len(roles))
+	for i := range roles {
+		result = append(result, toRoleResponse(&roles[i]))
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar role berhasil diambil", result))
+}
+
+// GetPermissions handles GET /v1/admin/permissions
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	permissions, err := h.roleService.GetPermissions(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		item := responses.PermissionResponse{Key: permission.Key}
+		if permission.Description != nil {
+			item.Description = *permission.Description
+		}
+		result = append(result, item)
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar permission berhasil diambil", result))
+}
+
+// Create handles POST /v1/admin/roles
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req requests.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	role, err := h.roleService.Create(GetContextWithRequestInfo(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Role berhasil dibuat", toRoleResponse(role)))
+}
+
+// Update handles PUT /v1/admin/roles/:id
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	var req requests.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	role, err := h.roleService.Update(GetContextWithRequestInfo(c), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Role berhasil diupdate", toRoleResponse(role)))
+}
+
+// Delete handles DELETE /v1/admin/roles/:id
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.roleService.Delete(GetContextWithRequestInfo(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Role berhasil dihapus", nil))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *RoleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrInvalidRoleKey), errors.Is(err, service.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrRoleKeyExists), errors.Is(err, service.ErrRoleInUse):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	case errors.Is(err, service.ErrSystemRoleLocked):
+		c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// toRoleResponse mengubah domain.Role ke DTO
+func toRoleResponse(role *domain.Role) responses.RoleResponse {
+	result := responses.RoleResponse{
+		ID:          role.ID,
+		Key:         role.Key,
+		Name:        role.Name,
+		IsSystem:    role.IsSystem,
+		Permissions: role.PermissionKeys(),
+	}
+	if role.Description != nil {
+		result.Description = *role.Description
+	}
+	return result
+}
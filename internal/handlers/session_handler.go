@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler handles HTTP requests untuk manajemen session login
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+// NewSessionHandler constructor untuk SessionHandler
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// GetMySessions handles GET /v1/users/me/sessions
+func (h *SessionHandler) GetMySessions(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	sessions, err := h.sessionService.GetActiveSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar sesi berhasil diambil", toSessionResponses(sessions, c.GetString("session_id"))))
+}
+
+// RevokeMySession handles DELETE /v1/users/me/sessions/:id
+func (h *SessionHandler) RevokeMySession(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	if err := h.sessionService.RevokeSession(GetContextWithRequestInfo(c), userID, c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Sesi berhasil dicabut", nil))
+}
+
+// RevokeMyOtherSessions handles DELETE /v1/users/me/sessions
+// Logout dari semua perangkat lain, session yang sedang dipakai tetap aktif
+func (h *SessionHandler) RevokeMyOtherSessions(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	revoked, err := h.sessionService.RevokeOtherSessions(GetContextWithRequestInfo(c), userID, c.GetString("session_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Semua sesi lain berhasil dicabut", gin.H{"revoked": revoked}))
+}
+
+// GetUserSessions handles GET /v1/admin/users/:id/sessions
+func (h *SessionHandler) GetUserSessions(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	sessions, err := h.sessionService.GetActiveSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Flag current hanya relevan jika admin melihat session miliknya sendiri
+	currentSessionID := ""
+	if userID == c.GetInt("user_id") {
+		currentSessionID = c.GetString("session_id")
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar sesi berhasil diambil", toSessionResponses(sessions, currentSessionID)))
+}
+
+// RevokeUserSession handles DELETE /v1/admin/users/:id/sessions/:sessionId
+func (h *SessionHandler) RevokeUserSession(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(GetContextWithRequestInfo(c), userID, c.Param("sessionId")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Sesi berhasil dicabut", nil))
+}
+
+// RevokeAllUserSessions handles DELETE /v1/admin/users/:id/sessions
+// Logout user dari semua perangkat
+func (h *SessionHandler) RevokeAllUserSessions(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return
+	}
+
+	// Admin yang mencabut session miliknya sendiri tetap mempertahankan session saat ini
+	keepSessionID := ""
+	if userID == c.GetInt("user_id") {
+		keepSessionID = c.GetString("session_id")
+	}
+
+	revoked, err := h.sessionService.RevokeOtherSessions(GetContextWithRequestInfo(c), userID, keepSessionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Semua sesi user berhasil dicabut", gin.H{"revoked": revoked}))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *SessionHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// toSessionResponses mengubah domain.UserSession ke DTO dan menandai session saat ini
+func toSessionResponses(sessions []domain.UserSession, currentSessionID string) []responses.SessionResponse {
+	result := make([]responses.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		item := responses.SessionResponse{
+			ID:         session.FamilyID,
+			LastUsedAt: session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    currentSessionID != "" && session.FamilyID == currentSessionID,
+		}
+		// Row lama (sebelum last_used_at diisi saat login/refresh) memakai waktu pembuatan row
+		if session.LastUsedAt != nil {
+			item.LastUsedAt = *session.LastUsedAt
+		}
+		if session.Device != nil {
+			item.Device = *session.Device
+		}
+		if session.IPAddress != nil {
+			item.IPAddress = *session.IPAddress
+		}
+		if session.UserAgent != nil {
+			item.UserAgent = *session.UserAgent
+		}
+		result = append(result, item)
+	}
+	return result
+}
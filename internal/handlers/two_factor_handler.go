@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler handles HTTP requests untuk pengelolaan 2FA milik user sendiri
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+// NewTwoFactorHandler constructor untuk TwoFactorHandler
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// GetStatus handles GET /v1/users/me/2fa
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	status, err := h.twoFactorService.GetStatus(c.Request.Context(), c.GetInt("user_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Status 2FA berhasil diambil", responses.TwoFactorStatusResponse{
+		Enabled:                status.Enabled,
+		EnabledAt:              status.EnabledAt,
+		Required:               status.Required,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	}))
+}
+
+// Enroll handles POST /v1/users/me/2fa/enroll
+// Membuat secret baru, 2FA belum aktif sampai dikonfirmasi dengan kode pertama
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	enrollment, err := h.twoFactorService.Enroll(GetContextWithRequestInfo(c), c.GetInt("user_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Scan QR code dengan aplikasi authenticator", responses.TwoFactorEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.OTPAuthURI,
+	}))
+}
+
+// Confirm handles POST /v1/users/me/2fa/confirm
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req requests.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Kode 2FA wajib diisi"},
+		}))
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(GetContextWithRequestInfo(c), c.GetInt("user_id"), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "2FA berhasil diaktifkan, simpan recovery code di tempat aman", responses.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}))
+}
+
+// RegenerateRecoveryCodes handles POST /v1/users/me/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req requests.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Kode 2FA wajib diisi"},
+		}))
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(GetContextWithRequestInfo(c), c.GetInt("user_id"), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Recovery code berhasil dibuat ulang", responses.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	}))
+}
+
+// Disable handles DELETE /v1/users/me/2fa
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req requests.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(map[string][]string{
+			"code": {"Password dan kode 2FA wajib diisi"},
+		}))
+		return
+	}
+
+	if err := h.twoFactorService.Disable(GetContextWithRequestInfo(c), c.GetInt("user_id"), req.Password, req.Code); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "2FA berhasil dinonaktifkan", nil))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *TwoFactorHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrTwoFactorRequiredForAdmin):
+		c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+	case errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrInvalidPasswordConfirmation),
+		errors.Is(err, service.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
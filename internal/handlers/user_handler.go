@@ -242,6 +242,8 @@ func (h *UserHandler) UpdateUserByID(c *gin.Context) {
 			c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
 		case errors.Is(err, service.ErrEmailAlreadyUsed):
 			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+		case errors.Is(err, service.ErrRoleNotFound):
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
 		case errors.Is(err, service.ErrInvalidPassword):
 			c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
 		case errors.Is(err, service.ErrPhotoUploadFailed):
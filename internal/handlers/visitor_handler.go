@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// VisitorHandler handles HTTP requests untuk beacon visitor analytics
+type VisitorHandler struct {
+	visitorService service.VisitorService
+}
+
+// NewVisitorHandler constructor untuk VisitorHandler
+func NewVisitorHandler(visitorService service.VisitorService) *VisitorHandler {
+	return &VisitorHandler{visitorService: visitorService}
+}
+
+// Beacon handles POST /v1/analytics/beacon
+// Body selalu dibaca sebagai JSON karena navigator.sendBeacon mengirim Content-Type text/plain
+func (h *VisitorHandler) Beacon(c *gin.Context) {
+	var req requests.VisitorBeaconRequest
+	if err := c.ShouldBindWith(&req, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	if err := h.visitorService.RecordBeacon(c.ClientIP(), c.Request.UserAgent(), req); err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, "Gagal mencatat kunjungan"))
+		return
+	}
+
+	// Kunjungan sudah tercatat, VisitorTracker tidak perlu mencatat ulang
+	c.Set("visitor_recorded", true)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles HTTP requests untuk manajemen webhook keluar
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler constructor untuk WebhookHandler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// GetAll handles GET /v1/admin/webhooks
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	hooks, err := h.webhookService.GetAll(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.WebhookResponse, 0, len(hooks))
+	for i := range hooks {
+		result = append(result, toWebhookResponse(&hooks[i]))
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar webhook berhasil diambil", result))
+}
+
+// GetEvents handles GET /v1/admin/webhooks/events
+// Daftar event yang bisa dilanggan
+func (h *WebhookHandler) GetEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Daftar event webhook berhasil diambil", domain.WebhookEvents))
+}
+
+// Create handles POST /v1/admin/webhooks
+// Secret hanya dikembalikan di response ini, simpan segera
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req requests.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	hook, err := h.webhookService.Create(GetContextWithRequestInfo(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse(201, "Webhook berhasil dibuat, simpan secret ini karena tidak akan ditampilkan lagi", responses.WebhookSecretResponse{
+		WebhookResponse: toWebhookResponse(hook),
+		Secret:          hook.Secret,
+	}))
+}
+
+// Update handles PUT /v1/admin/webhooks/:id
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req requests.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ValidationErrorResponse(err.Error()))
+		return
+	}
+
+	hook, newSecret, err := h.webhookService.Update(GetContextWithRequestInfo(c), int(id), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if newSecret != "" {
+		c.JSON(http.StatusOK, responses.SuccessResponse(200, "Webhook berhasil diupdate, simpan secret baru karena tidak akan ditampilkan lagi", responses.WebhookSecretResponse{
+			WebhookResponse: toWebhookResponse(hook),
+			Secret:          newSecret,
+		}))
+		return
+	}
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Webhook berhasil diupdate", toWebhookResponse(hook)))
+}
+
+// Delete handles DELETE /v1/admin/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(GetContextWithRequestInfo(c), int(id)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse(200, "Webhook berhasil dihapus", nil))
+}
+
+// Test handles POST /v1/admin/webhooks/:id/test
+// Event webhook.test dikirim asinkron, hasilnya terlihat di log pengiriman
+func (h *WebhookHandler) Test(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Test(GetContextWithRequestInfo(c), int(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, responses.SuccessResponse(202, "Tes webhook diantrikan", toWebhookDeliveryResponse(delivery)))
+}
+
+// GetDeliveries handles GET /v1/admin/webhooks/:id/deliveries
+// Query Params:
+//   - page: int (default: 1)
+//   - limit: int (default: 20)
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	id, ok := parseWebhookID(c, "id")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	deliveries, lastPage, total, err := h.webhookService.GetDeliveries(c.Request.Context(), int(id), page, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result := make([]responses.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		result = append(result, toWebhookDeliveryResponse(&deliveries[i]))
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponseWithPagination(
+		200, "Log pengiriman webhook berhasil dimuat", result, page, limit, total, lastPage,
+	))
+}
+
+// Replay handles POST /v1/admin/webhooks/deliveries/:deliveryId/replay
+// Payload yang sama dikirim ulang sebagai delivery baru (event id tetap sama)
+func (h *WebhookHandler) Replay(c *gin.Context) {
+	id, ok := parseWebhookID(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(GetContextWithRequestInfo(c), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, responses.SuccessResponse(202, "Pengiriman ulang webhook diantrikan", toWebhookDeliveryResponse(delivery)))
+}
+
+// handleError memetakan error service ke HTTP response
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound), errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		c.JSON(http.StatusNotFound, responses.ErrorResponse(404, err.Error()))
+	case errors.Is(err, service.ErrInvalidWebhookURL), errors.Is(err, service.ErrWebhookPrivateURL), errors.Is(err, service.ErrUnknownWebhookEvent):
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, err.Error()))
+	case errors.Is(err, service.ErrWebhookInactive):
+		c.JSON(http.StatusConflict, responses.ErrorResponse(409, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse(500, err.Error()))
+	}
+}
+
+// parseWebhookID membaca ID dari path, response 400 sudah dikirim jika tidak valid
+func parseWebhookID(c *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse(400, "ID tidak valid"))
+		return 0, false
+	}
+	return id, true
+}
+
+// toWebhookResponse mengubah domain.Webhook ke DTO
+func toWebhookResponse(hook *domain.Webhook) responses.WebhookResponse {
+	return responses.WebhookResponse{
+		ID:        hook.ID,
+		Name:      hook.Name,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		IsActive:  hook.IsActive,
+		CreatedBy: hook.CreatedBy,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+	}
+}
+
+// toWebhookDeliveryResponse mengubah domain.WebhookDelivery ke DTO
+func toWebhookDeliveryResponse(delivery *domain.WebhookDelivery) responses.WebhookDeliveryResponse {
+	return responses.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReplayOf:       delivery.ReplayOf,
+		Payload:        json.RawMessage(delivery.Payload),
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminIPChecker mengecek apakah IP boleh mengakses admin API (diimplementasikan oleh service.AdminIPAllowlistService)
+type AdminIPChecker interface {
+	IsAllowed(ipAddress string) (bool, error)
+}
+
+// blockedAdminLogInterval jeda minimal antar activity log akses diblokir dari IP yang sama
+// Request anonim bisa ditolak terus-menerus, tanpa jeda setiap request menulis satu entry hash chain
+const blockedAdminLogInterval = 10 * time.Minute
+
+// blockedAdminLogThrottle mencatat kapan akses diblokir per IP terakhir ditulis ke activity log (per proses)
+type blockedAdminLogThrottle struct {
+	mu         sync.Mutex
+	lastLogged map[string]time.Time
+}
+
+// allow true jika IP belum dicatat dalam blockedAdminLogInterval terakhir
+func (t *blockedAdminLogThrottle) allow(ipAddress string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.lastLogged[ipAddress]; ok && now.Sub(last) < blockedAdminLogInterval {
+		return false
+	}
+	// Buang IP yang jedanya sudah lewat agar map tidak tumbuh tanpa batas
+	if len(t.lastLogged) >= 1024 {
+		for ip, last := range t.lastLogged {
+			if now.Sub(last) >= blockedAdminLogInterval {
+				delete(t.lastLogged, ip)
+			}
+		}
+	}
+	t.lastLogged[ipAddress] = now
+	return true
+}
+
+// AdminIPAllowlist menolak request admin dari IP di luar allowlist dan mencatatnya ke activity log
+// Dipasang sebelum AuthMiddleware/APIKeyMiddleware agar IP di luar allowlist ditolak sebelum token/API key diproses
+// Akses yang diblokir dicatat paling banyak sekali per IP per blockedAdminLogInterval
+func AdminIPAllowlist(checker AdminIPChecker, activityLogRepo repository.ActivityLogRepository) gin.HandlerFunc {
+	throttle := &blockedAdminLogThrottle{lastLogged: make(map[string]time.Time)}
+	return func(c *gin.Context) {
+		ipAddress := c.ClientIP()
+
+		allowed, err := checker.IsAllowed(ipAddress)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, responses.ErrorResponse(503, "Gagal memeriksa allowlist IP admin"))
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			if throttle.allow(ipAddress, time.Now()) {
+				logBlockedAdminAccess(c, activityLogRepo, ipAddress)
+			}
+			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "Akses admin tidak diizinkan dari IP ini"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// logBlockedAdminAccess mencatat percobaan akses admin dari IP yang tidak diizinkan
+// Request belum diautentikasi sehingga entry disimpan tanpa user (user_id NULL)
+func logBlockedAdminAccess(c *gin.Context, activityLogRepo repository.ActivityLogRepository, ipAddress string) {
+	userAgent := c.GetHeader("User-Agent")
+	description := "Akses admin diblokir dari IP di luar allowlist: " + ipAddress
+
+	log := &domain.ActivityLog{
+		ActionType:  domain.ActionAccessDenied,
+		Module:      domain.ModuleSecurity,
+		Description: &description,
+		NewValue: map[string]any{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+			"route":  c.FullPath(),
+		},
+		IPAddress: &ipAddress,
+		UserAgent: &userAgent,
+	}
+
+	// Ignore error - logging should not affect main operation
+	_ = activityLogRepo.Create(c.Request.Context(), log)
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeAdminIPChecker mengizinkan satu IP saja
+type fakeAdminIPChecker struct {
+	allowedIP string
+	err       error
+}
+
+func (f fakeAdminIPChecker) IsAllowed(ipAddress string) (bool, error) {
+	return ipAddress == f.allowedIP, f.err
+}
+
+func newAdminIPAllowlistTestRouter(checker AdminIPChecker, activityLogRepo *fakeActivityLogRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	// Sama seperti main.go tanpa TRUSTED_PROXIES: header X-Forwarded-For diabaikan
+	_ = r.SetTrustedProxies(nil)
+	r.Use(AdminIPAllowlist(checker, activityLogRepo))
+	r.GET("/v1/admin/dashboard", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func doAdminIPAllowlistRequest(r *gin.Engine, ip string, headers ...string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/dashboard", nil)
+	req.RemoteAddr = ip + ":1234"
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestAdminIPAllowlist_AllowedIP(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newAdminIPAllowlistTestRouter(fakeAdminIPChecker{allowedIP: "203.0.113.7"}, logs)
+
+	if code := doAdminIPAllowlistRequest(r, "203.0.113.7"); code != http.StatusOK {
+		t.Errorf("expected 200, got %d", code)
+	}
+	if len(logs.logs) != 0 {
+		t.Errorf("expected no activity log for allowed IP, got %d", len(logs.logs))
+	}
+}
+
+func TestAdminIPAllowlist_BlockedIPIsLogged(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newAdminIPAllowlistTestRouter(fakeAdminIPChecker{allowedIP: "203.0.113.7"}, logs)
+
+	if code := doAdminIPAllowlistRequest(r, "192.0.2.1"); code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", code)
+	}
+	if len(logs.logs) != 1 {
+		t.Fatalf("expected 1 activity log, got %d", len(logs.logs))
+	}
+
+	log := logs.logs[0]
+	if log.ActionType != domain.ActionAccessDenied || log.Module != domain.ModuleSecurity || log.UserID != 0 {
+		t.Errorf("unexpected log: %+v", log)
+	}
+	if log.IPAddress == nil || *log.IPAddress != "192.0.2.1" {
+		t.Errorf("expected blocked IP in log, got %v", log.IPAddress)
+	}
+	if log.NewValue["route"] != "/v1/admin/dashboard" {
+		t.Errorf("expected route in log, got %v", log.NewValue["route"])
+	}
+}
+
+func TestAdminIPAllowlist_SpoofedForwardedForIgnored(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newAdminIPAllowlistTestRouter(fakeAdminIPChecker{allowedIP: "203.0.113.7"}, logs)
+
+	code := doAdminIPAllowlistRequest(r, "192.0.2.1", "X-Forwarded-For", "203.0.113.7", "X-Real-IP", "203.0.113.7")
+	if code != http.StatusForbidden {
+		t.Fatalf("expected 403 for spoofed X-Forwarded-For, got %d", code)
+	}
+	if len(logs.logs) != 1 || *logs.logs[0].IPAddress != "192.0.2.1" {
+		t.Errorf("expected blocked request logged with connection IP, got %+v", logs.logs)
+	}
+}
+
+func TestAdminIPAllowlist_BlockedIPLoggedOncePerWindow(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newAdminIPAllowlistTestRouter(fakeAdminIPChecker{allowedIP: "203.0.113.7"}, logs)
+
+	for i := 0; i < 5; i++ {
+		if code := doAdminIPAllowlistRequest(r, "192.0.2.1"); code != http.StatusForbidden {
+			t.Fatalf("expected 403, got %d", code)
+		}
+	}
+	doAdminIPAllowlistRequest(r, "192.0.2.2")
+
+	if len(logs.logs) != 2 {
+		t.Errorf("expected one activity log per blocked IP, got %d", len(logs.logs))
+	}
+}
+
+func TestAdminIPAllowlist_CheckerError(t *testing.T) {
+	r := newAdminIPAllowlistTestRouter(fakeAdminIPChecker{err: errors.New("db down")}, &fakeActivityLogRepo{})
+
+	if code := doAdminIPAllowlistRequest(r, "203.0.113.7"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", code)
+	}
+}
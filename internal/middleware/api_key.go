@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader header tempat integrasi mengirim API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator memvalidasi API key (diimplementasikan oleh service.APIKeyService)
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey, ipAddress, scope string) (*domain.APIKey, error)
+}
+
+// APIKeyMiddleware mengautentikasi request dengan header X-API-Key untuk satu scope
+// Dipasang sebelum AuthMiddleware: request tanpa X-API-Key diteruskan apa adanya ke JWT auth,
+// request dengan key valid melewati AuthMiddleware dan RequirePermission (scope sudah dicek di sini)
+func APIKeyMiddleware(authenticator APIKeyAuthenticator, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			c.Next()
+			return
+		}
+
+		key, err := authenticator.Authenticate(c.Request.Context(), rawKey, c.ClientIP(), scope)
+		if err != nil {
+			if errors.Is(err, service.ErrAPIKeyScopeDenied) || errors.Is(err, service.ErrAPIKeyIPDenied) {
+				c.JSON(http.StatusForbidden, responses.ErrorResponse(403, err.Error()))
+			} else {
+				c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, err.Error()))
+			}
+			c.Abort()
+			return
+		}
+
+		// Aksi dengan API key dicatat atas nama admin pembuat key beserta key yang dipakai
+		c.Set("user_id", key.CreatedBy)
+		c.Set("api_key_id", key.ID)
+		c.Set("api_key_name", key.Name)
+
+		c.Next()
+	}
+}
+
+// isAPIKeyAuthenticated cek apakah request sudah diautentikasi oleh APIKeyMiddleware
+func isAPIKeyAuthenticated(c *gin.Context) bool {
+	return c.GetInt("api_key_id") != 0
+}
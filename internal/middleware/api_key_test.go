@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAPIKeyAuthenticator struct {
+	key *domain.APIKey
+	err error
+}
+
+func (f *fakeAPIKeyAuthenticator) Authenticate(ctx context.Context, rawKey, ipAddress, scope string) (*domain.APIKey, error) {
+	return f.key, f.err
+}
+
+func newAPIKeyTestContext(rawKey string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if rawKey != "" {
+		c.Request.Header.Set(APIKeyHeader, rawKey)
+	}
+	return c, w
+}
+
+// TestAPIKeyMiddleware_NoHeaderPassesThrough menguji request tanpa X-API-Key diteruskan ke JWT auth
+func TestAPIKeyMiddleware_NoHeaderPassesThrough(t *testing.T) {
+	c, _ := newAPIKeyTestContext("")
+
+	APIKeyMiddleware(&fakeAPIKeyAuthenticator{err: service.ErrInvalidAPIKey}, domain.ScopeNewsRead)(c)
+
+	if c.IsAborted() || isAPIKeyAuthenticated(c) {
+		t.Error("Expected request without API key to pass through unauthenticated")
+	}
+}
+
+// TestAPIKeyMiddleware_ValidKey menguji key valid mengisi context dan melewati AuthMiddleware
+func TestAPIKeyMiddleware_ValidKey(t *testing.T) {
+	c, w := newAPIKeyTestContext("pmii_abc_def")
+
+	APIKeyMiddleware(&fakeAPIKeyAuthenticator{key: &domain.APIKey{ID: 3, Name: "Portal", CreatedBy: 1}}, domain.ScopeNewsRead)(c)
+	AuthMiddleware()(c)
+
+	if c.IsAborted() {
+		t.Fatalf("Expected request to pass, got status %d", w.Code)
+	}
+	if c.GetInt("user_id") != 1 || c.GetInt("api_key_id") != 3 {
+		t.Errorf("Expected user_id=1 api_key_id=3, got %d / %d", c.GetInt("user_id"), c.GetInt("api_key_id"))
+	}
+}
+
+// TestAPIKeyMiddleware_Rejected menguji key tidak valid (401) dan scope/IP ditolak (403)
+func TestAPIKeyMiddleware_Rejected(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{err: service.ErrInvalidAPIKey, expected: http.StatusUnauthorized},
+		{err: service.ErrAPIKeyScopeDenied, expected: http.StatusForbidden},
+		{err: service.ErrAPIKeyIPDenied, expected: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		c, w := newAPIKeyTestContext("pmii_abc_def")
+
+		APIKeyMiddleware(&fakeAPIKeyAuthenticator{err: tt.err}, domain.ScopeNewsRead)(c)
+
+		if !c.IsAborted() || w.Code != tt.expected {
+			t.Errorf("Expected %d for %v, got %d", tt.expected, tt.err, w.Code)
+		}
+	}
+}
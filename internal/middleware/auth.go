@@ -12,35 +12,63 @@ import (
 // AuthMiddleware memverifikasi JWT token dari header Authorization
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Ambil token dari header Authorization
+		// Request sudah diautentikasi dengan API key oleh APIKeyMiddleware
+		if isAPIKeyAuthenticated(c) {
+			c.Next()
+			return
+		}
+
+		// Ambil token dari header Authorization, atau dari session cookie (mode cookie)
+		var token string
 		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		if authHeader != "" {
+			// Format: "Bearer <token>"
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Format token tidak valid"))
+				c.Abort()
+				return
+			}
+
+			token = parts[1]
+		} else if utils.SessionCookiesEnabled() {
+			token, _ = c.Cookie(utils.SessionCookieName)
+
+			// Cookie dikirim otomatis oleh browser, request yang mengubah data wajib membawa token CSRF
+			if token != "" && !isSafeMethod(c.Request.Method) {
+				csrfCookie, _ := c.Cookie(utils.CSRFCookieName)
+				if !utils.ValidCSRFToken(csrfCookie, c.GetHeader(utils.CSRFHeaderName)) {
+					c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "CSRF token tidak valid"))
+					c.Abort()
+					return
+				}
+			}
+		}
+
+		if token == "" {
 			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak ditemukan"))
 			c.Abort()
 			return
 		}
 
-		// Format: "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Format token tidak valid"))
+		// Validasi token
+		claims, err := utils.ValidateJWT(token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau kadaluarsa"))
 			c.Abort()
 			return
 		}
 
-		token := parts[1]
-
-		// Cek apakah token sudah di-blacklist (logout)
-		if utils.IsBlacklisted(token) {
-			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
+		// Challenge token 2FA hanya berlaku untuk endpoint verifikasi login
+		if claims.Purpose != "" {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau kadaluarsa"))
 			c.Abort()
 			return
 		}
 
-		// Validasi token
-		claims, err := utils.ValidateJWT(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau kadaluarsa"))
+		// Cek apakah token sudah dicabut (logout, ganti password, user dinonaktifkan)
+		if utils.IsTokenRevoked(claims) {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse(401, "Token tidak valid atau sesi telah berakhir"))
 			c.Abort()
 			return
 		}
@@ -48,21 +76,22 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Set user info ke context untuk digunakan di handler
 		c.Set("user_id", claims.UserID)
 		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+
+		// Token impersonasi: simpan admin yang sebenarnya untuk audit dan BlockImpersonation
+		if claims.ImpersonatorID != 0 {
+			c.Set("impersonator_id", claims.ImpersonatorID)
+		}
 
 		c.Next()
 	}
 }
 
-// AdminOnly middleware untuk route yang hanya boleh diakses admin
-func AdminOnly() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("user_role")
-		if !exists || role != "1" {
-			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "Akses ditolak. Hanya admin yang diizinkan"))
-			c.Abort()
-			return
-		}
-
-		c.Next()
+// isSafeMethod mengecek method yang tidak mengubah data (tidak butuh CSRF token)
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
 	}
+	return false
 }
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func newCookieAuthTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	utils.InitRevocationStore(noopRevocationBackend{})
+	utils.InitSessionCookies(utils.SessionCookieConfig{Enabled: true, Secure: true, SameSite: http.SameSiteLaxMode})
+	t.Cleanup(func() { utils.InitSessionCookies(utils.SessionCookieConfig{}) })
+
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/v1/users/me/posts", AuthMiddleware(), ok)
+	r.POST("/v1/posts", AuthMiddleware(), ok)
+	return r
+}
+
+func doCookieRequest(r *gin.Engine, method, path, accessToken, csrfCookie, csrfHeader string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.AddCookie(&http.Cookie{Name: utils.SessionCookieName, Value: accessToken})
+	if csrfCookie != "" {
+		req.AddCookie(&http.Cookie{Name: utils.CSRFCookieName, Value: csrfCookie})
+	}
+	if csrfHeader != "" {
+		req.Header.Set(utils.CSRFHeaderName, csrfHeader)
+	}
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestAuthMiddleware_SessionCookie menguji access token dari cookie diterima dan request unsafe wajib CSRF double-submit
+func TestAuthMiddleware_SessionCookie(t *testing.T) {
+	r := newCookieAuthTestRouter(t)
+	token, _ := utils.GenerateJWT(2, "2", "")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		csrfCookie string
+		csrfHeader string
+		want       int
+	}{
+		{"GET tanpa CSRF", http.MethodGet, "/v1/users/me/posts", "", "", http.StatusOK},
+		{"POST dengan CSRF cocok", http.MethodPost, "/v1/posts", "csrf-123", "csrf-123", http.StatusOK},
+		{"POST tanpa header CSRF", http.MethodPost, "/v1/posts", "csrf-123", "", http.StatusForbidden},
+		{"POST dengan CSRF berbeda", http.MethodPost, "/v1/posts", "csrf-123", "csrf-456", http.StatusForbidden},
+		{"POST tanpa cookie CSRF", http.MethodPost, "/v1/posts", "", "csrf-123", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := doCookieRequest(r, tt.method, tt.path, token, tt.csrfCookie, tt.csrfHeader); code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, code)
+			}
+		})
+	}
+}
+
+// TestAuthMiddleware_BearerSkipsCSRF menguji bearer token tidak butuh CSRF karena tidak dikirim otomatis oleh browser
+func TestAuthMiddleware_BearerSkipsCSRF(t *testing.T) {
+	r := newCookieAuthTestRouter(t)
+	token, _ := utils.GenerateJWT(2, "2", "")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/posts", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+}
+
+// TestAuthMiddleware_CookieIgnoredWhenDisabled menguji session cookie diabaikan jika mode cookie tidak aktif
+func TestAuthMiddleware_CookieIgnoredWhenDisabled(t *testing.T) {
+	r := newCookieAuthTestRouter(t)
+	utils.InitSessionCookies(utils.SessionCookieConfig{})
+	token, _ := utils.GenerateJWT(2, "2", "")
+
+	if code := doCookieRequest(r, http.MethodGet, "/v1/users/me/posts", token, "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", code)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// BlockImpersonation menolak request dengan token impersonasi
+// Dipasang pada aksi berbahaya (ganti password, hapus user, 2FA, dsb.) yang hanya boleh dilakukan pemilik akun
+// Harus dipanggil setelah AuthMiddleware() karena depend on impersonator_id di context
+func BlockImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetInt("impersonator_id") != 0 {
+			c.JSON(http.StatusForbidden, responses.ErrorResponse(403, "Aksi ini tidak diizinkan selama impersonasi"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ImpersonationAudit mencatat setiap request yang dilakukan dengan token impersonasi ke activity log,
+// atas nama user yang diimpersonasi dan admin yang sebenarnya
+// Dipasang global, impersonator_id baru tersedia setelah AuthMiddleware jalan di handler chain
+func ImpersonationAudit(activityLogRepo repository.ActivityLogRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID := c.GetInt("impersonator_id")
+		if impersonatorID == 0 {
+			return
+		}
+
+		ipAddress := c.ClientIP()
+		userAgent := c.GetHeader("User-Agent")
+		description := fmt.Sprintf("Request saat impersonasi: %s %s", c.Request.Method, c.Request.URL.Path)
+
+		log := &domain.ActivityLog{
+			UserID:      c.GetInt("user_id"),
+			ActionType:  domain.ActionImpersonate,
+			Module:      domain.ModuleAuth,
+			Description: &description,
+			NewValue: map[string]any{
+				"method":          c.Request.Method,
+				"path":            c.Request.URL.Path,
+				"route":           c.FullPath(),
+				"status":          c.Writer.Status(),
+				"impersonator_id": impersonatorID,
+			},
+			IPAddress:      &ipAddress,
+			UserAgent:      &userAgent,
+			ImpersonatorID: &impersonatorID,
+		}
+
+		// Ignore error - logging should not affect main operation
+		_ = activityLogRepo.Create(c.Request.Context(), log)
+	}
+}
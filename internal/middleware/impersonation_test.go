@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeActivityLogRepo struct {
+	logs []*domain.ActivityLog
+}
+
+func (f *fakeActivityLogRepo) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeActivityLogRepo) Create(ctx context.Context, log *domain.ActivityLog) error {
+	f.logs = append(f.logs, log)
+	return nil
+}
+
+func (f *fakeActivityLogRepo) StreamActivityLogs(filter repository.ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error {
+	return nil
+}
+
+// noopRevocationBackend tidak pernah mencabut token
+type noopRevocationBackend struct{}
+
+func (noopRevocationBackend) SaveRevokedToken(jti string, userID int, expiresAt time.Time) error {
+	return nil
+}
+func (noopRevocationBackend) IsTokenRevoked(jti string) (bool, error)            { return false, nil }
+func (noopRevocationBackend) DeleteExpiredTokens() error                         { return nil }
+func (noopRevocationBackend) GetTokensValidAfter(userID int) (*time.Time, error) { return nil, nil }
+func (noopRevocationBackend) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	return nil
+}
+
+func newImpersonationTestRouter(activityLogRepo *fakeActivityLogRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	utils.InitRevocationStore(noopRevocationBackend{})
+
+	r := gin.New()
+	r.Use(ImpersonationAudit(activityLogRepo))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/v1/users/me/posts", AuthMiddleware(), ok)
+	r.POST("/v1/auth/change-password", AuthMiddleware(), BlockImpersonation(), ok)
+	return r
+}
+
+func doImpersonationRequest(r *gin.Engine, method, path, token string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+// TestImpersonation_RequestsAttributedToBothUsers menguji setiap request dengan token impersonasi dicatat atas nama user dan admin
+func TestImpersonation_RequestsAttributedToBothUsers(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newImpersonationTestRouter(logs)
+
+	token, err := utils.GenerateImpersonationToken(2, "2", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code := doImpersonationRequest(r, http.MethodGet, "/v1/users/me/posts", token); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if len(logs.logs) != 1 {
+		t.Fatalf("expected 1 activity log, got %d", len(logs.logs))
+	}
+	log := logs.logs[0]
+	if log.UserID != 2 || log.ImpersonatorID == nil || *log.ImpersonatorID != 1 || log.ActionType != domain.ActionImpersonate {
+		t.Errorf("log harus atas nama user 2 dengan impersonator 1, got %+v", log)
+	}
+	if log.NewValue["route"] != "/v1/users/me/posts" || log.NewValue["status"] != http.StatusOK {
+		t.Errorf("log harus mencatat route dan status, got %v", log.NewValue)
+	}
+}
+
+// TestImpersonation_BlocksDangerousActions menguji aksi berbahaya ditolak saat impersonasi tapi tetap dicatat
+func TestImpersonation_BlocksDangerousActions(t *testing.T) {
+	logs := &fakeActivityLogRepo{}
+	r := newImpersonationTestRouter(logs)
+
+	impersonationToken, _ := utils.GenerateImpersonationToken(2, "2", 1)
+	if code := doImpersonationRequest(r, http.MethodPost, "/v1/auth/change-password", impersonationToken); code != http.StatusForbidden {
+		t.Fatalf("expected 403 saat impersonasi, got %d", code)
+	}
+	if len(logs.logs) != 1 || logs.logs[0].NewValue["status"] != http.StatusForbidden {
+		t.Errorf("percobaan aksi yang ditolak tetap harus dicatat, got %+v", logs.logs)
+	}
+
+	// Token biasa tidak terpengaruh dan tidak dicatat oleh audit impersonasi
+	normalToken, _ := utils.GenerateJWT(2, "2", "")
+	if code := doImpersonationRequest(r, http.MethodPost, "/v1/auth/change-password", normalToken); code != http.StatusOK {
+		t.Fatalf("expected 200 untuk token biasa, got %d", code)
+	}
+	if len(logs.logs) != 1 {
+		t.Errorf("request tanpa impersonasi tidak boleh dicatat audit impersonasi, got %d logs", len(logs.logs))
+	}
+}
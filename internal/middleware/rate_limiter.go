@@ -1,90 +1,252 @@
 package middleware
 
 import (
+	"fmt"
+	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-// Visitor menyimpan rate limiter per IP
-type Visitor struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+// Nama policy rate limit, dipasang per route group di routes
+const (
+	RateLimitLogin              = "login"               // Login, refresh, 2FA, SSO
+	RateLimitPasswordReset      = "password-reset"      // Lupa/reset password dan undangan
+	RateLimitPublicRead         = "public-read"         // GET publik tanpa autentikasi
+	RateLimitPublicWrite        = "public-write"        // POST publik tanpa autentikasi
+	RateLimitCSPReport          = "csp-report"          // Laporan pelanggaran CSP dari browser
+	RateLimitAdminGate          = "admin-gate"          // Semua request admin per IP, sebelum allowlist dan autentikasi
+	RateLimitAuthenticatedRead  = "authenticated-read"  // GET dengan JWT atau API key
+	RateLimitAuthenticatedWrite = "authenticated-write" // POST/PUT/DELETE dengan JWT atau API key
+	RateLimitUpload             = "upload"              // Request multipart dengan file
+)
+
+// RateLimitPolicy batas request per window (fixed window) untuk satu policy
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultRateLimitPolicies policy bawaan, bisa ditimpa lewat RATE_LIMIT_POLICIES
+func DefaultRateLimitPolicies() []RateLimitPolicy {
+	return []RateLimitPolicy{
+		{Name: RateLimitLogin, Limit: 60, Window: time.Minute},
+		{Name: RateLimitPasswordReset, Limit: 5, Window: time.Minute},
+		{Name: RateLimitPublicRead, Limit: 300, Window: time.Minute},
+		{Name: RateLimitPublicWrite, Limit: 30, Window: time.Minute},
+		{Name: RateLimitCSPReport, Limit: 10, Window: time.Minute},
+		{Name: RateLimitAdminGate, Limit: 600, Window: time.Minute},
+		{Name: RateLimitAuthenticatedRead, Limit: 600, Window: time.Minute},
+		{Name: RateLimitAuthenticatedWrite, Limit: 120, Window: time.Minute},
+		{Name: RateLimitUpload, Limit: 20, Window: time.Minute},
+	}
 }
 
-// RateLimiter menyimpan semua visitor
+// ValidateRateLimitPolicyNames mengembalikan error jika ada nama policy yang tidak dikenal
+// Dipanggil saat startup agar salah ketik di RATE_LIMIT_POLICIES tidak diam-diam memakai limit bawaan
+func ValidateRateLimitPolicyNames(names []string) error {
+	known := make(map[string]bool)
+	var available []string
+	for _, policy := range DefaultRateLimitPolicies() {
+		known[policy.Name] = true
+		available = append(available, policy.Name)
+	}
+
+	var unknown []string
+	for _, name := range names {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown rate limit policy %s (available: %s)", strings.Join(unknown, ", "), strings.Join(available, ", "))
+}
+
+// RateLimitStore penyimpanan counter rate limit
+// Implementasi: MemoryRateLimitStore (satu proses) atau repository.RateLimitRepository (Postgres, antar replica)
+type RateLimitStore interface {
+	Increment(key string, windowStart, expiresAt time.Time) (int, error)
+	DeleteExpired() error
+}
+
+// RateLimiter menerapkan policy rate limit dengan store bersama
 type RateLimiter struct {
-	visitors map[string]*Visitor
-	mu       sync.RWMutex
-	rate     rate.Limit
-	burst    int
+	store    RateLimitStore
+	policies map[string]RateLimitPolicy
 }
 
-// NewRateLimiter membuat instance rate limiter baru
-// r = jumlah request per detik, b = burst capacity
-func NewRateLimiter(r rate.Limit, b int) *RateLimiter {
+// NewRateLimiter membuat instance rate limiter dengan daftar policy
+func NewRateLimiter(store RateLimitStore, policies []RateLimitPolicy) *RateLimiter {
 	rl := &RateLimiter{
-		visitors: make(map[string]*Visitor),
-		rate:     r,
-		burst:    b,
+		store:    store,
+		policies: make(map[string]RateLimitPolicy, len(policies)),
+	}
+	for _, policy := range policies {
+		rl.policies[policy.Name] = policy
 	}
 
-	// Cleanup visitor yang sudah tidak aktif setiap 5 menit
-	go rl.cleanupVisitors()
+	// Cleanup counter yang sudah lewat window setiap 5 menit
+	go rl.cleanupExpired()
 
 	return rl
 }
 
-// getVisitor mendapatkan atau membuat visitor baru untuk IP
-func (rl *RateLimiter) getVisitor(ip string) *rate.Limiter {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// cleanupExpired menghapus counter yang window-nya sudah lewat
+func (rl *RateLimiter) cleanupExpired() {
+	for {
+		time.Sleep(5 * time.Minute)
 
-	visitor, exists := rl.visitors[ip]
-	if !exists {
-		limiter := rate.NewLimiter(rl.rate, rl.burst)
-		rl.visitors[ip] = &Visitor{
-			limiter:  limiter,
-			lastSeen: time.Now(),
+		if err := rl.store.DeleteExpired(); err != nil {
+			log.Printf("[WARN] rate limit: failed to delete expired counters: %v", err)
 		}
-		return limiter
 	}
-
-	visitor.lastSeen = time.Now()
-	return visitor.limiter
 }
 
-// cleanupVisitors menghapus visitor yang tidak aktif lebih dari 5 menit
-func (rl *RateLimiter) cleanupVisitors() {
-	for {
-		time.Sleep(5 * time.Minute)
+// policy mengambil policy berdasarkan nama, panic saat setup route jika nama salah
+func (rl *RateLimiter) policy(name string) RateLimitPolicy {
+	policy, ok := rl.policies[name]
+	if !ok {
+		panic(fmt.Sprintf("rate limit policy %q is not defined", name))
+	}
+	return policy
+}
 
-		rl.mu.Lock()
-		for ip, visitor := range rl.visitors {
-			if time.Since(visitor.lastSeen) > 5*time.Minute {
-				delete(rl.visitors, ip)
-			}
+// Limit adalah middleware rate limit untuk satu policy
+// Pasang setelah AuthMiddleware/APIKeyMiddleware agar limit dihitung per user, bukan per IP
+func (rl *RateLimiter) Limit(name string) gin.HandlerFunc {
+	policy := rl.policy(name)
+	return func(c *gin.Context) {
+		if !rl.allow(c, policy) {
+			c.Abort()
+			return
 		}
-		rl.mu.Unlock()
+		c.Next()
 	}
 }
 
-// Limit adalah middleware untuk rate limiting
-func (rl *RateLimiter) Limit() gin.HandlerFunc {
+// LimitByMethod memilih policy baca (GET/HEAD/OPTIONS) atau tulis sesuai method request
+func (rl *RateLimiter) LimitByMethod(readName, writeName string) gin.HandlerFunc {
+	read, write := rl.policy(readName), rl.policy(writeName)
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := rl.getVisitor(ip)
+		policy := write
+		if isSafeMethod(c.Request.Method) {
+			policy = read
+		}
 
-		if !limiter.Allow() {
-			c.JSON(http.StatusTooManyRequests, responses.ErrorResponse(429, "Terlalu banyak request. Silakan coba lagi nanti"))
+		if !rl.allow(c, policy) {
 			c.Abort()
 			return
 		}
-
 		c.Next()
 	}
 }
+
+// allow menghitung request ke window saat ini, mengirim header RateLimit-* dan response 429 jika melebihi limit
+func (rl *RateLimiter) allow(c *gin.Context, policy RateLimitPolicy) bool {
+	now := time.Now().UTC()
+	windowStart := now.Truncate(policy.Window)
+	resetAt := windowStart.Add(policy.Window)
+
+	count, err := rl.store.Increment(policy.Name+":"+rateLimitSubject(c), windowStart, resetAt)
+	if err != nil {
+		// Store bermasalah tidak boleh membuat seluruh API down
+		log.Printf("[WARN] rate limit: failed to increment counter for policy %s: %v", policy.Name, err)
+		return true
+	}
+
+	remaining := policy.Limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	resetSeconds := int(resetAt.Sub(now).Seconds() + 0.999) // Dibulatkan ke atas
+	setRateLimitHeaders(c, policy, remaining, resetSeconds)
+
+	if count > policy.Limit {
+		c.Header("Retry-After", strconv.Itoa(resetSeconds))
+		c.JSON(http.StatusTooManyRequests, responses.ErrorResponse(429, "Terlalu banyak request. Silakan coba lagi nanti"))
+		return false
+	}
+	return true
+}
+
+// setRateLimitHeaders mengirim header RateLimit-* (draft IETF httpapi-ratelimit-headers)
+// Jika beberapa policy berlaku di satu route, yang ditampilkan adalah policy dengan sisa kuota paling sedikit
+func setRateLimitHeaders(c *gin.Context, policy RateLimitPolicy, remaining, resetSeconds int) {
+	if current, exists := c.Get("ratelimit_remaining"); exists && current.(int) <= remaining {
+		return
+	}
+	c.Set("ratelimit_remaining", remaining)
+
+	c.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(resetSeconds))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;name=%q", policy.Limit, int(policy.Window.Seconds()), policy.Name))
+}
+
+// rateLimitSubject menentukan key limit: API key, user ID (jika sudah terautentikasi), atau IP
+func rateLimitSubject(c *gin.Context) string {
+	if apiKeyID := c.GetInt("api_key_id"); apiKeyID != 0 {
+		return "api-key:" + strconv.Itoa(apiKeyID)
+	}
+	if userID := c.GetInt("user_id"); userID != 0 {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitCounter counter satu key pada satu window
+type rateLimitCounter struct {
+	windowStart time.Time
+	expiresAt   time.Time
+	count       int
+}
+
+// MemoryRateLimitStore menyimpan counter di memory, limit hanya berlaku per proses
+type MemoryRateLimitStore struct {
+	counters map[string]*rateLimitCounter
+	mu       sync.Mutex
+}
+
+// NewMemoryRateLimitStore membuat store rate limit in-memory
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{counters: make(map[string]*rateLimitCounter)}
+}
+
+// Increment menambah counter key, counter direset saat masuk window baru
+func (s *MemoryRateLimitStore) Increment(key string, windowStart, expiresAt time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counter, exists := s.counters[key]
+	if !exists || !counter.windowStart.Equal(windowStart) {
+		counter = &rateLimitCounter{windowStart: windowStart, expiresAt: expiresAt}
+		s.counters[key] = counter
+	}
+	counter.count++
+
+	return counter.count, nil
+}
+
+// DeleteExpired menghapus counter yang window-nya sudah lewat
+func (s *MemoryRateLimitStore) DeleteExpired() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, counter := range s.counters {
+		if now.After(counter.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+	return nil
+}
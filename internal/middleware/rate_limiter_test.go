@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingRateLimitStore mensimulasikan store (Postgres) yang sedang down
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Increment(key string, windowStart, expiresAt time.Time) (int, error) {
+	return 0, errors.New("db down")
+}
+func (failingRateLimitStore) DeleteExpired() error { return nil }
+
+func newRateLimitTestRouter(store RateLimitStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	rl := NewRateLimiter(store, []RateLimitPolicy{
+		{Name: RateLimitPublicRead, Limit: 3, Window: time.Hour},
+		{Name: RateLimitPublicWrite, Limit: 1, Window: time.Hour},
+	})
+
+	r := gin.New()
+	// Simulasi AuthMiddleware: header X-Test-User mengisi user_id
+	r.Use(func(c *gin.Context) {
+		if userID, err := strconv.Atoi(c.GetHeader("X-Test-User")); err == nil {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	r.Use(rl.LimitByMethod(RateLimitPublicRead, RateLimitPublicWrite))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/v1/news", ok)
+	r.POST("/v1/csp-report", ok)
+	return r
+}
+
+func doRateLimitRequest(r *gin.Engine, method, path, ip, userID string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_HeadersAndRetryAfter(t *testing.T) {
+	r := newRateLimitTestRouter(NewMemoryRateLimitStore())
+
+	for i := 1; i <= 3; i++ {
+		w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, w.Code)
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(3-i) {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %d", i, got, 3-i)
+		}
+		if got := w.Header().Get("RateLimit-Limit"); got != "3" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 3", i, got)
+		}
+	}
+
+	w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after limit, got %d", w.Code)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 3600 {
+		t.Errorf("expected Retry-After within window, got %q", w.Header().Get("Retry-After"))
+	}
+	if got := w.Header().Get("RateLimit-Policy"); got != `3;w=3600;name="public-read"` {
+		t.Errorf("RateLimit-Policy = %q", got)
+	}
+}
+
+func TestRateLimiter_KeyedByUserOrIP(t *testing.T) {
+	r := newRateLimitTestRouter(NewMemoryRateLimitStore())
+
+	// IP lain dan user yang login dari IP yang sama punya kuota sendiri
+	for i := 0; i < 3; i++ {
+		doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", "")
+	}
+	if w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.2", ""); w.Code != http.StatusOK {
+		t.Errorf("other IP: expected 200, got %d", w.Code)
+	}
+	if w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", "7"); w.Code != http.StatusOK {
+		t.Errorf("authenticated user on same IP: expected 200, got %d", w.Code)
+	}
+
+	// Kuota user mengikuti user ID walaupun IP berganti
+	for i := 0; i < 2; i++ {
+		doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.3", "7")
+	}
+	if w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.4", "7"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("same user from new IP: expected 429, got %d", w.Code)
+	}
+}
+
+func TestRateLimiter_PolicyByMethod(t *testing.T) {
+	r := newRateLimitTestRouter(NewMemoryRateLimitStore())
+
+	if w := doRateLimitRequest(r, http.MethodPost, "/v1/csp-report", "10.0.0.1", ""); w.Code != http.StatusOK {
+		t.Fatalf("first write: expected 200, got %d", w.Code)
+	}
+	if w := doRateLimitRequest(r, http.MethodPost, "/v1/csp-report", "10.0.0.1", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second write: expected 429 from public-write policy, got %d", w.Code)
+	}
+	// Kuota baca terpisah dari kuota tulis
+	if w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", ""); w.Code != http.StatusOK {
+		t.Errorf("read after write limit: expected 200, got %d", w.Code)
+	}
+}
+
+func TestRateLimiter_StoreErrorFailsOpen(t *testing.T) {
+	r := newRateLimitTestRouter(failingRateLimitStore{})
+
+	for i := 0; i < 5; i++ {
+		if w := doRateLimitRequest(r, http.MethodGet, "/v1/news", "10.0.0.1", ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200 when store is down, got %d", i, w.Code)
+		}
+	}
+}
+
+func TestRateLimiter_UnknownPolicyPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for undefined policy")
+		}
+	}()
+	NewRateLimiter(NewMemoryRateLimitStore(), nil).Limit("missing")
+}
+
+func TestValidateRateLimitPolicyNames(t *testing.T) {
+	if err := ValidateRateLimitPolicyNames([]string{RateLimitLogin, RateLimitUpload}); err != nil {
+		t.Errorf("expected known policies to be accepted, got %v", err)
+	}
+	if err := ValidateRateLimitPolicyNames([]string{RateLimitLogin, "public-raed"}); err == nil {
+		t.Error("expected error for misspelled policy name")
+	}
+}
@@ -5,263 +5,88 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-// TestRequireRole_ValidRolePasses menguji admin user akses admin route
-func TestRequireRole_ValidRolePasses(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Set user_role di context (dari AuthMiddleware)
-	c.Set("user_role", "1") // Admin role
-
-	// Handler yang akan dipanggil jika middleware pass
-	handlerCalled := false
-	testHandler := func(c *gin.Context) {
-		handlerCalled = true
-		c.JSON(200, gin.H{"message": "success"})
-	}
-
-	// Jalankan middleware
-	middleware := RequireRole("1")
-	middleware(c)
-
-	// Jika middleware pass, lanjut ke handler
-	if !c.IsAborted() {
-		testHandler(c)
-	}
-
-	// Validasi
-	if c.IsAborted() {
-		t.Error("Expected middleware to pass, but it aborted")
-	}
-	if !handlerCalled {
-		t.Error("Expected handler to be called")
-	}
-	if w.Code != 200 {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
+// mockPermissionBackend adalah mock untuk utils.PermissionBackend
+type mockPermissionBackend struct {
+	roles map[int][]string
 }
 
-// TestRequireRole_InvalidRoleBlocked menguji user biasa akses admin route (harus 403)
-func TestRequireRole_InvalidRoleBlocked(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Set user_role di context (User role, bukan Admin)
-	c.Set("user_role", "2") // User role
-
-	// Handler yang seharusnya TIDAK dipanggil
-	handlerCalled := false
-	testHandler := func(_ *gin.Context) {
-		handlerCalled = true
-	}
-
-	// Jalankan middleware
-	middleware := RequireRole("1") // Require Admin
-	middleware(c)
-
-	// Middleware harus abort
-	if !c.IsAborted() {
-		testHandler(c)
-	}
-
-	// Validasi
-	if !c.IsAborted() {
-		t.Error("Expected middleware to abort, but it passed")
-	}
-	if handlerCalled {
-		t.Error("Expected handler NOT to be called")
-	}
-	if w.Code != http.StatusForbidden {
-		t.Errorf("Expected status 403, got %d", w.Code)
+func (m *mockPermissionBackend) GetRolePermissionKeys(roleID int) (string, []string, error) {
+	permissions, ok := m.roles[roleID]
+	if !ok {
+		return "", nil, nil
 	}
+	return "role", permissions, nil
 }
 
-// TestRequireRole_NoAuthContext menguji request tanpa user_role di context (harus 401)
-func TestRequireRole_NoAuthContext(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// TIDAK set user_role di context (simulasi belum auth)
-
-	// Handler yang seharusnya TIDAK dipanggil
-	handlerCalled := false
-	testHandler := func(_ *gin.Context) {
-		handlerCalled = true
-	}
-
-	// Jalankan middleware
-	middleware := RequireRole("1")
-	middleware(c)
-
-	// Middleware harus abort
-	if !c.IsAborted() {
-		testHandler(c)
-	}
-
-	// Validasi
-	if !c.IsAborted() {
-		t.Error("Expected middleware to abort, but it passed")
-	}
-	if handlerCalled {
-		t.Error("Expected handler NOT to be called")
-	}
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
+func initTestPermissionStore() {
+	utils.InitPermissionStore(&mockPermissionBackend{roles: map[int][]string{
+		1: {"posts.publish", "ads.manage"},
+		2: {"posts.create"},
+	}})
 }
 
-// TestRequireRole_WrongRoleType menguji context value bukan string (harus 500)
-func TestRequireRole_WrongRoleType(t *testing.T) {
-	// Setup
+// TestRequirePermission_Granted menguji role yang memiliki permission boleh lanjut
+func TestRequirePermission_Granted(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	initTestPermissionStore()
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("user_role", "1")
 
-	// Set user_role dengan tipe yang salah (int bukan string)
-	c.Set("user_role", 1) // Tipe salah: int bukan string
-
-	// Handler yang seharusnya TIDAK dipanggil
-	handlerCalled := false
-	testHandler := func(_ *gin.Context) {
-		handlerCalled = true
-	}
-
-	// Jalankan middleware
-	middleware := RequireRole("1")
-	middleware(c)
-
-	// Middleware harus abort
-	if !c.IsAborted() {
-		testHandler(c)
-	}
+	RequirePermission("ads.manage")(c)
 
-	// Validasi
-	if !c.IsAborted() {
-		t.Error("Expected middleware to abort, but it passed")
-	}
-	if handlerCalled {
-		t.Error("Expected handler NOT to be called")
-	}
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status 500, got %d", w.Code)
+	if c.IsAborted() {
+		t.Errorf("Expected middleware to pass, got status %d", w.Code)
 	}
 }
 
-// TestRequireAnyRole_AdminAccess menguji RequireAnyRole dengan admin role
-func TestRequireAnyRole_AdminAccess(t *testing.T) {
-	// Setup
+// TestRequirePermission_Denied menguji role tanpa permission ditolak (403)
+func TestRequirePermission_Denied(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	w := httptest.NewRecorder()
-	c, _ := gin.CreateTestContext(w)
-
-	// Set admin role
-	c.Set("user_role", "1")
+	initTestPermissionStore()
 
-	// Handler
-	handlerCalled := false
-	testHandler := func(c *gin.Context) {
-		handlerCalled = true
-		c.JSON(200, gin.H{"message": "success"})
-	}
-
-	// Jalankan middleware - izinkan admin dan user
-	middleware := RequireAnyRole("1", "2")
-	middleware(c)
+	for _, role := range []string{"2", "99", "abc"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("user_role", role)
 
-	if !c.IsAborted() {
-		testHandler(c)
-	}
+		RequirePermission("posts.publish")(c)
 
-	// Validasi
-	if c.IsAborted() {
-		t.Error("Expected middleware to pass for admin role")
-	}
-	if !handlerCalled {
-		t.Error("Expected handler to be called")
-	}
-	if w.Code != 200 {
-		t.Errorf("Expected status 200, got %d", w.Code)
+		if !c.IsAborted() || w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 for role %q, got %d", role, w.Code)
+		}
 	}
 }
 
-// TestRequireAnyRole_UserAccess menguji RequireAnyRole dengan user role
-func TestRequireAnyRole_UserAccess(t *testing.T) {
-	// Setup
+// TestRequirePermission_NoAuthContext menguji request tanpa user_role di context (harus 401)
+func TestRequirePermission_NoAuthContext(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	initTestPermissionStore()
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 
-	// Set user role
-	c.Set("user_role", "2")
-
-	// Handler
-	handlerCalled := false
-	testHandler := func(c *gin.Context) {
-		handlerCalled = true
-		c.JSON(200, gin.H{"message": "success"})
-	}
-
-	// Jalankan middleware - izinkan admin dan user
-	middleware := RequireAnyRole("1", "2")
-	middleware(c)
-
-	if !c.IsAborted() {
-		testHandler(c)
-	}
+	RequirePermission("posts.publish")(c)
 
-	// Validasi
-	if c.IsAborted() {
-		t.Error("Expected middleware to pass for user role")
-	}
-	if !handlerCalled {
-		t.Error("Expected handler to be called")
-	}
-	if w.Code != 200 {
-		t.Errorf("Expected status 200, got %d", w.Code)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
 	}
 }
 
-// TestRequireAnyRole_UnauthorizedRole menguji RequireAnyRole dengan role yang tidak diizinkan
-func TestRequireAnyRole_UnauthorizedRole(t *testing.T) {
-	// Setup
+// TestRequirePermission_WrongRoleType menguji context value bukan string (harus 500)
+func TestRequirePermission_WrongRoleType(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	initTestPermissionStore()
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Set("user_role", 1) // Tipe salah: int bukan string
 
-	// Set role yang tidak ada dalam allowed list
-	c.Set("user_role", "3") // Role tidak dikenal
-
-	// Handler
-	handlerCalled := false
-	testHandler := func(_ *gin.Context) {
-		handlerCalled = true
-	}
-
-	// Jalankan middleware - hanya izinkan admin
-	middleware := RequireAnyRole("1")
-	middleware(c)
-
-	if !c.IsAborted() {
-		testHandler(c)
-	}
+	RequirePermission("posts.publish")(c)
 
-	// Validasi
-	if !c.IsAborted() {
-		t.Error("Expected middleware to abort for unauthorized role")
-	}
-	if handlerCalled {
-		t.Error("Expected handler NOT to be called")
-	}
-	if w.Code != http.StatusForbidden {
-		t.Errorf("Expected status 403, got %d", w.Code)
+	if !c.IsAborted() || w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected abort with status 500, got %d", w.Code)
 	}
 }
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPermissionsPolicy mematikan fitur browser yang tidak dipakai aplikasi
+const DefaultPermissionsPolicy = "accelerometer=(), camera=(), geolocation=(), gyroscope=(), magnetometer=(), microphone=(), payment=(), usb=()"
+
+// cspReportGroup nama endpoint Reporting API untuk directive report-to
+const cspReportGroup = "csp-endpoint"
+
+// SecurityHeadersConfig konfigurasi header keamanan untuk satu route group
+// Field kosong berarti header tersebut tidak dikirim
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            int    // Detik, 0 = tidak mengirim Strict-Transport-Security
+	ContentSecurityPolicy string // Tanpa report-uri, ditambahkan otomatis dari CSPReportURI
+	CSPReportOnly         bool   // Kirim sebagai Content-Security-Policy-Report-Only (tidak memblokir)
+	CSPReportURI          string // Endpoint penerima laporan pelanggaran, mis. /v1/csp-report
+	FrameOptions          string // DENY atau SAMEORIGIN
+	ReferrerPolicy        string
+	PermissionsPolicy     string
+	CacheControl          string // Mis. no-store untuk response admin yang sensitif
+}
+
+// SecurityHeaders middleware untuk mengirim header keamanan (HSTS, CSP, frame options, dsb.)
+// Bisa dipasang global lalu dipasang ulang di route group dengan policy lebih ketat, header group menimpa header global
+func SecurityHeaders(cfg SecurityHeadersConfig) gin.HandlerFunc {
+	// Header dihitung sekali saat setup, bukan per request
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", cfg.HSTSMaxAge)
+	}
+
+	cspHeader, otherCSPHeader := "Content-Security-Policy", "Content-Security-Policy-Report-Only"
+	if cfg.CSPReportOnly {
+		cspHeader, otherCSPHeader = otherCSPHeader, cspHeader
+	}
+
+	csp := strings.TrimRight(strings.TrimSpace(cfg.ContentSecurityPolicy), ";")
+	reportingEndpoints := ""
+	if csp != "" && cfg.CSPReportURI != "" {
+		csp += fmt.Sprintf("; report-uri %s; report-to %s", cfg.CSPReportURI, cspReportGroup)
+		reportingEndpoints = fmt.Sprintf("%s=%q", cspReportGroup, cfg.CSPReportURI)
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+
+		h.Set("X-Content-Type-Options", "nosniff")
+		setOrDeleteHeader(h, "Strict-Transport-Security", hsts)
+		setOrDeleteHeader(h, "X-Frame-Options", cfg.FrameOptions)
+		setOrDeleteHeader(h, "Referrer-Policy", cfg.ReferrerPolicy)
+		setOrDeleteHeader(h, "Permissions-Policy", cfg.PermissionsPolicy)
+		setOrDeleteHeader(h, "Reporting-Endpoints", reportingEndpoints)
+		if cfg.CacheControl != "" {
+			h.Set("Cache-Control", cfg.CacheControl)
+		}
+
+		// Hanya satu mode CSP yang aktif per response (group bisa beda mode dengan global)
+		h.Del(otherCSPHeader)
+		setOrDeleteHeader(h, cspHeader, csp)
+
+		c.Next()
+	}
+}
+
+// setOrDeleteHeader mengirim header jika value diisi, atau menghapus header dari middleware sebelumnya
+func setOrDeleteHeader(h http.Header, key, value string) {
+	if value == "" {
+		h.Del(key)
+		return
+	}
+	h.Set(key, value)
+}
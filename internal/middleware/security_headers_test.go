@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSecurityHeadersTestRouter(reportOnlyAdmin bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(SecurityHeaders(SecurityHeadersConfig{
+		HSTSMaxAge:            31536000,
+		ContentSecurityPolicy: "default-src 'self';",
+		CSPReportURI:          "/v1/csp-report",
+		FrameOptions:          "SAMEORIGIN",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     DefaultPermissionsPolicy,
+	}))
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/v1/posts", ok)
+
+	admin := r.Group("/v1/admin")
+	admin.Use(SecurityHeaders(SecurityHeadersConfig{
+		ContentSecurityPolicy: "default-src 'none'",
+		CSPReportOnly:         reportOnlyAdmin,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		CacheControl:          "no-store",
+	}))
+	admin.GET("/dashboard", ok)
+	return r
+}
+
+func doSecurityHeadersRequest(r *gin.Engine, path string) http.Header {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Header()
+}
+
+func TestSecurityHeaders_PublicDefaults(t *testing.T) {
+	h := doSecurityHeadersRequest(newSecurityHeadersTestRouter(false), "/v1/posts")
+
+	expected := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"X-Frame-Options":           "SAMEORIGIN",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Permissions-Policy":        DefaultPermissionsPolicy,
+		"Content-Security-Policy":   "default-src 'self'; report-uri /v1/csp-report; report-to csp-endpoint",
+		"Reporting-Endpoints":       `csp-endpoint="/v1/csp-report"`,
+	}
+	for key, value := range expected {
+		if got := h.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if got := h.Get("Content-Security-Policy-Report-Only"); got != "" {
+		t.Errorf("unexpected report-only header %q", got)
+	}
+}
+
+func TestSecurityHeaders_GroupOverridesGlobal(t *testing.T) {
+	h := doSecurityHeadersRequest(newSecurityHeadersTestRouter(false), "/v1/admin/dashboard")
+
+	if got := h.Get("Content-Security-Policy"); got != "default-src 'none'" {
+		t.Errorf("Content-Security-Policy = %q, want admin policy", got)
+	}
+	if got := h.Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want DENY", got)
+	}
+	if got := h.Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	// Field kosong di config group menghapus header dari config global
+	for _, key := range []string{"Strict-Transport-Security", "Permissions-Policy", "Reporting-Endpoints"} {
+		if got := h.Get(key); got != "" {
+			t.Errorf("%s = %q, want removed", key, got)
+		}
+	}
+}
+
+func TestSecurityHeaders_ReportOnly(t *testing.T) {
+	h := doSecurityHeadersRequest(newSecurityHeadersTestRouter(true), "/v1/admin/dashboard")
+
+	if got := h.Get("Content-Security-Policy-Report-Only"); got != "default-src 'none'" {
+		t.Errorf("Content-Security-Policy-Report-Only = %q, want admin policy", got)
+	}
+	// Header enforce dari middleware global harus dihapus agar policy publik tidak ikut berlaku
+	if got := h.Get("Content-Security-Policy"); got != "" {
+		t.Errorf("Content-Security-Policy = %q, want removed in report-only mode", got)
+	}
+}
@@ -1,25 +1,30 @@
 package middleware
 
 import (
-	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 // VisitorTracker middleware untuk mencatat unique visitor per hari
-// Setiap IP hanya dicatat 1x per hari
-func VisitorTracker(visitorRepo repository.VisitorRepository) gin.HandlerFunc {
+// Setiap visitor hanya dicatat 1x per hari, IP hanya disimpan sebagai hash dengan salt harian
+func VisitorTracker(visitorService service.VisitorService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		c.Next()
+
+		// Beacon kunjungan sudah mencatat visitor beserta sumbernya
+		if c.GetBool("visitor_recorded") {
+			return
+		}
+
 		// Get client IP (support proxy/load balancer)
 		clientIP := c.ClientIP()
 
 		// Get platform/user agent
-		platform := c.GetHeader("User-Agent")
+		userAgent := c.GetHeader("User-Agent")
 
 		// Record visit (akan skip jika sudah ada record hari ini)
 		go func() {
-			_ = visitorRepo.RecordVisit(clientIP, platform)
+			_ = visitorService.RecordVisit(clientIP, userAgent)
 		}()
-
-		c.Next()
 	}
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -9,6 +11,8 @@ import (
 type AboutRepository interface {
 	Get() (*domain.About, error)
 	Upsert(about *domain.About) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) AboutRepository
 }
 
 type aboutRepository struct {
@@ -20,6 +24,11 @@ func NewAboutRepository(db *gorm.DB) AboutRepository {
 	return &aboutRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *aboutRepository) WithContext(ctx context.Context) AboutRepository {
+	return &aboutRepository{db: r.db.WithContext(ctx)}
+}
+
 // Get mengambil data about (singleton - hanya ada 1 record)
 func (r *aboutRepository) Get() (*domain.About, error) {
 	var about domain.About
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// ActivityLogArchiveRepository interface untuk retensi activity log (arsip lalu hapus)
+type ActivityLogArchiveRepository interface {
+	// FindLastIDBefore mengambil id terbesar activity log yang dibuat sebelum cutoff, 0 jika tidak ada
+	FindLastIDBefore(cutoff time.Time) (int, error)
+
+	// StreamEntries mengirim entry dengan id <= toID per batch (urut id naik) ke fn
+	StreamEntries(toID, batchSize int, fn func(logs []domain.ActivityLog) error) error
+
+	// Purge menyimpan catatan arsip dan menghapus entry FromLogID..ToLogID dalam satu transaksi
+	// Mengembalikan false (tanpa menghapus apa pun) jika rentang sudah diarsipkan proses lain atau jumlah entry berubah
+	Purge(archive *domain.ActivityLogArchive) (bool, error)
+
+	// FindAll mengambil arsip terbaru lebih dulu dengan pagination
+	FindAll(offset, limit int) ([]domain.ActivityLogArchive, int64, error)
+}
+
+// errPurgeMismatch membatalkan transaksi purge jika jumlah entry yang terhapus tidak sama dengan isi arsip
+var errPurgeMismatch = errors.New("activity log berubah saat diarsipkan")
+
+type activityLogArchiveRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityLogArchiveRepository constructor untuk ActivityLogArchiveRepository
+func NewActivityLogArchiveRepository(db *gorm.DB) ActivityLogArchiveRepository {
+	return &activityLogArchiveRepository{db: db}
+}
+
+// FindLastIDBefore mencari batas atas arsip, entry yang diarsipkan selalu prefix berdasarkan id
+func (r *activityLogArchiveRepository) FindLastIDBefore(cutoff time.Time) (int, error) {
+	var lastID int
+	err := r.db.Model(&domain.ActivityLog{}).
+		Select("COALESCE(MAX(id), 0)").
+		Where("created_at < ?", cutoff).
+		Scan(&lastID).Error
+	return lastID, err
+}
+
+// StreamEntries membaca entry tanpa relasi, apa adanya seperti tersimpan (termasuk hash)
+func (r *activityLogArchiveRepository) StreamEntries(toID, batchSize int, fn func(logs []domain.ActivityLog) error) error {
+	var batch []domain.ActivityLog
+	return r.db.Where("id <= ?", toID).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// Purge mengunci hash chain selama transaksi agar tidak ada entry baru yang dihitung dari entry yang sedang dihapus
+func (r *activityLogArchiveRepository) Purge(archive *domain.ActivityLogArchive) (bool, error) {
+	purged := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := audit.LockChain(tx); err != nil {
+			return err
+		}
+
+		var archivedUntil int
+		if err := tx.Model(&domain.ActivityLogArchive{}).
+			Select("COALESCE(MAX(to_log_id), 0)").
+			Scan(&archivedUntil).Error; err != nil {
+			return err
+		}
+		if archivedUntil >= archive.FromLogID {
+			return nil
+		}
+
+		if err := tx.Create(archive).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id BETWEEN ? AND ?", archive.FromLogID, archive.ToLogID).Delete(&domain.ActivityLog{})
+		if result.Error != nil {
+			return result.Error
+		}
+		// Entry berubah sejak file arsip ditulis, batalkan agar tidak ada entry yang hilang tanpa arsip
+		if result.RowsAffected != archive.EntryCount {
+			return errPurgeMismatch
+		}
+
+		purged = true
+		return nil
+	})
+	if errors.Is(err, errPurgeMismatch) {
+		return false, nil
+	}
+	return purged, err
+}
+
+// FindAll mengambil daftar arsip
+func (r *activityLogArchiveRepository) FindAll(offset, limit int) ([]domain.ActivityLogArchive, int64, error) {
+	var archives []domain.ActivityLogArchive
+	var total int64
+
+	db := r.db.Model(&domain.ActivityLogArchive{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("to_log_id DESC").Limit(limit).Offset(offset).Find(&archives).Error; err != nil {
+		return nil, 0, err
+	}
+	return archives, total, nil
+}
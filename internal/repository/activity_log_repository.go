@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/garuda-labs-1/pmii-be/config"
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
 )
 
 // ActivityLogFilter contains filter options for querying activity logs
@@ -13,6 +17,7 @@ type ActivityLogFilter struct {
 	UserID     *int
 	Module     *string
 	ActionType *string
+	TargetID   *int
 	StartDate  *time.Time
 	EndDate    *time.Time
 	Search     string
@@ -20,7 +25,12 @@ type ActivityLogFilter struct {
 
 type ActivityLogRepository interface {
 	GetActivityLogs(offset, limit int, filter ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error)
-	Create(log *domain.ActivityLog) error
+	// Create menyimpan activity log, API key dan admin yang mengimpersonasi diambil dari context request
+	Create(ctx context.Context, log *domain.ActivityLog) error
+
+	// StreamActivityLogs mengirim semua activity log yang cocok dengan filter per batch (urut id naik) ke fn
+	// Dipakai untuk export CSV/NDJSON tanpa memuat seluruh data ke memory
+	StreamActivityLogs(filter ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error
 }
 
 type activityLogRepository struct{}
@@ -33,11 +43,36 @@ func (r *activityLogRepository) GetActivityLogs(offset, limit int, filter Activi
 	var logs []domain.ActivityLog
 	var total int64
 
-	db := config.DB.Model(&domain.ActivityLog{}).Preload("User")
+	db := filterActivityLogs(config.DB.Model(&domain.ActivityLog{}).Preload("User").Preload("APIKey").Preload("Impersonator"), filter)
+
+	// Count total before pagination
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination and ordering
+	if err := db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return toActivityLogResponses(logs), total, nil
+}
+
+// StreamActivityLogs membaca activity log dengan keyset pagination (id) agar export besar tetap ringan
+func (r *activityLogRepository) StreamActivityLogs(filter ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error {
+	var batch []domain.ActivityLog
+	db := filterActivityLogs(config.DB.Model(&domain.ActivityLog{}).Preload("User").Preload("APIKey").Preload("Impersonator"), filter)
 
-	// Apply filters
+	return db.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(toActivityLogResponses(batch))
+	}).Error
+}
+
+// filterActivityLogs menerapkan ActivityLogFilter ke query
+func filterActivityLogs(db *gorm.DB, filter ActivityLogFilter) *gorm.DB {
+	// Aktivitas user termasuk yang dilakukannya saat mengimpersonasi user lain
 	if filter.UserID != nil {
-		db = db.Where("user_id = ?", *filter.UserID)
+		db = db.Where("user_id = ? OR impersonator_id = ?", *filter.UserID, *filter.UserID)
 	}
 	if filter.Module != nil && *filter.Module != "" {
 		db = db.Where("module = ?", *filter.Module)
@@ -45,6 +80,9 @@ func (r *activityLogRepository) GetActivityLogs(offset, limit int, filter Activi
 	if filter.ActionType != nil && *filter.ActionType != "" {
 		db = db.Where("action_type = ?", *filter.ActionType)
 	}
+	if filter.TargetID != nil {
+		db = db.Where("target_id = ?", *filter.TargetID)
+	}
 	if filter.StartDate != nil {
 		db = db.Where("created_at >= ?", *filter.StartDate)
 	}
@@ -54,18 +92,11 @@ func (r *activityLogRepository) GetActivityLogs(offset, limit int, filter Activi
 	if filter.Search != "" {
 		db = db.Where("description ILIKE ?", "%"+filter.Search+"%")
 	}
+	return db
+}
 
-	// Count total before pagination
-	if err := db.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Apply pagination and ordering
-	if err := db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&logs).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Convert to response DTOs
+// toActivityLogResponses mengubah activity log (dengan relasi) ke response DTO
+func toActivityLogResponses(logs []domain.ActivityLog) []responses.ActivityLogResponse {
 	result := make([]responses.ActivityLogResponse, len(logs))
 	for i, log := range logs {
 		var userInfo *responses.ActivityLogUserInfo
@@ -77,26 +108,53 @@ func (r *activityLogRepository) GetActivityLogs(offset, limit int, filter Activi
 			}
 		}
 
+		var apiKeyInfo *responses.ActivityLogAPIKeyInfo
+		if log.APIKey != nil {
+			apiKeyInfo = &responses.ActivityLogAPIKeyInfo{
+				ID:     log.APIKey.ID,
+				Name:   log.APIKey.Name,
+				Prefix: log.APIKey.Prefix,
+			}
+		}
+
+		var impersonatorInfo *responses.ActivityLogUserInfo
+		if log.Impersonator != nil {
+			impersonatorInfo = &responses.ActivityLogUserInfo{
+				ID:       log.Impersonator.ID,
+				FullName: log.Impersonator.FullName,
+				Email:    log.Impersonator.Email,
+			}
+		}
+
 		result[i] = responses.ActivityLogResponse{
-			ID:          log.ID,
-			UserID:      log.UserID,
-			User:        userInfo,
-			ActionType:  log.ActionType,
-			Module:      log.Module,
-			Description: log.Description,
-			TargetID:    log.TargetID,
-			OldValue:    log.OldValue,
-			NewValue:    log.NewValue,
-			IPAddress:   log.IPAddress,
-			UserAgent:   log.UserAgent,
-			CreatedAt:   log.CreatedAt,
+			ID:           log.ID,
+			UserID:       log.UserID,
+			User:         userInfo,
+			APIKey:       apiKeyInfo,
+			Impersonator: impersonatorInfo,
+			ActionType:   log.ActionType,
+			Module:       log.Module,
+			Description:  log.Description,
+			TargetID:     log.TargetID,
+			OldValue:     log.OldValue,
+			NewValue:     log.NewValue,
+			IPAddress:    log.IPAddress,
+			UserAgent:    log.UserAgent,
+			CreatedAt:    log.CreatedAt,
 		}
 	}
 
-	return result, total, nil
+	return result
 }
 
-// Create inserts a new activity log entry
-func (r *activityLogRepository) Create(log *domain.ActivityLog) error {
-	return config.DB.Create(log).Error
+// Create inserts a new activity log entry ke hash chain
+// Sama dengan callback audit, setiap aksi saat impersonasi atau via API key tercatat dengan pelaku sebenarnya
+func (r *activityLogRepository) Create(ctx context.Context, log *domain.ActivityLog) error {
+	if log.ImpersonatorID == nil {
+		log.ImpersonatorID = utils.GetImpersonatorID(ctx)
+	}
+	if log.APIKeyID == nil {
+		log.APIKeyID = utils.GetAPIKeyID(ctx)
+	}
+	return audit.Append(config.DB, log)
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/config"
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
@@ -14,6 +16,8 @@ type AdRepository interface {
 	FindByPageAndSlot(page domain.AdPage, slot int) (*domain.Ad, error)
 	Update(ad *domain.Ad) error
 	UpdateImage(id int, imageURL string) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) AdRepository
 }
 
 type adRepository struct {
@@ -25,6 +29,11 @@ func NewAdRepository() AdRepository {
 	return &adRepository{db: config.DB}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *adRepository) WithContext(ctx context.Context) AdRepository {
+	return &adRepository{db: r.db.WithContext(ctx)}
+}
+
 // FindAll mengambil semua ads
 func (r *adRepository) FindAll() ([]domain.Ad, error) {
 	var ads []domain.Ad
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AdminIPAllowlistRepository interface untuk data layer allowlist IP admin
+type AdminIPAllowlistRepository interface {
+	// Create menyimpan entry allowlist baru
+	Create(entry *domain.AdminIPAllowlistEntry) error
+
+	// FindAll mengambil semua entry allowlist, terlama lebih dulu
+	FindAll() ([]domain.AdminIPAllowlistEntry, error)
+
+	// FindByID mengambil entry allowlist berdasarkan ID
+	FindByID(id int) (*domain.AdminIPAllowlistEntry, error)
+
+	// ExistsByCIDR mengecek apakah CIDR sudah ada di allowlist
+	ExistsByCIDR(cidr string) (bool, error)
+
+	// Delete menghapus entry allowlist
+	Delete(id int) error
+}
+
+type adminIPAllowlistRepository struct {
+	db *gorm.DB
+}
+
+// NewAdminIPAllowlistRepository constructor untuk AdminIPAllowlistRepository
+func NewAdminIPAllowlistRepository(db *gorm.DB) AdminIPAllowlistRepository {
+	return &adminIPAllowlistRepository{db: db}
+}
+
+// Create menyimpan entry allowlist baru
+func (r *adminIPAllowlistRepository) Create(entry *domain.AdminIPAllowlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// FindAll mengambil semua entry allowlist
+func (r *adminIPAllowlistRepository) FindAll() ([]domain.AdminIPAllowlistEntry, error) {
+	var entries []domain.AdminIPAllowlistEntry
+	if err := r.db.Order("created_at ASC, id ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindByID mengambil entry allowlist berdasarkan ID
+func (r *adminIPAllowlistRepository) FindByID(id int) (*domain.AdminIPAllowlistEntry, error) {
+	var entry domain.AdminIPAllowlistEntry
+	if err := r.db.First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ExistsByCIDR mengecek apakah CIDR sudah ada di allowlist
+func (r *adminIPAllowlistRepository) ExistsByCIDR(cidr string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.AdminIPAllowlistEntry{}).Where("cidr = ?", cidr).Count(&count).Error
+	return count > 0, err
+}
+
+// Delete menghapus entry allowlist
+func (r *adminIPAllowlistRepository) Delete(id int) error {
+	return r.db.Delete(&domain.AdminIPAllowlistEntry{}, id).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository interface untuk data layer API key integrasi
+type APIKeyRepository interface {
+	// Create menyimpan API key baru (hanya hash)
+	Create(key *domain.APIKey) error
+
+	// FindAll mengambil semua API key, terbaru lebih dulu
+	FindAll() ([]domain.APIKey, error)
+
+	// FindByID mengambil API key berdasarkan ID
+	FindByID(id int) (*domain.APIKey, error)
+
+	// FindByPrefix mengambil API key berdasarkan prefix (bagian publik dari key)
+	FindByPrefix(prefix string) (*domain.APIKey, error)
+
+	// Revoke mencabut API key, return false jika key sudah dicabut sebelumnya
+	Revoke(id int) (bool, error)
+
+	// RevokeAllByCreator mencabut semua API key aktif milik satu user
+	RevokeAllByCreator(userID int) (int64, error)
+
+	// TouchLastUsed mencatat waktu dan IP terakhir key dipakai
+	TouchLastUsed(id int, ipAddress string, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository constructor untuk APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create menyimpan API key baru
+func (r *apiKeyRepository) Create(key *domain.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindAll mengambil semua API key
+func (r *apiKeyRepository) FindAll() ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	if err := r.db.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// FindByID mengambil API key berdasarkan ID
+func (r *apiKeyRepository) FindByID(id int) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByPrefix mengambil API key berdasarkan prefix
+func (r *apiKeyRepository) FindByPrefix(prefix string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := r.db.Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke mencabut API key
+func (r *apiKeyRepository) Revoke(id int) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": now, "updated_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeAllByCreator mencabut semua API key aktif yang dibuat oleh user
+func (r *apiKeyRepository) RevokeAllByCreator(userID int) (int64, error) {
+	now := time.Now()
+	result := r.db.Model(&domain.APIKey{}).
+		Where("created_by = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]any{"revoked_at": now, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// TouchLastUsed mencatat waktu dan IP terakhir key dipakai
+func (r *apiKeyRepository) TouchLastUsed(id int, ipAddress string, usedAt time.Time) error {
+	return r.db.Model(&domain.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"last_used_at": usedAt, "last_used_ip": ipAddress}).Error
+}
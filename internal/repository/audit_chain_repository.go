@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AuditChainRepository interface untuk verifikasi hash chain activity log dan checkpoint-nya
+type AuditChainRepository interface {
+	// FindEntriesAfter mengambil activity log dengan id > afterID, urut id naik (untuk menelusuri chain per batch)
+	FindEntriesAfter(afterID, limit int) ([]domain.ActivityLog, error)
+
+	// GetArchiveAnchor mengambil arsip terakhir beserta total entry ber-hash yang sudah diarsipkan, nil jika belum ada arsip
+	GetArchiveAnchor() (*domain.ActivityLogArchive, int64, error)
+
+	CreateCheckpoint(checkpoint *domain.ActivityLogCheckpoint) error
+	GetLatestCheckpoint() (*domain.ActivityLogCheckpoint, error)
+
+	// GetCheckpoints mengambil semua checkpoint, urut last_log_id naik
+	GetCheckpoints() ([]domain.ActivityLogCheckpoint, error)
+
+	// WithCheckpointLock menjalankan fn hanya jika tidak ada proses (replica) lain yang sedang membuat checkpoint
+	// Mengembalikan false tanpa menjalankan fn jika lock sedang dipegang proses lain
+	WithCheckpointLock(fn func() error) (bool, error)
+}
+
+// checkpointLockKey adalah key pg_try_advisory_xact_lock untuk pembuatan checkpoint
+const checkpointLockKey = 440_045
+
+type auditChainRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditChainRepository constructor untuk AuditChainRepository
+func NewAuditChainRepository(db *gorm.DB) AuditChainRepository {
+	return &auditChainRepository{db: db}
+}
+
+// FindEntriesAfter mengambil satu batch entry tanpa relasi
+func (r *auditChainRepository) FindEntriesAfter(afterID, limit int) ([]domain.ActivityLog, error) {
+	var logs []domain.ActivityLog
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// GetArchiveAnchor mengambil titik awal chain setelah entry lama diarsipkan
+func (r *auditChainRepository) GetArchiveAnchor() (*domain.ActivityLogArchive, int64, error) {
+	var archive domain.ActivityLogArchive
+	err := r.db.Order("to_log_id DESC").First(&archive).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var archivedCount int64
+	if err := r.db.Model(&domain.ActivityLogArchive{}).
+		Select("COALESCE(SUM(chained_count), 0)").
+		Scan(&archivedCount).Error; err != nil {
+		return nil, 0, err
+	}
+	return &archive, archivedCount, nil
+}
+
+// CreateCheckpoint menyimpan checkpoint yang sudah ditandatangani
+func (r *auditChainRepository) CreateCheckpoint(checkpoint *domain.ActivityLogCheckpoint) error {
+	return r.db.Create(checkpoint).Error
+}
+
+// GetLatestCheckpoint mengambil checkpoint terakhir, nil jika belum ada
+func (r *auditChainRepository) GetLatestCheckpoint() (*domain.ActivityLogCheckpoint, error) {
+	var checkpoint domain.ActivityLogCheckpoint
+	err := r.db.Order("last_log_id DESC, id DESC").First(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// GetCheckpoints mengambil semua checkpoint
+func (r *auditChainRepository) GetCheckpoints() ([]domain.ActivityLogCheckpoint, error) {
+	var checkpoints []domain.ActivityLogCheckpoint
+	err := r.db.Order("last_log_id ASC, id ASC").Find(&checkpoints).Error
+	return checkpoints, err
+}
+
+// WithCheckpointLock memegang advisory lock dalam transaksi selama fn berjalan, lock dilepas saat transaksi selesai
+func (r *auditChainRepository) WithCheckpointLock(fn func() error) (bool, error) {
+	acquired := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", checkpointLockKey).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return fn()
+	})
+	return acquired, err
+}
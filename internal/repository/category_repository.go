@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/config"
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
 )
 
 type CategoryRepository interface {
@@ -11,18 +14,27 @@ type CategoryRepository interface {
 	Create(category *domain.Category) error
 	Update(category *domain.Category) error
 	Delete(category *domain.Category) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) CategoryRepository
 }
 
-type categoryRepository struct{}
+type categoryRepository struct {
+	db *gorm.DB
+}
 
 func NewCategoryRepository() CategoryRepository {
-	return &categoryRepository{}
+	return &categoryRepository{db: config.DB}
+}
+
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *categoryRepository) WithContext(ctx context.Context) CategoryRepository {
+	return &categoryRepository{db: r.db.WithContext(ctx)}
 }
 
 func (r *categoryRepository) FindAll(offset, limit int, search string) ([]domain.Category, int64, error) {
 	var categories []domain.Category
 	var total int64
-	db := config.DB.Model(&domain.Category{})
+	db := r.db.Model(&domain.Category{})
 
 	if search != "" {
 		db = db.Where("name ILIKE ?", "%"+search+"%")
@@ -35,18 +47,18 @@ func (r *categoryRepository) FindAll(offset, limit int, search string) ([]domain
 
 func (r *categoryRepository) FindByID(id string) (domain.Category, error) {
 	var category domain.Category
-	err := config.DB.First(&category, id).Error
+	err := r.db.First(&category, id).Error
 	return category, err
 }
 
 func (r *categoryRepository) Create(category *domain.Category) error {
-	return config.DB.Create(category).Error
+	return r.db.Create(category).Error
 }
 
 func (r *categoryRepository) Update(category *domain.Category) error {
-	return config.DB.Save(category).Error
+	return r.db.Save(category).Error
 }
 
 func (r *categoryRepository) Delete(category *domain.Category) error {
-	return config.DB.Delete(category).Error
+	return r.db.Delete(category).Error
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -9,6 +11,8 @@ import (
 type ContactRepository interface {
 	Get() (*domain.Contact, error)
 	Update(contact *domain.Contact) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) ContactRepository
 }
 
 type contactRepository struct {
@@ -20,6 +24,11 @@ func NewContactRepository(db *gorm.DB) ContactRepository {
 	return &contactRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *contactRepository) WithContext(ctx context.Context) ContactRepository {
+	return &contactRepository{db: r.db.WithContext(ctx)}
+}
+
 // Get mengambil contact info (singleton - mengambil record pertama yang ada)
 func (r *contactRepository) Get() (*domain.Contact, error) {
 	var contact domain.Contact
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CSPReportRepository interface untuk data layer laporan pelanggaran CSP
+type CSPReportRepository interface {
+	// CreateBatch menyimpan satu atau lebih laporan dari satu request browser
+	CreateBatch(reports []domain.CSPReport) error
+
+	// FindAll mengambil laporan terbaru dengan pagination, bisa difilter per directive
+	FindAll(offset, limit int, directive string) ([]domain.CSPReport, int64, error)
+
+	// Count menghitung seluruh laporan yang tersimpan
+	Count() (int64, error)
+
+	// DeleteBefore menghapus laporan yang dibuat sebelum waktu tertentu
+	DeleteBefore(before time.Time) (int64, error)
+
+	// DeleteExceptNewest menghapus laporan di luar keep laporan terbaru
+	DeleteExceptNewest(keep int) (int64, error)
+}
+
+type cspReportRepository struct {
+	db *gorm.DB
+}
+
+// NewCSPReportRepository constructor untuk CSPReportRepository
+func NewCSPReportRepository(db *gorm.DB) CSPReportRepository {
+	return &cspReportRepository{db: db}
+}
+
+// CreateBatch menyimpan laporan CSP
+func (r *cspReportRepository) CreateBatch(reports []domain.CSPReport) error {
+	return r.db.Create(&reports).Error
+}
+
+// FindAll mengambil laporan CSP, terbaru lebih dulu
+func (r *cspReportRepository) FindAll(offset, limit int, directive string) ([]domain.CSPReport, int64, error) {
+	var reports []domain.CSPReport
+	var total int64
+
+	db := r.db.Model(&domain.CSPReport{})
+	if directive != "" {
+		db = db.Where("effective_directive = ?", directive)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&reports).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// Count menghitung seluruh laporan CSP
+func (r *cspReportRepository) Count() (int64, error) {
+	var total int64
+	err := r.db.Model(&domain.CSPReport{}).Count(&total).Error
+	return total, err
+}
+
+// DeleteBefore menghapus laporan CSP yang melewati masa retensi
+func (r *cspReportRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&domain.CSPReport{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExceptNewest menyisakan keep laporan terbaru (berdasarkan id)
+func (r *cspReportRepository) DeleteExceptNewest(keep int) (int64, error) {
+	result := r.db.Exec(`DELETE FROM "csp_reports" WHERE "id" < (
+		SELECT "id" FROM "csp_reports" ORDER BY "id" DESC OFFSET ? LIMIT 1
+	)`, keep-1)
+	return result.RowsAffected, result.Error
+}
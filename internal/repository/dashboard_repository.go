@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -33,6 +34,43 @@ type ArticleStats struct {
 	Views int64
 }
 
+// TrafficStats struktur untuk jumlah visitor per sumber atau kelompok perangkat
+type TrafficStats struct {
+	Name     string
+	Visitors int64
+}
+
+// CampaignStats struktur untuk jumlah visitor per kampanye UTM
+type CampaignStats struct {
+	Campaign string
+	Source   string
+	Medium   string
+	Visitors int64
+}
+
+// TrafficTotals jumlah seluruh kunjungan dalam periode, dipakai sebagai pembagi persentase
+type TrafficTotals struct {
+	Visits    int64 // Semua kunjungan
+	Referred  int64 // Kunjungan dengan domain perujuk
+	Campaigns int64 // Kunjungan dengan parameter utm_campaign
+}
+
+// RegionStats struktur untuk jumlah visitor per negara dan provinsi
+type RegionStats struct {
+	Country  string
+	Region   string
+	Visitors int64
+}
+
+// VisitorDimension kolom klasifikasi perangkat di tabel visitors
+type VisitorDimension string
+
+const (
+	VisitorDimensionDevice  VisitorDimension = "device_type"
+	VisitorDimensionBrowser VisitorDimension = "browser"
+	VisitorDimensionOS      VisitorDimension = "os"
+)
+
 // ActivityLogStats struktur untuk activity log dashboard
 type ActivityLogStats struct {
 	ID          int
@@ -45,7 +83,7 @@ type ActivityLogStats struct {
 
 // DashboardRepository interface untuk data layer dashboard
 type DashboardRepository interface {
-	// GetUniqueVisitors menghitung unique visitors berdasarkan IP dalam periode tertentu
+	// GetUniqueVisitors menghitung unique visitors berdasarkan hash IP dalam periode tertentu
 	GetUniqueVisitors(startDate, endDate time.Time) (int64, error)
 
 	// GetPageViews menghitung total page views dalam periode tertentu
@@ -66,6 +104,23 @@ type DashboardRepository interface {
 	// GetTopArticles mendapatkan artikel dengan views terbanyak
 	GetTopArticles(startDate, endDate time.Time, limit int) ([]ArticleStats, error)
 
+	// GetTopReferrers mendapatkan domain perujuk dengan visitor terbanyak (kunjungan langsung tidak dihitung)
+	GetTopReferrers(startDate, endDate time.Time, limit int) ([]TrafficStats, error)
+
+	// GetTopCampaigns mendapatkan kampanye UTM dengan visitor terbanyak
+	GetTopCampaigns(startDate, endDate time.Time, limit int) ([]CampaignStats, error)
+
+	// GetTrafficTotals menghitung total kunjungan, kunjungan dari perujuk dan dari kampanye UTM (tanpa limit top N)
+	GetTrafficTotals(startDate, endDate time.Time) (*TrafficTotals, error)
+
+	// GetVisitorBreakdown mendapatkan jumlah visitor per device, browser atau OS
+	// Visitor tanpa klasifikasi dikelompokkan sebagai "unknown"
+	GetVisitorBreakdown(dimension VisitorDimension, startDate, endDate time.Time) ([]TrafficStats, error)
+
+	// GetVisitorsByRegion mendapatkan jumlah visitor per negara dan provinsi, urut dari terbanyak
+	// Visitor tanpa data lokasi (termasuk provinsi yang dikosongkan setelah masa retensi) dikelompokkan sebagai "unknown"
+	GetVisitorsByRegion(startDate, endDate time.Time) ([]RegionStats, error)
+
 	// GetAvailablePeriods mendapatkan periode yang tersedia (bulan-bulan yang ada datanya)
 	GetAvailablePeriods() ([]time.Time, error)
 
@@ -83,7 +138,7 @@ func NewDashboardRepository(db *gorm.DB) DashboardRepository {
 }
 
 // GetUniqueVisitors menghitung unique visitors dari tabel visitors
-// Sudah unique per hari (1 IP = 1 record per hari)
+// Sudah unique per hari (1 hash IP = 1 record per hari)
 func (r *dashboardRepository) GetUniqueVisitors(startDate, endDate time.Time) (int64, error) {
 	var count int64
 	err := r.db.Table("visitors").
@@ -192,6 +247,88 @@ func (r *dashboardRepository) GetTopArticles(startDate, endDate time.Time, limit
 	return results, err
 }
 
+// GetTopReferrers mendapatkan domain perujuk teratas dari tabel visitors
+func (r *dashboardRepository) GetTopReferrers(startDate, endDate time.Time, limit int) ([]TrafficStats, error) {
+	var results []TrafficStats
+
+	err := r.db.Table("visitors").
+		Select("referrer_host as name, COUNT(*) as visitors").
+		Where("visited_at >= ? AND visited_at < ?", startDate, endDate).
+		Where("referrer_host IS NOT NULL").
+		Group("referrer_host").
+		Order("visitors DESC, name ASC").
+		Limit(limit).
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTopCampaigns mendapatkan kampanye UTM teratas, dikelompokkan per campaign, source dan medium
+func (r *dashboardRepository) GetTopCampaigns(startDate, endDate time.Time, limit int) ([]CampaignStats, error) {
+	var results []CampaignStats
+
+	err := r.db.Table("visitors").
+		Select("utm_campaign as campaign, COALESCE(utm_source, '') as source, COALESCE(utm_medium, '') as medium, COUNT(*) as visitors").
+		Where("visited_at >= ? AND visited_at < ?", startDate, endDate).
+		Where("utm_campaign IS NOT NULL").
+		Group("utm_campaign, utm_source, utm_medium").
+		Order("visitors DESC, campaign ASC").
+		Limit(limit).
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetTrafficTotals menghitung semua total dalam satu query
+func (r *dashboardRepository) GetTrafficTotals(startDate, endDate time.Time) (*TrafficTotals, error) {
+	var totals TrafficTotals
+
+	err := r.db.Table("visitors").
+		Select("COUNT(*) as visits, COUNT(referrer_host) as referred, COUNT(utm_campaign) as campaigns").
+		Where("visited_at >= ? AND visited_at < ?", startDate, endDate).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// GetVisitorBreakdown mendapatkan distribusi visitor per kolom klasifikasi perangkat
+func (r *dashboardRepository) GetVisitorBreakdown(dimension VisitorDimension, startDate, endDate time.Time) ([]TrafficStats, error) {
+	var results []TrafficStats
+
+	switch dimension {
+	case VisitorDimensionDevice, VisitorDimensionBrowser, VisitorDimensionOS:
+	default:
+		return nil, fmt.Errorf("unknown visitor dimension %q", dimension)
+	}
+
+	column := string(dimension)
+	err := r.db.Table("visitors").
+		Select("COALESCE("+column+", 'unknown') as name, COUNT(*) as visitors").
+		Where("visited_at >= ? AND visited_at < ?", startDate, endDate).
+		Group("name").
+		Order("visitors DESC, name ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
+// GetVisitorsByRegion mendapatkan distribusi visitor per negara dan provinsi dari hasil geolokasi
+func (r *dashboardRepository) GetVisitorsByRegion(startDate, endDate time.Time) ([]RegionStats, error) {
+	var results []RegionStats
+
+	err := r.db.Table("visitors").
+		Select("COALESCE(country, 'unknown') as country, COALESCE(region, 'unknown') as region, COUNT(*) as visitors").
+		Where("visited_at >= ? AND visited_at < ?", startDate, endDate).
+		Group("country, region").
+		Order("visitors DESC, country ASC, region ASC").
+		Scan(&results).Error
+
+	return results, err
+}
+
 // GetAvailablePeriods mendapatkan periode yang tersedia
 func (r *dashboardRepository) GetAvailablePeriods() ([]time.Time, error) {
 	var results []time.Time
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -15,6 +17,8 @@ type DocumentRepository interface {
 	// Public methods
 	FindAllActive(fileType string) ([]domain.Document, error)
 	FindActiveByType(fileType string) ([]domain.Document, error)
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) DocumentRepository
 }
 
 type documentRepository struct {
@@ -26,6 +30,11 @@ func NewDocumentRepository(db *gorm.DB) DocumentRepository {
 	return &documentRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *documentRepository) WithContext(ctx context.Context) DocumentRepository {
+	return &documentRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create membuat document baru
 func (r *documentRepository) Create(document *domain.Document) error {
 	return r.db.Create(document).Error
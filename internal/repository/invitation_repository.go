@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// InvitationRepository interface untuk data layer undangan user
+type InvitationRepository interface {
+	// Create menyimpan undangan baru (hanya hash token)
+	Create(invitation *domain.UserInvitation) error
+
+	// FindByID mengambil undangan beserta role dan pengundang
+	FindByID(id int) (*domain.UserInvitation, error)
+
+	// FindByTokenHash mengambil undangan berdasarkan hash token beserta role-nya
+	FindByTokenHash(hash string) (*domain.UserInvitation, error)
+
+	// FindOpen mengambil undangan yang belum diterima atau dicabut (termasuk yang kadaluarsa)
+	FindOpen() ([]domain.UserInvitation, error)
+
+	// FindOpenByEmail mencari undangan terbuka yang belum kadaluarsa untuk email, nil jika tidak ada
+	FindOpenByEmail(email string) (*domain.UserInvitation, error)
+
+	// RevokeExpiredByEmail mencabut undangan terbuka yang sudah kadaluarsa untuk email
+	// agar undangan baru tidak bentrok dengan unique index undangan terbuka
+	RevokeExpiredByEmail(email string) error
+
+	// Update menyimpan perubahan undangan (kirim ulang)
+	Update(invitation *domain.UserInvitation) error
+
+	// Revoke mencabut undangan terbuka, return false jika sudah diterima/dicabut
+	Revoke(id int) (bool, error)
+
+	// Accept menandai undangan diterima dan membuat user dalam satu transaksi
+	// Return false jika undangan sudah tidak bisa dipakai (diterima, dicabut, atau kadaluarsa)
+	Accept(id int, user *domain.User) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository constructor untuk InvitationRepository
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+// Create menyimpan undangan baru
+func (r *invitationRepository) Create(invitation *domain.UserInvitation) error {
+	return r.db.Omit("Role", "Inviter").Create(invitation).Error
+}
+
+// FindByID mengambil undangan beserta role dan pengundang
+func (r *invitationRepository) FindByID(id int) (*domain.UserInvitation, error) {
+	var invitation domain.UserInvitation
+	if err := r.db.Preload("Role").Preload("Inviter").First(&invitation, id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindByTokenHash mengambil undangan berdasarkan hash token
+func (r *invitationRepository) FindByTokenHash(hash string) (*domain.UserInvitation, error) {
+	var invitation domain.UserInvitation
+	if err := r.db.Preload("Role").Where("token_hash = ?", hash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindOpen mengambil undangan yang belum diterima atau dicabut, terbaru lebih dulu
+func (r *invitationRepository) FindOpen() ([]domain.UserInvitation, error) {
+	var invitations []domain.UserInvitation
+	err := r.db.Preload("Role").Preload("Inviter").
+		Where("accepted_at IS NULL AND revoked_at IS NULL").
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// FindOpenByEmail mencari undangan terbuka yang belum kadaluarsa untuk email
+func (r *invitationRepository) FindOpenByEmail(email string) (*domain.UserInvitation, error) {
+	var invitation domain.UserInvitation
+	err := r.db.Where("lower(email) = lower(?) AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", email, time.Now()).First(&invitation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// RevokeExpiredByEmail mencabut undangan terbuka yang sudah kadaluarsa untuk email
+func (r *invitationRepository) RevokeExpiredByEmail(email string) error {
+	now := time.Now()
+	return r.db.Model(&domain.UserInvitation{}).
+		Where("lower(email) = lower(?) AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", email, now).
+		Updates(map[string]any{"revoked_at": now, "updated_at": now}).Error
+}
+
+// Update menyimpan perubahan undangan
+func (r *invitationRepository) Update(invitation *domain.UserInvitation) error {
+	return r.db.Omit("Role", "Inviter").Save(invitation).Error
+}
+
+// Revoke mencabut undangan terbuka (update kondisional)
+func (r *invitationRepository) Revoke(id int) (bool, error) {
+	result := r.db.Model(&domain.UserInvitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": time.Now(), "updated_at": time.Now()})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// Accept menandai undangan diterima dan membuat user dalam satu transaksi
+func (r *invitationRepository) Accept(id int, user *domain.User) (bool, error) {
+	accepted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.UserInvitation{}).
+			Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, now).
+			Updates(map[string]any{"accepted_at": now, "updated_at": now})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		accepted = true
+		return tx.Model(&domain.UserInvitation{}).Where("id = ?", id).Update("accepted_user_id", user.ID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return accepted, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository interface untuk data layer percobaan login dan status lockout akun
+type LoginAttemptRepository interface {
+	// Create mencatat satu percobaan login
+	Create(attempt *domain.LoginAttempt) error
+
+	// CountFailuresByIP menghitung percobaan login gagal dari satu IP sejak waktu tertentu
+	CountFailuresByIP(ipAddress string, since time.Time) (int64, error)
+
+	// RegisterFailure menaikkan counter gagal login user secara atomik
+	// Akun dikunci sampai lockUntil jika counter mencapai threshold
+	RegisterFailure(userID int, threshold int, lockUntil time.Time) (int, *time.Time, error)
+
+	// ResetFailures mengosongkan counter gagal login dan membuka kunci akun
+	ResetFailures(userID int) error
+
+	// DeleteBefore menghapus riwayat percobaan login yang lebih lama dari waktu tertentu
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository constructor untuk LoginAttemptRepository
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Create mencatat satu percobaan login
+func (r *loginAttemptRepository) Create(attempt *domain.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// CountFailuresByIP menghitung percobaan login gagal dari satu IP sejak waktu tertentu
+func (r *loginAttemptRepository) CountFailuresByIP(ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.LoginAttempt{}).
+		Where("ip_address = ? AND success = false AND created_at >= ?", ipAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+// RegisterFailure menaikkan counter gagal login user dalam satu statement agar aman dari request paralel
+func (r *loginAttemptRepository) RegisterFailure(userID int, threshold int, lockUntil time.Time) (int, *time.Time, error) {
+	var count int
+	var lockedUntil sql.NullTime
+
+	err := r.db.Raw(`
+		UPDATE users
+		SET failed_login_count = failed_login_count + 1,
+			last_failed_login_at = ?,
+			locked_until = CASE WHEN failed_login_count + 1 >= ? THEN ? ELSE locked_until END
+		WHERE id = ?
+		RETURNING failed_login_count, locked_until`,
+		time.Now(), threshold, lockUntil, userID,
+	).Row().Scan(&count, &lockedUntil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !lockedUntil.Valid {
+		return count, nil, nil
+	}
+	return count, &lockedUntil.Time, nil
+}
+
+// ResetFailures mengosongkan counter gagal login dan membuka kunci akun
+func (r *loginAttemptRepository) ResetFailures(userID int) error {
+	return r.db.Exec(`
+		UPDATE users
+		SET failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL
+		WHERE id = ?`, userID).Error
+}
+
+// DeleteBefore menghapus riwayat percobaan login yang lebih lama dari waktu tertentu
+func (r *loginAttemptRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&domain.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
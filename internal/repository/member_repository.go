@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -15,6 +17,8 @@ type MemberRepository interface {
 	// Public methods
 	FindActiveWithPagination(page, limit int, search string) ([]domain.Member, int64, error)
 	FindActiveByDepartment(department string, page, limit int, search string) ([]domain.Member, int64, error)
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) MemberRepository
 }
 
 type memberRepository struct {
@@ -26,6 +30,11 @@ func NewMemberRepository(db *gorm.DB) MemberRepository {
 	return &memberRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *memberRepository) WithContext(ctx context.Context) MemberRepository {
+	return &memberRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create membuat member baru
 func (r *memberRepository) Create(member *domain.Member) error {
 	return r.db.Create(member).Error
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OIDCRepository interface untuk data layer login OpenID Connect
+type OIDCRepository interface {
+	// CreateState menyimpan state login yang sedang berjalan
+	CreateState(state *domain.OIDCLoginState) error
+
+	// ConsumeState mengambil dan menghapus state (sekali pakai), nil jika tidak ada atau kadaluarsa
+	ConsumeState(provider, stateHash string) (*domain.OIDCLoginState, error)
+
+	// FindIdentity mencari identitas eksternal berdasarkan provider dan subject, nil jika belum terhubung
+	FindIdentity(provider, subject string) (*domain.UserIdentity, error)
+
+	// CreateIdentity menghubungkan identitas eksternal ke user
+	CreateIdentity(identity *domain.UserIdentity) error
+
+	// TouchIdentity mencatat waktu login terakhir dengan identitas ini
+	TouchIdentity(id int, at time.Time) error
+}
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCRepository constructor untuk OIDCRepository
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+// CreateState menyimpan state login yang sedang berjalan, sekaligus membersihkan state kadaluarsa
+func (r *oidcRepository) CreateState(state *domain.OIDCLoginState) error {
+	r.db.Where("expires_at < ?", time.Now()).Delete(&domain.OIDCLoginState{})
+	return r.db.Create(state).Error
+}
+
+// ConsumeState mengambil dan menghapus state dalam satu query (DELETE ... RETURNING) agar sekali pakai
+func (r *oidcRepository) ConsumeState(provider, stateHash string) (*domain.OIDCLoginState, error) {
+	var states []domain.OIDCLoginState
+	result := r.db.Clauses(clause.Returning{}).
+		Where("provider = ? AND state_hash = ? AND expires_at > ?", provider, stateHash, time.Now()).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+// FindIdentity mencari identitas eksternal berdasarkan provider dan subject
+func (r *oidcRepository) FindIdentity(provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateIdentity menghubungkan identitas eksternal ke user
+func (r *oidcRepository) CreateIdentity(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// TouchIdentity mencatat waktu login terakhir dengan identitas ini
+func (r *oidcRepository) TouchIdentity(id int, at time.Time) error {
+	return r.db.Model(&domain.UserIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository interface untuk data layer token reset password
+type PasswordResetRepository interface {
+	// Create menyimpan token reset baru
+	Create(token *domain.PasswordResetToken) error
+
+	// FindByTokenHash mencari token reset berdasarkan hash
+	FindByTokenHash(hash string) (*domain.PasswordResetToken, error)
+
+	// MarkUsed menandai token sudah dipakai, return false jika sudah dipakai sebelumnya
+	MarkUsed(id int) (bool, error)
+
+	// InvalidateForUser menandai semua token user yang belum dipakai sebagai terpakai
+	InvalidateForUser(userID int) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository constructor untuk PasswordResetRepository
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Create menyimpan token reset baru
+func (r *passwordResetRepository) Create(token *domain.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByTokenHash mencari token reset berdasarkan hash
+func (r *passwordResetRepository) FindByTokenHash(hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed menandai token sudah dipakai (update kondisional agar single-use)
+func (r *passwordResetRepository) MarkUsed(id int) (bool, error) {
+	result := r.db.Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateForUser menandai semua token user yang belum dipakai sebagai terpakai
+func (r *passwordResetRepository) InvalidateForUser(userID int) error {
+	return r.db.Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"strconv"
 	"time"
 
@@ -11,14 +12,19 @@ import (
 
 type PostRepository interface {
 	FindAll(offset, limit int, search string) ([]domain.Post, int64, error)
+	FindAllByUser(userID, offset, limit int, search string) ([]domain.Post, int64, error)
+	FindAllByStatus(status domain.PostStatus, offset, limit int, search string) ([]domain.Post, int64, error)
 	FindByID(id int) (domain.Post, error)
 	FindBySlugOrID(identifier string) (domain.Post, error)
 	Create(post *domain.Post) error
 	Update(post *domain.Post) error
 	Delete(post *domain.Post, unscoped bool) error
+	UpdateOwner(postID, userID int) error
 	GetTagBySlug(slug string, name string) (domain.Tag, error)
-	HasViewed(postID int, ip string, since time.Time) (bool, error)
+	HasViewed(postID int, visitorHash string, since time.Time) (bool, error)
 	AddView(view *domain.PostView) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) PostRepository
 }
 
 type postRepository struct {
@@ -29,6 +35,11 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *postRepository) WithContext(ctx context.Context) PostRepository {
+	return &postRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *postRepository) FindAll(offset, limit int, search string) ([]domain.Post, int64, error) {
 	var posts []domain.Post
 	var total int64
@@ -52,6 +63,51 @@ func (r *postRepository) FindAll(offset, limit int, search string) ([]domain.Pos
 	return posts, total, err
 }
 
+// FindAllByUser mengambil semua post milik user (termasuk draft) untuk halaman "post saya"
+func (r *postRepository) FindAllByUser(userID, offset, limit int, search string) ([]domain.Post, int64, error) {
+	var posts []domain.Post
+	var total int64
+
+	query := r.db.Model(&domain.Post{}).
+		Select("posts.*, (SELECT COUNT(*) FROM post_views WHERE post_views.post_id = posts.id) as views_count").
+		Preload("Tags").
+		Preload("Category").
+		Where("user_id = ?", userID)
+
+	if search != "" {
+		searchKeyword := "%" + search + "%"
+		query = query.Where("title ILIKE ? OR content ILIKE ?", searchKeyword, searchKeyword)
+	}
+
+	query.Count(&total)
+
+	err := query.Limit(limit).Offset(offset).Order("created_at DESC").Find(&posts).Error
+
+	return posts, total, err
+}
+
+// FindAllByStatus mengambil post dengan status tertentu dari semua penulis, terbaru diubah lebih dulu
+func (r *postRepository) FindAllByStatus(status domain.PostStatus, offset, limit int, search string) ([]domain.Post, int64, error) {
+	var posts []domain.Post
+	var total int64
+
+	query := r.db.Model(&domain.Post{}).
+		Select("posts.*, (SELECT COUNT(*) FROM post_views WHERE post_views.post_id = posts.id) as views_count").
+		Preload("Tags").
+		Preload("Category").
+		Where("status = ?", status)
+
+	if search != "" {
+		searchKeyword := "%" + search + "%"
+		query = query.Where("title ILIKE ? OR content ILIKE ?", searchKeyword, searchKeyword)
+	}
+
+	query.Count(&total)
+
+	err := query.Limit(limit).Offset(offset).Order("updated_at DESC").Find(&posts).Error
+	return posts, total, err
+}
+
 func (r *postRepository) FindByID(id int) (domain.Post, error) {
 	var post domain.Post
 	err := config.DB.Preload("Category").Preload("Tags").First(&post, id).Error
@@ -80,31 +136,36 @@ func (r *postRepository) FindBySlugOrID(identifier string) (domain.Post, error)
 }
 
 func (r *postRepository) Create(post *domain.Post) error {
-	return config.DB.Create(post).Error
+	return r.db.Create(post).Error
 }
 
 func (r *postRepository) Update(post *domain.Post) error {
-	return config.DB.Save(post).Error
+	return r.db.Save(post).Error
 }
 
 func (r *postRepository) Delete(post *domain.Post, unscoped bool) error {
-	db := config.DB
+	db := r.db
 	if unscoped {
 		db = db.Unscoped()
 	}
 	return db.Delete(post).Error
 }
 
+// UpdateOwner memindahkan kepemilikan post ke user lain tanpa menyentuh field lain
+func (r *postRepository) UpdateOwner(postID, userID int) error {
+	return r.db.Model(&domain.Post{}).Where("id = ?", postID).Update("user_id", userID).Error
+}
+
 func (r *postRepository) GetTagBySlug(slug string, name string) (domain.Tag, error) {
 	var tag domain.Tag
 	err := config.DB.Where(domain.Tag{Slug: slug}).Attrs(domain.Tag{Name: name}).FirstOrCreate(&tag).Error
 	return tag, err
 }
 
-func (r *postRepository) HasViewed(postID int, ip string, since time.Time) (bool, error) {
+func (r *postRepository) HasViewed(postID int, visitorHash string, since time.Time) (bool, error) {
 	var count int64
 	err := r.db.Model(&domain.PostView{}).
-		Where("post_id = ? AND ip_address = ? AND viewed_at > ?", postID, ip, since).
+		Where("post_id = ? AND visitor_hash = ? AND viewed_at > ?", postID, visitorHash, since).
 		Count(&count).Error
 	return count > 0, err
 }
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RateLimitRepository interface untuk counter rate limit di Postgres
+// Memenuhi middleware.RateLimitStore sehingga limit berlaku sama di semua replica
+type RateLimitRepository interface {
+	// Increment menambah counter key pada window tertentu dan mengembalikan jumlah request di window tersebut
+	Increment(key string, windowStart, expiresAt time.Time) (int, error)
+
+	// DeleteExpired menghapus counter yang window-nya sudah lewat
+	DeleteExpired() error
+}
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewRateLimitRepository constructor untuk RateLimitRepository
+func NewRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &rateLimitRepository{db: db}
+}
+
+// Increment menambah counter secara atomik (upsert) sehingga aman dipanggil paralel dari banyak instance
+func (r *rateLimitRepository) Increment(key string, windowStart, expiresAt time.Time) (int, error) {
+	var count int
+	err := r.db.Raw(`
+		INSERT INTO rate_limit_counters (key, window_start, count, expires_at)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT (key, window_start) DO UPDATE SET count = rate_limit_counters.count + 1
+		RETURNING count`,
+		key, windowStart, expiresAt,
+	).Scan(&count).Error
+
+	return count, err
+}
+
+// DeleteExpired menghapus counter yang window-nya sudah lewat
+func (r *rateLimitRepository) DeleteExpired() error {
+	return r.db.Exec("DELETE FROM rate_limit_counters WHERE expires_at < ?", time.Now().UTC()).Error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// RoleRepository interface untuk data layer role dan permission
+type RoleRepository interface {
+	// FindAll mengambil semua role beserta permission-nya
+	FindAll() ([]domain.Role, error)
+
+	// FindByID mengambil role beserta permission-nya
+	FindByID(id int) (*domain.Role, error)
+
+	// ExistsByKey mengecek apakah key role sudah dipakai
+	ExistsByKey(key string) (bool, error)
+
+	// Create menyimpan role baru beserta permission-nya
+	Create(role *domain.Role) error
+
+	// Update menyimpan perubahan role dan mengganti seluruh permission-nya
+	Update(role *domain.Role) error
+
+	// Delete menghapus role
+	Delete(id int) error
+
+	// CountUsers menghitung user yang memakai role
+	CountUsers(roleID int) (int64, error)
+
+	// FindAllPermissions mengambil semua permission yang tersedia
+	FindAllPermissions() ([]domain.Permission, error)
+
+	// FindPermissionsByKeys mengambil permission berdasarkan key
+	FindPermissionsByKeys(keys []string) ([]domain.Permission, error)
+
+	// GetRolePermissionKeys mengambil key role dan key permission-nya (backend utils.PermissionStore)
+	// Return key kosong tanpa error jika role tidak ada
+	GetRolePermissionKeys(roleID int) (string, []string, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository constructor untuk RoleRepository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// FindAll mengambil semua role beserta permission-nya
+func (r *roleRepository) FindAll() ([]domain.Role, error) {
+	var roles []domain.Role
+	if err := r.db.Preload("Permissions").Order("id ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// FindByID mengambil role beserta permission-nya
+func (r *roleRepository) FindByID(id int) (*domain.Role, error) {
+	var role domain.Role
+	if err := r.db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// ExistsByKey mengecek apakah key role sudah dipakai
+func (r *roleRepository) ExistsByKey(key string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&domain.Role{}).Where("key = ?", key).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Create menyimpan role baru beserta permission-nya
+func (r *roleRepository) Create(role *domain.Role) error {
+	return r.db.Create(role).Error
+}
+
+// Update menyimpan perubahan role dan mengganti seluruh permission-nya dalam satu transaksi
+func (r *roleRepository) Update(role *domain.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+// Delete menghapus role (role_permissions ikut terhapus lewat ON DELETE CASCADE)
+func (r *roleRepository) Delete(id int) error {
+	return r.db.Delete(&domain.Role{}, id).Error
+}
+
+// CountUsers menghitung user yang memakai role, termasuk user yang sudah di-soft delete
+func (r *roleRepository) CountUsers(roleID int) (int64, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&domain.User{}).Where("role = ?", roleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindAllPermissions mengambil semua permission yang tersedia
+func (r *roleRepository) FindAllPermissions() ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if err := r.db.Order("key ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// FindPermissionsByKeys mengambil permission berdasarkan key
+func (r *roleRepository) FindPermissionsByKeys(keys []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if len(keys) == 0 {
+		return permissions, nil
+	}
+	if err := r.db.Where("key IN ?", keys).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetRolePermissionKeys mengambil key role dan key permission-nya
+func (r *roleRepository) GetRolePermissionKeys(roleID int) (string, []string, error) {
+	var role domain.Role
+	err := r.db.Select("id", "key").First(&role, roleID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var keys []string
+	err = r.db.Table("permissions").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", roleID).
+		Pluck("permissions.key", &keys).Error
+	if err != nil {
+		return "", nil, err
+	}
+
+	return role.Key, keys, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SessionRepository interface untuk data layer refresh token sessions
+type SessionRepository interface {
+	// Create menyimpan refresh token (session) baru
+	Create(session *domain.UserSession) error
+
+	// FindByTokenHash mencari session berdasarkan hash refresh token
+	FindByTokenHash(hash string) (*domain.UserSession, error)
+
+	// Rotate menandai session lama sebagai rotated dan menyimpan penggantinya dalam satu transaksi
+	// Return false jika session lama sudah di-rotate/revoke lebih dulu (request paralel / reuse)
+	Rotate(current *domain.UserSession, next *domain.UserSession) (bool, error)
+
+	// RevokeFamily mencabut semua refresh token dalam satu family
+	RevokeFamily(familyID string) error
+
+	// RevokeAllForUser mencabut semua refresh token milik user
+	RevokeAllForUser(userID int) error
+
+	// FindActiveByUser mengambil refresh token aktif milik user (satu per session/family)
+	FindActiveByUser(userID int) ([]domain.UserSession, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository constructor untuk SessionRepository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create menyimpan refresh token (session) baru
+func (r *sessionRepository) Create(session *domain.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByTokenHash mencari session berdasarkan hash refresh token
+func (r *sessionRepository) FindByTokenHash(hash string) (*domain.UserSession, error) {
+	var session domain.UserSession
+	if err := r.db.Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate menandai session lama sebagai rotated dan menyimpan penggantinya
+func (r *sessionRepository) Rotate(current *domain.UserSession, next *domain.UserSession) (bool, error) {
+	rotated := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// Update kondisional agar hanya satu request yang bisa me-rotate token yang sama
+		result := tx.Model(&domain.UserSession{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Updates(map[string]any{"rotated_at": now, "last_used_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+
+	return rotated, err
+}
+
+// RevokeFamily mencabut semua refresh token dalam satu family
+func (r *sessionRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&domain.UserSession{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllForUser mencabut semua refresh token milik user
+func (r *sessionRepository) RevokeAllForUser(userID int) error {
+	return r.db.Model(&domain.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// FindActiveByUser mengambil refresh token aktif milik user (satu per session/family)
+func (r *sessionRepository) FindActiveByUser(userID int) ([]domain.UserSession, error) {
+	var sessions []domain.UserSession
+	err := r.db.Where("user_id = ?", userID).
+		Where("rotated_at IS NULL AND revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&sessions).Error
+
+	return sessions, err
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -9,6 +11,8 @@ import (
 type SiteSettingRepository interface {
 	Get() (*domain.SiteSetting, error)
 	Update(setting *domain.SiteSetting) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) SiteSettingRepository
 }
 
 type siteSettingRepository struct {
@@ -20,6 +24,11 @@ func NewSiteSettingRepository(db *gorm.DB) SiteSettingRepository {
 	return &siteSettingRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *siteSettingRepository) WithContext(ctx context.Context) SiteSettingRepository {
+	return &siteSettingRepository{db: r.db.WithContext(ctx)}
+}
+
 // Get mengambil site settings (singleton - always ID 1)
 func (r *siteSettingRepository) Get() (*domain.SiteSetting, error) {
 	var setting domain.SiteSetting
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/config"
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
 )
 
 type TagRepository interface {
@@ -12,18 +15,27 @@ type TagRepository interface {
 	Create(tag *domain.Tag) error
 	Update(tag *domain.Tag) error
 	Delete(tag *domain.Tag) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) TagRepository
 }
 
-type tagRepository struct{}
+type tagRepository struct {
+	db *gorm.DB
+}
 
 func NewTagRepository() TagRepository {
-	return &tagRepository{}
+	return &tagRepository{db: config.DB}
+}
+
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *tagRepository) WithContext(ctx context.Context) TagRepository {
+	return &tagRepository{db: r.db.WithContext(ctx)}
 }
 
 func (r *tagRepository) FindAll(offset, limit int, search string) ([]domain.Tag, int64, error) {
 	var tags []domain.Tag
 	var total int64
-	db := config.DB.Model(&domain.Tag{})
+	db := r.db.Model(&domain.Tag{})
 
 	// Implementasi Search menggunakan ILIKE
 	if search != "" {
@@ -37,24 +49,24 @@ func (r *tagRepository) FindAll(offset, limit int, search string) ([]domain.Tag,
 
 func (r *tagRepository) FindByID(id string) (domain.Tag, error) {
 	var tag domain.Tag
-	err := config.DB.First(&tag, id).Error
+	err := r.db.First(&tag, id).Error
 	return tag, err
 }
 
 func (r *tagRepository) FindBySlug(slug string) (domain.Tag, error) {
 	var tag domain.Tag
-	err := config.DB.Where("slug = ?", slug).First(&tag).Error
+	err := r.db.Where("slug = ?", slug).First(&tag).Error
 	return tag, err
 }
 
 func (r *tagRepository) Create(tag *domain.Tag) error {
-	return config.DB.Create(tag).Error
+	return r.db.Create(tag).Error
 }
 
 func (r *tagRepository) Update(tag *domain.Tag) error {
-	return config.DB.Save(tag).Error
+	return r.db.Save(tag).Error
 }
 
 func (r *tagRepository) Delete(tag *domain.Tag) error {
-	return config.DB.Delete(tag).Error
+	return r.db.Delete(tag).Error
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -12,6 +14,8 @@ type TestimonialRepository interface {
 	FindByID(id int) (*domain.Testimonial, error)
 	Update(testimonial *domain.Testimonial) error
 	Delete(id int) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) TestimonialRepository
 }
 
 type testimonialRepository struct {
@@ -23,6 +27,11 @@ func NewTestimonialRepository(db *gorm.DB) TestimonialRepository {
 	return &testimonialRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *testimonialRepository) WithContext(ctx context.Context) TestimonialRepository {
+	return &testimonialRepository{db: r.db.WithContext(ctx)}
+}
+
 // Create membuat testimonial baru
 func (r *testimonialRepository) Create(testimonial *domain.Testimonial) error {
 	return r.db.Create(testimonial).Error
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRevocationRepository interface untuk penyimpanan token revocation di Postgres
+// Memenuhi utils.RevocationBackend sehingga bisa dipakai sebagai backend revocation store
+type TokenRevocationRepository interface {
+	// SaveRevokedToken menyimpan jti token yang dicabut
+	SaveRevokedToken(jti string, userID int, expiresAt time.Time) error
+
+	// IsTokenRevoked mengecek apakah jti sudah dicabut
+	IsTokenRevoked(jti string) (bool, error)
+
+	// DeleteExpiredTokens menghapus revocation untuk token yang sudah expired
+	DeleteExpiredTokens() error
+
+	// GetTokensValidAfter mengambil watermark token user
+	GetTokensValidAfter(userID int) (*time.Time, error)
+
+	// SetTokensValidAfter mengatur watermark token user
+	SetTokensValidAfter(userID int, validAfter time.Time) error
+}
+
+type tokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRevocationRepository constructor untuk TokenRevocationRepository
+func NewTokenRevocationRepository(db *gorm.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db: db}
+}
+
+// SaveRevokedToken menyimpan jti token yang dicabut (idempotent)
+func (r *tokenRevocationRepository) SaveRevokedToken(jti string, userID int, expiresAt time.Time) error {
+	token := domain.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now(),
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+}
+
+// IsTokenRevoked mengecek apakah jti sudah dicabut
+func (r *tokenRevocationRepository) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.RevokedToken{}).
+		Where("jti = ?", jti).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+// DeleteExpiredTokens menghapus revocation untuk token yang sudah expired
+func (r *tokenRevocationRepository) DeleteExpiredTokens() error {
+	return r.db.Where("expires_at < ?", time.Now()).Delete(&domain.RevokedToken{}).Error
+}
+
+// GetTokensValidAfter mengambil watermark token user
+func (r *tokenRevocationRepository) GetTokensValidAfter(userID int) (*time.Time, error) {
+	var validAfter sql.NullTime
+	err := r.db.Table("users").
+		Select("tokens_valid_after").
+		Where("id = ?", userID).
+		Row().
+		Scan(&validAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !validAfter.Valid {
+		return nil, nil
+	}
+	return &validAfter.Time, nil
+}
+
+// SetTokensValidAfter mengatur watermark token user
+func (r *tokenRevocationRepository) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	return r.db.Exec("UPDATE users SET tokens_valid_after = ? WHERE id = ?", validAfter, userID).Error
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TwoFactorRepository interface untuk data layer TOTP 2FA dan recovery code
+type TwoFactorRepository interface {
+	// FindByUserID mengambil data 2FA user, return nil tanpa error jika user belum pernah enrollment
+	FindByUserID(userID int) (*domain.UserTwoFactor, error)
+
+	// SavePending menyimpan secret baru yang belum dikonfirmasi (menimpa enrollment yang belum aktif)
+	SavePending(userID int, secret string) error
+
+	// Enable mengaktifkan 2FA dan menyimpan recovery code baru dalam satu transaksi
+	// Return false jika 2FA sudah aktif sebelumnya
+	Enable(userID int, step int64, codeHashes []string) (bool, error)
+
+	// UpdateLastStep mencatat time step yang dipakai, return false jika step tidak lebih baru (replay)
+	UpdateLastStep(userID int, step int64) (bool, error)
+
+	// Disable menghapus secret dan semua recovery code user
+	Disable(userID int) error
+
+	// ReplaceRecoveryCodes mengganti semua recovery code user dengan yang baru
+	ReplaceRecoveryCodes(userID int, codeHashes []string) error
+
+	// UseRecoveryCode menandai recovery code terpakai, return false jika tidak ada/sudah dipakai
+	UseRecoveryCode(userID int, codeHash string) (bool, error)
+
+	// CountRecoveryCodes menghitung recovery code yang belum dipakai
+	CountRecoveryCodes(userID int) (int64, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository constructor untuk TwoFactorRepository
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// FindByUserID mengambil data 2FA user
+func (r *twoFactorRepository) FindByUserID(userID int) (*domain.UserTwoFactor, error) {
+	var twoFactor domain.UserTwoFactor
+	err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SavePending menyimpan secret enrollment baru
+// Enrollment yang sudah aktif tidak disentuh (kondisi WHERE pada upsert)
+func (r *twoFactorRepository) SavePending(userID int, secret string) error {
+	twoFactor := &domain.UserTwoFactor{
+		UserID: userID,
+		Secret: secret,
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"secret":         secret,
+			"last_used_step": nil,
+			"updated_at":     time.Now(),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: `"user_two_factors"."enabled_at" IS NULL`},
+		}},
+	}).Create(twoFactor).Error
+}
+
+// Enable mengaktifkan 2FA dan menyimpan recovery code baru
+func (r *twoFactorRepository) Enable(userID int, step int64, codeHashes []string) (bool, error) {
+	enabled := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.UserTwoFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Updates(map[string]any{
+				"enabled_at":     now,
+				"last_used_step": step,
+				"updated_at":     now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		enabled = true
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+
+	return enabled, err
+}
+
+// UpdateLastStep mencatat time step yang dipakai (update kondisional agar kode tidak bisa dipakai ulang)
+func (r *twoFactorRepository) UpdateLastStep(userID int, step int64) (bool, error) {
+	result := r.db.Model(&domain.UserTwoFactor{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL AND (last_used_step IS NULL OR last_used_step < ?)", userID, step).
+		Updates(map[string]any{
+			"last_used_step": step,
+			"updated_at":     time.Now(),
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// Disable menghapus secret dan semua recovery code user
+func (r *twoFactorRepository) Disable(userID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.UserTwoFactor{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes mengganti semua recovery code user dengan yang baru
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode menandai recovery code terpakai (update kondisional agar single-use)
+func (r *twoFactorRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	result := r.db.Model(&domain.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountRecoveryCodes menghitung recovery code yang belum dipakai
+func (r *twoFactorRepository) CountRecoveryCodes(userID int) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// replaceRecoveryCodes menghapus recovery code lama lalu menyimpan yang baru (dipanggil di dalam transaksi)
+func replaceRecoveryCodes(tx *gorm.DB, userID int, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&domain.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]domain.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, domain.UserRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	return tx.Create(&codes).Error
+}
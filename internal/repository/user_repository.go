@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
 )
@@ -14,6 +16,8 @@ type UserRepository interface {
 	Create(user *domain.User) error
 	Update(user *domain.User) error
 	Delete(id int) error
+	// WithContext mengikat query ke context request agar perubahan data tercatat di audit log
+	WithContext(ctx context.Context) UserRepository
 }
 
 type userRepository struct {
@@ -25,6 +29,11 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *userRepository) WithContext(ctx context.Context) UserRepository {
+	return &userRepository{db: r.db.WithContext(ctx)}
+}
+
 // FindByEmail mencari user berdasarkan email
 func (r *userRepository) FindByEmail(email string) (*domain.User, error) {
 	var user domain.User
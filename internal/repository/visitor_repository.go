@@ -5,21 +5,37 @@ import (
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // VisitorRepository interface untuk visitor tracking
 type VisitorRepository interface {
-	// RecordVisit mencatat visitor baru (1 IP = 1 record per hari)
-	RecordVisit(ipAddress string, platform string) error
+	// RecordVisit mencatat visitor baru (1 hash IP = 1 record per hari)
+	RecordVisit(visitor *domain.Visitor) error
 
-	// HasVisitedToday mengecek apakah IP sudah tercatat hari ini
-	HasVisitedToday(ipAddress string) (bool, error)
+	// RecordSource mengisi sumber kunjungan (referrer/UTM) pada record visitor hari ini
+	// yang belum punya sumber, atau mencatat visitor baru jika belum ada record hari ini
+	RecordSource(visitor *domain.Visitor) error
+
+	// HasVisitedToday mengecek apakah hash IP sudah tercatat hari ini
+	HasVisitedToday(visitorHash string) (bool, error)
 
 	// GetUniqueVisitors menghitung unique visitors dalam periode tertentu
 	GetUniqueVisitors(startDate, endDate time.Time) (int64, error)
 
 	// GetDailyVisitors mendapatkan jumlah visitor per hari
 	GetDailyVisitors(startDate, endDate time.Time) ([]DailyVisitors, error)
+
+	// GetOrCreateSalt mengambil salt hari tertentu, candidate disimpan jika belum ada
+	// Aman dipanggil bersamaan dari beberapa replica, semua mendapat salt yang sama
+	GetOrCreateSalt(day time.Time, candidate []byte) ([]byte, error)
+
+	// DeleteSaltsBefore menghapus salt sebelum hari tertentu agar hash lama tidak bisa dibalik
+	DeleteSaltsBefore(day time.Time) (int64, error)
+
+	// AnonymizeBefore mengosongkan hash IP, provinsi dan kota (serta user agent post view) pada data visitor
+	// dan post view yang lebih tua dari batas retensi, baris tetap disimpan agar jumlah di dashboard tidak berubah
+	AnonymizeBefore(before time.Time) (visitors int64, postViews int64, err error)
 }
 
 type visitorRepository struct {
@@ -32,9 +48,9 @@ func NewVisitorRepository(db *gorm.DB) VisitorRepository {
 }
 
 // RecordVisit mencatat visitor baru jika belum ada record hari ini
-func (r *visitorRepository) RecordVisit(ipAddress string, platform string) error {
-	// Cek apakah IP sudah tercatat hari ini
-	hasVisited, err := r.HasVisitedToday(ipAddress)
+func (r *visitorRepository) RecordVisit(visitor *domain.Visitor) error {
+	// Cek apakah visitor sudah tercatat hari ini
+	hasVisited, err := r.HasVisitedToday(*visitor.VisitorHash)
 	if err != nil {
 		return err
 	}
@@ -45,22 +61,38 @@ func (r *visitorRepository) RecordVisit(ipAddress string, platform string) error
 	}
 
 	// Insert record baru
-	visitor := domain.Visitor{
-		IPAddress: ipAddress,
-		Platform:  &platform,
-		VisitedAt: time.Now(),
+	visitor.VisitedAt = time.Now()
+	return r.db.Create(visitor).Error
+}
+
+// RecordSource memakai atribusi first-touch: sumber yang sudah tercatat hari ini tidak ditimpa
+func (r *visitorRepository) RecordSource(visitor *domain.Visitor) error {
+	today := time.Now().Format("2006-01-02")
+
+	result := r.db.Model(&domain.Visitor{}).
+		Where("visitor_hash = ?", *visitor.VisitorHash).
+		Where("DATE(visited_at) = ?", today).
+		Where("referrer_host IS NULL AND utm_source IS NULL AND utm_campaign IS NULL").
+		Updates(map[string]any{
+			"referrer_host": visitor.ReferrerHost,
+			"utm_source":    visitor.UTMSource,
+			"utm_medium":    visitor.UTMMedium,
+			"utm_campaign":  visitor.UTMCampaign,
+		})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
 	}
 
-	return r.db.Create(&visitor).Error
+	return r.RecordVisit(visitor)
 }
 
-// HasVisitedToday mengecek apakah IP sudah tercatat hari ini
-func (r *visitorRepository) HasVisitedToday(ipAddress string) (bool, error) {
+// HasVisitedToday mengecek apakah hash IP sudah tercatat hari ini
+func (r *visitorRepository) HasVisitedToday(visitorHash string) (bool, error) {
 	var count int64
 	today := time.Now().Format("2006-01-02")
 
 	err := r.db.Model(&domain.Visitor{}).
-		Where("ip_address = ?", ipAddress).
+		Where("visitor_hash = ?", visitorHash).
 		Where("DATE(visited_at) = ?", today).
 		Count(&count).Error
 
@@ -90,3 +122,48 @@ func (r *visitorRepository) GetDailyVisitors(startDate, endDate time.Time) ([]Da
 
 	return results, err
 }
+
+// GetOrCreateSalt menyimpan candidate sebagai salt hari itu jika belum ada, lalu membaca salt yang berlaku
+func (r *visitorRepository) GetOrCreateSalt(day time.Time, candidate []byte) ([]byte, error) {
+	salt := domain.VisitorSalt{Day: day, Salt: candidate}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&salt).Error; err != nil {
+		return nil, err
+	}
+
+	var stored domain.VisitorSalt
+	if err := r.db.Where("day = ?", day.Format("2006-01-02")).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	return stored.Salt, nil
+}
+
+// DeleteSaltsBefore menghapus salt yang sudah tidak dipakai
+func (r *visitorRepository) DeleteSaltsBefore(day time.Time) (int64, error) {
+	result := r.db.Where("day < ?", day.Format("2006-01-02")).Delete(&domain.VisitorSalt{})
+	return result.RowsAffected, result.Error
+}
+
+// AnonymizeBefore mengosongkan kolom identitas dalam satu transaksi
+func (r *visitorRepository) AnonymizeBefore(before time.Time) (visitors int64, postViews int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Visitor{}).
+			Where("visited_at < ?", before).
+			Where("visitor_hash IS NOT NULL OR region IS NOT NULL OR city IS NOT NULL").
+			Updates(map[string]any{"visitor_hash": nil, "region": nil, "city": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		visitors = result.RowsAffected
+
+		result = tx.Model(&domain.PostView{}).
+			Where("viewed_at < ?", before).
+			Where("visitor_hash IS NOT NULL OR user_agent IS NOT NULL OR region IS NOT NULL OR city IS NOT NULL").
+			Updates(map[string]any{"visitor_hash": nil, "user_agent": nil, "region": nil, "city": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		postViews = result.RowsAffected
+		return nil
+	})
+	return visitors, postViews, err
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository interface untuk data layer webhook dan log pengirimannya
+type WebhookRepository interface {
+	Create(webhook *domain.Webhook) error
+	FindAll() ([]domain.Webhook, error)
+	FindByID(id int) (*domain.Webhook, error)
+	Update(webhook *domain.Webhook) error
+	Delete(webhook *domain.Webhook) error
+
+	// FindActiveByEvent mengambil webhook aktif yang berlangganan event
+	FindActiveByEvent(event string) ([]domain.Webhook, error)
+
+	// CreateDeliveries memasukkan delivery baru ke antrian
+	CreateDeliveries(deliveries []domain.WebhookDelivery) error
+
+	// ClaimDueDeliveries mengambil delivery pending yang sudah jatuh tempo dan menundanya selama lease
+	// agar tidak diambil worker lain (replica lain) selama sedang dikirim
+	ClaimDueDeliveries(now time.Time, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+
+	// SaveDeliveryResult menyimpan hasil satu percobaan kirim
+	SaveDeliveryResult(delivery *domain.WebhookDelivery) error
+
+	FindDeliveries(webhookID, offset, limit int) ([]domain.WebhookDelivery, int64, error)
+	FindDeliveryByID(id int64) (*domain.WebhookDelivery, error)
+
+	// WithContext mengikat query ke context request agar perubahan webhook tercatat di audit log
+	WithContext(ctx context.Context) WebhookRepository
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository constructor untuk WebhookRepository
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// WithContext mengembalikan repository yang query-nya membawa context request
+func (r *webhookRepository) WithContext(ctx context.Context) WebhookRepository {
+	return &webhookRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create menyimpan webhook baru
+func (r *webhookRepository) Create(webhook *domain.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+// FindAll mengambil semua webhook, terbaru lebih dulu
+func (r *webhookRepository) FindAll() ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	if err := r.db.Order("created_at DESC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// FindByID mengambil webhook berdasarkan ID
+func (r *webhookRepository) FindByID(id int) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Update menyimpan perubahan webhook
+func (r *webhookRepository) Update(webhook *domain.Webhook) error {
+	webhook.UpdatedAt = time.Now()
+	return r.db.Save(webhook).Error
+}
+
+// Delete menghapus webhook beserta log pengirimannya (ON DELETE CASCADE)
+func (r *webhookRepository) Delete(webhook *domain.Webhook) error {
+	return r.db.Delete(webhook).Error
+}
+
+// FindActiveByEvent mengambil webhook aktif yang events-nya berisi event
+func (r *webhookRepository) FindActiveByEvent(event string) ([]domain.Webhook, error) {
+	filter, err := json.Marshal([]string{event})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []domain.Webhook
+	if err := r.db.Where("is_active = ? AND events @> ?::jsonb", true, string(filter)).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateDeliveries memasukkan delivery baru ke antrian
+func (r *webhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+// ClaimDueDeliveries memakai FOR UPDATE SKIP LOCKED agar beberapa worker bisa berjalan bersamaan
+func (r *webhookRepository) ClaimDueDeliveries(now time.Time, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		return tx.Model(&domain.WebhookDelivery{}).
+			Where("id IN ?", deliveryIDs(deliveries)).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	// Webhook dimuat setelah claim, perubahan URL/secret terbaru langsung dipakai
+	if err := r.db.Preload("Webhook").Find(&deliveries, "id IN ?", deliveryIDs(deliveries)).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// SaveDeliveryResult menyimpan status, jumlah percobaan dan jadwal berikutnya
+func (r *webhookRepository) SaveDeliveryResult(delivery *domain.WebhookDelivery) error {
+	return r.db.Model(&domain.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_status": delivery.ResponseStatus,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// FindDeliveries mengambil log pengiriman satu webhook, terbaru lebih dulu
+func (r *webhookRepository) FindDeliveries(webhookID, offset, limit int) ([]domain.WebhookDelivery, int64, error) {
+	var deliveries []domain.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// FindDeliveryByID mengambil satu delivery beserta webhook-nya
+func (r *webhookRepository) FindDeliveryByID(id int64) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := r.db.Preload("Webhook").First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func deliveryIDs(deliveries []domain.WebhookDelivery) []int64 {
+	ids := make([]int64, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.ID
+	}
+	return ids
+}
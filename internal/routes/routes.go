@@ -2,14 +2,16 @@ package routes
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/garuda-labs-1/pmii-be/config"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/handlers"
 	"github.com/garuda-labs-1/pmii-be/internal/middleware"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 	"github.com/garuda-labs-1/pmii-be/internal/service"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
 // SetupRoutes mengatur semua routing untuk aplikasi
@@ -29,7 +31,19 @@ func SetupRoutes(
 	publicDocumentHandler *handlers.PublicDocumentHandler,
 	dashboardHandler *handlers.DashboardHandler,
 	publicSiteSettingHandler *handlers.PublicSiteSettingHandler,
+	accountLockoutHandler *handlers.AccountLockoutHandler,
+	oidcHandler *handlers.OIDCHandler,
+	invitationHandler *handlers.InvitationHandler,
+	auditChainHandler *handlers.AuditChainHandler,
+	activityLogArchiveHandler *handlers.ActivityLogArchiveHandler,
+	webhookHandler *handlers.WebhookHandler,
+	cspReportHandler *handlers.CSPReportHandler,
+	webhooks service.WebhookPublisher,
 	visitorRepo repository.VisitorRepository,
+	visitorPrivacy service.VisitorPrivacyService,
+	geoLocator service.GeoLocator,
+	security config.SecurityConfig,
+	rateLimit config.RateLimitConfig,
 	allowedOrigins string,
 	environment string,
 ) {
@@ -44,26 +58,62 @@ func SetupRoutes(
 	newsSvc := service.NewNewsService(newsRepo)
 	newsHandler := handlers.NewNewsHandler(newsSvc)
 
+	userRepo := repository.NewUserRepository(config.DB) // Pastikan Anda memiliki fungsi New ini
 	postRepo := repository.NewPostRepository(config.DB)
-	postSvc := service.NewPostService(postRepo, activityLogRepo)
+	postSvc := service.NewPostService(postRepo, userRepo, webhooks, visitorPrivacy, geoLocator)
 	postHandler := handlers.NewPostHandler(postSvc)
+	privacyHandler := handlers.NewPrivacyHandler(visitorPrivacy)
+
+	// Visitor analytics: kunjungan dicatat middleware, sumber kunjungan (referrer/UTM) dari beacon frontend
+	visitorSvc := service.NewVisitorService(visitorRepo, visitorPrivacy, geoLocator, strings.Split(allowedOrigins, ","))
+	visitorHandler := handlers.NewVisitorHandler(visitorSvc)
 
 	catRepo := repository.NewCategoryRepository()
-	catSvc := service.NewCategoryService(catRepo, activityLogRepo)
+	catSvc := service.NewCategoryService(catRepo)
 	catHandler := handlers.NewCategoryHandler(catSvc)
 
 	tagRepo := repository.NewTagRepository()
-	tagSvc := service.NewTagService(tagRepo, activityLogRepo)
+	tagSvc := service.NewTagService(tagRepo)
 	tagHandler := handlers.NewTagHandler(tagSvc)
 
-	userRepo := repository.NewUserRepository(config.DB) // Pastikan Anda memiliki fungsi New ini
 	inboxRepo := repository.NewInboxRepository()
 	inboxSvc := service.NewInboxService(inboxRepo, userRepo) // Gunakan userRepo langsung
-	inboxHandler := handlers.NewInboxHandler(inboxSvc)
+	inboxHandler := handlers.NewInboxHandler(inboxSvc, strings.Split(allowedOrigins, ","))
+
+	// Inisialisasi Dependency untuk Session Management
+	sessionRepo := repository.NewSessionRepository(config.DB)
+	sessionSvc := service.NewSessionService(sessionRepo, userRepo, activityLogRepo)
+	sessionHandler := handlers.NewSessionHandler(sessionSvc)
+
+	// Inisialisasi Dependency untuk Two-Factor Authentication
+	twoFactorRepo := repository.NewTwoFactorRepository(config.DB)
+	siteSettingRepo := repository.NewSiteSettingRepository(config.DB)
+	twoFactorSvc := service.NewTwoFactorService(userRepo, twoFactorRepo, siteSettingRepo, activityLogRepo)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorSvc)
+
+	// Inisialisasi Dependency untuk Role & Permission Management
+	roleRepo := repository.NewRoleRepository(config.DB)
+	roleSvc := service.NewRoleService(roleRepo, activityLogRepo)
+	roleHandler := handlers.NewRoleHandler(roleSvc)
+
+	// Inisialisasi Dependency untuk API Key integrasi (machine-to-machine)
+	apiKeyRepo := repository.NewAPIKeyRepository(config.DB)
+	apiKeySvc := service.NewAPIKeyService(apiKeyRepo, userRepo, activityLogRepo)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeySvc)
+
+	// Inisialisasi Dependency untuk Impersonasi user oleh admin
+	impersonationSvc := service.NewImpersonationService(userRepo, activityLogRepo)
+	impersonationHandler := handlers.NewImpersonationHandler(impersonationSvc)
+
+	// Inisialisasi Dependency untuk allowlist IP admin API
+	adminIPAllowlistRepo := repository.NewAdminIPAllowlistRepository(config.DB)
+	adminIPAllowlistSvc := service.NewAdminIPAllowlistService(adminIPAllowlistRepo, activityLogRepo, security.AdminIPAllowlistBypass)
+	adminIPAllowlistHandler := handlers.NewAdminIPAllowlistHandler(adminIPAllowlistSvc)
+	adminIPAllowlist := middleware.AdminIPAllowlist(adminIPAllowlistSvc, activityLogRepo)
 
 	// Inisialisasi Dependency untuk Ads Management
 	adRepo := repository.NewAdRepository()
-	adSvc := service.NewAdService(adRepo, config.CloudinaryService, activityLogRepo)
+	adSvc := service.NewAdService(adRepo, config.CloudinaryService)
 	adHandler := handlers.NewAdHandler(adSvc)
 	publicAdSvc := service.NewPublicAdService(adRepo, config.CloudinaryService)
 	publicAdHandler := handlers.NewPublicAdHandler(publicAdSvc)
@@ -71,11 +121,46 @@ func SetupRoutes(
 	// Global Middlewares
 	r.Use(middleware.Recovery())
 	r.Use(middleware.CORS(allowedOrigins))
-	r.Use(middleware.RequestInfoMiddleware())     // Inject IP and User-Agent for activity logging
-	r.Use(middleware.VisitorTracker(visitorRepo)) // Track unique visitors per day
-
-	// Rate Limiter untuk login endpoint (60 request per menit = 1 req/s, burst 60)
-	loginLimiter := middleware.NewRateLimiter(rate.Limit(1), 60)
+	r.Use(middleware.SecurityHeaders(middleware.SecurityHeadersConfig{ // Header keamanan default untuk route publik
+		HSTSMaxAge:            security.HSTSMaxAge,
+		ContentSecurityPolicy: security.CSP,
+		CSPReportOnly:         security.CSPReportOnly,
+		CSPReportURI:          security.CSPReportURI,
+		FrameOptions:          "SAMEORIGIN",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     middleware.DefaultPermissionsPolicy,
+	}))
+	r.Use(middleware.RequestInfoMiddleware())             // Inject IP and User-Agent for activity logging
+	r.Use(middleware.ImpersonationAudit(activityLogRepo)) // Catat setiap request dengan token impersonasi
+	r.Use(middleware.VisitorTracker(visitorSvc))          // Track unique visitors per day
+
+	// Rate Limiter dengan policy per route group, dihitung per user jika sudah login atau per IP jika belum
+	// Store Postgres dipakai saat API berjalan lebih dari satu replica agar limit berlaku bersama
+	var rateLimitStore middleware.RateLimitStore = middleware.NewMemoryRateLimitStore()
+	if rateLimit.Store == "postgres" {
+		rateLimitStore = repository.NewRateLimitRepository(config.DB)
+	}
+	rateLimiter := middleware.NewRateLimiter(rateLimitStore, rateLimitPolicies(rateLimit))
+	loginLimit := rateLimiter.Limit(middleware.RateLimitLogin)
+	passwordResetLimit := rateLimiter.Limit(middleware.RateLimitPasswordReset)
+	uploadLimit := rateLimiter.Limit(middleware.RateLimitUpload)
+	cspReportLimit := rateLimiter.Limit(middleware.RateLimitCSPReport)
+	// Dipasang sebelum allowlist IP admin: request belum terautentikasi sehingga limit dihitung per IP
+	adminGateLimit := rateLimiter.Limit(middleware.RateLimitAdminGate)
+	publicRateLimit := rateLimiter.LimitByMethod(middleware.RateLimitPublicRead, middleware.RateLimitPublicWrite)
+	authenticatedRateLimit := rateLimiter.LimitByMethod(middleware.RateLimitAuthenticatedRead, middleware.RateLimitAuthenticatedWrite)
+
+	// Header keamanan lebih ketat untuk route admin, auth dan akun user (menimpa header global)
+	strictSecurityHeaders := middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		HSTSMaxAge:            security.HSTSMaxAge,
+		ContentSecurityPolicy: security.AdminCSP,
+		CSPReportOnly:         security.CSPReportOnly,
+		CSPReportURI:          security.CSPReportURI,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "no-referrer",
+		PermissionsPolicy:     middleware.DefaultPermissionsPolicy,
+		CacheControl:          "no-store",
+	})
 
 	// Health Check Routes
 	r.GET("/", func(c *gin.Context) {
@@ -94,145 +179,274 @@ func SetupRoutes(
 		})
 	})
 
+	// JWKS - public key untuk verifikasi JWT oleh service lain (RS256/EdDSA)
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, utils.JWKS())
+	})
+
 	// API Routes - Base URL: /v1 (Development)
 	v1 := r.Group("/v1")
 	{
 		// Public Routes - Authentication
 		auth := v1.Group("/auth")
+		auth.Use(strictSecurityHeaders)
 		{
-			// Login dengan rate limiter (60 req/menit per IP)
-			auth.POST("/login", loginLimiter.Limit(), authHandler.Login)
+			// Login dengan rate limiter (policy login, per IP)
+			auth.POST("/login", loginLimit, authHandler.Login)
 
-			// Logout (butuh auth)
-			auth.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
+			// Refresh token rotation (dibatasi rate limiter yang sama dengan login)
+			auth.POST("/refresh", loginLimit, authHandler.Refresh)
+
+			// Login dua langkah (2FA) dengan challenge token dari /login
+			auth.POST("/2fa/verify", loginLimit, authHandler.VerifyTwoFactor)
+			auth.POST("/2fa/setup", loginLimit, authHandler.BeginTwoFactorSetup)
+			auth.POST("/2fa/setup/confirm", loginLimit, authHandler.ConfirmTwoFactorSetup)
+
+			// Logout (access token atau refresh token, tidak harus masih berlaku keduanya)
+			auth.POST("/logout", loginLimit, authHandler.Logout)
 
 			// Ubah/ganti password
-			auth.POST("/change-password", middleware.AuthMiddleware(), authHandler.ChangePassword)
+			auth.POST("/change-password", middleware.AuthMiddleware(), authenticatedRateLimit, middleware.BlockImpersonation(), authHandler.ChangePassword)
+
+			// Lupa password: kirim link reset ke email, lalu reset dengan token dari email
+			auth.POST("/forgot-password", passwordResetLimit, authHandler.ForgotPassword)
+			auth.POST("/reset-password", passwordResetLimit, authHandler.ResetPassword)
+
+			// Undangan user baru - cek link dan buat akun dengan password sendiri
+			auth.POST("/invitations/verify", passwordResetLimit, invitationHandler.Verify)
+			auth.POST("/invitations/accept", passwordResetLimit, invitationHandler.Accept)
+
+			// SSO OpenID Connect (authorization code + PKCE)
+			auth.GET("/oidc/providers", publicRateLimit, oidcHandler.GetProviders)
+			auth.POST("/oidc/:provider/authorize", loginLimit, oidcHandler.Authorize)
+			auth.POST("/oidc/:provider/callback", loginLimit, oidcHandler.Callback)
 		}
 
-		v1.GET("/news", newsHandler.GetNewsList)                   // GET /v1/news
-		v1.GET("/news/:slug", postHandler.GetPost)                 // GET /v1/news/:slug
-		v1.GET("/categories/:slug", newsHandler.GetNewsByCategory) // GET /v1/categories/:slug
+		// Public Routes - tanpa autentikasi, rate limit per IP (public-read untuk GET, public-write untuk POST)
+		public := v1.Group("")
+		public.Use(publicRateLimit)
+
+		public.GET("/news", newsHandler.GetNewsList)                   // GET /v1/news
+		public.GET("/news/:slug", postHandler.GetPost)                 // GET /v1/news/:slug
+		public.GET("/categories/:slug", newsHandler.GetNewsByCategory) // GET /v1/categories/:slug
 
 		// Public Routes - About Page (No Authentication Required)
-		v1.GET("/about", publicAboutHandler.GetAboutPage)                               // GET /v1/about
-		v1.GET("/about/departments", publicAboutHandler.GetDepartments)                 // GET /v1/about/departments
-		v1.GET("/about/members/:department", publicAboutHandler.GetMembersByDepartment) // GET /v1/about/members/:department
+		public.GET("/about", publicAboutHandler.GetAboutPage)                               // GET /v1/about
+		public.GET("/about/departments", publicAboutHandler.GetDepartments)                 // GET /v1/about/departments
+		public.GET("/about/members/:department", publicAboutHandler.GetMembersByDepartment) // GET /v1/about/members/:department
 
 		// Public Routes - Home Page (No Authentication Required)
-		v1.GET("/home/hero", publicHomeHandler.GetHeroSection)               // GET /v1/home/hero
-		v1.GET("/home/latest-news", publicHomeHandler.GetLatestNewsSection)  // GET /v1/home/latest-news
-		v1.GET("/home/about-us", publicHomeHandler.GetAboutUsSection)        // GET /v1/home/about-us
-		v1.GET("/home/why", publicHomeHandler.GetWhySection)                 // GET /v1/home/why
-		v1.GET("/home/testimonial", publicHomeHandler.GetTestimonialSection) // GET /v1/home/testimonial
-		v1.GET("/home/faq", publicHomeHandler.GetFaqSection)                 // GET /v1/home/faq
-		v1.GET("/home/cta", publicHomeHandler.GetCtaSection)                 // GET /v1/home/cta
-		v1.GET("/documents", publicDocumentHandler.GetAllPublic)             // GET /v1/documents
-		v1.GET("/documents/:type", publicDocumentHandler.GetByTypePublic)    // GET /v1/documents/:type
+		public.GET("/home/hero", publicHomeHandler.GetHeroSection)               // GET /v1/home/hero
+		public.GET("/home/latest-news", publicHomeHandler.GetLatestNewsSection)  // GET /v1/home/latest-news
+		public.GET("/home/about-us", publicHomeHandler.GetAboutUsSection)        // GET /v1/home/about-us
+		public.GET("/home/why", publicHomeHandler.GetWhySection)                 // GET /v1/home/why
+		public.GET("/home/testimonial", publicHomeHandler.GetTestimonialSection) // GET /v1/home/testimonial
+		public.GET("/home/faq", publicHomeHandler.GetFaqSection)                 // GET /v1/home/faq
+		public.GET("/home/cta", publicHomeHandler.GetCtaSection)                 // GET /v1/home/cta
+		public.GET("/documents", publicDocumentHandler.GetAllPublic)             // GET /v1/documents
+		public.GET("/documents/:type", publicDocumentHandler.GetByTypePublic)    // GET /v1/documents/:type
 
 		// Public Routes - Ads (No Authentication Required)
-		v1.GET("/ads/pages", publicAdHandler.GetAvailablePages) // GET /v1/ads/pages
-		v1.GET("/ads/:page", publicAdHandler.GetAdsByPage)      // GET /v1/ads/:page
+		public.GET("/ads/pages", publicAdHandler.GetAvailablePages) // GET /v1/ads/pages
+		public.GET("/ads/:page", publicAdHandler.GetAdsByPage)      // GET /v1/ads/:page
 
 		// Public Routes - Site Settings (No Authentication Required)
-		v1.GET("/settings", publicSiteSettingHandler.Get) // GET /v1/settings
+		public.GET("/settings", publicSiteSettingHandler.Get) // GET /v1/settings
+
+		// Public Routes - Beacon sumber kunjungan (referrer, UTM) dari frontend
+		public.POST("/analytics/beacon", visitorHandler.Beacon) // POST /v1/analytics/beacon
 
-		// Admin Routes - Requires Admin Role (Level 1)
+		// Public Routes - Keterangan pemrosesan data visitor untuk kebijakan privasi
+		public.GET("/privacy/data-processing", privacyHandler.GetDataProcessingNotice) // GET /v1/privacy/data-processing
+
+		// Laporan pelanggaran CSP dari browser (report-uri / Reporting API, tanpa autentikasi)
+		public.POST("/csp-report", cspReportLimit, cspReportHandler.Report) // POST /v1/csp-report
+
+		// Admin Routes - Requires Authentication dan IP di allowlist, akses per fitur dicek lewat permission role
 		adminRoutes := v1.Group("/admin")
-		adminRoutes.Use(middleware.AuthMiddleware(), middleware.RequireRole("1"))
+		adminRoutes.Use(strictSecurityHeaders, adminGateLimit, adminIPAllowlist, middleware.AuthMiddleware(), authenticatedRateLimit)
 		{
 			// Dashboard Routes - Admin with Activity Logs
-			adminRoutes.GET("/dashboard", dashboardHandler.GetDashboard)                // GET /v1/admin/dashboard?year=2026&month=1
-			adminRoutes.GET("/dashboard/periods", dashboardHandler.GetAvailablePeriods) // GET /v1/admin/dashboard/periods
-
-			// Testimonial Routes - Admin Only
-			adminRoutes.POST("/testimonials", testimonialHandler.Create)       // POST /v1/admin/testimonials
-			adminRoutes.GET("/testimonials", testimonialHandler.GetAll)        // GET /v1/admin/testimonials
-			adminRoutes.GET("/testimonials/:id", testimonialHandler.GetByID)   // GET /v1/admin/testimonials/:id
-			adminRoutes.PUT("/testimonials/:id", testimonialHandler.Update)    // PUT /v1/admin/testimonials/:id
-			adminRoutes.DELETE("/testimonials/:id", testimonialHandler.Delete) // DELETE /v1/admin/testimonials/:id
-
-			// Member Routes - Admin Only
-			adminRoutes.POST("/members", memberHandler.Create)       // POST /v1/admin/members
-			adminRoutes.GET("/members", memberHandler.GetAll)        // GET /v1/admin/members
-			adminRoutes.GET("/members/:id", memberHandler.GetByID)   // GET /v1/admin/members/:id
-			adminRoutes.PUT("/members/:id", memberHandler.Update)    // PUT /v1/admin/members/:id
-			adminRoutes.DELETE("/members/:id", memberHandler.Delete) // DELETE /v1/admin/members/:id
-
-			// User Management Routes - Admin Only
-			adminRoutes.GET("/users", userHandler.GetAllUsers)           // GET /v1/admin/users
-			adminRoutes.GET("/users/:id", userHandler.GetUserByID)       // GET /v1/admin/users/:id
-			adminRoutes.POST("/users", userHandler.CreateUser)           // POST /v1/admin/users
-			adminRoutes.PUT("/users/:id", userHandler.UpdateUserByID)    // PUT /v1/admin/users/:id
-			adminRoutes.DELETE("/users/:id", userHandler.DeleteUserByID) // DELETE /v1/admin/users/:id
-			// About Routes - Admin Only (singleton - only GET and PUT)
-			adminRoutes.GET("/about", aboutHandler.Get)    // GET /v1/admin/about
-			adminRoutes.PUT("/about", aboutHandler.Update) // PUT /v1/admin/about
-
-			// Site Settings Routes - Admin Only (singleton - only GET and PUT)
-			adminRoutes.GET("/settings", siteSettingHandler.Get)    // GET /v1/admin/settings
-			adminRoutes.PUT("/settings", siteSettingHandler.Update) // PUT /v1/admin/settings
-
-			// Contact Routes - Admin Only (singleton - only GET and PUT)
-			adminRoutes.GET("/contact", contactHandler.Get)    // GET /v1/admin/contact
-			adminRoutes.PUT("/contact", contactHandler.Update) // PUT /v1/admin/contact
-
-			// Document Routes - Admin Only
-			adminRoutes.GET("/documents/types", documentHandler.GetTypes) // GET /v1/admin/documents/types
-			adminRoutes.POST("/documents", documentHandler.Create)        // POST /v1/admin/documents
-			adminRoutes.GET("/documents", documentHandler.GetAll)         // GET /v1/admin/documents
-			adminRoutes.GET("/documents/:id", documentHandler.GetByID)    // GET /v1/admin/documents/:id
-			adminRoutes.PUT("/documents/:id", documentHandler.Update)     // PUT /v1/admin/documents/:id
-			adminRoutes.DELETE("/documents/:id", documentHandler.Delete)  // DELETE /v1/admin/documents/:id
-
-			// Activity Log Routes - Admin Only
-			adminRoutes.GET("/activity-logs", activityLogHandler.GetActivityLogs) // GET /v1/admin/activity-logs
-
-			// Ads Management Routes - Admin Only
-			adminRoutes.GET("/ads", adHandler.GetAllAds)                  // GET /v1/admin/ads
-			adminRoutes.GET("/ads/:id", adHandler.GetAdByID)              // GET /v1/admin/ads/:id
-			adminRoutes.GET("/ads/page/:page", adHandler.GetAdsByPage)    // GET /v1/admin/ads/page/:page
-			adminRoutes.PUT("/ads/:id", adHandler.UpdateAd)               // PUT /v1/admin/ads/:id (upload image)
-			adminRoutes.DELETE("/ads/:id/image", adHandler.DeleteAdImage) // DELETE /v1/admin/ads/:id/image
+			adminRoutes.GET("/dashboard", middleware.RequirePermission(domain.PermDashboardView), dashboardHandler.GetDashboard)                // GET /v1/admin/dashboard?year=2026&month=1
+			adminRoutes.GET("/dashboard/periods", middleware.RequirePermission(domain.PermDashboardView), dashboardHandler.GetAvailablePeriods) // GET /v1/admin/dashboard/periods
+
+			// Testimonial Routes
+			adminRoutes.POST("/testimonials", middleware.RequirePermission(domain.PermTestimonialsManage), uploadLimit, testimonialHandler.Create)    // POST /v1/admin/testimonials
+			adminRoutes.GET("/testimonials", middleware.RequirePermission(domain.PermTestimonialsManage), testimonialHandler.GetAll)                  // GET /v1/admin/testimonials
+			adminRoutes.GET("/testimonials/:id", middleware.RequirePermission(domain.PermTestimonialsManage), testimonialHandler.GetByID)             // GET /v1/admin/testimonials/:id
+			adminRoutes.PUT("/testimonials/:id", middleware.RequirePermission(domain.PermTestimonialsManage), uploadLimit, testimonialHandler.Update) // PUT /v1/admin/testimonials/:id
+			adminRoutes.DELETE("/testimonials/:id", middleware.RequirePermission(domain.PermTestimonialsManage), testimonialHandler.Delete)           // DELETE /v1/admin/testimonials/:id
+
+			// Member Routes
+			adminRoutes.POST("/members", middleware.RequirePermission(domain.PermMembersManage), uploadLimit, memberHandler.Create)    // POST /v1/admin/members
+			adminRoutes.GET("/members", middleware.RequirePermission(domain.PermMembersManage), memberHandler.GetAll)                  // GET /v1/admin/members
+			adminRoutes.GET("/members/:id", middleware.RequirePermission(domain.PermMembersManage), memberHandler.GetByID)             // GET /v1/admin/members/:id
+			adminRoutes.PUT("/members/:id", middleware.RequirePermission(domain.PermMembersManage), uploadLimit, memberHandler.Update) // PUT /v1/admin/members/:id
+			adminRoutes.DELETE("/members/:id", middleware.RequirePermission(domain.PermMembersManage), memberHandler.Delete)           // DELETE /v1/admin/members/:id
+
+			// User Management Routes
+			adminRoutes.GET("/users", middleware.RequirePermission(domain.PermUsersManage), userHandler.GetAllUsers)                                                      // GET /v1/admin/users
+			adminRoutes.GET("/users/:id", middleware.RequirePermission(domain.PermUsersManage), userHandler.GetUserByID)                                                  // GET /v1/admin/users/:id
+			adminRoutes.POST("/users", middleware.RequirePermission(domain.PermUsersManage), uploadLimit, userHandler.CreateUser)                                         // POST /v1/admin/users
+			adminRoutes.PUT("/users/:id", middleware.RequirePermission(domain.PermUsersManage), middleware.BlockImpersonation(), uploadLimit, userHandler.UpdateUserByID) // PUT /v1/admin/users/:id
+			adminRoutes.DELETE("/users/:id", middleware.RequirePermission(domain.PermUsersManage), middleware.BlockImpersonation(), userHandler.DeleteUserByID)           // DELETE /v1/admin/users/:id
+			adminRoutes.POST("/users/:id/unlock", middleware.RequirePermission(domain.PermUsersManage), accountLockoutHandler.Unlock)                                     // POST /v1/admin/users/:id/unlock
+
+			// Impersonasi - admin masuk sebagai user lain, setiap request dicatat atas nama admin dan user
+			adminRoutes.POST("/users/:id/impersonate", middleware.RequirePermission(domain.PermUsersImpersonate), middleware.BlockImpersonation(), impersonationHandler.Impersonate) // POST /v1/admin/users/:id/impersonate
+
+			// User Invitation Routes
+			adminRoutes.GET("/invitations", middleware.RequirePermission(domain.PermUsersManage), invitationHandler.GetAll)             // GET /v1/admin/invitations
+			adminRoutes.POST("/invitations", middleware.RequirePermission(domain.PermUsersManage), invitationHandler.Create)            // POST /v1/admin/invitations
+			adminRoutes.POST("/invitations/:id/resend", middleware.RequirePermission(domain.PermUsersManage), invitationHandler.Resend) // POST /v1/admin/invitations/:id/resend
+			adminRoutes.DELETE("/invitations/:id", middleware.RequirePermission(domain.PermUsersManage), invitationHandler.Revoke)      // DELETE /v1/admin/invitations/:id
+
+			// User Session Routes
+			adminRoutes.GET("/users/:id/sessions", middleware.RequirePermission(domain.PermUsersManage), sessionHandler.GetUserSessions)                 // GET /v1/admin/users/:id/sessions
+			adminRoutes.DELETE("/users/:id/sessions", middleware.RequirePermission(domain.PermUsersManage), sessionHandler.RevokeAllUserSessions)        // DELETE /v1/admin/users/:id/sessions
+			adminRoutes.DELETE("/users/:id/sessions/:sessionId", middleware.RequirePermission(domain.PermUsersManage), sessionHandler.RevokeUserSession) // DELETE /v1/admin/users/:id/sessions/:sessionId
+			// About Routes (singleton - only GET and PUT)
+			adminRoutes.GET("/about", middleware.RequirePermission(domain.PermAboutManage), aboutHandler.Get)    // GET /v1/admin/about
+			adminRoutes.PUT("/about", middleware.RequirePermission(domain.PermAboutManage), aboutHandler.Update) // PUT /v1/admin/about
+
+			// Site Settings Routes (singleton - only GET and PUT)
+			adminRoutes.GET("/settings", middleware.RequirePermission(domain.PermSettingsManage), siteSettingHandler.Get)                 // GET /v1/admin/settings
+			adminRoutes.PUT("/settings", middleware.RequirePermission(domain.PermSettingsManage), uploadLimit, siteSettingHandler.Update) // PUT /v1/admin/settings
+
+			// Contact Routes (singleton - only GET and PUT)
+			adminRoutes.GET("/contact", middleware.RequirePermission(domain.PermContactManage), contactHandler.Get)    // GET /v1/admin/contact
+			adminRoutes.PUT("/contact", middleware.RequirePermission(domain.PermContactManage), contactHandler.Update) // PUT /v1/admin/contact
+
+			// Document Routes (GET ada di adminDocumentsRead karena bisa diakses dengan API key)
+			adminRoutes.POST("/documents", middleware.RequirePermission(domain.PermDocumentsManage), uploadLimit, documentHandler.Create)    // POST /v1/admin/documents
+			adminRoutes.PUT("/documents/:id", middleware.RequirePermission(domain.PermDocumentsManage), uploadLimit, documentHandler.Update) // PUT /v1/admin/documents/:id
+			adminRoutes.DELETE("/documents/:id", middleware.RequirePermission(domain.PermDocumentsManage), documentHandler.Delete)           // DELETE /v1/admin/documents/:id
+
+			// Activity Log Routes
+			adminRoutes.GET("/activity-logs", middleware.RequirePermission(domain.PermActivityLogsView), activityLogHandler.GetActivityLogs)                     // GET /v1/admin/activity-logs
+			adminRoutes.GET("/activity-logs/export", middleware.RequirePermission(domain.PermActivityLogsView), activityLogHandler.ExportActivityLogs)           // GET /v1/admin/activity-logs/export?format=csv|ndjson
+			adminRoutes.GET("/activity-logs/archives", middleware.RequirePermission(domain.PermActivityLogsView), activityLogArchiveHandler.GetArchives)         // GET /v1/admin/activity-logs/archives
+			adminRoutes.POST("/activity-logs/archives", middleware.RequirePermission(domain.PermSecurityManage), activityLogArchiveHandler.Run)                  // POST /v1/admin/activity-logs/archives
+			adminRoutes.GET("/activity-logs/verify", middleware.RequirePermission(domain.PermActivityLogsView), auditChainHandler.Verify)                        // GET /v1/admin/activity-logs/verify
+			adminRoutes.GET("/activity-logs/checkpoints/export", middleware.RequirePermission(domain.PermActivityLogsView), auditChainHandler.ExportCheckpoints) // GET /v1/admin/activity-logs/checkpoints/export
+			adminRoutes.POST("/activity-logs/checkpoints", middleware.RequirePermission(domain.PermSecurityManage), auditChainHandler.CreateCheckpoint)          // POST /v1/admin/activity-logs/checkpoints
+
+			// CSP Violation Report Routes
+			adminRoutes.GET("/csp-reports", middleware.RequirePermission(domain.PermActivityLogsView), cspReportHandler.GetAll) // GET /v1/admin/csp-reports?directive=script-src
+
+			// Ads Management Routes
+			adminRoutes.GET("/ads", middleware.RequirePermission(domain.PermAdsManage), adHandler.GetAllAds)                  // GET /v1/admin/ads
+			adminRoutes.GET("/ads/:id", middleware.RequirePermission(domain.PermAdsManage), adHandler.GetAdByID)              // GET /v1/admin/ads/:id
+			adminRoutes.GET("/ads/page/:page", middleware.RequirePermission(domain.PermAdsManage), adHandler.GetAdsByPage)    // GET /v1/admin/ads/page/:page
+			adminRoutes.PUT("/ads/:id", middleware.RequirePermission(domain.PermAdsManage), uploadLimit, adHandler.UpdateAd)  // PUT /v1/admin/ads/:id (upload image)
+			adminRoutes.DELETE("/ads/:id/image", middleware.RequirePermission(domain.PermAdsManage), adHandler.DeleteAdImage) // DELETE /v1/admin/ads/:id/image
+
+			// Post Ownership Routes
+			adminRoutes.PUT("/posts/:id/owner", middleware.RequirePermission(domain.PermPostsManageAny), postHandler.TransferOwnership) // PUT /v1/admin/posts/:id/owner
+
+			// Webhook Routes - langganan event keluar dan log pengirimannya
+			adminRoutes.GET("/webhooks", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.GetAll)                                      // GET /v1/admin/webhooks
+			adminRoutes.GET("/webhooks/events", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.GetEvents)                            // GET /v1/admin/webhooks/events
+			adminRoutes.POST("/webhooks", middleware.RequirePermission(domain.PermWebhooksManage), middleware.BlockImpersonation(), webhookHandler.Create)    // POST /v1/admin/webhooks (secret hanya ditampilkan sekali)
+			adminRoutes.PUT("/webhooks/:id", middleware.RequirePermission(domain.PermWebhooksManage), middleware.BlockImpersonation(), webhookHandler.Update) // PUT /v1/admin/webhooks/:id
+			adminRoutes.DELETE("/webhooks/:id", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.Delete)                               // DELETE /v1/admin/webhooks/:id
+			adminRoutes.POST("/webhooks/:id/test", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.Test)                              // POST /v1/admin/webhooks/:id/test
+			adminRoutes.GET("/webhooks/:id/deliveries", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.GetDeliveries)                // GET /v1/admin/webhooks/:id/deliveries
+			adminRoutes.POST("/webhooks/deliveries/:deliveryId/replay", middleware.RequirePermission(domain.PermWebhooksManage), webhookHandler.Replay)       // POST /v1/admin/webhooks/deliveries/:deliveryId/replay
+
+			// Change History Routes - timeline perubahan per entity dari activity log
+			adminRoutes.GET("/posts/:id/history", middleware.RequirePermission(domain.PermPostsManageAny), activityLogHandler.GetEntityHistory(domain.ModulePost))         // GET /v1/admin/posts/:id/history
+			adminRoutes.GET("/members/:id/history", middleware.RequirePermission(domain.PermMembersManage), activityLogHandler.GetEntityHistory(domain.ModuleMembers))     // GET /v1/admin/members/:id/history
+			adminRoutes.GET("/documents/:id/history", middleware.RequirePermission(domain.PermDocumentsManage), activityLogHandler.GetEntityHistory(domain.ModuleDokumen)) // GET /v1/admin/documents/:id/history
+
+			// API Key Routes
+			adminRoutes.GET("/api-keys", middleware.RequirePermission(domain.PermAPIKeysManage), apiKeyHandler.GetAll)                                   // GET /v1/admin/api-keys
+			adminRoutes.POST("/api-keys", middleware.RequirePermission(domain.PermAPIKeysManage), middleware.BlockImpersonation(), apiKeyHandler.Create) // POST /v1/admin/api-keys
+			adminRoutes.DELETE("/api-keys/:id", middleware.RequirePermission(domain.PermAPIKeysManage), apiKeyHandler.Revoke)                            // DELETE /v1/admin/api-keys/:id
+
+			// Admin IP Allowlist Routes - perubahan yang akan mengunci IP admin sendiri ditolak
+			adminRoutes.GET("/ip-allowlist", middleware.RequirePermission(domain.PermSecurityManage), adminIPAllowlistHandler.GetAll)                                         // GET /v1/admin/ip-allowlist
+			adminRoutes.POST("/ip-allowlist", middleware.RequirePermission(domain.PermSecurityManage), middleware.BlockImpersonation(), adminIPAllowlistHandler.Create)       // POST /v1/admin/ip-allowlist
+			adminRoutes.DELETE("/ip-allowlist/:id", middleware.RequirePermission(domain.PermSecurityManage), middleware.BlockImpersonation(), adminIPAllowlistHandler.Delete) // DELETE /v1/admin/ip-allowlist/:id
+
+			// Role & Permission Routes
+			adminRoutes.GET("/roles", middleware.RequirePermission(domain.PermRolesManage), roleHandler.GetAll)               // GET /v1/admin/roles
+			adminRoutes.POST("/roles", middleware.RequirePermission(domain.PermRolesManage), roleHandler.Create)              // POST /v1/admin/roles
+			adminRoutes.PUT("/roles/:id", middleware.RequirePermission(domain.PermRolesManage), roleHandler.Update)           // PUT /v1/admin/roles/:id
+			adminRoutes.DELETE("/roles/:id", middleware.RequirePermission(domain.PermRolesManage), roleHandler.Delete)        // DELETE /v1/admin/roles/:id
+			adminRoutes.GET("/permissions", middleware.RequirePermission(domain.PermRolesManage), roleHandler.GetPermissions) // GET /v1/admin/permissions
+		}
+
+		// Admin Document Read Routes - JWT dengan documents.manage atau API key dengan scope documents:read
+		adminDocumentsRead := v1.Group("/admin/documents")
+		adminDocumentsRead.Use(strictSecurityHeaders, adminGateLimit, adminIPAllowlist, middleware.APIKeyMiddleware(apiKeySvc, domain.ScopeDocumentsRead), middleware.AuthMiddleware(), authenticatedRateLimit, middleware.RequirePermission(domain.PermDocumentsManage))
+		{
+			adminDocumentsRead.GET("/types", documentHandler.GetTypes) // GET /v1/admin/documents/types
+			adminDocumentsRead.GET("", documentHandler.GetAll)         // GET /v1/admin/documents
+			adminDocumentsRead.GET("/:id", documentHandler.GetByID)    // GET /v1/admin/documents/:id
+		}
+
+		// Admin Draft Read Routes - JWT dengan posts.manage_any atau API key dengan scope news:read
+		adminDraftsRead := v1.Group("/admin/posts/drafts")
+		adminDraftsRead.Use(strictSecurityHeaders, adminGateLimit, adminIPAllowlist, middleware.APIKeyMiddleware(apiKeySvc, domain.ScopeNewsRead), middleware.AuthMiddleware(), authenticatedRateLimit, middleware.RequirePermission(domain.PermPostsManageAny))
+		{
+			adminDraftsRead.GET("", postHandler.GetDrafts)    // GET /v1/admin/posts/drafts
+			adminDraftsRead.GET("/:id", postHandler.GetDraft) // GET /v1/admin/posts/drafts/:id
 		}
 
+		// GET /v1/users/me/posts - Post milik sendiri termasuk draft (JWT atau API key dengan scope news:read)
+		v1.GET("/users/me/posts", middleware.APIKeyMiddleware(apiKeySvc, domain.ScopeNewsRead), middleware.AuthMiddleware(), authenticatedRateLimit, postHandler.GetMyPosts)
+
 		// User Routes - Requires Authentication (Any authenticated user)
 		userRoutes := v1.Group("/users")
-		userRoutes.Use(middleware.AuthMiddleware())
+		userRoutes.Use(strictSecurityHeaders, middleware.AuthMiddleware(), authenticatedRateLimit)
 		{
 			// GET /v1/users/me - Get own profile
 			userRoutes.GET("/me", userHandler.GetMyProfile)
 
+			// Session Management - daftar perangkat yang sedang login
+			userRoutes.GET("/me/sessions", sessionHandler.GetMySessions)                                             // GET /v1/users/me/sessions
+			userRoutes.DELETE("/me/sessions", middleware.BlockImpersonation(), sessionHandler.RevokeMyOtherSessions) // DELETE /v1/users/me/sessions
+			userRoutes.DELETE("/me/sessions/:id", middleware.BlockImpersonation(), sessionHandler.RevokeMySession)   // DELETE /v1/users/me/sessions/:id
+
+			// Two-Factor Authentication (TOTP) - perubahan 2FA hanya boleh oleh pemilik akun, bukan saat impersonasi
+			userRoutes.GET("/me/2fa", twoFactorHandler.GetStatus)                                                                            // GET /v1/users/me/2fa
+			userRoutes.POST("/me/2fa/enroll", middleware.BlockImpersonation(), twoFactorHandler.Enroll)                                      // POST /v1/users/me/2fa/enroll
+			userRoutes.POST("/me/2fa/confirm", middleware.BlockImpersonation(), loginLimit, twoFactorHandler.Confirm)                        // POST /v1/users/me/2fa/confirm
+			userRoutes.POST("/me/2fa/recovery-codes", middleware.BlockImpersonation(), loginLimit, twoFactorHandler.RegenerateRecoveryCodes) // POST /v1/users/me/2fa/recovery-codes
+			userRoutes.DELETE("/me/2fa", middleware.BlockImpersonation(), loginLimit, twoFactorHandler.Disable)                              // DELETE /v1/users/me/2fa
+
 			// Dashboard Routes - Author without Activity Logs
 			userRoutes.GET("/dashboard", dashboardHandler.GetDashboard)                // GET /v1/users/dashboard?year=2026&month=1
 			userRoutes.GET("/dashboard/periods", dashboardHandler.GetAvailablePeriods) // GET /v1/users/dashboard/periods
 		}
 
 		// Public Posts Routes - Untuk pengunjung melihat postingan
-		posts := v1.Group("/posts")
+		posts := public.Group("/posts")
 		{
 			posts.GET("", postHandler.GetPosts)
 			posts.GET("/:id", postHandler.GetPost)
 		}
 
-		// Protected Posts Routes - Requires Admin or Author
+		// Protected Posts Routes - Requires posts.* permission (Admin & Author bawaan) atau API key dengan scope posts:write
 		postsProtected := v1.Group("/posts")
-		postsProtected.Use(middleware.AuthMiddleware(), middleware.RequireAnyRole("1", "2"))
+		postsProtected.Use(middleware.APIKeyMiddleware(apiKeySvc, domain.ScopePostsWrite), middleware.AuthMiddleware(), authenticatedRateLimit)
 		{
-			postsProtected.POST("", postHandler.CreatePost)
-			postsProtected.PUT("/:id", postHandler.UpdatePost)
-			postsProtected.DELETE("/:id", postHandler.DeletePost)
+			postsProtected.POST("", middleware.RequirePermission(domain.PermPostsCreate), uploadLimit, postHandler.CreatePost)
+			postsProtected.PUT("/:id", middleware.RequirePermission(domain.PermPostsUpdate), uploadLimit, postHandler.UpdatePost)
+			postsProtected.DELETE("/:id", middleware.RequirePermission(domain.PermPostsDelete), postHandler.DeletePost)
 		}
 
 		// Public Categories Routes
-		categories := v1.Group("/categories")
+		categories := public.Group("/categories")
 		{
 			categories.GET("", catHandler.GetCategories)
 		}
 
-		// Protected Categories Routes - Requires Admin
+		// Protected Categories Routes - Requires categories.manage permission
 		categoriesProtected := v1.Group("/categories")
-		categoriesProtected.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
+		categoriesProtected.Use(middleware.AuthMiddleware(), authenticatedRateLimit, middleware.RequirePermission(domain.PermCategoriesManage))
 		{
 			categoriesProtected.POST("", catHandler.CreateCategory)
 			categoriesProtected.PUT("/:id", catHandler.UpdateCategory)
@@ -240,14 +454,14 @@ func SetupRoutes(
 		}
 
 		// Public Tags Routes
-		tags := v1.Group("/tags")
+		tags := public.Group("/tags")
 		{
 			tags.GET("", tagHandler.GetTags)
 		}
 
-		// Protected Tags Routes - Requires Admin
+		// Protected Tags Routes - Requires tags.manage permission
 		tagsProtected := v1.Group("/tags")
-		tagsProtected.Use(middleware.AuthMiddleware(), middleware.AdminOnly())
+		tagsProtected.Use(middleware.AuthMiddleware(), authenticatedRateLimit, middleware.RequirePermission(domain.PermTagsManage))
 		{
 			tagsProtected.POST("", tagHandler.CreateTag)
 			tagsProtected.PUT("/:id", tagHandler.UpdateTag)
@@ -256,7 +470,7 @@ func SetupRoutes(
 
 		// Inbox & Chat Routes - Requires Authentication
 		chatRoutes := v1.Group("/chat")
-		chatRoutes.Use(middleware.AuthMiddleware())
+		chatRoutes.Use(middleware.AuthMiddleware(), authenticatedRateLimit)
 		{
 			// GET /v1/chat/ws - Upgrade ke WebSocket
 			chatRoutes.GET("/ws", inboxHandler.HandleWebSocket)
@@ -266,7 +480,7 @@ func SetupRoutes(
 		}
 
 		inboxRoutes := v1.Group("/inbox")
-		inboxRoutes.Use(middleware.AuthMiddleware())
+		inboxRoutes.Use(middleware.AuthMiddleware(), authenticatedRateLimit)
 		{
 			// GET /v1/inbox - Daftar percakapan terakhir (Modal Inbox)
 			inboxRoutes.GET("", inboxHandler.GetInboxList)
@@ -274,3 +488,15 @@ func SetupRoutes(
 
 	}
 }
+
+// rateLimitPolicies menggabungkan policy bawaan dengan override dari RATE_LIMIT_POLICIES
+func rateLimitPolicies(cfg config.RateLimitConfig) []middleware.RateLimitPolicy {
+	policies := middleware.DefaultRateLimitPolicies()
+	for i, policy := range policies {
+		if override, ok := cfg.Overrides[policy.Name]; ok {
+			policies[i].Limit = override.Limit
+			policies[i].Window = override.Window
+		}
+	}
+	return policies
+}
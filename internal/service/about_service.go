@@ -65,7 +65,7 @@ func (s *aboutService) Update(ctx context.Context, req requests.UpdateAboutReque
 	}
 
 	// Save ke database (upsert)
-	if err := s.aboutRepo.Upsert(about); err != nil {
+	if err := s.aboutRepo.WithContext(ctx).Upsert(about); err != nil {
 		return nil, errors.New("gagal menyimpan about")
 	}
 
@@ -8,6 +8,7 @@ import (
 
 "github.com/garuda-labs-1/pmii-be/internal/domain"
 "github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
 // MockAboutRepository adalah mock untuk AboutRepository
@@ -30,6 +31,10 @@ return m.UpsertFunc(about)
 return errors.New("mock not configured")
 }
 
+func (m *MockAboutRepository) WithContext(ctx context.Context) repository.AboutRepository {
+return m
+}
+
 // ==================== GET TESTS ====================
 
 // Test: Get berhasil dengan data existing
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/logger"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// Delay progresif sebelum lockout: setelah loginDelayAfterFailures kali gagal,
+// percobaan berikutnya harus menunggu loginBaseDelay lalu dua kali lipat setiap kegagalan
+const (
+	loginDelayAfterFailures = 3
+	loginBaseDelay          = time.Second
+)
+
+// LockoutPolicy konfigurasi proteksi brute-force login
+type LockoutPolicy struct {
+	MaxFailedAttempts int           // Gagal berturut-turut per akun sebelum dikunci
+	LockoutDuration   time.Duration // Lama akun dikunci
+	MaxFailedPerIP    int           // Gagal per IP dalam IPWindow sebelum IP diblokir sementara
+	IPWindow          time.Duration
+	AttemptRetention  time.Duration // Lama riwayat login_attempts disimpan sebelum dihapus
+}
+
+// AccountLockoutService interface untuk proteksi brute-force per akun dan per IP
+type AccountLockoutService interface {
+	Check(ctx context.Context, user *domain.User) error
+	RecordFailure(ctx context.Context, email string, user *domain.User)
+	RecordSuccess(ctx context.Context, user *domain.User)
+	Unlock(ctx context.Context, userID int) error
+	PurgeAttempts() (int64, error)
+}
+
+// LoginThrottledError dikembalikan jika login ditolak karena terlalu banyak percobaan gagal
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // true jika akun dikunci, false jika hanya delay progresif / blokir IP
+	Account    bool // true jika berlaku per akun (kunci atau delay progresif), false jika blokir IP
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+type accountLockoutService struct {
+	userRepo         repository.UserRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	activityLogRepo  repository.ActivityLogRepository
+	mailer           MailerService
+	policy           LockoutPolicy
+}
+
+// NewAccountLockoutService constructor untuk AccountLockoutService
+func NewAccountLockoutService(userRepo repository.UserRepository, loginAttemptRepo repository.LoginAttemptRepository, activityLogRepo repository.ActivityLogRepository, mailer MailerService, policy LockoutPolicy) AccountLockoutService {
+	return &accountLockoutService{
+		userRepo:         userRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		activityLogRepo:  activityLogRepo,
+		mailer:           mailer,
+		policy:           policy,
+	}
+}
+
+// Check memastikan IP dan akun boleh mencoba login saat ini
+// user boleh nil (email tidak terdaftar), dalam hal ini hanya batas per IP yang dicek
+func (s *accountLockoutService) Check(ctx context.Context, user *domain.User) error {
+	if ipAddress := utils.GetIPAddress(ctx); ipAddress != "" && s.policy.MaxFailedPerIP > 0 {
+		failures, err := s.loginAttemptRepo.CountFailuresByIP(ipAddress, time.Now().Add(-s.policy.IPWindow))
+		if err == nil && failures >= int64(s.policy.MaxFailedPerIP) {
+			return &LoginThrottledError{RetryAfter: s.policy.IPWindow}
+		}
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	if user.IsLocked() {
+		return &LoginThrottledError{RetryAfter: time.Until(*user.LockedUntil), Locked: true, Account: true}
+	}
+
+	// Masa kunci sudah habis: counter dimulai dari nol lagi agar satu kegagalan tidak langsung mengunci ulang
+	if user.LockedUntil != nil {
+		if err := s.loginAttemptRepo.ResetFailures(user.ID); err != nil {
+			logger.Error.Printf("Check: failed to reset expired lockout for user %d: %v", user.ID, err)
+		} else {
+			user.FailedLoginCount = 0
+			user.LastFailedLoginAt = nil
+			user.LockedUntil = nil
+		}
+	}
+
+	if wait := s.progressiveDelay(user); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait, Account: true}
+	}
+
+	return nil
+}
+
+// RecordFailure mencatat login gagal, menaikkan counter akun dan mengunci akun jika melewati batas
+func (s *accountLockoutService) RecordFailure(ctx context.Context, email string, user *domain.User) {
+	attempt := &domain.LoginAttempt{Email: normalizeEmail(email)}
+	if ipAddress := utils.GetIPAddress(ctx); ipAddress != "" {
+		attempt.IPAddress = &ipAddress
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	_ = s.loginAttemptRepo.Create(attempt)
+
+	// Email tidak terdaftar hanya dihitung per IP
+	if user == nil {
+		return
+	}
+
+	count, lockedUntil, err := s.loginAttemptRepo.RegisterFailure(user.ID, s.policy.MaxFailedAttempts, time.Now().Add(s.policy.LockoutDuration))
+	if err != nil {
+		logger.Error.Printf("RecordFailure: failed to register login failure for user %d: %v", user.ID, err)
+		return
+	}
+
+	newlyLocked := lockedUntil != nil && time.Now().Before(*lockedUntil) && !user.IsLocked()
+
+	description := "Percobaan login gagal"
+	if newlyLocked {
+		description = "Akun dikunci sementara karena terlalu banyak percobaan login gagal"
+	}
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionLoginFailed,
+		Module:      domain.ModuleAuth,
+		Description: description,
+		TargetID:    &user.ID,
+		NewValue: map[string]any{
+			"failed_attempts": count,
+			"locked_until":    lockedUntil,
+		},
+	})
+
+	if newlyLocked {
+		go s.sendLockoutEmail(user, count, attempt.IPAddress, *lockedUntil)
+	}
+}
+
+// RecordSuccess mencatat login berhasil dan mereset counter gagal login akun
+func (s *accountLockoutService) RecordSuccess(ctx context.Context, user *domain.User) {
+	attempt := &domain.LoginAttempt{Email: normalizeEmail(user.Email), UserID: &user.ID, Success: true}
+	if ipAddress := utils.GetIPAddress(ctx); ipAddress != "" {
+		attempt.IPAddress = &ipAddress
+	}
+	_ = s.loginAttemptRepo.Create(attempt)
+
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		_ = s.loginAttemptRepo.ResetFailures(user.ID)
+	}
+}
+
+// Unlock membuka kunci akun secara manual oleh admin
+func (s *accountLockoutService) Unlock(ctx context.Context, userID int) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.loginAttemptRepo.ResetFailures(userID); err != nil {
+		return ErrAccountUnlockFailed
+	}
+
+	// Dicatat atas nama admin yang membuka kunci
+	if adminID, ok := utils.GetUserID(ctx); ok {
+		recordActivity(ctx, s.activityLogRepo, audit.Event{
+			UserID:      adminID,
+			Action:      domain.ActionUpdate,
+			Module:      domain.ModuleUser,
+			Description: "Membuka kunci akun: " + user.Email,
+			TargetID:    &user.ID,
+			NewValue: map[string]any{
+				"failed_attempts": user.FailedLoginCount,
+				"locked_until":    user.LockedUntil,
+			},
+		})
+	}
+
+	return nil
+}
+
+// PurgeAttempts menghapus riwayat percobaan login yang melewati masa retensi
+// Riwayat dalam jendela blokir IP selalu disimpan agar batas per IP tetap berlaku
+func (s *accountLockoutService) PurgeAttempts() (int64, error) {
+	retention := s.policy.AttemptRetention
+	if retention < s.policy.IPWindow {
+		retention = s.policy.IPWindow
+	}
+	return s.loginAttemptRepo.DeleteBefore(time.Now().Add(-retention))
+}
+
+// progressiveDelay menghitung sisa waktu tunggu sebelum percobaan login berikutnya
+func (s *accountLockoutService) progressiveDelay(user *domain.User) time.Duration {
+	if user.FailedLoginCount < loginDelayAfterFailures || user.LastFailedLoginAt == nil {
+		return 0
+	}
+
+	delay := loginBaseDelay << (user.FailedLoginCount - loginDelayAfterFailures)
+	if delay <= 0 || delay > s.policy.LockoutDuration {
+		delay = s.policy.LockoutDuration
+	}
+
+	return time.Until(user.LastFailedLoginAt.Add(delay))
+}
+
+// sendLockoutEmail memberi tahu pemilik akun bahwa akunnya dikunci sementara
+func (s *accountLockoutService) sendLockoutEmail(user *domain.User, failures int, ipAddress *string, lockedUntil time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	source := "tidak diketahui"
+	if ipAddress != nil {
+		source = *ipAddress
+	}
+
+	body := fmt.Sprintf(`Halo %s,
+
+Akun Anda dikunci sementara setelah %d kali percobaan login gagal (IP terakhir: %s).
+Akun akan terbuka kembali otomatis pada %s.
+
+Jika ini bukan Anda, segera ganti password setelah akun terbuka atau hubungi admin untuk membuka kunci akun.`,
+		user.FullName, failures, source, lockedUntil.Format("02 Jan 2006 15:04 MST"))
+
+	if err := s.mailer.Send(ctx, user.Email, "Peringatan Keamanan: Akun PMII Dikunci Sementara", body); err != nil {
+		logger.Error.Printf("sendLockoutEmail: failed to send lockout email to user %d: %v", user.ID, err)
+	}
+}
+
+// normalizeEmail menyamakan format email untuk pencatatan percobaan login
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Account lockout service errors
+var (
+	ErrTooManyLoginAttempts = errors.New("terlalu banyak percobaan login gagal, silakan coba lagi nanti")
+	ErrAccountUnlockFailed  = errors.New("gagal membuka kunci akun")
+)
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// MockAccountLockoutService adalah mock untuk AccountLockoutService
+type MockAccountLockoutService struct {
+	CheckFunc         func(ctx context.Context, user *domain.User) error
+	RecordFailureFunc func(ctx context.Context, email string, user *domain.User)
+}
+
+func (m *MockAccountLockoutService) Check(ctx context.Context, user *domain.User) error {
+	if m.CheckFunc != nil {
+		return m.CheckFunc(ctx, user)
+	}
+	return nil
+}
+
+func (m *MockAccountLockoutService) RecordFailure(ctx context.Context, email string, user *domain.User) {
+	if m.RecordFailureFunc != nil {
+		m.RecordFailureFunc(ctx, email, user)
+	}
+}
+
+// Stub methods (interface requirement)
+func (m *MockAccountLockoutService) RecordSuccess(ctx context.Context, user *domain.User) {}
+func (m *MockAccountLockoutService) Unlock(ctx context.Context, userID int) error         { return nil }
+func (m *MockAccountLockoutService) PurgeAttempts() (int64, error)                        { return 0, nil }
+
+// MockLoginAttemptRepository adalah mock untuk LoginAttemptRepository
+type MockLoginAttemptRepository struct {
+	CreateFunc            func(attempt *domain.LoginAttempt) error
+	CountFailuresByIPFunc func(ipAddress string, since time.Time) (int64, error)
+	RegisterFailureFunc   func(userID int, threshold int, lockUntil time.Time) (int, *time.Time, error)
+	ResetFailuresFunc     func(userID int) error
+	DeleteBeforeFunc      func(before time.Time) (int64, error)
+}
+
+func (m *MockLoginAttemptRepository) Create(attempt *domain.LoginAttempt) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(attempt)
+	}
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) CountFailuresByIP(ipAddress string, since time.Time) (int64, error) {
+	if m.CountFailuresByIPFunc != nil {
+		return m.CountFailuresByIPFunc(ipAddress, since)
+	}
+	return 0, nil
+}
+
+func (m *MockLoginAttemptRepository) RegisterFailure(userID int, threshold int, lockUntil time.Time) (int, *time.Time, error) {
+	if m.RegisterFailureFunc != nil {
+		return m.RegisterFailureFunc(userID, threshold, lockUntil)
+	}
+	return 1, nil, nil
+}
+
+func (m *MockLoginAttemptRepository) ResetFailures(userID int) error {
+	if m.ResetFailuresFunc != nil {
+		return m.ResetFailuresFunc(userID)
+	}
+	return nil
+}
+
+func (m *MockLoginAttemptRepository) DeleteBefore(before time.Time) (int64, error) {
+	if m.DeleteBeforeFunc != nil {
+		return m.DeleteBeforeFunc(before)
+	}
+	return 0, nil
+}
+
+var testLockoutPolicy = LockoutPolicy{
+	MaxFailedAttempts: 5,
+	LockoutDuration:   15 * time.Minute,
+	MaxFailedPerIP:    20,
+	IPWindow:          15 * time.Minute,
+	AttemptRetention:  90 * 24 * time.Hour,
+}
+
+// TestLogin_AccountLocked menguji akun terkunci ditolak meskipun password benar,
+// dengan jawaban yang sama seperti kredensial salah
+func TestLogin_AccountLocked(t *testing.T) {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	userRepo := &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return &domain.User{ID: 1, Email: email, PasswordHash: adminHashedPassword, IsActive: true, LockedUntil: &lockedUntil}, nil
+		},
+	}
+	lockout := NewAccountLockoutService(userRepo, &MockLoginAttemptRepository{}, &MockActivityLogRepoForAuth{}, &MockMailer{}, testLockoutPolicy)
+
+	authService := NewAuthService(userRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, lockout, &MockActivityLogRepoForAuth{})
+	_, tokens, err := authService.Login(context.Background(), "admin@example.com", "admin123")
+
+	if err == nil || err.Error() != "invalid credentials" {
+		t.Fatalf("Expected invalid credentials error, got %v", err)
+	}
+	if errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Error("Expected locked account not to be distinguishable from wrong credentials")
+	}
+	if tokens != nil {
+		t.Error("Expected no tokens for locked account")
+	}
+}
+
+// TestLockoutCheck_ExpiredLockResetsCounter menguji counter gagal login direset saat masa kunci habis
+func TestLockoutCheck_ExpiredLockResetsCounter(t *testing.T) {
+	resetUserID := 0
+	attemptRepo := &MockLoginAttemptRepository{
+		ResetFailuresFunc: func(userID int) error {
+			resetUserID = userID
+			return nil
+		},
+	}
+	svc := NewAccountLockoutService(&MockUserRepository{}, attemptRepo, &MockActivityLogRepoForAuth{}, &MockMailer{}, testLockoutPolicy)
+
+	expired := time.Now().Add(-time.Minute)
+	justFailed := time.Now()
+	user := &domain.User{ID: 3, FailedLoginCount: 5, LastFailedLoginAt: &justFailed, LockedUntil: &expired}
+	if err := svc.Check(context.Background(), user); err != nil {
+		t.Fatalf("Expected no error after lock expired, got %v", err)
+	}
+	if resetUserID != 3 || user.FailedLoginCount != 0 || user.LockedUntil != nil {
+		t.Errorf("Expected failure counter to be reset, got reset=%d count=%d", resetUserID, user.FailedLoginCount)
+	}
+}
+
+// TestPurgeAttempts_RetentionWindow menguji riwayat login dihapus sesuai retensi, minimal selama jendela IP
+func TestPurgeAttempts_RetentionWindow(t *testing.T) {
+	var cutoff time.Time
+	attemptRepo := &MockLoginAttemptRepository{
+		DeleteBeforeFunc: func(before time.Time) (int64, error) {
+			cutoff = before
+			return 12, nil
+		},
+	}
+
+	policy := testLockoutPolicy
+	policy.AttemptRetention = time.Minute
+	svc := NewAccountLockoutService(&MockUserRepository{}, attemptRepo, &MockActivityLogRepoForAuth{}, &MockMailer{}, policy)
+
+	deleted, err := svc.PurgeAttempts()
+	if err != nil || deleted != 12 {
+		t.Fatalf("Expected 12 deleted attempts, got %d (%v)", deleted, err)
+	}
+	if time.Since(cutoff) < policy.IPWindow {
+		t.Errorf("Expected attempts inside the IP window to be kept, cutoff %s", cutoff)
+	}
+}
+
+// TestLogin_WrongPasswordRecordsFailure menguji password salah dicatat sebagai login gagal
+func TestLogin_WrongPasswordRecordsFailure(t *testing.T) {
+	userRepo := &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return &domain.User{ID: 1, Email: email, PasswordHash: adminHashedPassword, IsActive: true}, nil
+		},
+	}
+
+	var recorded *domain.User
+	lockout := &MockAccountLockoutService{
+		RecordFailureFunc: func(ctx context.Context, email string, user *domain.User) {
+			recorded = user
+		},
+	}
+
+	authService := NewAuthService(userRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, lockout, &MockActivityLogRepoForAuth{})
+	if _, _, err := authService.Login(context.Background(), "admin@example.com", "wrong"); err == nil {
+		t.Fatal("Expected error for wrong password")
+	}
+	if recorded == nil || recorded.ID != 1 {
+		t.Error("Expected failed attempt to be recorded for the account")
+	}
+}
+
+// TestLockoutCheck_IPBlocked menguji IP dengan terlalu banyak percobaan gagal diblokir
+func TestLockoutCheck_IPBlocked(t *testing.T) {
+	attemptRepo := &MockLoginAttemptRepository{
+		CountFailuresByIPFunc: func(ipAddress string, since time.Time) (int64, error) {
+			return 20, nil
+		},
+	}
+	svc := NewAccountLockoutService(&MockUserRepository{}, attemptRepo, &MockActivityLogRepoForAuth{}, &MockMailer{}, testLockoutPolicy)
+
+	ctx := utils.WithRequestInfo(context.Background(), "10.0.0.1", "test-agent")
+	err := svc.Check(ctx, nil)
+
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("Expected LoginThrottledError, got %v", err)
+	}
+	if throttled.Locked {
+		t.Error("Expected IP block, not account lock")
+	}
+}
+
+// TestLockoutCheck_ProgressiveDelay menguji delay progresif setelah beberapa kali gagal
+func TestLockoutCheck_ProgressiveDelay(t *testing.T) {
+	svc := NewAccountLockoutService(&MockUserRepository{}, &MockLoginAttemptRepository{}, &MockActivityLogRepoForAuth{}, &MockMailer{}, testLockoutPolicy)
+
+	justFailed := time.Now()
+	if err := svc.Check(context.Background(), &domain.User{ID: 1, FailedLoginCount: 4, LastFailedLoginAt: &justFailed}); !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Errorf("Expected ErrTooManyLoginAttempts right after failure, got %v", err)
+	}
+
+	longAgo := time.Now().Add(-time.Minute)
+	if err := svc.Check(context.Background(), &domain.User{ID: 1, FailedLoginCount: 4, LastFailedLoginAt: &longAgo}); err != nil {
+		t.Errorf("Expected no error after delay has passed, got %v", err)
+	}
+}
+
+// TestRecordFailure_LocksAndNotifies menguji akun dikunci, dicatat sebagai login_failed dan pemilik diberi tahu
+func TestRecordFailure_LocksAndNotifies(t *testing.T) {
+	attemptRepo := &MockLoginAttemptRepository{
+		RegisterFailureFunc: func(userID int, threshold int, lockUntil time.Time) (int, *time.Time, error) {
+			return threshold, &lockUntil, nil
+		},
+	}
+
+	var logged *domain.ActivityLog
+	activityLogRepo := &MockActivityLogRepoForAuth{
+		CreateFunc: func(log *domain.ActivityLog) error {
+			logged = log
+			return nil
+		},
+	}
+	mailer := &MockMailer{Sent: make(chan string, 1)}
+
+	svc := NewAccountLockoutService(&MockUserRepository{}, attemptRepo, activityLogRepo, mailer, testLockoutPolicy)
+	svc.RecordFailure(context.Background(), "Admin@Example.com", &domain.User{ID: 1, Email: "admin@example.com", FullName: "Admin"})
+
+	if logged == nil || logged.ActionType != domain.ActionLoginFailed {
+		t.Fatal("Expected login_failed activity log")
+	}
+
+	select {
+	case body := <-mailer.Sent:
+		if !strings.Contains(body, "dikunci") {
+			t.Errorf("Expected lockout notification body, got %s", body)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected lockout notification email")
+	}
+}
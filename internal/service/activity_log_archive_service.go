@@ -0,0 +1,270 @@
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/archive"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// activityLogArchiveBatchSize jumlah entry yang dibaca per query saat menulis arsip
+const activityLogArchiveBatchSize = 500
+
+// ActivityLogArchiveService interface untuk retensi activity log
+type ActivityLogArchiveService interface {
+	// Run mengarsipkan activity log yang lebih tua dari masa retensi lalu menghapusnya dari database
+	// Mengembalikan nil jika tidak ada entry yang perlu diarsipkan
+	Run(ctx context.Context) (*domain.ActivityLogArchive, error)
+
+	GetArchives(page, limit int) ([]domain.ActivityLogArchive, int, int64, error)
+}
+
+type activityLogArchiveService struct {
+	archiveRepo     repository.ActivityLogArchiveRepository
+	activityLogRepo repository.ActivityLogRepository
+	auditChainSvc   AuditChainService
+	storage         archive.Storage
+	retentionMonths int
+
+	running sync.Mutex
+}
+
+// NewActivityLogArchiveService constructor untuk ActivityLogArchiveService
+// retentionMonths 0 = retensi nonaktif, Run selalu ditolak
+func NewActivityLogArchiveService(archiveRepo repository.ActivityLogArchiveRepository, activityLogRepo repository.ActivityLogRepository, auditChainSvc AuditChainService, storage archive.Storage, retentionMonths int) ActivityLogArchiveService {
+	return &activityLogArchiveService{
+		archiveRepo:     archiveRepo,
+		activityLogRepo: activityLogRepo,
+		auditChainSvc:   auditChainSvc,
+		storage:         storage,
+		retentionMonths: retentionMonths,
+	}
+}
+
+var (
+	ErrActivityLogRetentionDisabled = errors.New("retensi activity log tidak aktif (AUDIT_RETENTION_MONTHS)")
+	ErrActivityLogArchiveRunning    = errors.New("arsip activity log sedang berjalan")
+	ErrActivityLogArchiveConflict   = errors.New("activity log sudah diarsipkan atau berubah saat diarsipkan, coba lagi")
+	ErrActivityLogArchiveFailed     = errors.New("gagal mengarsipkan activity log")
+	ErrActivityLogArchivesFailed    = errors.New("gagal mengambil daftar arsip activity log")
+)
+
+// activityLogArchiveRecord adalah satu baris NDJSON di file arsip
+// Semua kolom yang ikut di-hash disimpan apa adanya sehingga hash chain di arsip tetap bisa diverifikasi
+type activityLogArchiveRecord struct {
+	ID             int                       `json:"id"`
+	UserID         int                       `json:"user_id"`
+	ActionType     domain.ActivityActionType `json:"action_type"`
+	Module         domain.ActivityModuleType `json:"module"`
+	Description    *string                   `json:"description"`
+	TargetID       *int                      `json:"target_id"`
+	OldValue       map[string]any            `json:"old_value"`
+	NewValue       map[string]any            `json:"new_value"`
+	IPAddress      *string                   `json:"ip_address"`
+	UserAgent      *string                   `json:"user_agent"`
+	APIKeyID       *int                      `json:"api_key_id"`
+	ImpersonatorID *int                      `json:"impersonator_id"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	PrevHash       *string                   `json:"prev_hash"`
+	EntryHash      *string                   `json:"entry_hash"`
+}
+
+// Run dijalankan berkala dari main.go atau manual oleh admin
+// Chain diverifikasi dulu, entry yang rusak tidak boleh dihapus karena arsip akan menghilangkan buktinya
+func (s *activityLogArchiveService) Run(ctx context.Context) (*domain.ActivityLogArchive, error) {
+	if s.retentionMonths <= 0 {
+		return nil, ErrActivityLogRetentionDisabled
+	}
+	if !s.running.TryLock() {
+		return nil, ErrActivityLogArchiveRunning
+	}
+	defer s.running.Unlock()
+
+	cutoff := time.Now().AddDate(0, -s.retentionMonths, 0)
+	toID, err := s.archiveRepo.FindLastIDBefore(cutoff)
+	if err != nil {
+		return nil, ErrActivityLogArchiveFailed
+	}
+	if toID == 0 {
+		return nil, nil
+	}
+
+	verification, err := s.auditChainSvc.Verify()
+	if err != nil {
+		return nil, ErrActivityLogArchiveFailed
+	}
+	if !verification.Valid {
+		s.logFailure(ctx, cutoff, "hash chain rusak: "+verification.BrokenLink.Reason)
+		return nil, ErrAuditChainBroken
+	}
+
+	record, file, err := s.writeArchive(toID, cutoff)
+	if file != nil {
+		defer os.Remove(file.Name())
+		defer file.Close()
+	}
+	if err != nil {
+		log.Printf("[WARN] Gagal menulis arsip activity log: %v", err)
+		s.logFailure(ctx, cutoff, "gagal menulis file arsip")
+		return nil, ErrActivityLogArchiveFailed
+	}
+	if record.EntryCount == 0 {
+		return nil, nil
+	}
+
+	name := fmt.Sprintf("activity-logs-%d-%d-%s.ndjson.gz", record.FromLogID, record.ToLogID, time.Now().UTC().Format("20060102150405"))
+	location, err := s.storage.Save(ctx, name, file)
+	if err != nil {
+		log.Printf("[WARN] Gagal menyimpan arsip activity log ke %s: %v", s.storage.Name(), err)
+		s.logFailure(ctx, cutoff, "gagal menyimpan file arsip ke "+s.storage.Name())
+		return nil, ErrActivityLogArchiveFailed
+	}
+	record.Storage = s.storage.Name()
+	record.Location = location
+	if userID, ok := utils.GetUserID(ctx); ok {
+		record.CreatedBy = &userID
+	}
+
+	purged, err := s.archiveRepo.Purge(record)
+	if err != nil {
+		log.Printf("[WARN] Gagal menghapus activity log yang sudah diarsipkan (%s): %v", location, err)
+		s.logFailure(ctx, cutoff, "gagal menghapus activity log yang sudah diarsipkan")
+		return nil, ErrActivityLogArchiveFailed
+	}
+	if !purged {
+		log.Printf("[WARN] Arsip activity log %s tidak dipakai karena rentang sudah diarsipkan atau berubah", location)
+		return nil, ErrActivityLogArchiveConflict
+	}
+
+	description := fmt.Sprintf("Mengarsipkan %d activity log sebelum %s", record.EntryCount, cutoff.Format("2006-01-02"))
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionArchive,
+		Module:      domain.ModuleActivityLogs,
+		Description: description,
+		TargetID:    &record.ID,
+		NewValue: map[string]any{
+			"from_log_id": record.FromLogID,
+			"to_log_id":   record.ToLogID,
+			"entry_count": record.EntryCount,
+			"storage":     record.Storage,
+			"location":    record.Location,
+			"sha256":      record.SHA256,
+		},
+	})
+	return record, nil
+}
+
+// GetArchives mengambil daftar arsip dengan pagination
+func (s *activityLogArchiveService) GetArchives(page, limit int) ([]domain.ActivityLogArchive, int, int64, error) {
+	offset := (page - 1) * limit
+	archives, total, err := s.archiveRepo.FindAll(offset, limit)
+	if err != nil {
+		return nil, 0, 0, ErrActivityLogArchivesFailed
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	return archives, lastPage, total, nil
+}
+
+// writeArchive menulis entry dengan id <= toID ke file sementara (NDJSON gzip)
+// File dikembalikan dalam posisi awal agar bisa langsung disimpan ke storage
+func (s *activityLogArchiveService) writeArchive(toID int, cutoff time.Time) (*domain.ActivityLogArchive, *os.File, error) {
+	file, err := os.CreateTemp("", "activity-logs-*.ndjson.gz")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	record := &domain.ActivityLogArchive{Cutoff: cutoff}
+	hasher := sha256.New()
+	counter := &countingWriter{}
+	gz := gzip.NewWriter(io.MultiWriter(file, hasher, counter))
+	encoder := json.NewEncoder(gz)
+
+	err = s.archiveRepo.StreamEntries(toID, activityLogArchiveBatchSize, func(logs []domain.ActivityLog) error {
+		for i := range logs {
+			entry := &logs[i]
+			if record.EntryCount == 0 {
+				record.FromLogID = entry.ID
+			}
+			record.ToLogID = entry.ID
+			record.EntryCount++
+			if entry.EntryHash != nil {
+				record.ChainedCount++
+				record.LastEntryHash = entry.EntryHash
+			}
+
+			if err := encoder.Encode(activityLogArchiveRecord{
+				ID:             entry.ID,
+				UserID:         entry.UserID,
+				ActionType:     entry.ActionType,
+				Module:         entry.Module,
+				Description:    entry.Description,
+				TargetID:       entry.TargetID,
+				OldValue:       entry.OldValue,
+				NewValue:       entry.NewValue,
+				IPAddress:      entry.IPAddress,
+				UserAgent:      entry.UserAgent,
+				APIKeyID:       entry.APIKeyID,
+				ImpersonatorID: entry.ImpersonatorID,
+				CreatedAt:      entry.CreatedAt,
+				PrevHash:       entry.PrevHash,
+				EntryHash:      entry.EntryHash,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, file, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, file, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, file, err
+	}
+
+	record.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+	record.SizeBytes = counter.n
+	return record, file, nil
+}
+
+// logFailure mencatat run arsip yang gagal agar terlihat di activity log
+func (s *activityLogArchiveService) logFailure(ctx context.Context, cutoff time.Time, reason string) {
+	description := fmt.Sprintf("Gagal mengarsipkan activity log sebelum %s: %s", cutoff.Format("2006-01-02"), reason)
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionArchive,
+		Module:      domain.ModuleActivityLogs,
+		Description: description,
+		NewValue:    map[string]any{"status": "failed", "reason": reason},
+	})
+}
+
+// countingWriter menghitung ukuran file arsip yang ditulis
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// MockActivityLogArchiveRepository memakai entry dari MockAuditChainRepository agar verifikasi chain dan arsip melihat data yang sama
+type MockActivityLogArchiveRepository struct {
+	Chain         *MockAuditChainRepository
+	LastIDBefore  int
+	PurgeConflict bool
+	PurgeCalls    int
+}
+
+func (m *MockActivityLogArchiveRepository) FindLastIDBefore(cutoff time.Time) (int, error) {
+	return m.LastIDBefore, nil
+}
+
+func (m *MockActivityLogArchiveRepository) StreamEntries(toID, batchSize int, fn func(logs []domain.ActivityLog) error) error {
+	var batch []domain.ActivityLog
+	for _, entry := range m.Chain.Entries {
+		if entry.ID > toID {
+			break
+		}
+		batch = append(batch, entry)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func (m *MockActivityLogArchiveRepository) Purge(archive *domain.ActivityLogArchive) (bool, error) {
+	m.PurgeCalls++
+	if m.PurgeConflict {
+		return false, nil
+	}
+	archive.ID = len(m.Chain.Archives) + 1
+	m.Chain.Archives = append(m.Chain.Archives, *archive)
+
+	var remaining []domain.ActivityLog
+	for _, entry := range m.Chain.Entries {
+		if entry.ID > archive.ToLogID {
+			remaining = append(remaining, entry)
+		}
+	}
+	m.Chain.Entries = remaining
+	return true, nil
+}
+
+func (m *MockActivityLogArchiveRepository) FindAll(offset, limit int) ([]domain.ActivityLogArchive, int64, error) {
+	return m.Chain.Archives, int64(len(m.Chain.Archives)), nil
+}
+
+// memoryArchiveStorage menyimpan file arsip di memory
+type memoryArchiveStorage struct {
+	files map[string][]byte
+	err   error
+}
+
+func (s *memoryArchiveStorage) Name() string { return "memory" }
+
+func (s *memoryArchiveStorage) Save(ctx context.Context, name string, src io.Reader) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", err
+	}
+	if s.files == nil {
+		s.files = map[string][]byte{}
+	}
+	s.files[name] = data
+	return "memory://" + name, nil
+}
+
+func newArchiveTestService(t *testing.T, entries int) (*MockActivityLogArchiveRepository, *memoryArchiveStorage, *[]domain.ActivityLog, ActivityLogArchiveService) {
+	t.Helper()
+	chain := &MockAuditChainRepository{}
+	chain.appendEntries(t, entries)
+	archiveRepo := &MockActivityLogArchiveRepository{Chain: chain}
+	storage := &memoryArchiveStorage{}
+
+	logged := &[]domain.ActivityLog{}
+	activityLogRepo := &MockActivityLogRepository{CreateFunc: func(log *domain.ActivityLog) error {
+		*logged = append(*logged, *log)
+		return nil
+	}}
+
+	svc := NewActivityLogArchiveService(archiveRepo, activityLogRepo, NewAuditChainService(chain, nil), storage, 6)
+	return archiveRepo, storage, logged, svc
+}
+
+// TestActivityLogArchiveRun_Success menguji arsip, purge, dan pencatatan run di activity log
+func TestActivityLogArchiveRun_Success(t *testing.T) {
+	archiveRepo, storage, logged, svc := newArchiveTestService(t, 5)
+	archiveRepo.LastIDBefore = 3
+	archivedHash := *archiveRepo.Chain.Entries[2].EntryHash
+
+	archive, err := svc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archive == nil || archive.FromLogID != 1 || archive.ToLogID != 3 || archive.EntryCount != 3 || archive.ChainedCount != 3 {
+		t.Fatalf("unexpected archive: %+v", archive)
+	}
+	if archive.LastEntryHash == nil || *archive.LastEntryHash != archivedHash || archive.Storage != "memory" || archive.CreatedBy != nil {
+		t.Errorf("unexpected archive metadata: %+v", archive)
+	}
+	if len(archiveRepo.Chain.Entries) != 2 {
+		t.Errorf("expected 2 remaining entries, got %d", len(archiveRepo.Chain.Entries))
+	}
+
+	// File arsip berisi NDJSON gzip dengan hash chain, checksum sesuai isi file
+	data := storage.files[archive.Location[len("memory://"):]]
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != archive.SHA256 || int64(len(data)) != archive.SizeBytes {
+		t.Errorf("checksum/size mismatch: %s %d", archive.SHA256, archive.SizeBytes)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("archive is not gzip: %v", err)
+	}
+	scanner := bufio.NewScanner(gz)
+	var lines int
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid NDJSON line: %v", err)
+		}
+		if record["entry_hash"] == nil || record["prev_hash"] == nil {
+			t.Errorf("expected hashes in archived record: %v", record)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Errorf("expected 3 archived lines, got %d", lines)
+	}
+
+	// Chain tetap valid setelah purge
+	if result, _ := NewAuditChainService(archiveRepo.Chain, nil).Verify(); !result.Valid || result.ArchivedEntries != 3 {
+		t.Errorf("expected valid chain after archive, got %+v", result)
+	}
+
+	if len(*logged) != 1 {
+		t.Fatalf("expected 1 activity log, got %d", len(*logged))
+	}
+	entry := (*logged)[0]
+	if entry.ActionType != domain.ActionArchive || entry.Module != domain.ModuleActivityLogs || entry.UserID != 0 {
+		t.Errorf("unexpected activity log: %+v", entry)
+	}
+	if entry.TargetID == nil || *entry.TargetID != archive.ID {
+		t.Errorf("expected archive id as target, got %v", entry.TargetID)
+	}
+}
+
+// TestActivityLogArchiveRun_ManualRunRecordsUser menguji run manual oleh admin
+func TestActivityLogArchiveRun_ManualRunRecordsUser(t *testing.T) {
+	archiveRepo, _, logged, svc := newArchiveTestService(t, 2)
+	archiveRepo.LastIDBefore = 1
+
+	ctx := context.WithValue(context.Background(), utils.ContextKeyUserID, 9)
+	archive, err := svc.Run(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archive.CreatedBy == nil || *archive.CreatedBy != 9 {
+		t.Errorf("expected created_by 9, got %v", archive.CreatedBy)
+	}
+	if len(*logged) != 1 || (*logged)[0].UserID != 9 {
+		t.Errorf("expected activity log by user 9, got %+v", *logged)
+	}
+}
+
+// TestActivityLogArchiveRun_NothingToArchive menguji run tanpa entry yang melewati masa retensi
+func TestActivityLogArchiveRun_NothingToArchive(t *testing.T) {
+	archiveRepo, _, logged, svc := newArchiveTestService(t, 3)
+
+	archive, err := svc.Run(context.Background())
+	if err != nil || archive != nil {
+		t.Fatalf("expected nil archive, got %+v, %v", archive, err)
+	}
+	if archiveRepo.PurgeCalls != 0 || len(*logged) != 0 {
+		t.Errorf("expected no purge and no log, got %d purges, %d logs", archiveRepo.PurgeCalls, len(*logged))
+	}
+}
+
+// TestActivityLogArchiveRun_BrokenChain menguji entry tidak dihapus jika chain rusak
+func TestActivityLogArchiveRun_BrokenChain(t *testing.T) {
+	archiveRepo, _, logged, svc := newArchiveTestService(t, 3)
+	archiveRepo.LastIDBefore = 2
+	archiveRepo.Chain.Entries[1].NewValue = map[string]any{"title": "Diubah"}
+
+	if _, err := svc.Run(context.Background()); !errors.Is(err, ErrAuditChainBroken) {
+		t.Fatalf("expected ErrAuditChainBroken, got %v", err)
+	}
+	if archiveRepo.PurgeCalls != 0 {
+		t.Error("expected no purge on broken chain")
+	}
+	if len(*logged) != 1 || (*logged)[0].NewValue["status"] != "failed" {
+		t.Errorf("expected failed run to be logged, got %+v", *logged)
+	}
+}
+
+// TestActivityLogArchiveRun_StorageError menguji entry tidak dihapus jika file arsip gagal disimpan
+func TestActivityLogArchiveRun_StorageError(t *testing.T) {
+	archiveRepo, storage, logged, svc := newArchiveTestService(t, 3)
+	archiveRepo.LastIDBefore = 2
+	storage.err = errors.New("disk full")
+
+	if _, err := svc.Run(context.Background()); !errors.Is(err, ErrActivityLogArchiveFailed) {
+		t.Fatalf("expected ErrActivityLogArchiveFailed, got %v", err)
+	}
+	if archiveRepo.PurgeCalls != 0 || len(archiveRepo.Chain.Entries) != 3 {
+		t.Error("expected entries to be kept when storage fails")
+	}
+	if len(*logged) != 1 {
+		t.Errorf("expected failed run to be logged, got %d logs", len(*logged))
+	}
+}
+
+// TestActivityLogArchiveRun_Conflict menguji purge yang ditolak karena rentang sudah diarsipkan
+func TestActivityLogArchiveRun_Conflict(t *testing.T) {
+	archiveRepo, _, _, svc := newArchiveTestService(t, 3)
+	archiveRepo.LastIDBefore = 2
+	archiveRepo.PurgeConflict = true
+
+	if _, err := svc.Run(context.Background()); !errors.Is(err, ErrActivityLogArchiveConflict) {
+		t.Fatalf("expected ErrActivityLogArchiveConflict, got %v", err)
+	}
+}
+
+// TestActivityLogArchiveRun_Disabled menguji retensi 0 bulan
+func TestActivityLogArchiveRun_Disabled(t *testing.T) {
+	chain := &MockAuditChainRepository{}
+	svc := NewActivityLogArchiveService(&MockActivityLogArchiveRepository{Chain: chain}, &MockActivityLogRepository{}, NewAuditChainService(chain, nil), &memoryArchiveStorage{}, 0)
+
+	if _, err := svc.Run(context.Background()); !errors.Is(err, ErrActivityLogRetentionDisabled) {
+		t.Fatalf("expected ErrActivityLogRetentionDisabled, got %v", err)
+	}
+}
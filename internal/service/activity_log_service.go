@@ -1,22 +1,38 @@
 package service
 
 import (
+	"context"
+	"log"
 	"math"
+	"reflect"
+	"sort"
 	"time"
 
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
 type ActivityLogService interface {
 	GetActivityLogs(page, limit int, filter ActivityLogFilterParams) ([]responses.ActivityLogResponse, int, int64, error)
+
+	// ExportActivityLogs mengirim semua activity log yang cocok dengan filter per batch ke fn (untuk export streaming)
+	ExportActivityLogs(filter ActivityLogFilterParams, fn func(logs []responses.ActivityLogResponse) error) error
+
+	// GetEntityHistory mengambil timeline perubahan satu entity (module + target id), terbaru lebih dulu
+	GetEntityHistory(module domain.ActivityModuleType, targetID, page, limit int) ([]responses.EntityHistoryResponse, int, int64, error)
 }
 
+// activityLogExportBatchSize jumlah activity log yang dibaca per query saat export
+const activityLogExportBatchSize = 500
+
 // ActivityLogFilterParams is the service-level filter struct for activity logs
 type ActivityLogFilterParams struct {
 	UserID     *int
 	Module     *string
 	ActionType *string
+	TargetID   *int
 	StartDate  *time.Time
 	EndDate    *time.Time
 	Search     string
@@ -33,17 +49,7 @@ func NewActivityLogService(repo repository.ActivityLogRepository) ActivityLogSer
 func (s *activityLogService) GetActivityLogs(page, limit int, filter ActivityLogFilterParams) ([]responses.ActivityLogResponse, int, int64, error) {
 	offset := (page - 1) * limit
 
-	// Convert service filter to repository filter
-	repoFilter := repository.ActivityLogFilter{
-		UserID:     filter.UserID,
-		Module:     filter.Module,
-		ActionType: filter.ActionType,
-		StartDate:  filter.StartDate,
-		EndDate:    filter.EndDate,
-		Search:     filter.Search,
-	}
-
-	logs, total, err := s.repo.GetActivityLogs(offset, limit, repoFilter)
+	logs, total, err := s.repo.GetActivityLogs(offset, limit, filter.toRepository())
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -55,3 +61,82 @@ func (s *activityLogService) GetActivityLogs(page, limit int, filter ActivityLog
 
 	return logs, lastPage, total, nil
 }
+
+// ExportActivityLogs memakai filter yang sama dengan GetActivityLogs, diurutkan dari yang terlama
+func (s *activityLogService) ExportActivityLogs(filter ActivityLogFilterParams, fn func(logs []responses.ActivityLogResponse) error) error {
+	return s.repo.StreamActivityLogs(filter.toRepository(), activityLogExportBatchSize, fn)
+}
+
+// GetEntityHistory memakai activity log yang sama dengan GetActivityLogs,
+// old/new value diubah menjadi daftar perubahan per field
+func (s *activityLogService) GetEntityHistory(module domain.ActivityModuleType, targetID, page, limit int) ([]responses.EntityHistoryResponse, int, int64, error) {
+	moduleName := string(module)
+	logs, lastPage, total, err := s.GetActivityLogs(page, limit, ActivityLogFilterParams{Module: &moduleName, TargetID: &targetID})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	history := make([]responses.EntityHistoryResponse, len(logs))
+	for i, log := range logs {
+		history[i] = responses.EntityHistoryResponse{
+			ID:           log.ID,
+			ActionType:   log.ActionType,
+			Description:  log.Description,
+			Actor:        log.User,
+			Impersonator: log.Impersonator,
+			APIKey:       log.APIKey,
+			Changes:      fieldChanges(log.OldValue, log.NewValue),
+			CreatedAt:    log.CreatedAt,
+		}
+	}
+	return history, lastPage, total, nil
+}
+
+// fieldChanges menggabungkan old/new value menjadi perubahan per field, diurutkan berdasarkan nama field
+// Create hanya punya new value dan delete hanya punya old value, sehingga sisi lainnya nil
+// Field yang nilainya sama (log manual yang menyimpan snapshot penuh) dilewati
+func fieldChanges(oldValue, newValue map[string]any) []responses.EntityHistoryChange {
+	fields := make([]string, 0, len(newValue))
+	for field := range newValue {
+		fields = append(fields, field)
+	}
+	for field := range oldValue {
+		if _, ok := newValue[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]responses.EntityHistoryChange, 0, len(fields))
+	for _, field := range fields {
+		if reflect.DeepEqual(oldValue[field], newValue[field]) {
+			continue
+		}
+		changes = append(changes, responses.EntityHistoryChange{
+			Field:  field,
+			Before: oldValue[field],
+			After:  newValue[field],
+		})
+	}
+	return changes
+}
+
+// toRepository converts service filter to repository filter
+func (f ActivityLogFilterParams) toRepository() repository.ActivityLogFilter {
+	return repository.ActivityLogFilter{
+		UserID:     f.UserID,
+		Module:     f.Module,
+		ActionType: f.ActionType,
+		TargetID:   f.TargetID,
+		StartDate:  f.StartDate,
+		EndDate:    f.EndDate,
+		Search:     f.Search,
+	}
+}
+
+// recordActivity mencatat event ke activity log, gagal mencatat tidak membatalkan operasi utama
+func recordActivity(ctx context.Context, repo repository.ActivityLogRepository, event audit.Event) {
+	if err := repo.Create(ctx, audit.NewActivityLog(ctx, event)); err != nil {
+		log.Printf("[WARN] Gagal mencatat activity log %s/%s: %v", event.Module, event.Action, err)
+	}
+}
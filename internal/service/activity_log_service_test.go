@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -12,8 +13,9 @@ import (
 
 // MockActivityLogRepository adalah mock untuk ActivityLogRepository
 type MockActivityLogRepository struct {
-	GetActivityLogsFunc func(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error)
-	CreateFunc          func(log *domain.ActivityLog) error
+	GetActivityLogsFunc    func(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error)
+	CreateFunc             func(log *domain.ActivityLog) error
+	StreamActivityLogsFunc func(filter repository.ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error
 }
 
 func (m *MockActivityLogRepository) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
@@ -23,13 +25,20 @@ func (m *MockActivityLogRepository) GetActivityLogs(offset, limit int, filter re
 	return nil, 0, errors.New("mock not configured")
 }
 
-func (m *MockActivityLogRepository) Create(log *domain.ActivityLog) error {
+func (m *MockActivityLogRepository) Create(ctx context.Context, log *domain.ActivityLog) error {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(log)
 	}
 	return nil
 }
 
+func (m *MockActivityLogRepository) StreamActivityLogs(filter repository.ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error {
+	if m.StreamActivityLogsFunc != nil {
+		return m.StreamActivityLogsFunc(filter, batchSize, fn)
+	}
+	return nil
+}
+
 // TestGetActivityLogs_Success menguji GetActivityLogs yang berhasil
 func TestGetActivityLogs_Success(t *testing.T) {
 	description := "Created new post"
@@ -308,3 +317,115 @@ func TestNewActivityLogService(t *testing.T) {
 		t.Error("Expected service to not be nil")
 	}
 }
+
+// TestExportActivityLogs_StreamsBatchesWithFilter menguji export memakai filter yang sama dan meneruskan setiap batch
+func TestExportActivityLogs_StreamsBatchesWithFilter(t *testing.T) {
+	module := "post"
+	var gotFilter repository.ActivityLogFilter
+	var gotBatchSize int
+	mockRepo := &MockActivityLogRepository{
+		StreamActivityLogsFunc: func(filter repository.ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error {
+			gotFilter = filter
+			gotBatchSize = batchSize
+			if err := fn([]responses.ActivityLogResponse{{ID: 1}, {ID: 2}}); err != nil {
+				return err
+			}
+			return fn([]responses.ActivityLogResponse{{ID: 3}})
+		},
+	}
+	svc := NewActivityLogService(mockRepo)
+
+	var ids []int
+	err := svc.ExportActivityLogs(ActivityLogFilterParams{Module: &module, Search: "judul"}, func(logs []responses.ActivityLogResponse) error {
+		for _, log := range logs {
+			ids = append(ids, log.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("expected 3 exported logs, got %v", ids)
+	}
+	if gotFilter.Module == nil || *gotFilter.Module != "post" || gotFilter.Search != "judul" || gotBatchSize != activityLogExportBatchSize {
+		t.Errorf("unexpected filter/batch size: %+v %d", gotFilter, gotBatchSize)
+	}
+}
+
+// TestGetEntityHistory_FieldChanges menguji timeline entity dengan perubahan per field
+func TestGetEntityHistory_FieldChanges(t *testing.T) {
+	var gotFilter repository.ActivityLogFilter
+	mockRepo := &MockActivityLogRepository{
+		GetActivityLogsFunc: func(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
+			gotFilter = filter
+			return []responses.ActivityLogResponse{
+				{
+					ID:         12,
+					UserID:     3,
+					User:       &responses.ActivityLogUserInfo{ID: 3, FullName: "Sekretaris"},
+					ActionType: domain.ActionUpdate,
+					Module:     domain.ModuleMembers,
+					OldValue:   map[string]any{"position": "Anggota", "department": "Kaderisasi"},
+					NewValue:   map[string]any{"position": "Ketua", "department": "Kaderisasi", "photo": "members/a.jpg"},
+				},
+				{
+					ID:         4,
+					UserID:     1,
+					ActionType: domain.ActionCreate,
+					Module:     domain.ModuleMembers,
+					NewValue:   map[string]any{"full_name": "Budi"},
+				},
+			}, 2, nil
+		},
+	}
+	svc := NewActivityLogService(mockRepo)
+
+	history, lastPage, total, err := svc.GetEntityHistory(domain.ModuleMembers, 7, 1, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotFilter.Module == nil || *gotFilter.Module != string(domain.ModuleMembers) || gotFilter.TargetID == nil || *gotFilter.TargetID != 7 {
+		t.Errorf("unexpected filter: %+v", gotFilter)
+	}
+	if total != 2 || lastPage != 1 || len(history) != 2 {
+		t.Fatalf("unexpected pagination: total=%d lastPage=%d len=%d", total, lastPage, len(history))
+	}
+
+	update := history[0]
+	if update.Actor == nil || update.Actor.FullName != "Sekretaris" {
+		t.Errorf("expected actor name, got %+v", update.Actor)
+	}
+	// Field diurutkan, field yang tidak berubah dilewati, field baru tanpa nilai lama tetap muncul
+	expected := []responses.EntityHistoryChange{
+		{Field: "photo", Before: nil, After: "members/a.jpg"},
+		{Field: "position", Before: "Anggota", After: "Ketua"},
+	}
+	if len(update.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), update.Changes)
+	}
+	for i, change := range expected {
+		if update.Changes[i] != change {
+			t.Errorf("change %d: expected %+v, got %+v", i, change, update.Changes[i])
+		}
+	}
+
+	create := history[1]
+	if len(create.Changes) != 1 || create.Changes[0].Before != nil || create.Changes[0].After != "Budi" {
+		t.Errorf("unexpected create changes: %+v", create.Changes)
+	}
+}
+
+// TestGetEntityHistory_RepositoryError menguji error dari repository
+func TestGetEntityHistory_RepositoryError(t *testing.T) {
+	mockRepo := &MockActivityLogRepository{
+		GetActivityLogsFunc: func(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
+			return nil, 0, errors.New("database error")
+		},
+	}
+	svc := NewActivityLogService(mockRepo)
+
+	if _, _, _, err := svc.GetEntityHistory(domain.ModulePost, 1, 1, 30); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"mime/multipart"
 	"time"
 
@@ -23,17 +22,15 @@ type AdService interface {
 }
 
 type adService struct {
-	adRepo          repository.AdRepository
-	cloudinary      CloudinaryService
-	activityLogRepo repository.ActivityLogRepository
+	adRepo     repository.AdRepository
+	cloudinary CloudinaryService
 }
 
 // NewAdService constructor untuk AdService
-func NewAdService(adRepo repository.AdRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository) AdService {
+func NewAdService(adRepo repository.AdRepository, cloudinaryService CloudinaryService) AdService {
 	return &adService{
-		adRepo:          adRepo,
-		cloudinary:      cloudinaryService,
-		activityLogRepo: activityLogRepo,
+		adRepo:     adRepo,
+		cloudinary: cloudinaryService,
 	}
 }
 
@@ -81,10 +78,6 @@ func (s *adService) UpdateAd(ctx context.Context, id int, image *multipart.FileH
 		return nil, errors.New("ad tidak ditemukan")
 	}
 
-	oldData := map[string]any{
-		"image_url": ad.ImageURL,
-	}
-
 	// Upload new image if provided
 	if image != nil {
 		// Validate image aspect ratio (10% tolerance)
@@ -108,16 +101,10 @@ func (s *adService) UpdateAd(ctx context.Context, id int, image *multipart.FileH
 	ad.UpdatedAt = time.Now()
 
 	// Save to database
-	if err := s.adRepo.Update(ad); err != nil {
+	if err := s.adRepo.WithContext(ctx).Update(ad); err != nil {
 		return nil, errors.New("gagal mengupdate ad")
 	}
 
-	// Log activity
-	newData := map[string]any{
-		"image_url": ad.ImageURL,
-	}
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleAds, "Update "+ad.GetSlotName(), oldData, newData, &ad.ID)
-
 	response := s.toAdResponseWithFullURL(ad)
 	return &response, nil
 }
@@ -130,8 +117,6 @@ func (s *adService) DeleteAdImage(ctx context.Context, id int) (*responses.AdRes
 		return nil, errors.New("ad tidak ditemukan")
 	}
 
-	oldImageURL := ad.ImageURL
-
 	// Delete image from cloudinary if exists
 	if ad.ImageURL != nil && *ad.ImageURL != "" {
 		_ = s.cloudinary.DeleteImage(ctx, "ads", *ad.ImageURL)
@@ -142,57 +127,14 @@ func (s *adService) DeleteAdImage(ctx context.Context, id int) (*responses.AdRes
 	ad.UpdatedAt = time.Now()
 
 	// Save to database
-	if err := s.adRepo.Update(ad); err != nil {
+	if err := s.adRepo.WithContext(ctx).Update(ad); err != nil {
 		return nil, errors.New("gagal menghapus gambar ad")
 	}
 
-	// Log activity
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleAds, "Delete image "+ad.GetSlotName(), map[string]any{
-		"deleted_image_url": oldImageURL,
-	}, nil, &ad.ID)
-
 	response := s.toAdResponseWithFullURL(ad)
 	return &response, nil
 }
 
-// logActivity helper untuk mencatat activity log
-func (s *adService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		// Log warning for debugging
-		fmt.Println("[WARN] logActivity: user_id not found in context, skipping activity log")
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	if err := s.activityLogRepo.Create(log); err != nil {
-		fmt.Printf("[WARN] logActivity: failed to create activity log: %v\n", err)
-	}
-}
-
 // toAdResponseWithFullURL converts domain.Ad to AdResponse with full cloudinary URL
 func (s *adService) toAdResponseWithFullURL(ad *domain.Ad) responses.AdResponse {
 	var imageURL *string
@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
-	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
@@ -56,6 +55,10 @@ func (m *MockAdRepository) UpdateImage(id int, imageURL string) error {
 	return nil
 }
 
+func (m *MockAdRepository) WithContext(ctx context.Context) repository.AdRepository {
+	return m
+}
+
 // MockCloudinaryServiceForAd adalah mock untuk CloudinaryService
 type MockCloudinaryServiceForAd struct {
 	UploadImageFunc func(ctx context.Context, folder string, file *multipart.FileHeader) (string, error)
@@ -100,17 +103,6 @@ func (m *MockCloudinaryServiceForAd) GetDownloadURL(folder string, filename stri
 	return "https://example.com/download/" + folder + "/" + filename
 }
 
-// MockActivityLogRepoForAd adalah mock untuk ActivityLogRepository
-type MockActivityLogRepoForAd struct{}
-
-func (m *MockActivityLogRepoForAd) Create(log *domain.ActivityLog) error {
-	return nil
-}
-
-func (m *MockActivityLogRepoForAd) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
-	return nil, 0, nil
-}
-
 // TestGetAllAds_Success tests successful retrieval of all ads
 func TestGetAllAds_Success(t *testing.T) {
 	imageURL := "test_image.jpg"
@@ -126,7 +118,7 @@ func TestGetAllAds_Success(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.GetAllAds(context.Background())
 
 	assert.NoError(t, err)
@@ -154,7 +146,7 @@ func TestGetAdByID_Success(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.GetAdByID(context.Background(), 1)
 
 	assert.NoError(t, err)
@@ -170,7 +162,7 @@ func TestGetAdByID_NotFound(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.GetAdByID(context.Background(), 999)
 
 	assert.Error(t, err)
@@ -195,7 +187,7 @@ func TestGetAdsByPage_Success(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.GetAdsByPage(context.Background(), "landing")
 
 	assert.NoError(t, err)
@@ -207,7 +199,7 @@ func TestGetAdsByPage_Success(t *testing.T) {
 func TestGetAdsByPage_InvalidPage(t *testing.T) {
 	mockRepo := &MockAdRepository{}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.GetAdsByPage(context.Background(), "invalid_page")
 
 	assert.Error(t, err)
@@ -235,7 +227,7 @@ func TestUpdateAd_Success(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 
 	result, err := adService.UpdateAd(context.Background(), 1, nil)
 
@@ -263,7 +255,7 @@ func TestDeleteAdImage_Success(t *testing.T) {
 		},
 	}
 
-	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAd{})
+	adService := NewAdService(mockRepo, &MockCloudinaryServiceForAd{})
 	result, err := adService.DeleteAdImage(context.Background(), 1)
 
 	assert.NoError(t, err)
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// adminIPAllowlistCacheTTL lama allowlist disimpan di memory
+// Perubahan dari instance lain paling lambat berlaku setelah durasi ini
+const adminIPAllowlistCacheTTL = 30 * time.Second
+
+// AdminIPAllowlistService interface untuk allowlist IP yang boleh mengakses admin API
+type AdminIPAllowlistService interface {
+	GetAll(ctx context.Context) ([]domain.AdminIPAllowlistEntry, error)
+	Create(ctx context.Context, req *requests.CreateAdminIPAllowlistRequest) (*domain.AdminIPAllowlistEntry, error)
+	Delete(ctx context.Context, id int) error
+	IsAllowed(ipAddress string) (bool, error)
+	Bypassed() bool
+}
+
+type adminIPAllowlistService struct {
+	allowlistRepo   repository.AdminIPAllowlistRepository
+	activityLogRepo repository.ActivityLogRepository
+	bypass          bool
+
+	mu          sync.RWMutex
+	cached      []domain.AdminIPAllowlistEntry
+	cacheLoaded bool
+	cachedUntil time.Time
+}
+
+// NewAdminIPAllowlistService constructor untuk AdminIPAllowlistService
+// bypass = true mematikan pengecekan allowlist (pemulihan darurat jika admin terkunci)
+func NewAdminIPAllowlistService(allowlistRepo repository.AdminIPAllowlistRepository, activityLogRepo repository.ActivityLogRepository, bypass bool) AdminIPAllowlistService {
+	if bypass {
+		log.Println("[WARN] ADMIN_IP_ALLOWLIST_BYPASS aktif: allowlist IP admin tidak diterapkan")
+	}
+	return &adminIPAllowlistService{
+		allowlistRepo:   allowlistRepo,
+		activityLogRepo: activityLogRepo,
+		bypass:          bypass,
+	}
+}
+
+// GetAll mengambil semua entry allowlist
+func (s *adminIPAllowlistService) GetAll(ctx context.Context) ([]domain.AdminIPAllowlistEntry, error) {
+	entries, err := s.allowlistRepo.FindAll()
+	if err != nil {
+		return nil, ErrAdminIPAllowlistFetchFailed
+	}
+	return entries, nil
+}
+
+// Create menambah IP atau CIDR ke allowlist
+// Ditolak jika setelah perubahan IP admin yang sedang request tidak lagi diizinkan
+func (s *adminIPAllowlistService) Create(ctx context.Context, req *requests.CreateAdminIPAllowlistRequest) (*domain.AdminIPAllowlistEntry, error) {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	cidr, err := normalizeAllowlistCIDR(req.CIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.allowlistRepo.ExistsByCIDR(cidr)
+	if err != nil {
+		return nil, ErrAdminIPAllowlistSaveFailed
+	}
+	if exists {
+		return nil, ErrAdminIPAllowlistEntryExists
+	}
+
+	entries, err := s.allowlistRepo.FindAll()
+	if err != nil {
+		return nil, ErrAdminIPAllowlistFetchFailed
+	}
+
+	entry := &domain.AdminIPAllowlistEntry{CIDR: cidr, CreatedBy: &adminID}
+	if description := strings.TrimSpace(req.Description); description != "" {
+		entry.Description = &description
+	}
+
+	if err := checkAllowlistLockout(append(entries, *entry), utils.GetIPAddress(ctx)); err != nil {
+		return nil, err
+	}
+
+	if err := s.allowlistRepo.Create(entry); err != nil {
+		return nil, ErrAdminIPAllowlistSaveFailed
+	}
+	s.invalidateCache()
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleSecurity,
+		Description: "Menambah allowlist IP admin: " + cidr,
+		TargetID:    &entry.ID,
+		NewValue: map[string]any{
+			"id":          entry.ID,
+			"cidr":        entry.CIDR,
+			"description": entry.Description,
+		},
+	})
+
+	return entry, nil
+}
+
+// Delete menghapus entry dari allowlist
+// Ditolak jika sisa allowlist tidak lagi mengizinkan IP admin yang sedang request
+func (s *adminIPAllowlistService) Delete(ctx context.Context, id int) error {
+	entry, err := s.allowlistRepo.FindByID(id)
+	if err != nil {
+		return ErrAdminIPAllowlistEntryNotFound
+	}
+
+	entries, err := s.allowlistRepo.FindAll()
+	if err != nil {
+		return ErrAdminIPAllowlistFetchFailed
+	}
+
+	remaining := make([]domain.AdminIPAllowlistEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.ID != entry.ID {
+			remaining = append(remaining, e)
+		}
+	}
+	if err := checkAllowlistLockout(remaining, utils.GetIPAddress(ctx)); err != nil {
+		return err
+	}
+
+	if err := s.allowlistRepo.Delete(entry.ID); err != nil {
+		return ErrAdminIPAllowlistSaveFailed
+	}
+	s.invalidateCache()
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionDelete,
+		Module:      domain.ModuleSecurity,
+		Description: "Menghapus allowlist IP admin: " + entry.CIDR,
+		TargetID:    &entry.ID,
+		OldValue: map[string]any{
+			"id":          entry.ID,
+			"cidr":        entry.CIDR,
+			"description": entry.Description,
+		},
+	})
+
+	return nil
+}
+
+// IsAllowed mengecek apakah IP boleh mengakses admin API
+// Allowlist kosong atau bypass aktif = semua IP diizinkan
+func (s *adminIPAllowlistService) IsAllowed(ipAddress string) (bool, error) {
+	if s.bypass {
+		return true, nil
+	}
+
+	entries, err := s.entries()
+	if err != nil {
+		return false, err
+	}
+	return allowlistContains(entries, ipAddress), nil
+}
+
+// Bypassed mengecek apakah bypass darurat aktif
+func (s *adminIPAllowlistService) Bypassed() bool {
+	return s.bypass
+}
+
+// entries mengambil allowlist dari cache, reload dari database setelah cache kadaluarsa
+// Jika reload gagal, allowlist terakhir tetap dipakai agar gangguan database tidak membuka akses admin
+func (s *adminIPAllowlistService) entries() ([]domain.AdminIPAllowlistEntry, error) {
+	s.mu.RLock()
+	if s.cacheLoaded && time.Now().Before(s.cachedUntil) {
+		entries := s.cached
+		s.mu.RUnlock()
+		return entries, nil
+	}
+	s.mu.RUnlock()
+
+	entries, err := s.allowlistRepo.FindAll()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if s.cacheLoaded {
+			log.Printf("[WARN] admin IP allowlist: reload failed, using cached allowlist: %v", err)
+			return s.cached, nil
+		}
+		return nil, ErrAdminIPAllowlistFetchFailed
+	}
+
+	s.cached = entries
+	s.cacheLoaded = true
+	s.cachedUntil = time.Now().Add(adminIPAllowlistCacheTTL)
+	return entries, nil
+}
+
+// invalidateCache memaksa allowlist dibaca ulang dari database di request berikutnya
+func (s *adminIPAllowlistService) invalidateCache() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cachedUntil = time.Time{}
+}
+
+// allowlistContains mengecek IP terhadap allowlist, allowlist kosong mengizinkan semua IP
+func allowlistContains(entries []domain.AdminIPAllowlistEntry, ipAddress string) bool {
+	if len(entries) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip == nil {
+		return false
+	}
+	for i := range entries {
+		if entries[i].Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAllowlistLockout memastikan allowlist hasil perubahan masih mengizinkan IP admin yang melakukan perubahan
+func checkAllowlistLockout(entries []domain.AdminIPAllowlistEntry, currentIP string) error {
+	if !allowlistContains(entries, currentIP) {
+		return ErrAdminIPAllowlistLockout
+	}
+	return nil
+}
+
+// normalizeAllowlistCIDR memvalidasi IP atau CIDR, IP tunggal diubah menjadi /32 (IPv4) atau /128 (IPv6)
+func normalizeAllowlistCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network.String(), nil
+	}
+	if ip := net.ParseIP(value); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.String() + "/32", nil
+		}
+		return ip.String() + "/128", nil
+	}
+	return "", ErrInvalidAdminIPAllowlistCIDR
+}
+
+// Admin IP allowlist service errors
+var (
+	ErrInvalidAdminIPAllowlistCIDR   = errors.New("cidr harus berisi IP atau CIDR yang valid")
+	ErrAdminIPAllowlistEntryExists   = errors.New("IP atau CIDR sudah ada di allowlist")
+	ErrAdminIPAllowlistEntryNotFound = errors.New("entry allowlist tidak ditemukan")
+	ErrAdminIPAllowlistLockout       = errors.New("perubahan ditolak karena IP Anda saat ini tidak akan diizinkan mengakses admin")
+	ErrAdminIPAllowlistFetchFailed   = errors.New("gagal mengambil allowlist IP admin")
+	ErrAdminIPAllowlistSaveFailed    = errors.New("gagal menyimpan allowlist IP admin")
+)
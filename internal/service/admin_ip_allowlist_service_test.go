@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MockAdminIPAllowlistRepository menyimpan allowlist di memory
+type MockAdminIPAllowlistRepository struct {
+	entries   []domain.AdminIPAllowlistEntry
+	nextID    int
+	findCalls int
+	findErr   error
+}
+
+func (m *MockAdminIPAllowlistRepository) Create(entry *domain.AdminIPAllowlistEntry) error {
+	m.nextID++
+	entry.ID = m.nextID
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+
+func (m *MockAdminIPAllowlistRepository) FindAll() ([]domain.AdminIPAllowlistEntry, error) {
+	m.findCalls++
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	return append([]domain.AdminIPAllowlistEntry(nil), m.entries...), nil
+}
+
+func (m *MockAdminIPAllowlistRepository) FindByID(id int) (*domain.AdminIPAllowlistEntry, error) {
+	for i := range m.entries {
+		if m.entries[i].ID == id {
+			entry := m.entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockAdminIPAllowlistRepository) ExistsByCIDR(cidr string) (bool, error) {
+	for _, entry := range m.entries {
+		if entry.CIDR == cidr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockAdminIPAllowlistRepository) Delete(id int) error {
+	for i := range m.entries {
+		if m.entries[i].ID == id {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// adminContextFromIP membuat context admin (user ID 1) yang request dari IP tertentu
+func adminContextFromIP(ip string) context.Context {
+	return utils.WithRequestInfo(utils.WithUserID(context.Background(), 1), ip, "test-agent")
+}
+
+func TestAdminIPAllowlistCreate_NormalizesAndLogs(t *testing.T) {
+	repo := &MockAdminIPAllowlistRepository{}
+	var logs []*domain.ActivityLog
+	svc := NewAdminIPAllowlistService(repo, &MockActivityLogRepository{
+		CreateFunc: func(log *domain.ActivityLog) error { logs = append(logs, log); return nil },
+	}, false)
+
+	entry, err := svc.Create(adminContextFromIP("203.0.113.7"), &requests.CreateAdminIPAllowlistRequest{CIDR: " 203.0.113.7 ", Description: "Kantor PB"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if entry.CIDR != "203.0.113.7/32" {
+		t.Errorf("Expected single IP stored as /32, got %s", entry.CIDR)
+	}
+	if len(logs) != 1 || logs[0].Module != domain.ModuleSecurity || logs[0].ActionType != domain.ActionCreate {
+		t.Errorf("Expected one security create log, got %+v", logs)
+	}
+
+	entry, err = svc.Create(adminContextFromIP("203.0.113.7"), &requests.CreateAdminIPAllowlistRequest{CIDR: "198.51.100.77/24"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if entry.CIDR != "198.51.100.0/24" {
+		t.Errorf("Expected CIDR normalized to network address, got %s", entry.CIDR)
+	}
+}
+
+func TestAdminIPAllowlistCreate_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  []string
+		cidr      string
+		currentIP string
+		wantErr   error
+	}{
+		{"invalid cidr", nil, "not-an-ip", "203.0.113.7", ErrInvalidAdminIPAllowlistCIDR},
+		{"duplicate", []string{"203.0.113.0/24"}, "203.0.113.9/24", "203.0.113.7", ErrAdminIPAllowlistEntryExists},
+		{"first entry excludes current ip", nil, "198.51.100.0/24", "203.0.113.7", ErrAdminIPAllowlistLockout},
+		{"current ip unknown", nil, "198.51.100.0/24", "", ErrAdminIPAllowlistLockout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockAdminIPAllowlistRepository{}
+			for _, cidr := range tt.existing {
+				_ = repo.Create(&domain.AdminIPAllowlistEntry{CIDR: cidr})
+			}
+			svc := NewAdminIPAllowlistService(repo, &MockActivityLogRepository{}, false)
+
+			_, err := svc.Create(adminContextFromIP(tt.currentIP), &requests.CreateAdminIPAllowlistRequest{CIDR: tt.cidr})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if len(repo.entries) != len(tt.existing) {
+				t.Errorf("Expected allowlist unchanged, got %d entries", len(repo.entries))
+			}
+		})
+	}
+}
+
+func TestAdminIPAllowlistDelete_LockoutCheck(t *testing.T) {
+	repo := &MockAdminIPAllowlistRepository{}
+	_ = repo.Create(&domain.AdminIPAllowlistEntry{CIDR: "203.0.113.0/24"})
+	_ = repo.Create(&domain.AdminIPAllowlistEntry{CIDR: "198.51.100.0/24"})
+	svc := NewAdminIPAllowlistService(repo, &MockActivityLogRepository{}, false)
+	ctx := adminContextFromIP("203.0.113.7")
+
+	// Menghapus range yang dipakai admin sendiri ditolak selama masih ada entry lain
+	if err := svc.Delete(ctx, 1); !errors.Is(err, ErrAdminIPAllowlistLockout) {
+		t.Fatalf("Expected ErrAdminIPAllowlistLockout, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, 2); err != nil {
+		t.Fatalf("Expected deleting other range to succeed, got %v", err)
+	}
+
+	// Menghapus entry terakhir mengosongkan allowlist (semua IP diizinkan), jadi tidak mengunci
+	if err := svc.Delete(ctx, 1); err != nil {
+		t.Fatalf("Expected deleting last entry to succeed, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, 99); !errors.Is(err, ErrAdminIPAllowlistEntryNotFound) {
+		t.Errorf("Expected ErrAdminIPAllowlistEntryNotFound, got %v", err)
+	}
+}
+
+func TestAdminIPAllowlistIsAllowed(t *testing.T) {
+	repo := &MockAdminIPAllowlistRepository{}
+	svc := NewAdminIPAllowlistService(repo, &MockActivityLogRepository{}, false)
+
+	if allowed, _ := svc.IsAllowed("192.0.2.1"); !allowed {
+		t.Error("Expected empty allowlist to allow every IP")
+	}
+
+	if _, err := svc.Create(adminContextFromIP("203.0.113.7"), &requests.CreateAdminIPAllowlistRequest{CIDR: "203.0.113.0/24"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Cache di-invalidate setelah perubahan sehingga allowlist baru langsung berlaku
+	if allowed, _ := svc.IsAllowed("192.0.2.1"); allowed {
+		t.Error("Expected IP outside allowlist to be denied")
+	}
+	if allowed, _ := svc.IsAllowed("203.0.113.200"); !allowed {
+		t.Error("Expected IP inside allowlist to be allowed")
+	}
+	if allowed, _ := svc.IsAllowed("garbage"); allowed {
+		t.Error("Expected unparseable IP to be denied")
+	}
+
+	// Database down setelah cache terisi: allowlist terakhir tetap dipakai
+	calls := repo.findCalls
+	svc.(*adminIPAllowlistService).invalidateCache()
+	repo.findErr = errors.New("db down")
+	if allowed, err := svc.IsAllowed("192.0.2.1"); err != nil || allowed {
+		t.Errorf("Expected cached allowlist to deny IP on reload failure, got allowed=%v err=%v", allowed, err)
+	}
+	if repo.findCalls != calls+1 {
+		t.Errorf("Expected one reload attempt, got %d", repo.findCalls-calls)
+	}
+}
+
+func TestAdminIPAllowlistIsAllowed_Bypass(t *testing.T) {
+	repo := &MockAdminIPAllowlistRepository{}
+	_ = repo.Create(&domain.AdminIPAllowlistEntry{CIDR: "203.0.113.0/24"})
+	svc := NewAdminIPAllowlistService(repo, &MockActivityLogRepository{}, true)
+
+	if allowed, _ := svc.IsAllowed("192.0.2.1"); !allowed {
+		t.Error("Expected bypass to allow IP outside allowlist")
+	}
+	if !svc.Bypassed() {
+		t.Error("Expected Bypassed to report true")
+	}
+}
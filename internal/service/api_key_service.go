@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// Format API key: pmii_<prefix>_<secret>
+// Prefix disimpan plaintext untuk lookup, seluruh key disimpan sebagai hash SHA-256
+const (
+	apiKeyTokenPrefix  = "pmii"
+	apiKeyPrefixBytes  = 4
+	apiKeySecretBytes  = 24
+	apiKeyTouchTimeout = time.Minute // Jeda minimal update last_used_at agar tidak menulis di setiap request
+)
+
+// APIKeyService interface untuk business logic API key integrasi
+type APIKeyService interface {
+	GetAll(ctx context.Context) ([]domain.APIKey, error)
+	Create(ctx context.Context, req *requests.CreateAPIKeyRequest) (*domain.APIKey, string, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, rawKey, ipAddress, scope string) (*domain.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo      repository.APIKeyRepository
+	userRepo        repository.UserRepository
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewAPIKeyService constructor untuk APIKeyService
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, activityLogRepo repository.ActivityLogRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:      apiKeyRepo,
+		userRepo:        userRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// GetAll mengambil semua API key
+func (s *apiKeyService) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.apiKeyRepo.FindAll()
+	if err != nil {
+		return nil, ErrAPIKeyFetchFailed
+	}
+	return keys, nil
+}
+
+// Create membuat API key baru dan mengembalikan key plaintext yang hanya ditampilkan sekali
+func (s *apiKeyService) Create(ctx context.Context, req *requests.CreateAPIKeyRequest) (*domain.APIKey, string, error) {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, "", ErrUserNotFound
+	}
+
+	scopes, err := normalizeAPIKeyScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	allowedIPs, err := normalizeAllowedIPs(req.AllowedIPs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrAPIKeyExpiryInPast
+	}
+
+	prefix, err := utils.GenerateRandomToken(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, "", ErrAPIKeySaveFailed
+	}
+	secret, err := utils.GenerateRandomToken(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", ErrAPIKeySaveFailed
+	}
+	rawKey := fmt.Sprintf("%s_%s_%s", apiKeyTokenPrefix, prefix, secret)
+
+	key := &domain.APIKey{
+		Name:       req.Name,
+		Prefix:     prefix,
+		KeyHash:    utils.HashToken(rawKey),
+		Scopes:     scopes,
+		AllowedIPs: allowedIPs,
+		CreatedBy:  adminID,
+		ExpiresAt:  req.ExpiresAt,
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, "", ErrAPIKeySaveFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleAuth,
+		Description: "Membuat API key: " + key.Name,
+		TargetID:    &key.ID,
+		NewValue: map[string]any{
+			"id":          key.ID,
+			"name":        key.Name,
+			"prefix":      key.Prefix,
+			"scopes":      key.Scopes,
+			"allowed_ips": key.AllowedIPs,
+			"expires_at":  key.ExpiresAt,
+		},
+	})
+
+	return key, rawKey, nil
+}
+
+// Revoke mencabut API key, key yang dicabut langsung ditolak di request berikutnya
+func (s *apiKeyService) Revoke(ctx context.Context, id int) error {
+	key, err := s.apiKeyRepo.FindByID(id)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(id)
+	if err != nil {
+		return ErrAPIKeySaveFailed
+	}
+	if !revoked {
+		return ErrAPIKeyAlreadyRevoked
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionDelete,
+		Module:      domain.ModuleAuth,
+		Description: "Mencabut API key: " + key.Name,
+		TargetID:    &key.ID,
+		OldValue: map[string]any{
+			"id":     key.ID,
+			"name":   key.Name,
+			"prefix": key.Prefix,
+		},
+	})
+
+	return nil
+}
+
+// Authenticate memvalidasi API key untuk satu scope dari IP tertentu
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey, ipAddress, scope string) (*domain.APIKey, error) {
+	parts := strings.Split(rawKey, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTokenPrefix || parts[1] == "" || parts[2] == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.FindByPrefix(parts[1])
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(rawKey)), []byte(key.KeyHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !key.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	if !key.AllowsIP(ipAddress) {
+		return nil, ErrAPIKeyIPDenied
+	}
+	if !key.HasScope(scope) {
+		return nil, ErrAPIKeyScopeDenied
+	}
+
+	// Key bertindak atas nama pembuatnya, jadi pembuat harus masih aktif
+	// dan masih memegang permission yang dibutuhkan scope
+	creator, err := s.userRepo.FindByID(key.CreatedBy)
+	if err != nil || !creator.IsActive {
+		return nil, ErrInvalidAPIKey
+	}
+	role := strconv.Itoa(creator.Role)
+	for _, perm := range domain.APIKeyScopePermissions[scope] {
+		if !utils.HasPermission(role, perm) {
+			return nil, ErrAPIKeyScopeDenied
+		}
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchTimeout {
+		_ = s.apiKeyRepo.TouchLastUsed(key.ID, ipAddress, now)
+	}
+
+	return key, nil
+}
+
+// normalizeAPIKeyScopes memvalidasi dan menghapus duplikat scope
+func normalizeAPIKeyScopes(scopes []string) ([]string, error) {
+	result := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if seen[scope] {
+			continue
+		}
+
+		valid := false
+		for _, known := range domain.APIKeyScopes {
+			if scope == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, ErrUnknownAPIKeyScope
+		}
+
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	if len(result) == 0 {
+		return nil, ErrUnknownAPIKeyScope
+	}
+	return result, nil
+}
+
+// normalizeAllowedIPs memvalidasi allowlist berupa IP atau CIDR
+func normalizeAllowedIPs(entries []string) ([]string, error) {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			result = append(result, network.String())
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			result = append(result, ip.String())
+			continue
+		}
+		return nil, ErrInvalidAllowedIP
+	}
+	return result, nil
+}
+
+// API key service errors
+var (
+	ErrInvalidAPIKey        = errors.New("API key tidak valid atau sudah tidak berlaku")
+	ErrAPIKeyScopeDenied    = errors.New("API key tidak memiliki scope untuk resource ini")
+	ErrAPIKeyIPDenied       = errors.New("API key tidak diizinkan dari IP ini")
+	ErrAPIKeyNotFound       = errors.New("API key tidak ditemukan")
+	ErrAPIKeyAlreadyRevoked = errors.New("API key sudah dicabut")
+	ErrAPIKeyExpiryInPast   = errors.New("waktu kedaluwarsa API key harus di masa depan")
+	ErrUnknownAPIKeyScope   = errors.New("scope API key tidak dikenal")
+	ErrInvalidAllowedIP     = errors.New("allowed_ips harus berisi IP atau CIDR yang valid")
+	ErrAPIKeyFetchFailed    = errors.New("gagal mengambil data API key")
+	ErrAPIKeySaveFailed     = errors.New("gagal menyimpan API key")
+)
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MockAPIKeyRepository menyimpan API key di memory berdasarkan prefix
+type MockAPIKeyRepository struct {
+	keys    map[string]*domain.APIKey
+	touched int
+}
+
+func newMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{keys: map[string]*domain.APIKey{}}
+}
+
+func (m *MockAPIKeyRepository) Create(key *domain.APIKey) error {
+	key.ID = len(m.keys) + 1
+	m.keys[key.Prefix] = key
+	return nil
+}
+
+func (m *MockAPIKeyRepository) FindAll() ([]domain.APIKey, error) {
+	result := make([]domain.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		result = append(result, *key)
+	}
+	return result, nil
+}
+
+func (m *MockAPIKeyRepository) FindByID(id int) (*domain.APIKey, error) {
+	for _, key := range m.keys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(prefix string) (*domain.APIKey, error) {
+	if key, ok := m.keys[prefix]; ok {
+		return key, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockAPIKeyRepository) Revoke(id int) (bool, error) {
+	key, err := m.FindByID(id)
+	if err != nil || key.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return true, nil
+}
+
+func (m *MockAPIKeyRepository) RevokeAllByCreator(userID int) (int64, error) {
+	var revoked int64
+	now := time.Now()
+	for _, key := range m.keys {
+		if key.CreatedBy == userID && key.RevokedAt == nil {
+			key.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(id int, ipAddress string, usedAt time.Time) error {
+	m.touched++
+	key, _ := m.FindByID(id)
+	key.LastUsedAt = &usedAt
+	return nil
+}
+
+// newAPIKeyCreatorRepo mengembalikan user repository berisi pembuat key (user 1)
+// dan menyiapkan permission store: role 1 memegang semua permission scope, role 2 tidak
+func newAPIKeyCreatorRepo(creator *domain.User) *MockUserRepository {
+	utils.InitPermissionStore(&MockRoleRepository{
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			if roleID == domain.RoleAdminID {
+				return "admin", []string{domain.PermPostsManageAny, domain.PermPostsCreate, domain.PermPostsUpdate, domain.PermPostsDelete, domain.PermDocumentsManage}, nil
+			}
+			return "author", []string{domain.PermPostsCreate}, nil
+		},
+	})
+	return &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			if id != creator.ID {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return creator, nil
+		},
+	}
+}
+
+func createTestAPIKey(t *testing.T, svc APIKeyService, req *requests.CreateAPIKeyRequest) (*domain.APIKey, string) {
+	t.Helper()
+	key, rawKey, err := svc.Create(utils.WithUserID(context.Background(), 1), req)
+	if err != nil {
+		t.Fatalf("Expected no error creating key, got %v", err)
+	}
+	return key, rawKey
+}
+
+// TestAPIKeyCreate_StoresHashOnly menguji key plaintext tidak disimpan, hanya hash
+func TestAPIKeyCreate_StoresHashOnly(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	svc := NewAPIKeyService(repo, &MockUserRepository{}, &MockActivityLogRepoForAuth{})
+
+	key, rawKey := createTestAPIKey(t, svc, &requests.CreateAPIKeyRequest{
+		Name:   "Portal Mitra",
+		Scopes: []string{domain.ScopeNewsRead, domain.ScopeNewsRead},
+	})
+
+	if !strings.HasPrefix(rawKey, "pmii_"+key.Prefix+"_") {
+		t.Errorf("Expected raw key to contain prefix %s, got %s", key.Prefix, rawKey)
+	}
+	if key.KeyHash != utils.HashToken(rawKey) || strings.Contains(key.KeyHash, rawKey) {
+		t.Error("Expected only the hash of the key to be stored")
+	}
+	if len(key.Scopes) != 1 || key.CreatedBy != 1 {
+		t.Errorf("Expected deduplicated scopes and creator 1, got %v / %d", key.Scopes, key.CreatedBy)
+	}
+}
+
+// TestAPIKeyCreate_Validation menguji scope, allowlist IP, dan kedaluwarsa yang tidak valid ditolak
+func TestAPIKeyCreate_Validation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		req      requests.CreateAPIKeyRequest
+		expected error
+	}{
+		{name: "unknown scope", req: requests.CreateAPIKeyRequest{Name: "x", Scopes: []string{"users:write"}}, expected: ErrUnknownAPIKeyScope},
+		{name: "invalid ip", req: requests.CreateAPIKeyRequest{Name: "x", Scopes: []string{domain.ScopeNewsRead}, AllowedIPs: []string{"10.0.0.0/99"}}, expected: ErrInvalidAllowedIP},
+		{name: "expiry in past", req: requests.CreateAPIKeyRequest{Name: "x", Scopes: []string{domain.ScopeNewsRead}, ExpiresAt: &past}, expected: ErrAPIKeyExpiryInPast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAPIKeyService(newMockAPIKeyRepository(), &MockUserRepository{}, &MockActivityLogRepoForAuth{})
+			_, _, err := svc.Create(utils.WithUserID(context.Background(), 1), &tt.req)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestAPIKeyAuthenticate menguji validasi key, scope, IP allowlist, revoke, dan kedaluwarsa
+func TestAPIKeyAuthenticate(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	creator := &domain.User{ID: 1, Role: domain.RoleAdminID, IsActive: true}
+	svc := NewAPIKeyService(repo, newAPIKeyCreatorRepo(creator), &MockActivityLogRepoForAuth{})
+
+	key, rawKey := createTestAPIKey(t, svc, &requests.CreateAPIKeyRequest{
+		Name:       "Portal Mitra",
+		Scopes:     []string{domain.ScopeNewsRead},
+		AllowedIPs: []string{"10.0.0.0/24"},
+	})
+
+	tests := []struct {
+		name     string
+		rawKey   string
+		ip       string
+		scope    string
+		expected error
+	}{
+		{name: "valid", rawKey: rawKey, ip: "10.0.0.7", scope: domain.ScopeNewsRead, expected: nil},
+		{name: "wrong secret", rawKey: "pmii_" + key.Prefix + "_deadbeef", ip: "10.0.0.7", scope: domain.ScopeNewsRead, expected: ErrInvalidAPIKey},
+		{name: "malformed", rawKey: "not-a-key", ip: "10.0.0.7", scope: domain.ScopeNewsRead, expected: ErrInvalidAPIKey},
+		{name: "ip outside allowlist", rawKey: rawKey, ip: "192.168.1.1", scope: domain.ScopeNewsRead, expected: ErrAPIKeyIPDenied},
+		{name: "missing scope", rawKey: rawKey, ip: "10.0.0.7", scope: domain.ScopePostsWrite, expected: ErrAPIKeyScopeDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Authenticate(context.Background(), tt.rawKey, tt.ip, tt.scope)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if tt.expected == nil && got.ID != key.ID {
+				t.Errorf("Expected key %d, got %d", key.ID, got.ID)
+			}
+		})
+	}
+
+	if repo.touched != 1 {
+		t.Errorf("Expected last_used_at to be updated once, got %d", repo.touched)
+	}
+
+	// Key kedaluwarsa ditolak
+	expired := time.Now().Add(-time.Minute)
+	key.ExpiresAt = &expired
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected expired key to be rejected, got %v", err)
+	}
+	key.ExpiresAt = nil
+
+	// Key yang dicabut langsung ditolak
+	if err := svc.Revoke(utils.WithUserID(context.Background(), 1), key.ID); err != nil {
+		t.Fatalf("Expected no error revoking key, got %v", err)
+	}
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected revoked key to be rejected, got %v", err)
+	}
+	if err := svc.Revoke(utils.WithUserID(context.Background(), 1), key.ID); !errors.Is(err, ErrAPIKeyAlreadyRevoked) {
+		t.Errorf("Expected ErrAPIKeyAlreadyRevoked, got %v", err)
+	}
+}
+
+// TestAPIKeyAuthenticate_CreatorChecks menguji key ditolak jika pembuatnya nonaktif,
+// dihapus, atau tidak lagi memegang permission yang dibutuhkan scope
+func TestAPIKeyAuthenticate_CreatorChecks(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	creator := &domain.User{ID: 1, Role: domain.RoleAdminID, IsActive: true}
+	svc := NewAPIKeyService(repo, newAPIKeyCreatorRepo(creator), &MockActivityLogRepoForAuth{})
+
+	_, rawKey := createTestAPIKey(t, svc, &requests.CreateAPIKeyRequest{
+		Name:   "Portal Mitra",
+		Scopes: []string{domain.ScopeNewsRead, domain.ScopePostsWrite},
+	})
+
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); err != nil {
+		t.Fatalf("Expected key of active admin to be accepted, got %v", err)
+	}
+
+	// Pembuat diturunkan ke author: posts:write masih boleh, news:read butuh posts.manage_any
+	creator.Role = 2
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); !errors.Is(err, ErrAPIKeyScopeDenied) {
+		t.Errorf("Expected ErrAPIKeyScopeDenied after demotion, got %v", err)
+	}
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopePostsWrite); !errors.Is(err, ErrAPIKeyScopeDenied) {
+		t.Errorf("Expected ErrAPIKeyScopeDenied for posts:write without update/delete, got %v", err)
+	}
+
+	// Pembuat dinonaktifkan
+	creator.Role = domain.RoleAdminID
+	creator.IsActive = false
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for inactive creator, got %v", err)
+	}
+
+	// Pembuat dihapus
+	creator.ID = 2
+	if _, err := svc.Authenticate(context.Background(), rawKey, "10.0.0.7", domain.ScopeNewsRead); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey for deleted creator, got %v", err)
+	}
+}
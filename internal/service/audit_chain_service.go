@@ -0,0 +1,364 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+)
+
+// auditChainBatchSize jumlah entry yang dibaca per query saat menelusuri chain
+const auditChainBatchSize = 500
+
+// AuditChainService interface untuk verifikasi hash chain activity log dan checkpoint bertanda tangan
+type AuditChainService interface {
+	// Verify menelusuri seluruh chain dan melaporkan link pertama yang rusak
+	Verify() (*responses.AuditChainVerificationResponse, error)
+
+	// VerifyExport menelusuri chain dengan checkpoint dari berkas ekspor (disimpan di luar database)
+	// Signature dicek dengan public key di berkas tersebut, bukan key yang sedang dikonfigurasi
+	VerifyExport(export *responses.AuditCheckpointExportResponse) (*responses.AuditChainVerificationResponse, error)
+
+	// CreateCheckpoint memverifikasi chain sejak checkpoint terakhir lalu menandatangani head-nya
+	// Mengembalikan nil jika tidak ada entry baru sejak checkpoint terakhir
+	CreateCheckpoint() (*responses.AuditCheckpointResponse, error)
+
+	// ExportCheckpoints mengembalikan semua checkpoint beserta public key untuk disimpan di luar database
+	ExportCheckpoints() (*responses.AuditCheckpointExportResponse, error)
+}
+
+type auditChainService struct {
+	repo   repository.AuditChainRepository
+	signer *audit.CheckpointSigner
+}
+
+// NewAuditChainService constructor untuk AuditChainService
+// signer nil = checkpoint tidak dibuat, verifikasi chain tetap berjalan
+func NewAuditChainService(repo repository.AuditChainRepository, signer *audit.CheckpointSigner) AuditChainService {
+	if signer == nil {
+		log.Println("[WARN] AUDIT_CHECKPOINT_KEY_FILE tidak diset: checkpoint activity log tidak dibuat")
+	}
+	return &auditChainService{repo: repo, signer: signer}
+}
+
+var (
+	ErrAuditChainVerifyFailed  = errors.New("gagal memverifikasi hash chain activity log")
+	ErrInvalidAuditExport      = errors.New("berkas ekspor checkpoint tidak valid")
+	ErrAuditChainBroken        = errors.New("hash chain activity log rusak, checkpoint tidak dibuat")
+	ErrAuditCheckpointDisabled = errors.New("checkpoint activity log belum dikonfigurasi (AUDIT_CHECKPOINT_KEY_FILE)")
+	ErrAuditCheckpointFailed   = errors.New("gagal membuat checkpoint activity log")
+	ErrAuditCheckpointBusy     = errors.New("checkpoint activity log sedang dibuat oleh proses lain")
+	ErrAuditCheckpointsFailed  = errors.New("gagal mengambil checkpoint activity log")
+)
+
+// Verify menelusuri chain dari entry pertama sampai head dengan checkpoint yang tersimpan di database
+func (s *auditChainService) Verify() (*responses.AuditChainVerificationResponse, error) {
+	checkpoints, err := s.repo.GetCheckpoints()
+	if err != nil {
+		return nil, ErrAuditChainVerifyFailed
+	}
+
+	var verifier *audit.CheckpointVerifier
+	if s.signer != nil {
+		verifier = &s.signer.CheckpointVerifier
+	}
+	return s.verify(checkpoints, verifier)
+}
+
+// VerifyExport berguna jika checkpoint di database ikut dihapus atau diganti
+func (s *auditChainService) VerifyExport(export *responses.AuditCheckpointExportResponse) (*responses.AuditChainVerificationResponse, error) {
+	if export == nil || export.PublicKey == "" {
+		return nil, ErrInvalidAuditExport
+	}
+	verifier, err := audit.ParseCheckpointVerifier(export.PublicKey)
+	if err != nil {
+		return nil, ErrInvalidAuditExport
+	}
+
+	checkpoints := make([]domain.ActivityLogCheckpoint, 0, len(export.Checkpoints))
+	for _, checkpoint := range export.Checkpoints {
+		checkpoints = append(checkpoints, domain.ActivityLogCheckpoint{
+			ID:         checkpoint.ID,
+			LastLogID:  checkpoint.LastLogID,
+			EntryHash:  checkpoint.EntryHash,
+			EntryCount: checkpoint.EntryCount,
+			KeyID:      checkpoint.KeyID,
+			Signature:  checkpoint.Signature,
+			CreatedAt:  checkpoint.CreatedAt,
+		})
+	}
+	sort.SliceStable(checkpoints, func(i, j int) bool {
+		return checkpoints[i].LastLogID < checkpoints[j].LastLogID
+	})
+
+	return s.verify(checkpoints, verifier)
+}
+
+// verify mengecek signature checkpoint lebih dulu agar checkpoint palsu tidak dipakai sebagai acuan
+// verifier nil = signature tidak dicek, checkpoint tetap dicocokkan dengan chain
+func (s *auditChainService) verify(checkpoints []domain.ActivityLogCheckpoint, verifier *audit.CheckpointVerifier) (*responses.AuditChainVerificationResponse, error) {
+	walker := &auditChainWalker{
+		repo:        s.repo,
+		checkpoints: checkpoints,
+		prevHash:    audit.GenesisHash,
+		result:      &responses.AuditChainVerificationResponse{Valid: true, VerifiedAt: time.Now()},
+	}
+	if err := walker.startFromArchive(); err != nil {
+		return nil, ErrAuditChainVerifyFailed
+	}
+
+	for i := range checkpoints {
+		if verifier == nil || checkpoints[i].KeyID != verifier.KeyID() {
+			walker.result.UnverifiedCheckpoints++
+			continue
+		}
+		if !verifier.Verify(checkpoints[i]) {
+			walker.fail(nil, &checkpoints[i].ID, "signature checkpoint tidak valid")
+			return walker.result, nil
+		}
+	}
+
+	if err := walker.walk(); err != nil {
+		return nil, ErrAuditChainVerifyFailed
+	}
+	return walker.result, nil
+}
+
+// CreateCheckpoint hanya menandatangani head yang sudah terverifikasi
+// Penelusuran dimulai dari entry checkpoint terakhir sehingga tidak perlu membaca ulang seluruh chain
+// Hanya satu replica yang membuat checkpoint pada satu waktu (advisory lock)
+func (s *auditChainService) CreateCheckpoint() (*responses.AuditCheckpointResponse, error) {
+	if s.signer == nil {
+		return nil, ErrAuditCheckpointDisabled
+	}
+
+	var result *responses.AuditCheckpointResponse
+	var createErr error
+	acquired, err := s.repo.WithCheckpointLock(func() error {
+		result, createErr = s.createCheckpoint()
+		return nil
+	})
+	if err != nil {
+		return nil, ErrAuditCheckpointFailed
+	}
+	if !acquired {
+		return nil, ErrAuditCheckpointBusy
+	}
+	return result, createErr
+}
+
+// createCheckpoint memverifikasi chain sejak checkpoint terakhir lalu menyimpan checkpoint baru, dipanggil saat lock dipegang
+func (s *auditChainService) createCheckpoint() (*responses.AuditCheckpointResponse, error) {
+
+	latest, err := s.repo.GetLatestCheckpoint()
+	if err != nil {
+		return nil, ErrAuditCheckpointFailed
+	}
+
+	walker := &auditChainWalker{
+		repo:     s.repo,
+		prevHash: audit.GenesisHash,
+		result:   &responses.AuditChainVerificationResponse{Valid: true, VerifiedAt: time.Now()},
+	}
+	if err := walker.startFromArchive(); err != nil {
+		return nil, ErrAuditCheckpointFailed
+	}
+	// Setelah rotasi key atau jika entry checkpoint sudah diarsipkan, chain ditelusuri dari awal (atau dari arsip terakhir)
+	if latest != nil && latest.KeyID == s.signer.KeyID() && latest.LastLogID > walker.afterID {
+		if !s.signer.Verify(*latest) {
+			log.Printf("[WARN] Signature checkpoint activity log #%d tidak valid, checkpoint baru tidak dibuat", latest.ID)
+			return nil, ErrAuditChainBroken
+		}
+		// Mulai tepat di entry checkpoint terakhir, prev_hash entry tersebut sudah dijamin checkpoint
+		walker.checkpoints = []domain.ActivityLogCheckpoint{*latest}
+		walker.afterID = latest.LastLogID - 1
+		walker.prevHash = ""
+		walker.started = true
+		walker.offset = latest.EntryCount - 1
+	}
+
+	if err := walker.walk(); err != nil {
+		return nil, ErrAuditCheckpointFailed
+	}
+	if !walker.result.Valid {
+		log.Printf("[WARN] Hash chain activity log rusak: %s", walker.result.BrokenLink.Reason)
+		return nil, ErrAuditChainBroken
+	}
+
+	head := walker.result.HeadLogID
+	if head == nil || (latest != nil && *head == latest.LastLogID && latest.KeyID == s.signer.KeyID()) {
+		return nil, nil
+	}
+
+	checkpoint := &domain.ActivityLogCheckpoint{
+		LastLogID:  *head,
+		EntryHash:  walker.result.HeadHash,
+		EntryCount: walker.offset + walker.result.CheckedEntries,
+		CreatedAt:  time.Now(),
+	}
+	s.signer.Sign(checkpoint)
+	if err := s.repo.CreateCheckpoint(checkpoint); err != nil {
+		return nil, ErrAuditCheckpointFailed
+	}
+
+	result := toAuditCheckpointResponse(checkpoint)
+	return &result, nil
+}
+
+// ExportCheckpoints menyusun berkas ekspor checkpoint
+func (s *auditChainService) ExportCheckpoints() (*responses.AuditCheckpointExportResponse, error) {
+	checkpoints, err := s.repo.GetCheckpoints()
+	if err != nil {
+		return nil, ErrAuditCheckpointsFailed
+	}
+
+	export := &responses.AuditCheckpointExportResponse{
+		Algorithm:   "ed25519",
+		ExportedAt:  time.Now(),
+		Checkpoints: make([]responses.AuditCheckpointResponse, 0, len(checkpoints)),
+	}
+	if s.signer != nil {
+		publicKey, err := s.signer.PublicKeyPEM()
+		if err != nil {
+			return nil, ErrAuditCheckpointsFailed
+		}
+		export.KeyID = s.signer.KeyID()
+		export.PublicKey = publicKey
+	}
+	for i := range checkpoints {
+		export.Checkpoints = append(export.Checkpoints, toAuditCheckpointResponse(&checkpoints[i]))
+	}
+	return export, nil
+}
+
+// auditChainWalker menelusuri entry per batch dan berhenti di link pertama yang rusak
+type auditChainWalker struct {
+	repo        repository.AuditChainRepository
+	checkpoints []domain.ActivityLogCheckpoint // urut last_log_id naik
+	afterID     int
+	prevHash    string // Kosong = prev_hash entry pertama tidak dicek (penelusuran dimulai dari checkpoint)
+	started     bool   // false = entry tanpa hash masih dianggap entry lama sebelum chain aktif
+	offset      int64  // Jumlah entry ber-hash sebelum afterID (sudah diarsipkan atau dijamin checkpoint)
+	next        int    // Index checkpoint berikutnya yang harus ditemui
+	result      *responses.AuditChainVerificationResponse
+}
+
+// startFromArchive memulai penelusuran setelah entry terakhir yang sudah diarsipkan
+// Checkpoint yang entry-nya sudah diarsipkan tidak bisa dicocokkan lagi sehingga dilewati
+func (w *auditChainWalker) startFromArchive() error {
+	archive, archivedCount, err := w.repo.GetArchiveAnchor()
+	if err != nil || archive == nil {
+		return err
+	}
+
+	w.afterID = archive.ToLogID
+	w.offset = archivedCount
+	w.result.ArchivedEntries = archivedCount
+	if archive.LastEntryHash != nil {
+		w.prevHash = *archive.LastEntryHash
+		w.started = true
+	}
+	for w.next < len(w.checkpoints) && w.checkpoints[w.next].LastLogID <= archive.ToLogID {
+		w.next++
+	}
+	return nil
+}
+
+func (w *auditChainWalker) walk() error {
+	for {
+		entries, err := w.repo.FindEntriesAfter(w.afterID, auditChainBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range entries {
+			if !w.check(&entries[i]) {
+				return nil
+			}
+		}
+		if len(entries) < auditChainBatchSize {
+			break
+		}
+		w.afterID = entries[len(entries)-1].ID
+	}
+
+	// Checkpoint yang entry-nya tidak pernah ditemui berarti entry terakhir chain dihapus
+	if w.next < len(w.checkpoints) {
+		checkpoint := w.checkpoints[w.next]
+		w.fail(&checkpoint.LastLogID, &checkpoint.ID, "entry checkpoint tidak ditemukan, entry terakhir chain dihapus")
+	}
+	return nil
+}
+
+// check memverifikasi satu entry, false jika chain rusak di entry ini
+func (w *auditChainWalker) check(entry *domain.ActivityLog) bool {
+	if w.next < len(w.checkpoints) && w.checkpoints[w.next].LastLogID < entry.ID {
+		checkpoint := w.checkpoints[w.next]
+		w.fail(&checkpoint.LastLogID, &checkpoint.ID, "entry checkpoint tidak ditemukan, entry sudah dihapus")
+		return false
+	}
+
+	if entry.EntryHash == nil {
+		if !w.started {
+			w.result.UnchainedEntries++
+			return true
+		}
+		w.fail(&entry.ID, nil, "entry_hash kosong padahal hash chain sudah aktif")
+		return false
+	}
+	w.started = true
+
+	if entry.PrevHash == nil || (w.prevHash != "" && *entry.PrevHash != w.prevHash) {
+		w.fail(&entry.ID, nil, "prev_hash tidak cocok dengan entry sebelumnya, ada entry yang dihapus atau disisipkan")
+		return false
+	}
+	hash, err := audit.EntryHash(entry)
+	if err != nil || hash != *entry.EntryHash {
+		w.fail(&entry.ID, nil, "isi entry tidak cocok dengan entry_hash, entry sudah diubah")
+		return false
+	}
+	w.result.CheckedEntries++
+
+	for w.next < len(w.checkpoints) && w.checkpoints[w.next].LastLogID == entry.ID {
+		checkpoint := w.checkpoints[w.next]
+		if checkpoint.EntryHash != hash || checkpoint.EntryCount != w.offset+w.result.CheckedEntries {
+			w.fail(&entry.ID, &checkpoint.ID, "entry tidak cocok dengan checkpoint, chain sudah ditulis ulang")
+			return false
+		}
+		w.result.CheckedCheckpoints++
+		w.next++
+	}
+
+	id := entry.ID
+	w.prevHash = hash
+	w.result.HeadLogID = &id
+	w.result.HeadHash = hash
+	return true
+}
+
+func (w *auditChainWalker) fail(logID, checkpointID *int, reason string) {
+	w.result.Valid = false
+	w.result.BrokenLink = &responses.AuditChainBreakResponse{
+		LogID:        logID,
+		CheckpointID: checkpointID,
+		Reason:       reason,
+	}
+}
+
+func toAuditCheckpointResponse(checkpoint *domain.ActivityLogCheckpoint) responses.AuditCheckpointResponse {
+	return responses.AuditCheckpointResponse{
+		ID:         checkpoint.ID,
+		LastLogID:  checkpoint.LastLogID,
+		EntryHash:  checkpoint.EntryHash,
+		EntryCount: checkpoint.EntryCount,
+		KeyID:      checkpoint.KeyID,
+		Payload:    audit.CheckpointPayload(checkpoint),
+		Signature:  checkpoint.Signature,
+		CreatedAt:  checkpoint.CreatedAt,
+	}
+}
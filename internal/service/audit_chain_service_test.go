@@ -0,0 +1,354 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+)
+
+// MockAuditChainRepository menyimpan activity log dan checkpoint di memory
+type MockAuditChainRepository struct {
+	Entries     []domain.ActivityLog
+	Checkpoints []domain.ActivityLogCheckpoint
+	Archives    []domain.ActivityLogArchive
+	FindErr     error
+	LockHeld    bool
+}
+
+func (m *MockAuditChainRepository) FindEntriesAfter(afterID, limit int) ([]domain.ActivityLog, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	var result []domain.ActivityLog
+	for _, entry := range m.Entries {
+		if entry.ID > afterID && len(result) < limit {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAuditChainRepository) GetArchiveAnchor() (*domain.ActivityLogArchive, int64, error) {
+	if len(m.Archives) == 0 {
+		return nil, 0, nil
+	}
+	var archivedCount int64
+	for _, archive := range m.Archives {
+		archivedCount += archive.ChainedCount
+	}
+	latest := m.Archives[len(m.Archives)-1]
+	return &latest, archivedCount, nil
+}
+
+func (m *MockAuditChainRepository) CreateCheckpoint(checkpoint *domain.ActivityLogCheckpoint) error {
+	checkpoint.ID = len(m.Checkpoints) + 1
+	m.Checkpoints = append(m.Checkpoints, *checkpoint)
+	return nil
+}
+
+func (m *MockAuditChainRepository) GetLatestCheckpoint() (*domain.ActivityLogCheckpoint, error) {
+	if len(m.Checkpoints) == 0 {
+		return nil, nil
+	}
+	latest := m.Checkpoints[len(m.Checkpoints)-1]
+	return &latest, nil
+}
+
+func (m *MockAuditChainRepository) GetCheckpoints() ([]domain.ActivityLogCheckpoint, error) {
+	return m.Checkpoints, nil
+}
+
+func (m *MockAuditChainRepository) WithCheckpointLock(fn func() error) (bool, error) {
+	if m.LockHeld {
+		return false, nil
+	}
+	return true, fn()
+}
+
+// appendEntries menambahkan entry ber-hash ke chain, id dimulai setelah entry terakhir
+func (m *MockAuditChainRepository) appendEntries(t *testing.T, count int) {
+	t.Helper()
+	prevHash := audit.GenesisHash
+	nextID := 1
+	if len(m.Entries) > 0 {
+		last := m.Entries[len(m.Entries)-1]
+		if last.EntryHash != nil {
+			prevHash = *last.EntryHash
+		}
+		nextID = last.ID + 1
+	}
+
+	for i := 0; i < count; i++ {
+		description := "Mengupdate post"
+		entry := domain.ActivityLog{
+			ID:          nextID + i,
+			UserID:      1,
+			ActionType:  domain.ActionUpdate,
+			Module:      domain.ModulePost,
+			Description: &description,
+			NewValue:    map[string]any{"title": "Judul", "version": i},
+		}
+		if err := audit.Seal(&entry, prevHash); err != nil {
+			t.Fatalf("failed to seal entry: %v", err)
+		}
+		prevHash = *entry.EntryHash
+		m.Entries = append(m.Entries, entry)
+	}
+}
+
+func newTestCheckpointSigner(t *testing.T) *audit.CheckpointSigner {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return audit.NewCheckpointSigner(private)
+}
+
+// TestAuditChainVerify_Valid menguji chain utuh dengan entry lama sebelum chain aktif
+func TestAuditChainVerify_Valid(t *testing.T) {
+	repo := &MockAuditChainRepository{Entries: []domain.ActivityLog{{ID: 1}, {ID: 2}}}
+	repo.appendEntries(t, 3)
+	svc := NewAuditChainService(repo, nil)
+
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid || result.CheckedEntries != 3 || result.UnchainedEntries != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.HeadLogID == nil || *result.HeadLogID != 5 || result.HeadHash != *repo.Entries[4].EntryHash {
+		t.Errorf("unexpected head: %v %s", result.HeadLogID, result.HeadHash)
+	}
+}
+
+// TestAuditChainVerify_ModifiedEntry menguji entry yang isinya diubah setelah disimpan
+func TestAuditChainVerify_ModifiedEntry(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 4)
+	repo.Entries[1].NewValue = map[string]any{"title": "Judul palsu"}
+	svc := NewAuditChainService(repo, nil)
+
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.BrokenLink == nil || result.BrokenLink.LogID == nil || *result.BrokenLink.LogID != 2 {
+		t.Errorf("expected broken link at entry 2, got %+v", result.BrokenLink)
+	}
+}
+
+// TestAuditChainVerify_DeletedEntry menguji entry di tengah chain yang dihapus
+func TestAuditChainVerify_DeletedEntry(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 4)
+	repo.Entries = append(repo.Entries[:2], repo.Entries[3:]...)
+	svc := NewAuditChainService(repo, nil)
+
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.BrokenLink == nil || *result.BrokenLink.LogID != 4 {
+		t.Errorf("expected broken link at entry 4, got %+v", result.BrokenLink)
+	}
+}
+
+// TestAuditChainVerify_TruncatedTail menguji penghapusan entry terakhir yang hanya terdeteksi lewat checkpoint
+func TestAuditChainVerify_TruncatedTail(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 3)
+	signer := newTestCheckpointSigner(t)
+	svc := NewAuditChainService(repo, signer)
+
+	if _, err := svc.CreateCheckpoint(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Entries = repo.Entries[:2]
+
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.BrokenLink == nil || result.BrokenLink.CheckpointID == nil || *result.BrokenLink.CheckpointID != 1 {
+		t.Errorf("expected broken link at checkpoint 1, got %+v", result.BrokenLink)
+	}
+}
+
+// TestAuditChainVerify_ForgedCheckpoint menguji checkpoint yang diubah di database
+func TestAuditChainVerify_ForgedCheckpoint(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 2)
+	signer := newTestCheckpointSigner(t)
+	svc := NewAuditChainService(repo, signer)
+
+	if _, err := svc.CreateCheckpoint(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.Checkpoints[0].EntryCount = 1
+
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.BrokenLink == nil || result.BrokenLink.Reason != "signature checkpoint tidak valid" {
+		t.Errorf("expected invalid signature, got %+v", result.BrokenLink)
+	}
+}
+
+// TestAuditChainCreateCheckpoint_Incremental menguji checkpoint berikutnya hanya dibuat jika ada entry baru
+func TestAuditChainCreateCheckpoint_Incremental(t *testing.T) {
+	repo := &MockAuditChainRepository{Entries: []domain.ActivityLog{{ID: 1}}}
+	repo.appendEntries(t, 2)
+	signer := newTestCheckpointSigner(t)
+	svc := NewAuditChainService(repo, signer)
+
+	first, err := svc.CreateCheckpoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first == nil || first.LastLogID != 3 || first.EntryCount != 2 || first.EntryHash != *repo.Entries[2].EntryHash {
+		t.Fatalf("unexpected first checkpoint: %+v", first)
+	}
+
+	again, err := svc.CreateCheckpoint()
+	if err != nil || again != nil {
+		t.Fatalf("expected no checkpoint without new entries, got %+v (err: %v)", again, err)
+	}
+
+	repo.appendEntries(t, 2)
+	second, err := svc.CreateCheckpoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second == nil || second.LastLogID != 5 || second.EntryCount != 4 {
+		t.Fatalf("unexpected second checkpoint: %+v", second)
+	}
+	if !signer.Verify(repo.Checkpoints[1]) {
+		t.Error("expected second checkpoint to be signed")
+	}
+}
+
+// TestAuditChainCreateCheckpoint_BrokenChain menguji head yang tidak ditandatangani jika chain rusak
+func TestAuditChainCreateCheckpoint_BrokenChain(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 3)
+	repo.Entries[2].UserID = 99
+	svc := NewAuditChainService(repo, newTestCheckpointSigner(t))
+
+	_, err := svc.CreateCheckpoint()
+	if !errors.Is(err, ErrAuditChainBroken) {
+		t.Errorf("expected ErrAuditChainBroken, got %v", err)
+	}
+	if len(repo.Checkpoints) != 0 {
+		t.Errorf("expected no checkpoint, got %d", len(repo.Checkpoints))
+	}
+}
+
+// TestAuditChainCreateCheckpoint_Disabled menguji checkpoint tanpa key
+func TestAuditChainCreateCheckpoint_Disabled(t *testing.T) {
+	svc := NewAuditChainService(&MockAuditChainRepository{}, nil)
+
+	if _, err := svc.CreateCheckpoint(); !errors.Is(err, ErrAuditCheckpointDisabled) {
+		t.Errorf("expected ErrAuditCheckpointDisabled, got %v", err)
+	}
+}
+
+// TestAuditChainCreateCheckpoint_Busy menguji checkpoint tidak dibuat saat replica lain memegang lock
+func TestAuditChainCreateCheckpoint_Busy(t *testing.T) {
+	repo := &MockAuditChainRepository{LockHeld: true}
+	repo.appendEntries(t, 2)
+	svc := NewAuditChainService(repo, newTestCheckpointSigner(t))
+
+	if _, err := svc.CreateCheckpoint(); !errors.Is(err, ErrAuditCheckpointBusy) {
+		t.Errorf("expected ErrAuditCheckpointBusy, got %v", err)
+	}
+	if len(repo.Checkpoints) != 0 {
+		t.Errorf("expected no checkpoint, got %d", len(repo.Checkpoints))
+	}
+}
+
+// TestAuditChainVerifyExport menguji verifikasi dengan berkas ekspor saat checkpoint di database sudah dihapus
+func TestAuditChainVerifyExport(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 3)
+	svc := NewAuditChainService(repo, newTestCheckpointSigner(t))
+
+	if _, err := svc.CreateCheckpoint(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	export, err := svc.ExportCheckpoints()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.PublicKey == "" || len(export.Checkpoints) != 1 || export.Checkpoints[0].Payload == "" {
+		t.Fatalf("unexpected export: %+v", export)
+	}
+
+	// Penyerang menghapus entry terakhir beserta checkpoint-nya di database
+	repo.Entries = repo.Entries[:2]
+	repo.Checkpoints = nil
+
+	if result, _ := svc.Verify(); !result.Valid {
+		t.Fatalf("expected database-only verification to miss the truncation, got %+v", result.BrokenLink)
+	}
+	result, err := svc.VerifyExport(export)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.CheckedCheckpoints != 0 || result.UnverifiedCheckpoints != 0 {
+		t.Errorf("expected truncation to be detected with exported checkpoints, got %+v", result)
+	}
+}
+
+// TestAuditChainVerify_ExportWithoutPublicKey menguji berkas ekspor yang tidak valid
+func TestAuditChainVerify_ExportWithoutPublicKey(t *testing.T) {
+	svc := NewAuditChainService(&MockAuditChainRepository{}, nil)
+
+	if _, err := svc.VerifyExport(&responses.AuditCheckpointExportResponse{ExportedAt: time.Now()}); !errors.Is(err, ErrInvalidAuditExport) {
+		t.Errorf("expected ErrInvalidAuditExport, got %v", err)
+	}
+}
+
+// TestAuditChainVerify_AfterArchive menguji chain yang entry lamanya sudah diarsipkan dan dihapus
+func TestAuditChainVerify_AfterArchive(t *testing.T) {
+	repo := &MockAuditChainRepository{}
+	repo.appendEntries(t, 5)
+	svc := NewAuditChainService(repo, newTestCheckpointSigner(t))
+
+	if _, err := svc.CreateCheckpoint(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	archived := repo.Entries[:3]
+	repo.Entries = repo.Entries[3:]
+
+	// Tanpa catatan arsip, entry pertama yang tersisa tidak menunjuk genesis
+	if result, _ := svc.Verify(); result.Valid {
+		t.Fatal("expected purge without archive record to break the chain")
+	}
+
+	repo.Archives = []domain.ActivityLogArchive{{FromLogID: 1, ToLogID: 3, EntryCount: 3, ChainedCount: 3, LastEntryHash: archived[2].EntryHash}}
+	result, err := svc.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid || result.CheckedEntries != 2 || result.ArchivedEntries != 3 || result.CheckedCheckpoints != 1 {
+		t.Errorf("unexpected result: %+v (broken: %+v)", result, result.BrokenLink)
+	}
+
+	repo.appendEntries(t, 1)
+	checkpoint, err := svc.CreateCheckpoint()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkpoint == nil || checkpoint.LastLogID != 6 || checkpoint.EntryCount != 6 {
+		t.Errorf("unexpected checkpoint after archive: %+v", checkpoint)
+	}
+}
@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
@@ -13,126 +17,527 @@ import (
 
 // AuthService interface untuk business logic authentication
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (*domain.User, string, error)
-	Logout(ctx context.Context, userID int, token string) error
-	ChangePassword(userID int, req requests.ChangePasswordRequest) error
+	Login(ctx context.Context, email, password string) (*domain.User, *AuthTokens, error)
+	LoginExternal(ctx context.Context, user *domain.User) (*domain.User, *AuthTokens, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.User, *AuthTokens, error)
+	Logout(ctx context.Context, accessToken, refreshToken string) error
+	ChangePassword(ctx context.Context, userID int, req requests.ChangePasswordRequest) (*AuthTokens, error)
+	VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*domain.User, *AuthTokens, error)
+	BeginTwoFactorSetup(ctx context.Context, challengeToken string) (*TwoFactorEnrollment, error)
+	ConfirmTwoFactorSetup(ctx context.Context, challengeToken, code string) (*domain.User, *AuthTokens, []string, error)
+}
+
+// AuthTokens berisi pasangan access token dan refresh token hasil login/refresh
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // Masa berlaku access token dalam detik
+}
+
+// TwoFactorChallengeError dikembalikan Login jika password benar tapi masih butuh langkah 2FA
+// Handler memakai errors.As untuk mengambil challenge token-nya
+type TwoFactorChallengeError struct {
+	ChallengeToken string
+	ExpiresIn      int  // Masa berlaku challenge token dalam detik
+	SetupRequired  bool // true jika admin wajib 2FA tapi belum enrollment
+}
+
+func (e *TwoFactorChallengeError) Error() string {
+	return ErrTwoFactorRequired.Error()
+}
+
+func (e *TwoFactorChallengeError) Unwrap() error {
+	return ErrTwoFactorRequired
 }
 
 type authService struct {
 	userRepo        repository.UserRepository
+	sessionRepo     repository.SessionRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	siteSettingRepo repository.SiteSettingRepository
+	lockoutService  AccountLockoutService
 	activityLogRepo repository.ActivityLogRepository
 }
 
 // NewAuthService constructor untuk AuthService
-func NewAuthService(userRepo repository.UserRepository, activityLogRepo repository.ActivityLogRepository) AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, twoFactorRepo repository.TwoFactorRepository, siteSettingRepo repository.SiteSettingRepository, lockoutService AccountLockoutService, activityLogRepo repository.ActivityLogRepository) AuthService {
 	return &authService{
 		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		twoFactorRepo:   twoFactorRepo,
+		siteSettingRepo: siteSettingRepo,
+		lockoutService:  lockoutService,
 		activityLogRepo: activityLogRepo,
 	}
 }
 
 // Login melakukan proses login user
-func (s *authService) Login(ctx context.Context, email, password string) (*domain.User, string, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (*domain.User, *AuthTokens, error) {
 	// 1. Cari user by email
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		user = nil
+	}
+
+	// 2. Tolak jika IP/akun sedang diblokir karena terlalu banyak percobaan gagal
+	// Akun terkunci dijawab sama seperti kredensial salah agar tidak membocorkan email yang terdaftar
+	if err := s.lockoutService.Check(ctx, user); err != nil {
+		var throttled *LoginThrottledError
+		if errors.As(err, &throttled) && throttled.Account {
+			return nil, nil, errors.New("invalid credentials")
+		}
+		return nil, nil, err
 	}
 
-	// 2. Verify password dengan bcrypt
+	if user == nil {
+		s.lockoutService.RecordFailure(ctx, email, nil)
+		return nil, nil, errors.New("invalid credentials")
+	}
+
+	// 3. Verify password dengan bcrypt
 	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return nil, "", errors.New("invalid credentials")
+		s.lockoutService.RecordFailure(ctx, email, user)
+		return nil, nil, errors.New("invalid credentials")
 	}
 
-	// 3. Cek status user aktif
+	// 4. Cek status aktif, 2FA, lalu buat session dan token
+	return s.finishLogin(ctx, user)
+}
+
+// LoginExternal menyelesaikan login untuk user yang sudah diautentikasi pihak luar (SSO/OIDC)
+// Melewati pengecekan password, tapi tetap menerapkan status aktif dan 2FA seperti login biasa
+func (s *authService) LoginExternal(ctx context.Context, user *domain.User) (*domain.User, *AuthTokens, error) {
+	return s.finishLogin(ctx, user)
+}
+
+// finishLogin langkah login setelah identitas user terverifikasi (password atau SSO)
+func (s *authService) finishLogin(ctx context.Context, user *domain.User) (*domain.User, *AuthTokens, error) {
+	// Cek status user aktif
 	if !user.IsActive {
-		return nil, "", errors.New("user account is inactive")
+		return nil, nil, errors.New("user account is inactive")
+	}
+
+	// Login dua langkah jika 2FA aktif, atau admin wajib 2FA tapi belum enrollment
+	twoFactor, err := s.twoFactorRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, nil, ErrTwoFactorFetchFailed
+	}
+
+	switch {
+	case twoFactor != nil && twoFactor.IsEnabled():
+		return nil, nil, s.newTwoFactorChallenge(user, utils.PurposeTwoFactorVerify)
+	case isTwoFactorRequired(user, s.siteSettingRepo):
+		return nil, nil, s.newTwoFactorChallenge(user, utils.PurposeTwoFactorSetup)
+	}
+
+	// Buat session dan token
+	tokens, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// VerifyTwoFactor menyelesaikan login dua langkah dengan kode TOTP atau recovery code
+func (s *authService) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*domain.User, *AuthTokens, error) {
+	claims, user, err := s.resolveChallenge(challengeToken, utils.PurposeTwoFactorVerify)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	twoFactor, err := s.twoFactorRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, nil, ErrTwoFactorFetchFailed
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return nil, nil, ErrInvalidChallengeToken
+	}
+
+	// Kode 2FA yang salah dihitung sebagai login gagal agar tidak bisa di-brute-force
+	if err := s.lockoutService.Check(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	if !verifyTwoFactorCode(s.twoFactorRepo, twoFactor, code) {
+		s.lockoutService.RecordFailure(ctx, user.Email, user)
+		return nil, nil, ErrInvalidTwoFactorCode
+	}
+
+	// Challenge token hanya boleh dipakai sekali
+	_ = utils.RevokeToken(claims.ID, user.ID, claims.ExpiresAt.Time)
+
+	tokens, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// BeginTwoFactorSetup memulai enrollment 2FA untuk admin yang diwajibkan 2FA saat login
+func (s *authService) BeginTwoFactorSetup(ctx context.Context, challengeToken string) (*TwoFactorEnrollment, error) {
+	_, user, err := s.resolveChallenge(challengeToken, utils.PurposeTwoFactorSetup)
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. Generate JWT token (convert role int to string)
-	token, err := utils.GenerateJWT(user.ID, strconv.Itoa(user.Role))
+	return startTwoFactorEnrollment(s.twoFactorRepo, s.siteSettingRepo, user)
+}
+
+// ConfirmTwoFactorSetup mengaktifkan 2FA lalu menyelesaikan login
+// Mengembalikan recovery code plaintext yang hanya ditampilkan sekali
+func (s *authService) ConfirmTwoFactorSetup(ctx context.Context, challengeToken, code string) (*domain.User, *AuthTokens, []string, error) {
+	claims, user, err := s.resolveChallenge(challengeToken, utils.PurposeTwoFactorSetup)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, nil, err
 	}
 
-	// 5. Log activity (synchronous)
-	s.logActivity(ctx, user.ID, domain.ActionLogin, domain.ModuleAuth, "User berhasil login", nil, map[string]any{
-		"user_id":   user.ID,
-		"email":     user.Email,
-		"full_name": user.FullName,
+	recoveryCodes, err := confirmTwoFactorEnrollment(s.twoFactorRepo, user.ID, code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	_ = utils.RevokeToken(claims.ID, user.ID, claims.ExpiresAt.Time)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Mengaktifkan autentikasi dua faktor (2FA)",
 	})
 
-	return user, token, nil
+	tokens, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, tokens, recoveryCodes, nil
 }
 
-// Logout melakukan proses logout user dengan blacklist token
-func (s *authService) Logout(ctx context.Context, userID int, token string) error {
-	// Validate token untuk get expiry time
-	claims, err := utils.ValidateJWT(token)
+// Refresh menukar refresh token dengan pasangan token baru (rotation)
+// Jika refresh token lama dipakai ulang, seluruh family session dicabut
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*domain.User, *AuthTokens, error) {
+	// 1. Cari session berdasarkan hash refresh token
+	session, err := s.sessionRepo.FindByTokenHash(utils.HashToken(refreshToken))
 	if err != nil {
-		return errors.New("invalid token")
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// 2. Session sudah dicabut (logout / reuse sebelumnya)
+	if session.RevokedAt != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// 3. Token sudah pernah di-rotate: indikasi token dicuri, cabut seluruh family
+	if session.RotatedAt != nil {
+		s.revokeFamilyOnReuse(ctx, session)
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	// 4. Token kadaluarsa
+	if !time.Now().Before(session.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// 5. Pastikan user masih ada, aktif, dan session tidak terbit sebelum watermark token user
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil || !user.IsActive || (user.TokensValidAfter != nil && session.CreatedAt.Before(*user.TokensValidAfter)) {
+		_ = s.sessionRepo.RevokeFamily(session.FamilyID)
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	// 6. Rotate: buat refresh token baru di family yang sama
+	next, newRefreshToken, err := s.newSession(ctx, user.ID, session.FamilyID)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
 	}
 
-	// Add token to blacklist dengan expiry time
-	utils.AddToBlacklist(token, claims.ExpiresAt.Time)
+	rotated, err := s.sessionRepo.Rotate(session, next)
+	if err != nil {
+		return nil, nil, errors.New("failed to rotate session")
+	}
+	if !rotated {
+		// Token yang sama sudah ditukar oleh request lain
+		s.revokeFamilyOnReuse(ctx, session)
+		return nil, nil, ErrRefreshTokenReused
+	}
+
+	tokens, err := s.issueTokens(user, session.FamilyID, newRefreshToken)
+	if err != nil {
+		return nil, nil, errors.New("failed to generate token")
+	}
+
+	return user, tokens, nil
+}
+
+// Logout melakukan proses logout user dengan mencabut token dan session-nya
+// Cukup salah satu token: access token yang sudah kadaluarsa tetap bisa logout dengan refresh token
+func (s *authService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	userID := 0
+
+	// Cabut access token (by jti) sampai waktu expiry-nya beserta refresh token session-nya
+	if accessToken != "" {
+		if claims, err := utils.ValidateJWT(accessToken); err == nil && claims.Purpose == "" {
+			if err := utils.RevokeToken(claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+				return errors.New("failed to revoke token")
+			}
+			if claims.SessionID != "" {
+				_ = s.sessionRepo.RevokeFamily(claims.SessionID)
+			}
+			userID = claims.UserID
+		}
+	}
+
+	// Cabut seluruh family dari refresh token
+	if refreshToken != "" {
+		if session, err := s.sessionRepo.FindByTokenHash(utils.HashToken(refreshToken)); err == nil {
+			if err := s.sessionRepo.RevokeFamily(session.FamilyID); err != nil {
+				return errors.New("failed to revoke session")
+			}
+			userID = session.UserID
+		}
+	}
+
+	if userID == 0 {
+		return errors.New("invalid token")
+	}
 
 	// Log activity (synchronous)
-	s.logActivity(ctx, userID, domain.ActionLogout, domain.ModuleAuth, "User berhasil logout", nil, nil)
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      userID,
+		Action:      domain.ActionLogout,
+		Module:      domain.ModuleAuth,
+		Description: "User berhasil logout",
+	})
 
 	return nil
 }
 
-func (s *authService) ChangePassword(userID int, req requests.ChangePasswordRequest) error {
+// ChangePassword mengganti password user yang sedang login
+// Semua token dan session user selalu dicabut, RevokeOtherSessions hanya menentukan apakah perangkat ini diberi session baru
+func (s *authService) ChangePassword(ctx context.Context, userID int, req requests.ChangePasswordRequest) (*AuthTokens, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return errors.New("user tidak ditemukan")
+		return nil, errors.New("user tidak ditemukan")
 	}
 
 	if !user.IsActive {
-		return errors.New("user tidak aktif")
+		return nil, errors.New("user tidak aktif")
 	}
 
 	if !utils.CheckPasswordHash(req.OldPassword, user.PasswordHash) {
-		return errors.New("password lama salah")
+		return nil, errors.New("password lama salah")
 	}
 
 	if req.OldPassword == req.NewPassword {
-		return errors.New("password baru tidak boleh sama dengan password lama")
+		return nil, errors.New("password baru tidak boleh sama dengan password lama")
 	}
 
 	newHash, err := utils.HashPassword(req.NewPassword)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	user.PasswordHash = newHash
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	// Password berubah: semua token dan session lama tidak berlaku lagi, termasuk session saat ini
+	revokeAllUserTokens(userID)
+	if err := s.sessionRepo.RevokeAllForUser(userID); err != nil {
+		return nil, ErrSessionRevokeFailed
+	}
+
+	// Opsional: perangkat ini tetap login dengan session baru, perangkat lain harus login ulang
+	if !req.RevokeOtherSessions {
+		return nil, nil
+	}
+
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	session, refreshToken, err := s.newSession(ctx, user.ID, familyID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	return s.issueTokens(user, familyID, refreshToken)
 }
 
-// logActivity helper untuk mencatat activity log
-func (s *authService) logActivity(ctx context.Context, userID int, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any) {
+// completeLogin membuat session baru (family baru), menerbitkan token dan mencatat activity log
+func (s *authService) completeLogin(ctx context.Context, user *domain.User) (*AuthTokens, error) {
+	familyID, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	session, refreshToken, err := s.newSession(ctx, user.ID, familyID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, errors.New("failed to create session")
+	}
+
+	// Generate access token (convert role int to string)
+	tokens, err := s.issueTokens(user, familyID, refreshToken)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	// Reset counter gagal login
+	s.lockoutService.RecordSuccess(ctx, user)
+
+	// Log activity (synchronous)
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionLogin,
+		Module:      domain.ModuleAuth,
+		Description: "User berhasil login",
+		NewValue: map[string]any{
+			"user_id":   user.ID,
+			"email":     user.Email,
+			"full_name": user.FullName,
+		},
+	})
+
+	return tokens, nil
+}
+
+// newTwoFactorChallenge membuat challenge token untuk langkah kedua login
+func (s *authService) newTwoFactorChallenge(user *domain.User, purpose string) error {
+	token, err := utils.GenerateChallengeToken(user.ID, strconv.Itoa(user.Role), purpose)
+	if err != nil {
+		return errors.New("failed to generate token")
+	}
+
+	return &TwoFactorChallengeError{
+		ChallengeToken: token,
+		ExpiresIn:      int(utils.ChallengeTokenTTL().Seconds()),
+		SetupRequired:  purpose == utils.PurposeTwoFactorSetup,
+	}
+}
+
+// resolveChallenge memvalidasi challenge token dan memastikan user-nya masih aktif
+func (s *authService) resolveChallenge(challengeToken, purpose string) (*utils.Claims, *domain.User, error) {
+	claims, err := utils.ValidateChallengeToken(challengeToken, purpose)
+	if err != nil {
+		return nil, nil, ErrInvalidChallengeToken
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil || !user.IsActive {
+		return nil, nil, ErrInvalidChallengeToken
+	}
+
+	return claims, user, nil
+}
+
+// newSession menyiapkan row session baru beserta refresh token plaintext-nya
+func (s *authService) newSession(ctx context.Context, userID int, familyID string) (*domain.UserSession, string, error) {
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
 	ipAddress := utils.GetIPAddress(ctx)
 	userAgent := utils.GetUserAgent(ctx)
+	device := detectDevice(userAgent)
+	now := time.Now()
 
-	var ipPtr, uaPtr *string
+	// Row baru dibuat saat login atau refresh, keduanya dihitung sebagai pemakaian terakhir session
+	session := &domain.UserSession{
+		UserID:           userID,
+		FamilyID:         familyID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		Device:           &device,
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL()),
+		LastUsedAt:       &now,
+	}
 	if ipAddress != "" {
-		ipPtr = &ipAddress
+		session.IPAddress = &ipAddress
 	}
 	if userAgent != "" {
-		uaPtr = &userAgent
+		session.UserAgent = &userAgent
 	}
 
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
+	return session, refreshToken, nil
+}
+
+// issueTokens membuat access token untuk session dan membungkusnya bersama refresh token
+func (s *authService) issueTokens(user *domain.User, familyID, refreshToken string) (*AuthTokens, error) {
+	accessToken, err := utils.GenerateJWT(user.ID, strconv.Itoa(user.Role), familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// revokeFamilyOnReuse mencabut seluruh family session dan mencatatnya di activity log
+func (s *authService) revokeFamilyOnReuse(ctx context.Context, session *domain.UserSession) {
+	_ = s.sessionRepo.RevokeFamily(session.FamilyID)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      session.UserID,
+		Action:      domain.ActionLogout,
+		Module:      domain.ModuleAuth,
+		Description: "Refresh token dipakai ulang, seluruh sesi pada perangkat dicabut",
+		NewValue: map[string]any{
+			"session_id": session.ID,
+			"family_id":  session.FamilyID,
+		},
+	})
+}
+
+// detectDevice menebak jenis perangkat dari User-Agent untuk ditampilkan di daftar session
+func detectDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case ua == "":
+		return "Unknown"
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"):
+		return "iOS"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "mac os"), strings.Contains(ua, "macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Unknown"
 	}
+}
 
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
+// revokeAllUserTokens mengaktifkan watermark token user
+// Kegagalan tidak membatalkan operasi utama, tapi dicatat agar bisa ditindaklanjuti
+func revokeAllUserTokens(userID int) {
+	if err := utils.RevokeAllUserTokens(userID); err != nil {
+		fmt.Printf("[WARN] revokeAllUserTokens: failed to revoke tokens for user %d: %v\n", userID, err)
+	}
 }
+
+// Auth service errors
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token tidak valid atau kadaluarsa")
+	ErrRefreshTokenReused  = errors.New("refresh token sudah pernah digunakan, silakan login kembali")
+
+	ErrTwoFactorRequired     = errors.New("verifikasi 2FA diperlukan")
+	ErrInvalidChallengeToken = errors.New("sesi verifikasi 2FA tidak valid atau kadaluarsa, silakan login kembali")
+)
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
 // MockUserRepository adalah mock untuk UserRepository
@@ -46,12 +48,16 @@ func (m *MockUserRepository) FindAll(page, limit int) ([]domain.User, int64, err
 	return nil, 0, nil
 }
 
+func (m *MockUserRepository) WithContext(ctx context.Context) repository.UserRepository {
+	return m
+}
+
 // MockActivityLogRepoForAuth adalah mock untuk ActivityLogRepository
 type MockActivityLogRepoForAuth struct {
 	CreateFunc func(log *domain.ActivityLog) error
 }
 
-func (m *MockActivityLogRepoForAuth) Create(log *domain.ActivityLog) error {
+func (m *MockActivityLogRepoForAuth) Create(ctx context.Context, log *domain.ActivityLog) error {
 	if m.CreateFunc != nil {
 		return m.CreateFunc(log)
 	}
@@ -62,6 +68,62 @@ func (m *MockActivityLogRepoForAuth) GetActivityLogs(offset, limit int, filter r
 	return nil, 0, nil
 }
 
+func (m *MockActivityLogRepoForAuth) StreamActivityLogs(filter repository.ActivityLogFilter, batchSize int, fn func(logs []responses.ActivityLogResponse) error) error {
+	return nil
+}
+
+// MockSessionRepository adalah mock untuk SessionRepository
+type MockSessionRepository struct {
+	CreateFunc           func(session *domain.UserSession) error
+	FindByTokenHashFunc  func(hash string) (*domain.UserSession, error)
+	RotateFunc           func(current *domain.UserSession, next *domain.UserSession) (bool, error)
+	RevokeFamilyFunc     func(familyID string) error
+	RevokeAllFunc        func(userID int) error
+	FindActiveByUserFunc func(userID int) ([]domain.UserSession, error)
+}
+
+func (m *MockSessionRepository) Create(session *domain.UserSession) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(session)
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) FindByTokenHash(hash string) (*domain.UserSession, error) {
+	if m.FindByTokenHashFunc != nil {
+		return m.FindByTokenHashFunc(hash)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *MockSessionRepository) Rotate(current *domain.UserSession, next *domain.UserSession) (bool, error) {
+	if m.RotateFunc != nil {
+		return m.RotateFunc(current, next)
+	}
+	return true, nil
+}
+
+func (m *MockSessionRepository) RevokeFamily(familyID string) error {
+	if m.RevokeFamilyFunc != nil {
+		return m.RevokeFamilyFunc(familyID)
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(userID int) error {
+	if m.RevokeAllFunc != nil {
+		return m.RevokeAllFunc(userID)
+	}
+	return nil
+}
+
+func (m *MockSessionRepository) FindActiveByUser(userID int) ([]domain.UserSession, error) {
+	if m.FindActiveByUserFunc != nil {
+		return m.FindActiveByUserFunc(userID)
+	}
+	return nil, nil
+}
+
 // TestLogin_UserNotFound menguji login dengan email yang tidak terdaftar
 func TestLogin_UserNotFound(t *testing.T) {
 	mockRepo := &MockUserRepository{
@@ -70,8 +132,8 @@ func TestLogin_UserNotFound(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
-	user, token, err := authService.Login(context.Background(), "notfound@example.com", "password123")
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	user, tokens, err := authService.Login(context.Background(), "notfound@example.com", "password123")
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -79,8 +141,8 @@ func TestLogin_UserNotFound(t *testing.T) {
 	if user != nil {
 		t.Errorf("Expected nil user, got %v", user)
 	}
-	if token != "" {
-		t.Errorf("Expected empty token, got %s", token)
+	if tokens != nil {
+		t.Errorf("Expected nil tokens, got %v", tokens)
 	}
 }
 
@@ -101,8 +163,8 @@ func TestLogin_WrongPassword(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
-	user, token, err := authService.Login(context.Background(), "test@example.com", "wrongpassword")
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	user, tokens, err := authService.Login(context.Background(), "test@example.com", "wrongpassword")
 
 	if err == nil {
 		t.Error("Expected error for wrong password, got nil")
@@ -110,8 +172,8 @@ func TestLogin_WrongPassword(t *testing.T) {
 	if user != nil {
 		t.Errorf("Expected nil user, got %v", user)
 	}
-	if token != "" {
-		t.Errorf("Expected empty token, got %s", token)
+	if tokens != nil {
+		t.Errorf("Expected nil tokens, got %v", tokens)
 	}
 }
 
@@ -131,8 +193,8 @@ func TestLogin_InactiveUser(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
-	user, token, err := authService.Login(context.Background(), "test@example.com", "admin123")
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	user, tokens, err := authService.Login(context.Background(), "test@example.com", "admin123")
 
 	if err == nil {
 		t.Error("Expected error for inactive user, got nil")
@@ -140,8 +202,8 @@ func TestLogin_InactiveUser(t *testing.T) {
 	if user != nil {
 		t.Errorf("Expected nil user, got %v", user)
 	}
-	if token != "" {
-		t.Errorf("Expected empty token, got %s", token)
+	if tokens != nil {
+		t.Errorf("Expected nil tokens, got %v", tokens)
 	}
 }
 
@@ -153,13 +215,13 @@ func TestChangePassword_UserNotFound(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
 	req := requests.ChangePasswordRequest{
 		OldPassword: "oldpass123!",
 		NewPassword: "newpass123!",
 	}
 
-	err := authService.ChangePassword(999, req)
+	_, err := authService.ChangePassword(context.Background(), 999, req)
 
 	if err == nil {
 		t.Error("Expected error, got nil")
@@ -185,13 +247,13 @@ func TestChangePassword_InactiveUser(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
 	req := requests.ChangePasswordRequest{
 		OldPassword: "admin123",
 		NewPassword: "newpass123!",
 	}
 
-	err := authService.ChangePassword(1, req)
+	_, err := authService.ChangePassword(context.Background(), 1, req)
 
 	if err == nil {
 		t.Error("Expected error for inactive user, got nil")
@@ -217,13 +279,13 @@ func TestChangePassword_WrongOldPassword(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
 	req := requests.ChangePasswordRequest{
 		OldPassword: "wrongpassword",
 		NewPassword: "newpass123!",
 	}
 
-	err := authService.ChangePassword(1, req)
+	_, err := authService.ChangePassword(context.Background(), 1, req)
 
 	if err == nil {
 		t.Error("Expected error for wrong old password, got nil")
@@ -249,13 +311,13 @@ func TestChangePassword_SamePassword(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
 	req := requests.ChangePasswordRequest{
 		OldPassword: "admin123",
 		NewPassword: "admin123",
 	}
 
-	err := authService.ChangePassword(1, req)
+	_, err := authService.ChangePassword(context.Background(), 1, req)
 
 	if err == nil {
 		t.Error("Expected error for same password, got nil")
@@ -289,13 +351,13 @@ func TestChangePassword_Success(t *testing.T) {
 		},
 	}
 
-	authService := NewAuthService(mockRepo, &MockActivityLogRepoForAuth{})
+	authService := NewAuthService(mockRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
 	req := requests.ChangePasswordRequest{
 		OldPassword: "admin123",
 		NewPassword: "newpass123!",
 	}
 
-	err := authService.ChangePassword(1, req)
+	_, err := authService.ChangePassword(context.Background(), 1, req)
 
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -304,3 +366,292 @@ func TestChangePassword_Success(t *testing.T) {
 		t.Error("Expected Update to be called")
 	}
 }
+
+// TestLogin_Success menguji login berhasil membuat session dan mengembalikan kedua token
+func TestLogin_Success(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	hashedPassword := "$2a$10$CaI1bA6w2H0LKVGF./iweOteBj/rAkkpx3QUO/dDK5.dRP6BDeB8a"
+
+	mockRepo := &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return &domain.User{ID: 1, Email: email, PasswordHash: hashedPassword, Role: 1, IsActive: true}, nil
+		},
+	}
+
+	var created *domain.UserSession
+	sessionRepo := &MockSessionRepository{
+		CreateFunc: func(session *domain.UserSession) error {
+			created = session
+			return nil
+		},
+	}
+
+	authService := NewAuthService(mockRepo, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, tokens, err := authService.Login(context.Background(), "test@example.com", "admin123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Error("Expected access token and refresh token to be returned")
+	}
+	if created == nil {
+		t.Fatal("Expected session to be created")
+	}
+	if created.RefreshTokenHash != utils.HashToken(tokens.RefreshToken) {
+		t.Error("Expected refresh token to be stored hashed")
+	}
+
+	claims, err := utils.ValidateJWT(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected valid access token, got %v", err)
+	}
+	if claims.SessionID != created.FamilyID {
+		t.Errorf("Expected sid %s, got %s", created.FamilyID, claims.SessionID)
+	}
+}
+
+// TestRefresh_InvalidToken menguji refresh dengan token yang tidak terdaftar
+func TestRefresh_InvalidToken(t *testing.T) {
+	sessionRepo := &MockSessionRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.UserSession, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+
+	authService := NewAuthService(&MockUserRepository{}, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, tokens, err := authService.Refresh(context.Background(), "unknown")
+
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+	}
+	if tokens != nil {
+		t.Errorf("Expected nil tokens, got %v", tokens)
+	}
+}
+
+// TestRefresh_ReuseRevokesFamily menguji reuse refresh token lama mencabut seluruh family
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	rotatedAt := time.Now().Add(-time.Minute)
+	revokedFamily := ""
+
+	sessionRepo := &MockSessionRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.UserSession, error) {
+			return &domain.UserSession{
+				ID:        10,
+				UserID:    1,
+				FamilyID:  "family-1",
+				ExpiresAt: time.Now().Add(time.Hour),
+				RotatedAt: &rotatedAt,
+			}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			revokedFamily = familyID
+			return nil
+		},
+	}
+
+	authService := NewAuthService(&MockUserRepository{}, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, _, err := authService.Refresh(context.Background(), "old-token")
+
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+	if revokedFamily != "family-1" {
+		t.Errorf("Expected family-1 to be revoked, got %q", revokedFamily)
+	}
+}
+
+// TestRefresh_Success menguji rotation refresh token berhasil
+func TestRefresh_Success(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+
+	current := &domain.UserSession{
+		ID:               10,
+		UserID:           1,
+		FamilyID:         "family-1",
+		RefreshTokenHash: utils.HashToken("current-token"),
+		ExpiresAt:        time.Now().Add(time.Hour),
+	}
+
+	var next *domain.UserSession
+	sessionRepo := &MockSessionRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.UserSession, error) {
+			return current, nil
+		},
+		RotateFunc: func(c *domain.UserSession, n *domain.UserSession) (bool, error) {
+			next = n
+			return true, nil
+		},
+	}
+	mockRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, Role: 2, IsActive: true}, nil
+		},
+	}
+
+	authService := NewAuthService(mockRepo, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, tokens, err := authService.Refresh(context.Background(), "current-token")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if next == nil {
+		t.Fatal("Expected Rotate to be called")
+	}
+	if next.FamilyID != current.FamilyID {
+		t.Errorf("Expected rotated session in same family, got %s", next.FamilyID)
+	}
+	if tokens.RefreshToken == "current-token" || next.RefreshTokenHash != utils.HashToken(tokens.RefreshToken) {
+		t.Error("Expected a new refresh token to be issued and stored hashed")
+	}
+	if next.LastUsedAt == nil || time.Since(*next.LastUsedAt) > time.Minute {
+		t.Errorf("Expected rotated session to record the refresh as last use, got %v", next.LastUsedAt)
+	}
+}
+
+// TestChangePassword_RevokesSessions menguji ganti password mencabut semua session user
+func TestChangePassword_RevokesSessions(t *testing.T) {
+	hashedPassword := "$2a$10$CaI1bA6w2H0LKVGF./iweOteBj/rAkkpx3QUO/dDK5.dRP6BDeB8a"
+	revokedUserID := 0
+
+	mockRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, PasswordHash: hashedPassword, Role: 2, IsActive: true}, nil
+		},
+	}
+	sessionRepo := &MockSessionRepository{
+		RevokeAllFunc: func(userID int) error {
+			revokedUserID = userID
+			return nil
+		},
+	}
+
+	authService := NewAuthService(mockRepo, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	tokens, err := authService.ChangePassword(context.Background(), 7, requests.ChangePasswordRequest{
+		OldPassword: "admin123",
+		NewPassword: "newpass123!",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revokedUserID != 7 {
+		t.Errorf("Expected sessions of user 7 to be revoked, got %d", revokedUserID)
+	}
+	if tokens != nil {
+		t.Error("Expected no new session without revoke_other_sessions")
+	}
+}
+
+// TestChangePassword_RevokeOtherSessions menguji semua session tetap dicabut, perangkat ini mendapat session baru
+func TestChangePassword_RevokeOtherSessions(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+
+	hashedPassword := "$2a$10$CaI1bA6w2H0LKVGF./iweOteBj/rAkkpx3QUO/dDK5.dRP6BDeB8a"
+	revokedUserID := 0
+	var created *domain.UserSession
+
+	mockRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, PasswordHash: hashedPassword, Role: 2, IsActive: true}, nil
+		},
+	}
+	sessionRepo := &MockSessionRepository{
+		RevokeAllFunc: func(userID int) error {
+			revokedUserID = userID
+			return nil
+		},
+		CreateFunc: func(session *domain.UserSession) error {
+			created = session
+			return nil
+		},
+	}
+
+	ctx := utils.WithSessionID(context.Background(), "current")
+	authService := NewAuthService(mockRepo, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	tokens, err := authService.ChangePassword(ctx, 7, requests.ChangePasswordRequest{
+		OldPassword:         "admin123",
+		NewPassword:         "newpass123!",
+		RevokeOtherSessions: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revokedUserID != 7 {
+		t.Errorf("Expected all sessions of user 7 to be revoked, got %d", revokedUserID)
+	}
+	if tokens == nil || created == nil {
+		t.Fatal("Expected a new session for the current device")
+	}
+	if created.FamilyID == "current" || created.RefreshTokenHash != utils.HashToken(tokens.RefreshToken) {
+		t.Error("Expected a fresh session family with the new refresh token stored hashed")
+	}
+}
+
+// TestRefresh_SessionBeforeWatermark menguji session yang terbit sebelum watermark user ditolak
+func TestRefresh_SessionBeforeWatermark(t *testing.T) {
+	watermark := time.Now()
+	revokedFamily := ""
+
+	sessionRepo := &MockSessionRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.UserSession, error) {
+			return &domain.UserSession{
+				ID:        10,
+				UserID:    1,
+				FamilyID:  "family-1",
+				ExpiresAt: time.Now().Add(time.Hour),
+				CreatedAt: watermark.Add(-time.Hour),
+			}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			revokedFamily = familyID
+			return nil
+		},
+	}
+	mockRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, Role: 2, IsActive: true, TokensValidAfter: &watermark}, nil
+		},
+	}
+
+	authService := NewAuthService(mockRepo, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, _, err := authService.Refresh(context.Background(), "old-session-token")
+
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+	}
+	if revokedFamily != "family-1" {
+		t.Errorf("Expected family-1 to be revoked, got %q", revokedFamily)
+	}
+}
+
+// TestLogout_RefreshTokenOnly menguji logout tetap mencabut session walaupun access token sudah tidak berlaku
+func TestLogout_RefreshTokenOnly(t *testing.T) {
+	var revokedFamily string
+	sessionRepo := &MockSessionRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.UserSession, error) {
+			if hash != utils.HashToken("refresh-token") {
+				return nil, errors.New("not found")
+			}
+			return &domain.UserSession{UserID: 7, FamilyID: "family-7"}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			revokedFamily = familyID
+			return nil
+		},
+	}
+	authService := NewAuthService(&MockUserRepository{}, sessionRepo, &MockTwoFactorRepository{}, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+
+	if err := authService.Logout(context.Background(), "expired-access-token", "refresh-token"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revokedFamily != "family-7" {
+		t.Errorf("Expected session family to be revoked, got %q", revokedFamily)
+	}
+
+	if err := authService.Logout(context.Background(), "expired-access-token", "unknown"); err == nil {
+		t.Error("Expected error when neither token is valid")
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
-	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
 type CategoryService interface {
@@ -19,14 +18,12 @@ type CategoryService interface {
 }
 
 type categoryService struct {
-	repo            repository.CategoryRepository
-	activityLogRepo repository.ActivityLogRepository
+	repo repository.CategoryRepository
 }
 
-func NewCategoryService(repo repository.CategoryRepository, activityLogRepo repository.ActivityLogRepository) CategoryService {
+func NewCategoryService(repo repository.CategoryRepository) CategoryService {
 	return &categoryService{
-		repo:            repo,
-		activityLogRepo: activityLogRepo,
+		repo: repo,
 	}
 }
 
@@ -57,17 +54,10 @@ func (s *categoryService) Create(ctx context.Context, req requests.CategoryReque
 		Description: descPtr,
 	}
 
-	if err := s.repo.Create(&category); err != nil {
+	if err := s.repo.WithContext(ctx).Create(&category); err != nil {
 		return responses.CategoryResponse{}, err
 	}
 
-	// Log activity - Create Category
-	s.logActivity(ctx, domain.ActionCreate, domain.ModuleCategory, "Membuat kategori baru: "+category.Name, nil, map[string]any{
-		"id":   category.ID,
-		"name": category.Name,
-		"slug": category.Slug,
-	}, &category.ID)
-
 	return responses.FromDomainToCategoryResponse(category), nil
 }
 
@@ -77,29 +67,16 @@ func (s *categoryService) Update(ctx context.Context, id string, req requests.Ca
 		return responses.CategoryResponse{}, err
 	}
 
-	// Store old values for audit
-	oldValues := map[string]any{
-		"name": category.Name,
-		"slug": category.Slug,
-	}
-
 	category.Name = req.Name
 	category.Slug = req.GetSlug()
 	if req.Description != "" {
 		category.Description = &req.Description
 	}
 
-	if err := s.repo.Update(&category); err != nil {
+	if err := s.repo.WithContext(ctx).Update(&category); err != nil {
 		return responses.CategoryResponse{}, err
 	}
 
-	// Log activity - Update Category
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleCategory, "Mengupdate kategori: "+category.Name, oldValues, map[string]any{
-		"id":   category.ID,
-		"name": category.Name,
-		"slug": category.Slug,
-	}, &category.ID)
-
 	return responses.FromDomainToCategoryResponse(category), nil
 }
 
@@ -109,46 +86,5 @@ func (s *categoryService) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleCategory, "Menghapus kategori: "+category.Name, map[string]any{
-		"id":   category.ID,
-		"name": category.Name,
-		"slug": category.Slug,
-	}, nil, &category.ID)
-
-	return s.repo.Delete(&category)
-}
-
-// logActivity helper untuk mencatat activity log
-func (s *categoryService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
+	return s.repo.WithContext(ctx).Delete(&category)
 }
@@ -61,7 +61,7 @@ func (s *contactService) Update(ctx context.Context, req requests.UpdateContactR
 	}
 
 	// Save to database
-	if err := s.contactRepo.Update(contact); err != nil {
+	if err := s.contactRepo.WithContext(ctx).Update(contact); err != nil {
 		return nil, errors.New("gagal menyimpan informasi kontak")
 	}
 
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// Batas laporan dari satu request dan panjang field, laporan dikirim browser tanpa autentikasi
+const (
+	maxCSPReportsPerRequest = 20
+	maxCSPReportFieldLength = 2048
+)
+
+// CSPReportService interface untuk menyimpan dan menampilkan laporan pelanggaran CSP
+type CSPReportService interface {
+	Record(ctx context.Context, body []byte) (int, error)
+	GetAll(ctx context.Context, page, limit int, directive string) ([]domain.CSPReport, int, int64, error)
+	Purge() (int64, error)
+}
+
+type cspReportService struct {
+	cspReportRepo repository.CSPReportRepository
+	retention     time.Duration
+	maxStored     int
+}
+
+// NewCSPReportService constructor untuk CSPReportService
+// Laporan lebih lama dari retentionDays dihapus oleh Purge, dan tabel tidak pernah melebihi maxStored laporan
+func NewCSPReportService(cspReportRepo repository.CSPReportRepository, retentionDays, maxStored int) CSPReportService {
+	return &cspReportService{
+		cspReportRepo: cspReportRepo,
+		retention:     time.Duration(retentionDays) * 24 * time.Hour,
+		maxStored:     maxStored,
+	}
+}
+
+// legacyCSPReport format report-uri (Content-Type: application/csp-report)
+type legacyCSPReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		OriginalPolicy     string `json:"original-policy"`
+		Disposition        string `json:"disposition"`
+		BlockedURI         string `json:"blocked-uri"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         *int   `json:"line-number"`
+		ColumnNumber       *int   `json:"column-number"`
+		StatusCode         *int   `json:"status-code"`
+		ScriptSample       string `json:"script-sample"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport format Reporting API (Content-Type: application/reports+json), dikirim dalam array
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		Referrer           string `json:"referrer"`
+		BlockedURL         string `json:"blockedURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		OriginalPolicy     string `json:"originalPolicy"`
+		SourceFile         string `json:"sourceFile"`
+		Sample             string `json:"sample"`
+		Disposition        string `json:"disposition"`
+		StatusCode         *int   `json:"statusCode"`
+		LineNumber         *int   `json:"lineNumber"`
+		ColumnNumber       *int   `json:"columnNumber"`
+	} `json:"body"`
+}
+
+// Record mem-parsing body laporan (format report-uri atau Reporting API) lalu menyimpannya
+// Return jumlah laporan yang disimpan
+func (s *cspReportService) Record(ctx context.Context, body []byte) (int, error) {
+	reports, err := parseCSPReports(body)
+	if err != nil {
+		return 0, err
+	}
+
+	ipAddress := utils.GetIPAddress(ctx)
+	userAgent := truncateCSPField(utils.GetUserAgent(ctx))
+	for i := range reports {
+		if ipAddress != "" {
+			reports[i].IPAddress = &ipAddress
+		}
+		if userAgent != "" {
+			reports[i].UserAgent = &userAgent
+		}
+	}
+
+	// Endpoint tanpa autentikasi: saat kuota penuh laporan dibuang, bukan ditolak (browser tidak mengirim ulang)
+	stored, err := s.cspReportRepo.Count()
+	if err != nil {
+		return 0, ErrCSPReportSaveFailed
+	}
+	if remaining := int64(s.maxStored) - stored; remaining < int64(len(reports)) {
+		if remaining <= 0 {
+			return 0, nil
+		}
+		reports = reports[:remaining]
+	}
+
+	if err := s.cspReportRepo.CreateBatch(reports); err != nil {
+		return 0, ErrCSPReportSaveFailed
+	}
+	return len(reports), nil
+}
+
+// Purge menghapus laporan yang melewati masa retensi lalu memangkas tabel ke maxStored laporan terbaru
+func (s *cspReportService) Purge() (int64, error) {
+	expired, err := s.cspReportRepo.DeleteBefore(time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+	trimmed, err := s.cspReportRepo.DeleteExceptNewest(s.maxStored)
+	if err != nil {
+		return expired, err
+	}
+	return expired + trimmed, nil
+}
+
+// GetAll mengambil laporan CSP dengan pagination
+func (s *cspReportService) GetAll(ctx context.Context, page, limit int, directive string) ([]domain.CSPReport, int, int64, error) {
+	reports, total, err := s.cspReportRepo.FindAll((page-1)*limit, limit, directive)
+	if err != nil {
+		return nil, 0, 0, ErrCSPReportFetchFailed
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return reports, lastPage, total, nil
+}
+
+// parseCSPReports mengenali format laporan dari bentuk JSON-nya (object = report-uri, array = Reporting API)
+func parseCSPReports(body []byte) ([]domain.CSPReport, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, ErrInvalidCSPReport
+	}
+
+	var reports []domain.CSPReport
+	if body[0] == '[' {
+		var entries []reportingAPIReport
+		if err := json.Unmarshal(body, &entries); err != nil {
+			return nil, ErrInvalidCSPReport
+		}
+		for _, entry := range entries {
+			if entry.Type != "csp-violation" {
+				continue
+			}
+			reports = append(reports, domain.CSPReport{
+				DocumentURI:        entry.Body.DocumentURL,
+				Referrer:           entry.Body.Referrer,
+				ViolatedDirective:  entry.Body.EffectiveDirective,
+				EffectiveDirective: entry.Body.EffectiveDirective,
+				BlockedURI:         entry.Body.BlockedURL,
+				SourceFile:         entry.Body.SourceFile,
+				LineNumber:         entry.Body.LineNumber,
+				ColumnNumber:       entry.Body.ColumnNumber,
+				StatusCode:         entry.Body.StatusCode,
+				Sample:             entry.Body.Sample,
+				Disposition:        entry.Body.Disposition,
+				OriginalPolicy:     entry.Body.OriginalPolicy,
+			})
+		}
+	} else {
+		var legacy legacyCSPReport
+		if err := json.Unmarshal(body, &legacy); err != nil {
+			return nil, ErrInvalidCSPReport
+		}
+		report := legacy.Report
+		effectiveDirective := report.EffectiveDirective
+		if effectiveDirective == "" {
+			// Browser lama hanya mengirim violated-directive (mis. "script-src 'self'")
+			effectiveDirective, _, _ = strings.Cut(report.ViolatedDirective, " ")
+		}
+		reports = append(reports, domain.CSPReport{
+			DocumentURI:        report.DocumentURI,
+			Referrer:           report.Referrer,
+			ViolatedDirective:  report.ViolatedDirective,
+			EffectiveDirective: effectiveDirective,
+			BlockedURI:         report.BlockedURI,
+			SourceFile:         report.SourceFile,
+			LineNumber:         report.LineNumber,
+			ColumnNumber:       report.ColumnNumber,
+			StatusCode:         report.StatusCode,
+			Sample:             report.ScriptSample,
+			Disposition:        report.Disposition,
+			OriginalPolicy:     report.OriginalPolicy,
+		})
+	}
+
+	if len(reports) == 0 || len(reports) > maxCSPReportsPerRequest {
+		return nil, ErrInvalidCSPReport
+	}
+
+	for i := range reports {
+		if reports[i].DocumentURI == "" || reports[i].EffectiveDirective == "" {
+			return nil, ErrInvalidCSPReport
+		}
+		truncateCSPReport(&reports[i])
+	}
+
+	return reports, nil
+}
+
+// truncateCSPReport memotong field teks agar laporan palsu tidak membengkakkan tabel
+func truncateCSPReport(report *domain.CSPReport) {
+	for _, field := range []*string{
+		&report.DocumentURI, &report.Referrer, &report.ViolatedDirective, &report.EffectiveDirective,
+		&report.BlockedURI, &report.SourceFile, &report.Sample, &report.Disposition, &report.OriginalPolicy,
+	} {
+		*field = truncateCSPField(*field)
+	}
+
+	// Kolom varchar di database
+	if len(report.ViolatedDirective) > 255 {
+		report.ViolatedDirective = truncateUTF8(report.ViolatedDirective, 255)
+	}
+	if len(report.EffectiveDirective) > 255 {
+		report.EffectiveDirective = truncateUTF8(report.EffectiveDirective, 255)
+	}
+	if len(report.Disposition) > 20 {
+		report.Disposition = truncateUTF8(report.Disposition, 20)
+	}
+}
+
+func truncateCSPField(value string) string {
+	if len(value) <= maxCSPReportFieldLength {
+		return value
+	}
+	return truncateUTF8(value, maxCSPReportFieldLength)
+}
+
+// truncateUTF8 memotong string maksimal n byte tanpa memotong karakter multi-byte
+func truncateUTF8(value string, n int) string {
+	if len(value) <= n {
+		return value
+	}
+	for n > 0 && !utf8.RuneStart(value[n]) {
+		n--
+	}
+	return value[:n]
+}
+
+// CSP report service errors
+var (
+	ErrInvalidCSPReport     = errors.New("laporan CSP tidak valid")
+	ErrCSPReportSaveFailed  = errors.New("gagal menyimpan laporan CSP")
+	ErrCSPReportFetchFailed = errors.New("gagal mengambil laporan CSP")
+)
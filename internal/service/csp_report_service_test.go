@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// MockCSPReportRepository menyimpan laporan CSP di memory
+type MockCSPReportRepository struct {
+	reports []domain.CSPReport
+	err     error
+}
+
+func (m *MockCSPReportRepository) CreateBatch(reports []domain.CSPReport) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.reports = append(m.reports, reports...)
+	return nil
+}
+
+func (m *MockCSPReportRepository) FindAll(offset, limit int, directive string) ([]domain.CSPReport, int64, error) {
+	return m.reports, int64(len(m.reports)), m.err
+}
+
+func (m *MockCSPReportRepository) Count() (int64, error) {
+	return int64(len(m.reports)), m.err
+}
+
+func (m *MockCSPReportRepository) DeleteBefore(before time.Time) (int64, error) {
+	kept := m.reports[:0]
+	for _, report := range m.reports {
+		if !report.CreatedAt.Before(before) {
+			kept = append(kept, report)
+		}
+	}
+	deleted := int64(len(m.reports) - len(kept))
+	m.reports = kept
+	return deleted, nil
+}
+
+func (m *MockCSPReportRepository) DeleteExceptNewest(keep int) (int64, error) {
+	if len(m.reports) <= keep {
+		return 0, nil
+	}
+	deleted := int64(len(m.reports) - keep)
+	m.reports = m.reports[len(m.reports)-keep:]
+	return deleted, nil
+}
+
+func TestCSPReportRecord_LegacyFormat(t *testing.T) {
+	repo := &MockCSPReportRepository{}
+	svc := NewCSPReportService(repo, 30, 100)
+	ctx := utils.WithRequestInfo(context.Background(), "10.0.0.1", "Mozilla/5.0")
+
+	body := `{"csp-report":{"document-uri":"https://pmii.id/admin","violated-directive":"script-src 'self'","blocked-uri":"https://evil.example/x.js","line-number":12,"disposition":"report"}}`
+	count, err := svc.Record(ctx, []byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 || len(repo.reports) != 1 {
+		t.Fatalf("expected 1 report saved, got count=%d saved=%d", count, len(repo.reports))
+	}
+
+	report := repo.reports[0]
+	if report.EffectiveDirective != "script-src" {
+		t.Errorf("expected effective directive derived from violated-directive, got %q", report.EffectiveDirective)
+	}
+	if report.LineNumber == nil || *report.LineNumber != 12 {
+		t.Errorf("expected line number 12, got %v", report.LineNumber)
+	}
+	if report.IPAddress == nil || *report.IPAddress != "10.0.0.1" {
+		t.Errorf("expected IP address from context, got %v", report.IPAddress)
+	}
+}
+
+func TestCSPReportRecord_ReportingAPIFormat(t *testing.T) {
+	repo := &MockCSPReportRepository{}
+	svc := NewCSPReportService(repo, 30, 100)
+
+	body := `[
+		{"type":"csp-violation","body":{"documentURL":"https://pmii.id/","effectiveDirective":"img-src","blockedURL":"https://cdn.example/a.png","disposition":"enforce"}},
+		{"type":"deprecation","body":{"id":"x"}},
+		{"type":"csp-violation","body":{"documentURL":"https://pmii.id/berita","effectiveDirective":"style-src-elem","blockedURL":"inline","sample":"body{}"}}
+	]`
+	count, err := svc.Record(context.Background(), []byte(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 csp-violation reports saved, got %d", count)
+	}
+	if repo.reports[1].ViolatedDirective != "style-src-elem" || repo.reports[1].Sample != "body{}" {
+		t.Errorf("unexpected report mapping: %+v", repo.reports[1])
+	}
+}
+
+func TestCSPReportRecord_TruncatesLongFields(t *testing.T) {
+	repo := &MockCSPReportRepository{}
+	svc := NewCSPReportService(repo, 30, 100)
+
+	long := strings.Repeat("a", maxCSPReportFieldLength*2)
+	body := `{"csp-report":{"document-uri":"https://pmii.id/","effective-directive":"` + long + `","blocked-uri":"` + long + `"}}`
+	if _, err := svc.Record(context.Background(), []byte(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report := repo.reports[0]
+	if len(report.BlockedURI) != maxCSPReportFieldLength {
+		t.Errorf("expected blocked-uri truncated to %d, got %d", maxCSPReportFieldLength, len(report.BlockedURI))
+	}
+	if len(report.EffectiveDirective) != 255 {
+		t.Errorf("expected effective directive truncated to column size, got %d", len(report.EffectiveDirective))
+	}
+}
+
+func TestCSPReportRecord_Invalid(t *testing.T) {
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat(`{"type":"csp-violation","body":{"documentURL":"https://pmii.id/","effectiveDirective":"img-src"}},`, maxCSPReportsPerRequest+1), ",") + "]"
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty body", ""},
+		{"not json", "hello"},
+		{"missing document uri", `{"csp-report":{"violated-directive":"script-src"}}`},
+		{"missing directive", `{"csp-report":{"document-uri":"https://pmii.id/"}}`},
+		{"no csp violation entries", `[{"type":"deprecation","body":{}}]`},
+		{"too many reports", tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockCSPReportRepository{}
+			svc := NewCSPReportService(repo, 30, 100)
+
+			_, err := svc.Record(context.Background(), []byte(tt.body))
+			if !errors.Is(err, ErrInvalidCSPReport) {
+				t.Errorf("expected ErrInvalidCSPReport, got %v", err)
+			}
+			if len(repo.reports) != 0 {
+				t.Errorf("expected nothing saved, got %d reports", len(repo.reports))
+			}
+		})
+	}
+}
+
+func TestCSPReportRecord_SaveFailed(t *testing.T) {
+	svc := NewCSPReportService(&MockCSPReportRepository{err: errors.New("db down")}, 30, 100)
+
+	body := `{"csp-report":{"document-uri":"https://pmii.id/","effective-directive":"img-src"}}`
+	if _, err := svc.Record(context.Background(), []byte(body)); !errors.Is(err, ErrCSPReportSaveFailed) {
+		t.Errorf("expected ErrCSPReportSaveFailed, got %v", err)
+	}
+}
+
+// TestCSPReportRecord_StorageCap menguji laporan dibuang tanpa error saat kuota penyimpanan penuh
+func TestCSPReportRecord_StorageCap(t *testing.T) {
+	repo := &MockCSPReportRepository{}
+	svc := NewCSPReportService(repo, 30, 2)
+
+	body := `[{"type":"csp-violation","body":{"documentURL":"https://pmii.id/","effectiveDirective":"img-src"}},` +
+		`{"type":"csp-violation","body":{"documentURL":"https://pmii.id/","effectiveDirective":"img-src"}},` +
+		`{"type":"csp-violation","body":{"documentURL":"https://pmii.id/","effectiveDirective":"img-src"}}]`
+	count, err := svc.Record(context.Background(), []byte(body))
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 reports saved up to the cap, got count=%d err=%v", count, err)
+	}
+
+	count, err = svc.Record(context.Background(), []byte(body))
+	if err != nil || count != 0 || len(repo.reports) != 2 {
+		t.Errorf("expected reports to be dropped when full, got count=%d saved=%d err=%v", count, len(repo.reports), err)
+	}
+}
+
+// TestCSPReportPurge menguji laporan lama dihapus dan tabel dipangkas ke kuota
+func TestCSPReportPurge(t *testing.T) {
+	now := time.Now()
+	repo := &MockCSPReportRepository{reports: []domain.CSPReport{
+		{ID: 1, CreatedAt: now.AddDate(0, 0, -40)},
+		{ID: 2, CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: 3, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: 4, CreatedAt: now.Add(-time.Hour)},
+	}}
+	svc := NewCSPReportService(repo, 30, 2)
+
+	deleted, err := svc.Purge()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted != 2 || len(repo.reports) != 2 || repo.reports[0].ID != 3 {
+		t.Errorf("expected expired and oldest report removed, got deleted=%d remaining=%+v", deleted, repo.reports)
+	}
+}
@@ -82,6 +82,33 @@ func (s *dashboardService) GetDashboard(ctx context.Context, year, month int, is
 		return nil, fmt.Errorf("gagal mengambil top articles: %w", err)
 	}
 
+	// 6. Get Traffic Sources (referrer, kampanye UTM, perangkat dan lokasi)
+	// Persentase dihitung dari total seluruh kunjungan, bukan hanya dari baris top N
+	trafficTotals, err := s.dashboardRepo.GetTrafficTotals(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil total traffic: %w", err)
+	}
+
+	topReferrers, err := s.getTopReferrers(startDate, endDate, trafficTotals.Referred)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil top referrers: %w", err)
+	}
+
+	topCampaigns, err := s.getTopCampaigns(startDate, endDate, trafficTotals.Campaigns)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil top campaigns: %w", err)
+	}
+
+	deviceMix, err := s.getDeviceMix(startDate, endDate, trafficTotals.Visits)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil device mix: %w", err)
+	}
+
+	visitorsByRegion, err := s.getVisitorsByRegion(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil visitors by region: %w", err)
+	}
+
 	// Format period label
 	periodLabel := fmt.Sprintf("%s %d", monthNames[month], year)
 
@@ -92,9 +119,13 @@ func (s *dashboardService) GetDashboard(ctx context.Context, year, month int, is
 		VisitorsTrend:        visitorsTrend,
 		CategoryDistribution: categoryDist,
 		TopArticles:          topArticles,
+		TopReferrers:         topReferrers,
+		TopCampaigns:         topCampaigns,
+		DeviceMix:            *deviceMix,
+		VisitorsByRegion:     visitorsByRegion,
 	}
 
-	// 6. Get Activity Logs (hanya untuk Admin)
+	// 7. Get Activity Logs (hanya untuk Admin)
 	if isAdmin {
 		activityLogs, err := s.getRecentActivityLogs()
 		if err != nil {
@@ -234,6 +265,117 @@ func (s *dashboardService) getTopArticles(startDate, endDate time.Time) ([]respo
 	return result, nil
 }
 
+// getTopReferrers mendapatkan top 10 domain perujuk, persentase dari seluruh kunjungan dengan perujuk
+func (s *dashboardService) getTopReferrers(startDate, endDate time.Time, total int64) ([]responses.TrafficBreakdown, error) {
+	stats, err := s.dashboardRepo.GetTopReferrers(startDate, endDate, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	return toTrafficBreakdown(stats, total), nil
+}
+
+// getTopCampaigns mendapatkan top 10 kampanye UTM, persentase dari seluruh kunjungan kampanye
+func (s *dashboardService) getTopCampaigns(startDate, endDate time.Time, total int64) ([]responses.TopCampaign, error) {
+	stats, err := s.dashboardRepo.GetTopCampaigns(startDate, endDate, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]responses.TopCampaign, 0, len(stats))
+	for _, cs := range stats {
+		result = append(result, responses.TopCampaign{
+			Campaign:   cs.Campaign,
+			Source:     cs.Source,
+			Medium:     cs.Medium,
+			Visitors:   cs.Visitors,
+			Percentage: percentageOf(cs.Visitors, total),
+		})
+	}
+
+	return result, nil
+}
+
+// getDeviceMix mendapatkan distribusi visitor per device, browser dan OS, persentase dari seluruh kunjungan
+func (s *dashboardService) getDeviceMix(startDate, endDate time.Time, total int64) (*responses.DeviceMix, error) {
+	devices, err := s.dashboardRepo.GetVisitorBreakdown(repository.VisitorDimensionDevice, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	browsers, err := s.dashboardRepo.GetVisitorBreakdown(repository.VisitorDimensionBrowser, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	operatingSystems, err := s.dashboardRepo.GetVisitorBreakdown(repository.VisitorDimensionOS, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.DeviceMix{
+		Devices:          toTrafficBreakdown(devices, total),
+		Browsers:         toTrafficBreakdown(browsers, total),
+		OperatingSystems: toTrafficBreakdown(operatingSystems, total),
+	}, nil
+}
+
+// getVisitorsByRegion mendapatkan top 10 provinsi/region, persentase dihitung dari seluruh visitor
+func (s *dashboardService) getVisitorsByRegion(startDate, endDate time.Time) ([]responses.RegionBreakdown, error) {
+	stats, err := s.dashboardRepo.GetVisitorsByRegion(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, rs := range stats {
+		total += rs.Visitors
+	}
+
+	if len(stats) > 10 {
+		stats = stats[:10]
+	}
+
+	result := make([]responses.RegionBreakdown, 0, len(stats))
+	for _, rs := range stats {
+		percentage := float64(0)
+		if total > 0 {
+			percentage = float64(rs.Visitors) * 100.0 / float64(total)
+		}
+
+		result = append(result, responses.RegionBreakdown{
+			Country:    rs.Country,
+			Region:     rs.Region,
+			Visitors:   rs.Visitors,
+			Percentage: roundToTwoDecimals(percentage),
+		})
+	}
+
+	return result, nil
+}
+
+// toTrafficBreakdown menghitung persentase tiap baris terhadap total kunjungan (bukan jumlah baris yang ditampilkan)
+func toTrafficBreakdown(stats []repository.TrafficStats, total int64) []responses.TrafficBreakdown {
+	result := make([]responses.TrafficBreakdown, 0, len(stats))
+	for _, ts := range stats {
+		result = append(result, responses.TrafficBreakdown{
+			Name:       ts.Name,
+			Visitors:   ts.Visitors,
+			Percentage: percentageOf(ts.Visitors, total),
+		})
+	}
+
+	return result
+}
+
+// percentageOf menghitung persentase value terhadap total, dibulatkan 2 desimal
+func percentageOf(value, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return roundToTwoDecimals(float64(value) * 100.0 / float64(total))
+}
+
 // GetAvailablePeriods mendapatkan list periode yang tersedia
 func (s *dashboardService) GetAvailablePeriods(ctx context.Context) (*responses.DashboardPeriodsResponse, error) {
 	periods, err := s.dashboardRepo.GetAvailablePeriods()
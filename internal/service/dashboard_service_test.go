@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -18,6 +19,11 @@ type MockDashboardRepository struct {
 	GetCategoryDistributionFunc func(startDate, endDate time.Time) ([]repository.CategoryStats, error)
 	GetDailyVisitorsFunc        func(startDate, endDate time.Time) ([]repository.DailyVisitors, error)
 	GetTopArticlesFunc          func(startDate, endDate time.Time, limit int) ([]repository.ArticleStats, error)
+	GetTopReferrersFunc         func(startDate, endDate time.Time, limit int) ([]repository.TrafficStats, error)
+	GetTopCampaignsFunc         func(startDate, endDate time.Time, limit int) ([]repository.CampaignStats, error)
+	GetTrafficTotalsFunc        func(startDate, endDate time.Time) (*repository.TrafficTotals, error)
+	GetVisitorBreakdownFunc     func(dimension repository.VisitorDimension, startDate, endDate time.Time) ([]repository.TrafficStats, error)
+	GetVisitorsByRegionFunc     func(startDate, endDate time.Time) ([]repository.RegionStats, error)
 	GetAvailablePeriodsFunc     func() ([]time.Time, error)
 	GetRecentActivityLogsFunc   func(limit int) ([]repository.ActivityLogStats, error)
 }
@@ -71,6 +77,41 @@ func (m *MockDashboardRepository) GetTopArticles(startDate, endDate time.Time, l
 	return nil, nil
 }
 
+func (m *MockDashboardRepository) GetTopReferrers(startDate, endDate time.Time, limit int) ([]repository.TrafficStats, error) {
+	if m.GetTopReferrersFunc != nil {
+		return m.GetTopReferrersFunc(startDate, endDate, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockDashboardRepository) GetTopCampaigns(startDate, endDate time.Time, limit int) ([]repository.CampaignStats, error) {
+	if m.GetTopCampaignsFunc != nil {
+		return m.GetTopCampaignsFunc(startDate, endDate, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockDashboardRepository) GetTrafficTotals(startDate, endDate time.Time) (*repository.TrafficTotals, error) {
+	if m.GetTrafficTotalsFunc != nil {
+		return m.GetTrafficTotalsFunc(startDate, endDate)
+	}
+	return &repository.TrafficTotals{}, nil
+}
+
+func (m *MockDashboardRepository) GetVisitorBreakdown(dimension repository.VisitorDimension, startDate, endDate time.Time) ([]repository.TrafficStats, error) {
+	if m.GetVisitorBreakdownFunc != nil {
+		return m.GetVisitorBreakdownFunc(dimension, startDate, endDate)
+	}
+	return nil, nil
+}
+
+func (m *MockDashboardRepository) GetVisitorsByRegion(startDate, endDate time.Time) ([]repository.RegionStats, error) {
+	if m.GetVisitorsByRegionFunc != nil {
+		return m.GetVisitorsByRegionFunc(startDate, endDate)
+	}
+	return nil, nil
+}
+
 func (m *MockDashboardRepository) GetAvailablePeriods() ([]time.Time, error) {
 	if m.GetAvailablePeriodsFunc != nil {
 		return m.GetAvailablePeriodsFunc()
@@ -169,6 +210,98 @@ func TestGetDashboard_Success(t *testing.T) {
 	}
 }
 
+// TestGetDashboard_TrafficSources menguji section referrer, kampanye dan device mix
+func TestGetDashboard_TrafficSources(t *testing.T) {
+	mockRepo := &MockDashboardRepository{
+		GetTopReferrersFunc: func(startDate, endDate time.Time, limit int) ([]repository.TrafficStats, error) {
+			return []repository.TrafficStats{
+				{Name: "google.com", Visitors: 300},
+				{Name: "facebook.com", Visitors: 100},
+			}, nil
+		},
+		GetTopCampaignsFunc: func(startDate, endDate time.Time, limit int) ([]repository.CampaignStats, error) {
+			return []repository.CampaignStats{
+				{Campaign: "harlah-pmii", Source: "instagram", Medium: "social", Visitors: 80},
+			}, nil
+		},
+		// Top 10 tidak memuat semua baris: total sebenarnya lebih besar dari jumlah baris yang dikembalikan
+		GetTrafficTotalsFunc: func(startDate, endDate time.Time) (*repository.TrafficTotals, error) {
+			return &repository.TrafficTotals{Visits: 1200, Referred: 500, Campaigns: 160}, nil
+		},
+		GetVisitorBreakdownFunc: func(dimension repository.VisitorDimension, startDate, endDate time.Time) ([]repository.TrafficStats, error) {
+			if dimension != repository.VisitorDimensionDevice {
+				return []repository.TrafficStats{{Name: "Other", Visitors: 1}}, nil
+			}
+			return []repository.TrafficStats{
+				{Name: "mobile", Visitors: 600},
+				{Name: "desktop", Visitors: 300},
+				{Name: "unknown", Visitors: 100},
+			}, nil
+		},
+	}
+
+	dashboardService := NewDashboardService(mockRepo)
+	result, err := dashboardService.GetDashboard(context.Background(), 2025, 12, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.TopReferrers) != 2 || result.TopReferrers[0].Percentage != 60 {
+		t.Errorf("Expected google.com with 60%% of all referred visits, got %+v", result.TopReferrers)
+	}
+
+	if len(result.TopCampaigns) != 1 || result.TopCampaigns[0].Campaign != "harlah-pmii" || result.TopCampaigns[0].Source != "instagram" || result.TopCampaigns[0].Percentage != 50 {
+		t.Errorf("Expected harlah-pmii campaign with 50%%, got %+v", result.TopCampaigns)
+	}
+
+	if len(result.DeviceMix.Devices) != 3 || result.DeviceMix.Devices[0].Percentage != 50 {
+		t.Errorf("Expected mobile 50%% of all visits, got %+v", result.DeviceMix.Devices)
+	}
+
+	if len(result.DeviceMix.Browsers) != 1 || len(result.DeviceMix.OperatingSystems) != 1 {
+		t.Errorf("Expected browser and OS breakdowns, got %+v", result.DeviceMix)
+	}
+}
+
+// TestGetDashboard_VisitorsByRegion menguji top 10 region dengan persentase dari seluruh visitor
+func TestGetDashboard_VisitorsByRegion(t *testing.T) {
+	stats := []repository.RegionStats{
+		{Country: "ID", Region: "Jawa Timur", Visitors: 400},
+		{Country: "ID", Region: "Jawa Tengah", Visitors: 200},
+		{Country: "unknown", Region: "unknown", Visitors: 100},
+	}
+	for i := 0; i < 10; i++ {
+		stats = append(stats, repository.RegionStats{Country: "ID", Region: fmt.Sprintf("Region %d", i), Visitors: 30})
+	}
+
+	mockRepo := &MockDashboardRepository{
+		GetVisitorsByRegionFunc: func(startDate, endDate time.Time) ([]repository.RegionStats, error) {
+			return stats, nil
+		},
+	}
+
+	dashboardService := NewDashboardService(mockRepo)
+	result, err := dashboardService.GetDashboard(context.Background(), 2025, 12, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.VisitorsByRegion) != 10 {
+		t.Fatalf("Expected 10 regions, got %d", len(result.VisitorsByRegion))
+	}
+
+	first := result.VisitorsByRegion[0]
+	if first.Country != "ID" || first.Region != "Jawa Timur" || first.Percentage != 40 {
+		t.Errorf("Expected Jawa Timur with 40%%, got %+v", first)
+	}
+
+	if result.VisitorsByRegion[2].Region != "unknown" || result.VisitorsByRegion[2].Percentage != 10 {
+		t.Errorf("Expected unknown region with 10%%, got %+v", result.VisitorsByRegion[2])
+	}
+}
+
 // TestGetDashboard_EmptyData menguji GetDashboard tanpa data
 func TestGetDashboard_EmptyData(t *testing.T) {
 	mockRepo := &MockDashboardRepository{
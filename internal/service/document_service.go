@@ -9,7 +9,6 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
-	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
 // DocumentService interface untuk business logic document (admin)
@@ -25,15 +24,16 @@ type DocumentService interface {
 type documentService struct {
 	documentRepo      repository.DocumentRepository
 	cloudinaryService CloudinaryService
-	activityLogRepo   repository.ActivityLogRepository
+	webhooks          WebhookPublisher
 }
 
 // NewDocumentService constructor untuk DocumentService
-func NewDocumentService(documentRepo repository.DocumentRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository) DocumentService {
+// webhooks boleh nil jika event dokumen tidak perlu dikirim ke webhook
+func NewDocumentService(documentRepo repository.DocumentRepository, cloudinaryService CloudinaryService, webhooks WebhookPublisher) DocumentService {
 	return &documentService{
 		documentRepo:      documentRepo,
 		cloudinaryService: cloudinaryService,
-		activityLogRepo:   activityLogRepo,
+		webhooks:          orNoopWebhookPublisher(webhooks),
 	}
 }
 
@@ -67,20 +67,16 @@ func (s *documentService) Create(ctx context.Context, req requests.CreateDocumen
 	}
 
 	// Save ke database
-	if err := s.documentRepo.Create(document); err != nil {
+	if err := s.documentRepo.WithContext(ctx).Create(document); err != nil {
 		// Rollback: hapus file dari Cloudinary jika save gagal
 		_ = s.cloudinaryService.DeleteFile(ctx, folder, filename)
 		return nil, errors.New("gagal menyimpan dokumen")
 	}
 
-	// Log activity - Create Document
-	s.logActivity(ctx, domain.ActionCreate, domain.ModuleDokumen, "Membuat dokumen baru: "+document.Name, nil, map[string]any{
-		"id":        document.ID,
-		"name":      document.Name,
-		"file_type": string(document.FileType),
-	}, &document.ID)
+	res := s.toResponseDTO(document)
+	s.webhooks.Publish(ctx, domain.WebhookEventDocumentCreated, res)
 
-	return s.toResponseDTO(document), nil
+	return res, nil
 }
 
 // GetAll mengambil semua document dengan pagination dan search
@@ -148,14 +144,6 @@ func (s *documentService) Update(ctx context.Context, id int, req requests.Updat
 	oldFileURI := document.FileURI
 	oldFileType := document.FileType
 
-	// Store old values for audit log
-	oldValues := map[string]any{
-		"id":        document.ID,
-		"name":      document.Name,
-		"file_type": string(document.FileType),
-		"file_uri":  document.FileURI,
-	}
-
 	// Update file type if provided
 	if req.FileType != "" {
 		docType := domain.DocumentType(req.FileType)
@@ -183,7 +171,7 @@ func (s *documentService) Update(ctx context.Context, id int, req requests.Updat
 	}
 
 	// Save ke database
-	if err := s.documentRepo.Update(document); err != nil {
+	if err := s.documentRepo.WithContext(ctx).Update(document); err != nil {
 		// Rollback: hapus file baru jika update gagal
 		if newFilename != nil {
 			_ = s.cloudinaryService.DeleteFile(ctx, document.FileType.GetCloudinaryFolder(), *newFilename)
@@ -196,15 +184,10 @@ func (s *documentService) Update(ctx context.Context, id int, req requests.Updat
 		_ = s.cloudinaryService.DeleteFile(ctx, oldFileType.GetCloudinaryFolder(), oldFileURI)
 	}
 
-	// Log activity - Update Document
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleDokumen, "Mengupdate dokumen: "+document.Name, oldValues, map[string]any{
-		"id":        document.ID,
-		"name":      document.Name,
-		"file_type": string(document.FileType),
-		"file_uri":  document.FileURI,
-	}, &document.ID)
+	res := s.toResponseDTO(document)
+	s.webhooks.Publish(ctx, domain.WebhookEventDocumentUpdated, res)
 
-	return s.toResponseDTO(document), nil
+	return res, nil
 }
 
 // Delete menghapus document (soft delete) dan file dari Cloudinary
@@ -215,15 +198,8 @@ func (s *documentService) Delete(ctx context.Context, id int) error {
 		return errors.New("dokumen tidak ditemukan")
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleDokumen, "Menghapus dokumen: "+document.Name, map[string]any{
-		"id":        document.ID,
-		"name":      document.Name,
-		"file_type": string(document.FileType),
-	}, nil, &document.ID)
-
 	// Soft delete dari database
-	if err := s.documentRepo.Delete(id); err != nil {
+	if err := s.documentRepo.WithContext(ctx).Delete(id); err != nil {
 		return errors.New("gagal menghapus dokumen")
 	}
 
@@ -231,6 +207,7 @@ func (s *documentService) Delete(ctx context.Context, id int) error {
 	folder := document.FileType.GetCloudinaryFolder()
 	_ = s.cloudinaryService.DeleteFile(ctx, folder, document.FileURI)
 
+	s.webhooks.Publish(ctx, domain.WebhookEventDocumentDeleted, map[string]any{"id": document.ID, "name": document.Name, "fileType": document.FileType})
 	return nil
 }
 
@@ -274,37 +251,3 @@ func (s *documentService) toDetailResponseDTO(d *domain.Document) *responses.Doc
 		FileURL:       fileURL,
 	}
 }
-
-// logActivity helper untuk mencatat activity log
-func (s *documentService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
-}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
 	"github.com/garuda-labs-1/pmii-be/pkg/logger"
 )
 
@@ -116,6 +117,10 @@ func (m *MockTestimonialRepositoryForHomeService) Delete(id int) error {
 	return nil
 }
 
+func (m *MockTestimonialRepositoryForHomeService) WithContext(ctx context.Context) repository.TestimonialRepository {
+	return m
+}
+
 // MockCloudinaryServiceForHomeService adalah mock untuk CloudinaryService
 type MockCloudinaryServiceForHomeService struct {
 	GetImageURLFunc    func(folder string, fileName string) string
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// ImpersonationResult berisi token impersonasi dan user yang diimpersonasi
+type ImpersonationResult struct {
+	AccessToken string
+	ExpiresIn   int // Masa berlaku token impersonasi dalam detik
+	User        *domain.User
+}
+
+// ImpersonationService interface untuk admin yang masuk sebagai user lain (troubleshooting)
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, targetUserID int) (*ImpersonationResult, error)
+}
+
+type impersonationService struct {
+	userRepo        repository.UserRepository
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewImpersonationService constructor untuk ImpersonationService
+func NewImpersonationService(userRepo repository.UserRepository, activityLogRepo repository.ActivityLogRepository) ImpersonationService {
+	return &impersonationService{
+		userRepo:        userRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// Impersonate membuat token berumur pendek atas nama user target
+// Token membawa ID user target dan ID admin, sehingga setiap request tercatat atas nama keduanya
+func (s *impersonationService) Impersonate(ctx context.Context, targetUserID int) (*ImpersonationResult, error) {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	// Tidak boleh impersonasi berantai dari token impersonasi
+	if utils.GetImpersonatorID(ctx) != nil {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	if targetUserID == adminID {
+		return nil, ErrCannotImpersonateSelf
+	}
+
+	target, err := s.userRepo.FindByID(targetUserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if !target.IsActive {
+		return nil, ErrImpersonationTargetInactive
+	}
+
+	// Admin lain tidak bisa diimpersonasi agar impersonasi tidak dipakai untuk menyamarkan aksi admin
+	role := strconv.Itoa(target.Role)
+	if utils.HasPermission(role, domain.PermUsersManage) || utils.HasPermission(role, domain.PermUsersImpersonate) {
+		return nil, ErrImpersonationTargetPrivileged
+	}
+
+	token, err := utils.GenerateImpersonationToken(target.ID, role, adminID)
+	if err != nil {
+		return nil, ErrImpersonationFailed
+	}
+
+	ttl := utils.ImpersonationTokenTTL()
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      adminID,
+		Action:      domain.ActionImpersonate,
+		Module:      domain.ModuleUser,
+		Description: "Memulai impersonasi sebagai user: " + target.Email,
+		TargetID:    &target.ID,
+		NewValue: map[string]any{
+			"user_id":    target.ID,
+			"email":      target.Email,
+			"full_name":  target.FullName,
+			"expires_at": time.Now().Add(ttl),
+		},
+	})
+
+	return &ImpersonationResult{
+		AccessToken: token,
+		ExpiresIn:   int(ttl.Seconds()),
+		User:        target,
+	}, nil
+}
+
+// Impersonation service errors
+var (
+	ErrCannotImpersonateSelf         = errors.New("tidak dapat mengimpersonasi akun sendiri")
+	ErrImpersonationTargetInactive   = errors.New("user yang tidak aktif tidak dapat diimpersonasi")
+	ErrImpersonationTargetPrivileged = errors.New("user dengan hak kelola user tidak dapat diimpersonasi")
+	ErrImpersonationNotAllowed       = errors.New("tidak dapat memulai impersonasi dari sesi impersonasi")
+	ErrImpersonationFailed           = errors.New("gagal membuat token impersonasi")
+)
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// initImpersonationPermissions menyiapkan permission store: role 1 (admin) boleh kelola dan impersonasi user
+func initImpersonationPermissions() {
+	utils.InitPermissionStore(&MockRoleRepository{
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			if roleID == domain.RoleAdminID {
+				return "admin", []string{domain.PermUsersManage, domain.PermUsersImpersonate}, nil
+			}
+			return "author", []string{domain.PermPostsCreate}, nil
+		},
+	})
+}
+
+func newImpersonationTestUserRepo() *MockUserRepositoryForUserService {
+	users := map[int]*domain.User{
+		1: {ID: 1, Email: "admin@example.com", Role: domain.RoleAdminID, IsActive: true},
+		2: {ID: 2, Email: "author@example.com", FullName: "Author", Role: domain.RoleAuthorID, IsActive: true},
+		3: {ID: 3, Email: "inactive@example.com", Role: domain.RoleAuthorID, IsActive: false},
+		4: {ID: 4, Email: "admin2@example.com", Role: domain.RoleAdminID, IsActive: true},
+	}
+	return &MockUserRepositoryForUserService{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			if user, ok := users[id]; ok {
+				return user, nil
+			}
+			return nil, errors.New("record not found")
+		},
+	}
+}
+
+func TestImpersonate_IssuesTokenForUserAndAdmin(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	initImpersonationPermissions()
+
+	var logged *domain.ActivityLog
+	activityLogRepo := &MockActivityLogRepoForAuth{
+		CreateFunc: func(log *domain.ActivityLog) error {
+			logged = log
+			return nil
+		},
+	}
+	svc := NewImpersonationService(newImpersonationTestUserRepo(), activityLogRepo)
+
+	result, err := svc.Impersonate(utils.WithUserID(context.Background(), 1), 2)
+	if err != nil {
+		t.Fatalf("Impersonate error: %v", err)
+	}
+	if result.User.ID != 2 || result.ExpiresIn != int(utils.ImpersonationTokenTTL().Seconds()) {
+		t.Fatalf("hasil impersonasi tidak sesuai: %+v", result)
+	}
+
+	claims, err := utils.ValidateJWT(result.AccessToken)
+	if err != nil {
+		t.Fatalf("token impersonasi tidak valid: %v", err)
+	}
+	if claims.UserID != 2 || claims.ImpersonatorID != 1 || claims.Role != "2" {
+		t.Errorf("claims = user %d, impersonator %d, role %s; want 2, 1, 2", claims.UserID, claims.ImpersonatorID, claims.Role)
+	}
+	if claims.SessionID != "" {
+		t.Error("token impersonasi tidak boleh terikat session")
+	}
+
+	if logged == nil || logged.UserID != 1 || logged.ActionType != domain.ActionImpersonate || logged.TargetID == nil || *logged.TargetID != 2 {
+		t.Fatalf("mulai impersonasi harus dicatat atas nama admin dengan target user, got %+v", logged)
+	}
+}
+
+func TestImpersonate_Rejected(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	initImpersonationPermissions()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		targetID int
+		wantErr  error
+	}{
+		{"akun sendiri", utils.WithUserID(context.Background(), 1), 1, ErrCannotImpersonateSelf},
+		{"user tidak ditemukan", utils.WithUserID(context.Background(), 1), 99, ErrUserNotFound},
+		{"user tidak aktif", utils.WithUserID(context.Background(), 1), 3, ErrImpersonationTargetInactive},
+		{"admin lain", utils.WithUserID(context.Background(), 1), 4, ErrImpersonationTargetPrivileged},
+		{"dari sesi impersonasi", utils.WithImpersonatorID(utils.WithUserID(context.Background(), 2), 1), 3, ErrImpersonationNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewImpersonationService(newImpersonationTestUserRepo(), &MockActivityLogRepoForAuth{})
+			if _, err := svc.Impersonate(tt.ctx, tt.targetID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/logger"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// invitationTTL masa berlaku link undangan, dihitung ulang setiap kali undangan dikirim ulang
+const invitationTTL = 7 * 24 * time.Hour
+
+// InvitationService interface untuk business logic undangan user baru
+type InvitationService interface {
+	GetOpen(ctx context.Context) ([]domain.UserInvitation, error)
+	Create(ctx context.Context, req *requests.CreateInvitationRequest) (*domain.UserInvitation, error)
+	Resend(ctx context.Context, id int) (*domain.UserInvitation, error)
+	Revoke(ctx context.Context, id int) error
+	Verify(ctx context.Context, token string) (*domain.UserInvitation, error)
+	Accept(ctx context.Context, req *requests.AcceptInvitationRequest, photoFile *multipart.FileHeader) (*domain.User, error)
+}
+
+type invitationService struct {
+	invitationRepo    repository.InvitationRepository
+	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	cloudinaryService CloudinaryService
+	activityLogRepo   repository.ActivityLogRepository
+	mailer            MailerService
+	frontendURL       string
+}
+
+// NewInvitationService constructor untuk InvitationService
+func NewInvitationService(invitationRepo repository.InvitationRepository, userRepo repository.UserRepository, roleRepo repository.RoleRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository, mailer MailerService, frontendURL string) InvitationService {
+	return &invitationService{
+		invitationRepo:    invitationRepo,
+		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		cloudinaryService: cloudinaryService,
+		activityLogRepo:   activityLogRepo,
+		mailer:            mailer,
+		frontendURL:       strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// GetOpen mengambil undangan yang belum diterima atau dicabut
+func (s *invitationService) GetOpen(ctx context.Context) ([]domain.UserInvitation, error) {
+	invitations, err := s.invitationRepo.FindOpen()
+	if err != nil {
+		return nil, ErrInvitationFetchFailed
+	}
+	return invitations, nil
+}
+
+// Create membuat undangan untuk email dan role tertentu lalu mengirim link undangan
+func (s *invitationService) Create(ctx context.Context, req *requests.CreateInvitationRequest) (*domain.UserInvitation, error) {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if existingUser, _ := s.userRepo.FindByEmail(email); existingUser != nil {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	pending, err := s.invitationRepo.FindOpenByEmail(email)
+	if err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+	if pending != nil {
+		return nil, ErrInvitationAlreadyPending
+	}
+
+	role, err := s.roleRepo.FindByID(req.Role)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if err := authorizeInvitationRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	// Undangan lama yang sudah kadaluarsa diganti undangan baru
+	if err := s.invitationRepo.RevokeExpiredByEmail(email); err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+
+	now := time.Now()
+	invitation := &domain.UserInvitation{
+		Email:      email,
+		RoleID:     role.ID,
+		TokenHash:  utils.HashToken(token),
+		InvitedBy:  adminID,
+		ExpiresAt:  now.Add(invitationTTL),
+		LastSentAt: now,
+		SendCount:  1,
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+	invitation.Role = *role
+
+	go s.sendInvitationEmail(invitation, token)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      adminID,
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleUser,
+		Description: "Mengundang user baru: " + email,
+		TargetID:    &invitation.ID,
+		NewValue: map[string]any{
+			"id":         invitation.ID,
+			"email":      email,
+			"role":       role.Key,
+			"expires_at": invitation.ExpiresAt,
+		},
+	})
+
+	return invitation, nil
+}
+
+// Resend membuat token baru (link lama tidak berlaku), memperpanjang masa berlaku, lalu mengirim ulang email
+func (s *invitationService) Resend(ctx context.Context, id int) (*domain.UserInvitation, error) {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	invitation, err := s.invitationRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrInvitationNotFound
+	}
+	if !invitation.IsOpen() {
+		return nil, ErrInvitationClosed
+	}
+	role, err := s.roleRepo.FindByID(invitation.RoleID)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if err := authorizeInvitationRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+
+	now := time.Now()
+	invitation.TokenHash = utils.HashToken(token)
+	invitation.ExpiresAt = now.Add(invitationTTL)
+	invitation.LastSentAt = now
+	invitation.SendCount++
+	if err := s.invitationRepo.Update(invitation); err != nil {
+		return nil, ErrInvitationSaveFailed
+	}
+
+	go s.sendInvitationEmail(invitation, token)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      adminID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleUser,
+		Description: "Mengirim ulang undangan: " + invitation.Email,
+		TargetID:    &invitation.ID,
+		NewValue: map[string]any{
+			"id":         invitation.ID,
+			"email":      invitation.Email,
+			"send_count": invitation.SendCount,
+			"expires_at": invitation.ExpiresAt,
+		},
+	})
+
+	return invitation, nil
+}
+
+// Revoke mencabut undangan, link undangan langsung tidak berlaku
+func (s *invitationService) Revoke(ctx context.Context, id int) error {
+	adminID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	invitation, err := s.invitationRepo.FindByID(id)
+	if err != nil {
+		return ErrInvitationNotFound
+	}
+
+	revoked, err := s.invitationRepo.Revoke(id)
+	if err != nil {
+		return ErrInvitationSaveFailed
+	}
+	if !revoked {
+		return ErrInvitationClosed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      adminID,
+		Action:      domain.ActionDelete,
+		Module:      domain.ModuleUser,
+		Description: "Mencabut undangan: " + invitation.Email,
+		TargetID:    &invitation.ID,
+		OldValue: map[string]any{
+			"id":    invitation.ID,
+			"email": invitation.Email,
+		},
+	})
+
+	return nil
+}
+
+// Verify mengecek link undangan dan mengembalikan data undangan untuk ditampilkan di form pendaftaran
+func (s *invitationService) Verify(ctx context.Context, token string) (*domain.UserInvitation, error) {
+	invitation, err := s.invitationRepo.FindByTokenHash(utils.HashToken(token))
+	if err != nil || !invitation.IsUsable(time.Now()) {
+		return nil, ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// Accept membuat akun dari undangan dengan password dan profil yang diisi sendiri oleh user
+func (s *invitationService) Accept(ctx context.Context, req *requests.AcceptInvitationRequest, photoFile *multipart.FileHeader) (*domain.User, error) {
+	invitation, err := s.Verify(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isValidPassword(req.Password) {
+		return nil, ErrInvalidPassword
+	}
+
+	// Email bisa saja didaftarkan admin lewat jalur lain setelah undangan dibuat
+	if existingUser, _ := s.userRepo.FindByEmail(invitation.Email); existingUser != nil {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, ErrPasswordProcessing
+	}
+
+	var photoFileName *string
+	if photoFile != nil {
+		fileName, err := s.cloudinaryService.UploadImage(ctx, "users/avatars", photoFile)
+		if err != nil {
+			return nil, ErrPhotoUploadFailed
+		}
+		photoFileName = &fileName
+	}
+
+	user := &domain.User{
+		Role:         invitation.RoleID,
+		FullName:     strings.TrimSpace(req.FullName),
+		Email:        invitation.Email,
+		PasswordHash: hashedPassword,
+		PhotoURI:     photoFileName,
+		IsActive:     true,
+	}
+
+	accepted, err := s.invitationRepo.Accept(invitation.ID, user)
+	if err != nil || !accepted {
+		if photoFileName != nil {
+			_ = s.cloudinaryService.DeleteImage(ctx, "users/avatars", *photoFileName)
+		}
+		if err != nil {
+			return nil, ErrUserCreateFailed
+		}
+		return nil, ErrInvalidInvitation
+	}
+
+	if photoFileName != nil {
+		fullPhotoURL := s.cloudinaryService.GetImageURL("users/avatars", *photoFileName)
+		user.PhotoURI = &fullPhotoURL
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleUser,
+		Description: "Menerima undangan dan membuat akun: " + user.Email,
+		TargetID:    &user.ID,
+		NewValue: map[string]any{
+			"invitation_id": invitation.ID,
+			"invited_by":    invitation.InvitedBy,
+			"user_id":       user.ID,
+			"email":         user.Email,
+			"full_name":     user.FullName,
+			"role":          invitation.Role.Key,
+		},
+	})
+
+	return user, nil
+}
+
+// sendInvitationEmail mengirim email berisi link undangan
+func (s *invitationService) sendInvitationEmail(invitation *domain.UserInvitation, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", s.frontendURL, token)
+	body := fmt.Sprintf(`Halo,
+
+Anda diundang untuk bergabung sebagai %s di dashboard PMII. Buka link berikut untuk membuat password dan melengkapi profil Anda:
+
+%s
+
+Link ini berlaku sampai %s dan hanya dapat digunakan satu kali.
+Jika Anda merasa tidak seharusnya menerima undangan ini, abaikan email ini.`, invitation.Role.Name, link, invitation.ExpiresAt.Format("02 Jan 2006 15:04"))
+
+	if err := s.mailer.Send(ctx, invitation.Email, "Undangan Akun PMII", body); err != nil {
+		logger.Error.Printf("sendInvitationEmail: failed to send invitation %d: %v", invitation.ID, err)
+	}
+}
+
+// authorizeInvitationRole mencegah eskalasi hak akses lewat undangan: pengundang tanpa roles.manage
+// hanya boleh mengundang ke role yang seluruh permission-nya juga ia miliki
+func authorizeInvitationRole(ctx context.Context, role *domain.Role) error {
+	inviterRole := utils.GetUserRole(ctx)
+	if utils.HasPermission(inviterRole, domain.PermRolesManage) {
+		return nil
+	}
+	for _, permission := range role.Permissions {
+		if !utils.HasPermission(inviterRole, permission.Key) {
+			return ErrInvitationRoleForbidden
+		}
+	}
+	return nil
+}
+
+// Invitation service errors
+var (
+	ErrInvitationNotFound       = errors.New("undangan tidak ditemukan")
+	ErrInvitationAlreadyPending = errors.New("email ini sudah memiliki undangan yang belum diterima")
+	ErrInvitationClosed         = errors.New("undangan sudah diterima atau dicabut")
+	ErrInvalidInvitation        = errors.New("link undangan tidak valid atau sudah kadaluarsa")
+	ErrInvitationFetchFailed    = errors.New("gagal mengambil data undangan")
+	ErrInvitationSaveFailed     = errors.New("gagal menyimpan undangan")
+	ErrInvitationRoleForbidden  = errors.New("tidak boleh mengundang ke role dengan hak akses melebihi milik sendiri")
+)
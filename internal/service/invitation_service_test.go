@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MockInvitationRepository menyimpan undangan di memory
+type MockInvitationRepository struct {
+	invitations []*domain.UserInvitation
+	users       []*domain.User
+}
+
+func (m *MockInvitationRepository) Create(invitation *domain.UserInvitation) error {
+	invitation.ID = len(m.invitations) + 1
+	m.invitations = append(m.invitations, invitation)
+	return nil
+}
+
+func (m *MockInvitationRepository) FindByID(id int) (*domain.UserInvitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.ID == id {
+			return invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockInvitationRepository) FindByTokenHash(hash string) (*domain.UserInvitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.TokenHash == hash {
+			return invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockInvitationRepository) FindOpen() ([]domain.UserInvitation, error) {
+	var result []domain.UserInvitation
+	for _, invitation := range m.invitations {
+		if invitation.IsOpen() {
+			result = append(result, *invitation)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockInvitationRepository) FindOpenByEmail(email string) (*domain.UserInvitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.IsUsable(time.Now()) && invitation.Email == email {
+			return invitation, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockInvitationRepository) RevokeExpiredByEmail(email string) error {
+	now := time.Now()
+	for _, invitation := range m.invitations {
+		if invitation.IsOpen() && !invitation.IsUsable(now) && invitation.Email == email {
+			invitation.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockInvitationRepository) Update(invitation *domain.UserInvitation) error {
+	return nil
+}
+
+func (m *MockInvitationRepository) Revoke(id int) (bool, error) {
+	invitation, err := m.FindByID(id)
+	if err != nil || !invitation.IsOpen() {
+		return false, nil
+	}
+	now := time.Now()
+	invitation.RevokedAt = &now
+	return true, nil
+}
+
+func (m *MockInvitationRepository) Accept(id int, user *domain.User) (bool, error) {
+	invitation, err := m.FindByID(id)
+	if err != nil || !invitation.IsUsable(time.Now()) {
+		return false, nil
+	}
+	user.ID = 100 + len(m.users)
+	m.users = append(m.users, user)
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	invitation.AcceptedUserID = &user.ID
+	return true, nil
+}
+
+func newTestInvitationService(repo *MockInvitationRepository, userRepo *MockUserRepositoryForUserService, mailer *MockMailer) InvitationService {
+	roleRepo := &MockRoleRepository{
+		FindByIDFunc: func(id int) (*domain.Role, error) {
+			switch id {
+			case 1:
+				return &domain.Role{ID: 1, Key: "admin", Name: "Admin", Permissions: []domain.Permission{
+					{Key: domain.PermUsersManage}, {Key: domain.PermRolesManage},
+				}}, nil
+			case 2:
+				return &domain.Role{ID: 2, Key: "author", Name: "Author"}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	return NewInvitationService(repo, userRepo, roleRepo, &MockCloudinaryServiceForAd{}, &MockActivityLogRepoForAuth{}, mailer, "http://localhost:3000/")
+}
+
+// invitationTokenFromEmail mengambil token dari link di body email undangan
+func invitationTokenFromEmail(t *testing.T, mailer *MockMailer) string {
+	t.Helper()
+	var body string
+	select {
+	case body = <-mailer.Sent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("email undangan tidak terkirim")
+	}
+
+	prefix := "http://localhost:3000/accept-invitation?token="
+	idx := strings.Index(body, prefix)
+	if idx < 0 {
+		t.Fatalf("link undangan tidak ditemukan di email: %q", body)
+	}
+	return strings.Fields(body[idx+len(prefix):])[0]
+}
+
+func TestInvitationCreate_SendsLinkAndStoresHashOnly(t *testing.T) {
+	repo := &MockInvitationRepository{}
+	mailer := &MockMailer{Sent: make(chan string, 1)}
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, mailer)
+
+	invitation, err := svc.Create(utils.WithUserID(context.Background(), 1), &requests.CreateInvitationRequest{Email: " New@Example.com ", Role: 2})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if invitation.Email != "new@example.com" || invitation.InvitedBy != 1 || invitation.RoleID != 2 {
+		t.Fatalf("undangan tidak sesuai: %+v", invitation)
+	}
+
+	token := invitationTokenFromEmail(t, mailer)
+	if invitation.TokenHash == token || invitation.TokenHash != utils.HashToken(token) {
+		t.Fatal("yang disimpan harus hash dari token, bukan token mentah")
+	}
+
+	preview, err := svc.Verify(context.Background(), token)
+	if err != nil || preview.ID != invitation.ID {
+		t.Fatalf("Verify error: %v", err)
+	}
+}
+
+func TestInvitationCreate_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		role     int
+		existing bool
+		wantErr  error
+	}{
+		{"email sudah terdaftar", "taken@example.com", 2, false, ErrEmailAlreadyExists},
+		{"undangan masih terbuka", "pending@example.com", 2, true, ErrInvitationAlreadyPending},
+		{"role tidak dikenal", "someone@example.com", 99, false, ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockInvitationRepository{}
+			if tt.existing {
+				_ = repo.Create(&domain.UserInvitation{Email: tt.email, ExpiresAt: time.Now().Add(time.Hour)})
+			}
+			userRepo := &MockUserRepositoryForUserService{
+				FindByEmailFunc: func(email string) (*domain.User, error) {
+					if email == "taken@example.com" {
+						return &domain.User{ID: 5, Email: email}, nil
+					}
+					return nil, errors.New("not found")
+				},
+			}
+			svc := newTestInvitationService(repo, userRepo, &MockMailer{})
+
+			_, err := svc.Create(utils.WithUserID(context.Background(), 1), &requests.CreateInvitationRequest{Email: tt.email, Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvitationAccept(t *testing.T) {
+	repo := &MockInvitationRepository{}
+	mailer := &MockMailer{Sent: make(chan string, 1)}
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, mailer)
+
+	invitation, err := svc.Create(utils.WithUserID(context.Background(), 1), &requests.CreateInvitationRequest{Email: "new@example.com", Role: 2})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	token := invitationTokenFromEmail(t, mailer)
+
+	req := &requests.AcceptInvitationRequest{Token: token, FullName: "New User", Password: "weak"}
+	if _, err := svc.Accept(context.Background(), req, nil); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("password lemah harus ditolak, got %v", err)
+	}
+
+	req.Password = "Str0ngPass!"
+	user, err := svc.Accept(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("Accept error: %v", err)
+	}
+	if user.Email != "new@example.com" || user.Role != 2 || !user.IsActive {
+		t.Fatalf("user tidak sesuai undangan: %+v", user)
+	}
+	if !utils.CheckPasswordHash(req.Password, user.PasswordHash) {
+		t.Fatal("password user harus berasal dari form undangan")
+	}
+	if invitation.AcceptedUserID == nil || *invitation.AcceptedUserID != user.ID {
+		t.Fatal("undangan harus ditandai diterima oleh user baru")
+	}
+
+	if _, err := svc.Accept(context.Background(), req, nil); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("token undangan hanya boleh dipakai sekali, got %v", err)
+	}
+}
+
+func TestInvitationAccept_ExpiredOrRevoked(t *testing.T) {
+	repo := &MockInvitationRepository{}
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, &MockMailer{})
+
+	_ = repo.Create(&domain.UserInvitation{Email: "expired@example.com", RoleID: 2, TokenHash: utils.HashToken("expired"), ExpiresAt: time.Now().Add(-time.Minute)})
+	_ = repo.Create(&domain.UserInvitation{Email: "revoked@example.com", RoleID: 2, TokenHash: utils.HashToken("revoked"), ExpiresAt: time.Now().Add(time.Hour)})
+
+	if err := svc.Revoke(utils.WithUserID(context.Background(), 1), 2); err != nil {
+		t.Fatalf("Revoke error: %v", err)
+	}
+	if err := svc.Revoke(utils.WithUserID(context.Background(), 1), 2); !errors.Is(err, ErrInvitationClosed) {
+		t.Fatalf("revoke kedua harus ErrInvitationClosed, got %v", err)
+	}
+
+	for _, token := range []string{"expired", "revoked", "unknown"} {
+		req := &requests.AcceptInvitationRequest{Token: token, FullName: "Someone", Password: "Str0ngPass!"}
+		if _, err := svc.Accept(context.Background(), req, nil); !errors.Is(err, ErrInvalidInvitation) {
+			t.Errorf("token %q: error = %v, want ErrInvalidInvitation", token, err)
+		}
+	}
+}
+
+// TestInvitationCreate_RoleEscalation menguji pengundang tanpa roles.manage tidak bisa mengundang ke role yang lebih tinggi
+func TestInvitationCreate_RoleEscalation(t *testing.T) {
+	utils.InitPermissionStore(&MockRoleRepository{
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			if roleID == 1 {
+				return "admin", []string{domain.PermUsersManage, domain.PermRolesManage}, nil
+			}
+			return "sekretariat", []string{domain.PermUsersManage}, nil
+		},
+	})
+
+	repo := &MockInvitationRepository{}
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, &MockMailer{})
+	secretariat := utils.WithUserRole(utils.WithUserID(context.Background(), 3), "3")
+
+	if _, err := svc.Create(secretariat, &requests.CreateInvitationRequest{Email: "boss@example.com", Role: 1}); !errors.Is(err, ErrInvitationRoleForbidden) {
+		t.Errorf("error = %v, want ErrInvitationRoleForbidden", err)
+	}
+	if _, err := svc.Create(secretariat, &requests.CreateInvitationRequest{Email: "author@example.com", Role: 2}); err != nil {
+		t.Errorf("undangan ke role author harus diizinkan, got %v", err)
+	}
+
+	admin := utils.WithUserRole(utils.WithUserID(context.Background(), 1), "1")
+	invitation, err := svc.Create(admin, &requests.CreateInvitationRequest{Email: "boss@example.com", Role: 1})
+	if err != nil {
+		t.Fatalf("admin dengan roles.manage harus bisa mengundang admin, got %v", err)
+	}
+	if _, err := svc.Resend(secretariat, invitation.ID); !errors.Is(err, ErrInvitationRoleForbidden) {
+		t.Errorf("Resend error = %v, want ErrInvitationRoleForbidden", err)
+	}
+}
+
+// TestInvitationCreate_ReplacesExpired menguji undangan kadaluarsa tidak menghalangi undangan baru
+func TestInvitationCreate_ReplacesExpired(t *testing.T) {
+	repo := &MockInvitationRepository{}
+	_ = repo.Create(&domain.UserInvitation{Email: "late@example.com", ExpiresAt: time.Now().Add(-time.Hour)})
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, &MockMailer{})
+
+	if _, err := svc.Create(utils.WithUserID(context.Background(), 1), &requests.CreateInvitationRequest{Email: "late@example.com", Role: 2}); err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if repo.invitations[0].RevokedAt == nil {
+		t.Error("undangan lama yang kadaluarsa harus dicabut")
+	}
+}
+
+func TestInvitationResend_RotatesToken(t *testing.T) {
+	repo := &MockInvitationRepository{}
+	mailer := &MockMailer{Sent: make(chan string, 1)}
+	svc := newTestInvitationService(repo, &MockUserRepositoryForUserService{}, mailer)
+	ctx := utils.WithUserID(context.Background(), 1)
+
+	invitation, err := svc.Create(ctx, &requests.CreateInvitationRequest{Email: "new@example.com", Role: 2})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	oldToken := invitationTokenFromEmail(t, mailer)
+
+	if _, err := svc.Resend(ctx, invitation.ID); err != nil {
+		t.Fatalf("Resend error: %v", err)
+	}
+	newToken := invitationTokenFromEmail(t, mailer)
+
+	if invitation.SendCount != 2 {
+		t.Errorf("SendCount = %d, want 2", invitation.SendCount)
+	}
+	if _, err := svc.Verify(context.Background(), oldToken); !errors.Is(err, ErrInvalidInvitation) {
+		t.Error("link lama harus tidak berlaku setelah dikirim ulang")
+	}
+	if _, err := svc.Verify(context.Background(), newToken); err != nil {
+		t.Errorf("link baru harus berlaku, got %v", err)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
-	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
 // MemberService interface untuk business logic member
@@ -24,15 +23,16 @@ type MemberService interface {
 type memberService struct {
 	memberRepo        repository.MemberRepository
 	cloudinaryService CloudinaryService
-	activityLogRepo   repository.ActivityLogRepository
+	webhooks          WebhookPublisher
 }
 
 // NewMemberService constructor untuk MemberService
-func NewMemberService(memberRepo repository.MemberRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository) MemberService {
+// webhooks boleh nil jika event member tidak perlu dikirim ke webhook
+func NewMemberService(memberRepo repository.MemberRepository, cloudinaryService CloudinaryService, webhooks WebhookPublisher) MemberService {
 	return &memberService{
 		memberRepo:        memberRepo,
 		cloudinaryService: cloudinaryService,
-		activityLogRepo:   activityLogRepo,
+		webhooks:          orNoopWebhookPublisher(webhooks),
 	}
 }
 
@@ -59,7 +59,7 @@ func (s *memberService) Create(ctx context.Context, req requests.CreateMemberReq
 	}
 
 	// Save ke database
-	if err := s.memberRepo.Create(member); err != nil {
+	if err := s.memberRepo.WithContext(ctx).Create(member); err != nil {
 		// Rollback: hapus foto dari Cloudinary jika save gagal
 		if photoFilename != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "members", *photoFilename)
@@ -69,18 +69,7 @@ func (s *memberService) Create(ctx context.Context, req requests.CreateMemberReq
 
 	// Convert to response DTO
 	resp := s.toDetailResponseDTO(member)
-
-	// Log activity - Create Member
-	s.logActivity(ctx, domain.ActionCreate, domain.ModuleMembers, "Membuat member baru: "+member.FullName, nil, map[string]any{
-		"id":           member.ID,
-		"full_name":    member.FullName,
-		"position":     member.Position,
-		"department":   string(member.Department),
-		"photo_uri":    member.PhotoURI,
-		"social_links": member.SocialLinks,
-		"is_active":    member.IsActive,
-		"created_at":   member.CreatedAt,
-	}, &member.ID)
+	s.webhooks.Publish(ctx, domain.WebhookEventMemberCreated, resp)
 
 	return resp, nil
 }
@@ -146,17 +135,6 @@ func (s *memberService) Update(ctx context.Context, id int, req requests.UpdateM
 	// Simpan foto lama untuk rollback
 	oldPhotoURI := member.PhotoURI
 
-	// Store old values for audit log
-	oldValues := map[string]any{
-		"id":           member.ID,
-		"full_name":    member.FullName,
-		"position":     member.Position,
-		"department":   string(member.Department),
-		"photo_uri":    member.PhotoURI,
-		"social_links": member.SocialLinks,
-		"is_active":    member.IsActive,
-	}
-
 	// Upload foto baru ke Cloudinary (jika ada)
 	var newPhotoFilename *string
 	if photoFile != nil {
@@ -186,7 +164,7 @@ func (s *memberService) Update(ctx context.Context, id int, req requests.UpdateM
 	}
 
 	// Save ke database
-	if err := s.memberRepo.Update(member); err != nil {
+	if err := s.memberRepo.WithContext(ctx).Update(member); err != nil {
 		// Rollback: hapus foto baru jika update gagal
 		if newPhotoFilename != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "members", *newPhotoFilename)
@@ -199,18 +177,10 @@ func (s *memberService) Update(ctx context.Context, id int, req requests.UpdateM
 		_ = s.cloudinaryService.DeleteImage(ctx, "members", *oldPhotoURI)
 	}
 
-	// Log activity - Update Member
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleMembers, "Mengupdate member: "+member.FullName, oldValues, map[string]any{
-		"id":           member.ID,
-		"full_name":    member.FullName,
-		"position":     member.Position,
-		"department":   string(member.Department),
-		"photo_uri":    member.PhotoURI,
-		"social_links": member.SocialLinks,
-		"is_active":    member.IsActive,
-	}, &member.ID)
+	resp := s.toDetailResponseDTO(member)
+	s.webhooks.Publish(ctx, domain.WebhookEventMemberUpdated, resp)
 
-	return s.toDetailResponseDTO(member), nil
+	return resp, nil
 }
 
 // Delete menghapus member dan foto dari Cloudinary
@@ -221,20 +191,8 @@ func (s *memberService) Delete(ctx context.Context, id int) error {
 		return errors.New("member tidak ditemukan")
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleMembers, "Menghapus member: "+member.FullName, map[string]any{
-		"id":           member.ID,
-		"full_name":    member.FullName,
-		"position":     member.Position,
-		"department":   string(member.Department),
-		"photo_uri":    member.PhotoURI,
-		"social_links": member.SocialLinks,
-		"is_active":    member.IsActive,
-		"created_at":   member.CreatedAt,
-	}, nil, &member.ID)
-
 	// Hapus dari database
-	if err := s.memberRepo.Delete(id); err != nil {
+	if err := s.memberRepo.WithContext(ctx).Delete(id); err != nil {
 		return errors.New("gagal menghapus member")
 	}
 
@@ -243,6 +201,7 @@ func (s *memberService) Delete(ctx context.Context, id int) error {
 		_ = s.cloudinaryService.DeleteImage(ctx, "members", *member.PhotoURI)
 	}
 
+	s.webhooks.Publish(ctx, domain.WebhookEventMemberDeleted, map[string]any{"id": member.ID, "fullName": member.FullName})
 	return nil
 }
 
@@ -279,37 +238,3 @@ func (s *memberService) toDetailResponseDTO(m *domain.Member) *responses.MemberD
 		IsActive:    m.IsActive,
 	}
 }
-
-// logActivity helper untuk mencatat activity log
-func (s *memberService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
-}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
-	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
@@ -72,20 +71,8 @@ func (m *MockMemberRepository) FindActiveByDepartment(department string, page, l
 	return nil, 0, errors.New("mock not configured")
 }
 
-// MockActivityLogRepoForMember adalah mock untuk ActivityLogRepository
-type MockActivityLogRepoForMember struct {
-	CreateFunc func(log *domain.ActivityLog) error
-}
-
-func (m *MockActivityLogRepoForMember) Create(log *domain.ActivityLog) error {
-	if m.CreateFunc != nil {
-		return m.CreateFunc(log)
-	}
-	return nil
-}
-
-func (m *MockActivityLogRepoForMember) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
-	return nil, 0, nil
+func (m *MockMemberRepository) WithContext(ctx context.Context) repository.MemberRepository {
+	return m
 }
 
 // ==================== CREATE TESTS ====================
@@ -99,7 +86,7 @@ func TestMemberCreate_ErrorUploadFailed(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 	req := requests.CreateMemberRequest{FullName: "Test", Position: "Developer"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -130,7 +117,7 @@ func TestMemberCreate_ErrorDatabaseWithRollback(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 	req := requests.CreateMemberRequest{FullName: "Test", Position: "Developer"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -158,7 +145,7 @@ func TestMemberGetAll_ValidationDefaultPagination(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	// Test dengan invalid values
 	_, _, _, _, err := service.GetAll(context.Background(), 0, -5, "")
@@ -176,7 +163,7 @@ func TestMemberGetAll_ErrorDatabaseFailed(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	_, _, _, _, err := service.GetAll(context.Background(), 1, 10, "")
 
@@ -207,7 +194,7 @@ func TestMemberGetAll_LogicLastPageCalculation(t *testing.T) {
 		}
 
 		mockCloudinary := &MockCloudinaryService{}
-		service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+		service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 		_, _, lastPage, _, err := service.GetAll(context.Background(), 1, tt.limit, "")
 
@@ -231,7 +218,7 @@ func TestMemberGetByID_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	_, err := service.GetByID(context.Background(), 999)
 
@@ -279,7 +266,7 @@ func TestMemberUpdate_LogicDeleteOldPhotoAfterSuccess(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 	req := requests.UpdateMemberRequest{FullName: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "new.jpg"}
 
@@ -307,7 +294,7 @@ func TestMemberUpdate_ErrorUploadFailed(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 	req := requests.UpdateMemberRequest{FullName: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -348,7 +335,7 @@ func TestMemberUpdate_ErrorDatabaseWithRollback(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 	req := requests.UpdateMemberRequest{FullName: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "new.jpg"}
 
@@ -374,7 +361,7 @@ func TestMemberUpdate_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	_, err := service.Update(context.Background(), 999, requests.UpdateMemberRequest{}, nil)
 
@@ -383,6 +370,35 @@ func TestMemberUpdate_ErrorNotFound(t *testing.T) {
 	}
 }
 
+// Test: Update berhasil harus mengirim event webhook member.updated, update gagal tidak
+func TestMemberUpdate_LogicPublishWebhookAfterSuccess(t *testing.T) {
+	updateErr := errors.New("database error")
+	mockRepo := &MockMemberRepository{
+		FindByIDFunc: func(id int) (*domain.Member, error) {
+			return &domain.Member{ID: id, FullName: "Lama"}, nil
+		},
+		UpdateFunc: func(member *domain.Member) error {
+			return updateErr
+		},
+	}
+
+	webhooks := &recordingWebhookPublisher{}
+	service := NewMemberService(mockRepo, &MockCloudinaryService{}, webhooks)
+
+	_, _ = service.Update(context.Background(), 1, requests.UpdateMemberRequest{FullName: "Baru"}, nil)
+	if len(webhooks.events) != 0 {
+		t.Fatalf("Expected no webhook event on failed update, got: %v", webhooks.events)
+	}
+
+	updateErr = nil
+	if _, err := service.Update(context.Background(), 1, requests.UpdateMemberRequest{FullName: "Baru"}, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(webhooks.events) != 1 || webhooks.events[0] != domain.WebhookEventMemberUpdated {
+		t.Errorf("Expected member.updated event, got: %v", webhooks.events)
+	}
+}
+
 // ==================== DELETE TESTS ====================
 
 // Test: Delete harus hapus record database dulu, BARU hapus foto
@@ -409,7 +425,7 @@ func TestMemberDelete_LogicDeleteDatabaseFirst(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	err := service.Delete(context.Background(), 1)
 
@@ -439,7 +455,7 @@ func TestMemberDelete_ErrorDatabaseFailed(t *testing.T) {
 		},
 	}
 
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	err := service.Delete(context.Background(), 1)
 
@@ -460,7 +476,7 @@ func TestMemberDelete_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewMemberService(mockRepo, mockCloudinary, &MockActivityLogRepoForMember{})
+	service := NewMemberService(mockRepo, mockCloudinary, nil)
 
 	err := service.Delete(context.Background(), 999)
 
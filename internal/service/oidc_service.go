@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/oidc"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// oidcStateTTL masa berlaku state login SSO (waktu user di halaman login provider)
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCProvider interface untuk client OpenID Connect (diimplementasikan oleh oidc.Provider)
+type OIDCProvider interface {
+	Config() oidc.Config
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.IDToken, error)
+}
+
+// OIDCAuthorization berisi URL redirect ke provider dan state yang harus dicocokkan frontend saat callback
+// StateBinding disimpan di cookie HttpOnly agar callback hanya diterima dari browser yang memulai login
+type OIDCAuthorization struct {
+	AuthorizationURL string
+	State            string
+	StateBinding     string
+	ExpiresIn        int // Masa berlaku state dalam detik
+}
+
+// OIDCService interface untuk business logic login SSO (OpenID Connect)
+type OIDCService interface {
+	Providers() []string
+	BeginLogin(ctx context.Context, provider string) (*OIDCAuthorization, error)
+	CompleteLogin(ctx context.Context, provider, code, state, stateBinding string) (*domain.User, *AuthTokens, error)
+}
+
+type oidcService struct {
+	providers       map[string]OIDCProvider
+	oidcRepo        repository.OIDCRepository
+	userRepo        repository.UserRepository
+	authService     AuthService
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewOIDCService constructor untuk OIDCService
+// Login akhir (status aktif, 2FA, session) didelegasikan ke AuthService agar sama dengan login password
+func NewOIDCService(providers []OIDCProvider, oidcRepo repository.OIDCRepository, userRepo repository.UserRepository, authService AuthService, activityLogRepo repository.ActivityLogRepository) OIDCService {
+	byName := make(map[string]OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Config().Name] = provider
+	}
+
+	return &oidcService{
+		providers:       byName,
+		oidcRepo:        oidcRepo,
+		userRepo:        userRepo,
+		authService:     authService,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// Providers mengembalikan nama provider SSO yang aktif (untuk tombol login di frontend)
+func (s *oidcService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin membuat state, nonce, dan PKCE verifier lalu mengembalikan URL login provider
+func (s *oidcService) BeginLogin(ctx context.Context, providerName string) (*OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+	verifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		log.Printf("[WARN] oidc: failed to build authorization URL for %s: %v", providerName, err)
+		return nil, ErrOIDCProviderUnavailable
+	}
+
+	stateHash := utils.HashToken(state)
+	loginState := &domain.OIDCLoginState{
+		Provider:     providerName,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := s.oidcRepo.CreateState(loginState); err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	return &OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+		StateBinding:     stateHash,
+		ExpiresIn:        int(oidcStateTTL.Seconds()),
+	}, nil
+}
+
+// CompleteLogin menukar authorization code, memverifikasi ID token, lalu memetakan email ke user
+// stateBinding adalah nilai cookie dari BeginLogin, harus cocok dengan state agar penyerang
+// tidak bisa menyodorkan code miliknya ke browser korban (login CSRF)
+func (s *oidcService) CompleteLogin(ctx context.Context, providerName, code, state, stateBinding string) (*domain.User, *AuthTokens, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, nil, ErrOIDCProviderNotFound
+	}
+
+	stateHash := utils.HashToken(state)
+	if subtle.ConstantTimeCompare([]byte(stateHash), []byte(stateBinding)) != 1 {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	// State sekali pakai, harus dari provider yang sama dan belum kadaluarsa
+	loginState, err := s.oidcRepo.ConsumeState(providerName, stateHash)
+	if err != nil {
+		return nil, nil, ErrOIDCLoginFailed
+	}
+	if loginState == nil {
+		return nil, nil, ErrOIDCInvalidState
+	}
+
+	idToken, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Printf("[WARN] oidc: %s login rejected: %v", providerName, err)
+		return nil, nil, ErrOIDCTokenInvalid
+	}
+
+	if idToken.Email == "" || !idToken.EmailVerified {
+		return nil, nil, ErrOIDCEmailNotVerified
+	}
+	if !isOIDCDomainAllowed(provider.Config().AllowedDomains, idToken) {
+		return nil, nil, ErrOIDCDomainNotAllowed
+	}
+
+	user, err := s.resolveUser(ctx, provider.Config(), idToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.authService.LoginExternal(ctx, user)
+}
+
+// resolveUser mencari user berdasarkan identitas yang sudah terhubung, lalu email terverifikasi,
+// dan membuat user baru sebagai author jika auto-provisioning aktif
+func (s *oidcService) resolveUser(ctx context.Context, config oidc.Config, idToken *oidc.IDToken) (*domain.User, error) {
+	identity, err := s.oidcRepo.FindIdentity(config.Name, idToken.Subject)
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+	if identity != nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOIDCAccountNotFound
+		}
+		if err != nil {
+			return nil, ErrOIDCLoginFailed
+		}
+		_ = s.oidcRepo.TouchIdentity(identity.ID, time.Now())
+		return user, nil
+	}
+
+	user, err := s.userRepo.FindByEmail(idToken.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOIDCLoginFailed
+	}
+	if err != nil {
+		if !config.AutoProvision {
+			return nil, ErrOIDCAccountNotFound
+		}
+		if user, err = s.provisionUser(ctx, config, idToken); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if err := s.oidcRepo.CreateIdentity(&domain.UserIdentity{
+		UserID:      user.ID,
+		Provider:    config.Name,
+		Subject:     idToken.Subject,
+		Email:       idToken.Email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Akun terhubung dengan SSO " + config.Name,
+		TargetID:    &user.ID,
+		NewValue: map[string]any{
+			"provider": config.Name,
+			"email":    idToken.Email,
+		},
+	})
+
+	return user, nil
+}
+
+// provisionUser membuat user author baru dari ID token, password acak (login hanya via SSO atau reset password)
+func (s *oidcService) provisionUser(ctx context.Context, config oidc.Config, idToken *oidc.IDToken) (*domain.User, error) {
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+	passwordHash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	fullName := strings.TrimSpace(idToken.Name)
+	if fullName == "" {
+		fullName = strings.Split(idToken.Email, "@")[0]
+	}
+
+	user := &domain.User{
+		Role:         domain.RoleAuthorID,
+		FullName:     fullName,
+		Email:        idToken.Email,
+		PasswordHash: passwordHash,
+		IsActive:     true,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, ErrOIDCLoginFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleAuth,
+		Description: "Akun author dibuat otomatis via SSO " + config.Name,
+		TargetID:    &user.ID,
+		NewValue: map[string]any{
+			"id":        user.ID,
+			"email":     user.Email,
+			"full_name": user.FullName,
+			"role":      user.Role,
+		},
+	})
+
+	return user, nil
+}
+
+// isOIDCDomainAllowed cek domain email (dan klaim hd Google Workspace) terhadap allowlist provider
+func isOIDCDomainAllowed(allowedDomains []string, idToken *oidc.IDToken) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+
+	emailDomain := idToken.Email[strings.LastIndex(idToken.Email, "@")+1:]
+	for _, allowed := range allowedDomains {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if emailDomain == allowed && (idToken.HostedDomain == "" || strings.EqualFold(idToken.HostedDomain, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// OIDC service errors
+var (
+	ErrOIDCProviderNotFound    = errors.New("provider SSO tidak ditemukan")
+	ErrOIDCProviderUnavailable = errors.New("provider SSO tidak dapat dihubungi")
+	ErrOIDCInvalidState        = errors.New("sesi login SSO tidak valid atau sudah kadaluarsa")
+	ErrOIDCTokenInvalid        = errors.New("login SSO gagal diverifikasi")
+	ErrOIDCEmailNotVerified    = errors.New("email akun SSO belum terverifikasi")
+	ErrOIDCDomainNotAllowed    = errors.New("domain email tidak diizinkan untuk login SSO")
+	ErrOIDCAccountNotFound     = errors.New("akun dengan email ini belum terdaftar")
+	ErrOIDCLoginFailed         = errors.New("gagal memproses login SSO")
+)
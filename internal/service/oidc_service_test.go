@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// mockOIDCServer provider OpenID Connect lokal: discovery, JWKS, dan token endpoint dengan PKCE
+type mockOIDCServer struct {
+	*httptest.Server
+	key   *rsa.PrivateKey
+	codes map[string]mockOIDCCode
+}
+
+type mockOIDCCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockOIDCServer{key: key, codes: map[string]mockOIDCCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		code, ok := m.codes[r.PostForm.Get("code")]
+		if !ok || oidc.CodeChallengeS256(r.PostForm.Get("code_verifier")) != code.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, code.claims)
+		token.Header["kid"] = "test-key"
+		idToken, _ := token.SignedString(key)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "token_type": "Bearer"})
+	})
+
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+// authorize mensimulasikan user login di provider, mengembalikan code untuk callback
+func (m *mockOIDCServer) authorize(t *testing.T, authURL string, claims jwt.MapClaims) string {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("Expected PKCE S256, got %q", query.Get("code_challenge_method"))
+	}
+
+	base := jwt.MapClaims{
+		"iss":   m.URL,
+		"aud":   "pmii-client",
+		"sub":   "google-123",
+		"email": "kader@pmii.id",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		base[k] = v
+	}
+
+	code := "code-" + query.Get("state")[:8]
+	m.codes[code] = mockOIDCCode{challenge: query.Get("code_challenge"), claims: base}
+	return code
+}
+
+// MockOIDCRepository menyimpan state dan identitas di memory
+type MockOIDCRepository struct {
+	states     map[string]*domain.OIDCLoginState
+	identities []domain.UserIdentity
+}
+
+func newMockOIDCRepository() *MockOIDCRepository {
+	return &MockOIDCRepository{states: map[string]*domain.OIDCLoginState{}}
+}
+
+func (m *MockOIDCRepository) CreateState(state *domain.OIDCLoginState) error {
+	m.states[state.StateHash] = state
+	return nil
+}
+
+func (m *MockOIDCRepository) ConsumeState(provider, stateHash string) (*domain.OIDCLoginState, error) {
+	state, ok := m.states[stateHash]
+	if !ok || state.Provider != provider || time.Now().After(state.ExpiresAt) {
+		return nil, nil
+	}
+	delete(m.states, stateHash)
+	return state, nil
+}
+
+func (m *MockOIDCRepository) FindIdentity(provider, subject string) (*domain.UserIdentity, error) {
+	for i := range m.identities {
+		if m.identities[i].Provider == provider && m.identities[i].Subject == subject {
+			return &m.identities[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCRepository) CreateIdentity(identity *domain.UserIdentity) error {
+	identity.ID = len(m.identities) + 1
+	m.identities = append(m.identities, *identity)
+	return nil
+}
+
+func (m *MockOIDCRepository) TouchIdentity(id int, at time.Time) error { return nil }
+
+// fakeExternalAuthService hanya mengimplementasikan LoginExternal
+type fakeExternalAuthService struct {
+	AuthService
+	loggedIn *domain.User
+}
+
+func (f *fakeExternalAuthService) LoginExternal(ctx context.Context, user *domain.User) (*domain.User, *AuthTokens, error) {
+	f.loggedIn = user
+	return user, &AuthTokens{AccessToken: "access"}, nil
+}
+
+func newTestOIDCService(server *mockOIDCServer, config oidc.Config, userRepo *MockUserRepositoryForUserService) (OIDCService, *MockOIDCRepository, *fakeExternalAuthService) {
+	config.Name = "google"
+	config.IssuerURL = server.URL
+	config.ClientID = "pmii-client"
+	config.RedirectURL = "http://localhost:3000/auth/sso/google/callback"
+
+	repo := newMockOIDCRepository()
+	auth := &fakeExternalAuthService{}
+	svc := NewOIDCService([]OIDCProvider{oidc.NewProvider(config)}, repo, userRepo, auth, &MockActivityLogRepoForAuth{})
+	return svc, repo, auth
+}
+
+// TestOIDCLogin_ExistingUser menguji login SSO memetakan email terverifikasi ke user yang ada dan menautkan identitas
+func TestOIDCLogin_ExistingUser(t *testing.T) {
+	server := newMockOIDCServer(t)
+	userRepo := &MockUserRepositoryForUserService{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			if email == "kader@pmii.id" {
+				return &domain.User{ID: 7, Email: email, IsActive: true}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, Email: "kader@pmii.id", IsActive: true}, nil
+		},
+	}
+	svc, repo, auth := newTestOIDCService(server, oidc.Config{}, userRepo)
+
+	authorization, err := svc.BeginLogin(context.Background(), "google")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	code := server.authorize(t, authorization.AuthorizationURL, jwt.MapClaims{"email": "Kader@PMII.id", "email_verified": true})
+
+	user, _, err := svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.ID != 7 || auth.loggedIn == nil || auth.loggedIn.ID != 7 {
+		t.Errorf("Expected login as user 7, got %+v", user)
+	}
+	if len(repo.identities) != 1 || repo.identities[0].Subject != "google-123" {
+		t.Errorf("Expected identity to be linked, got %+v", repo.identities)
+	}
+
+	// State sekali pakai
+	if _, _, err := svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding); !errors.Is(err, ErrOIDCInvalidState) {
+		t.Errorf("Expected ErrOIDCInvalidState on replay, got %v", err)
+	}
+
+	// Login berikutnya memakai identitas yang sudah terhubung walaupun email di provider berubah
+	authorization, _ = svc.BeginLogin(context.Background(), "google")
+	code = server.authorize(t, authorization.AuthorizationURL, jwt.MapClaims{"email": "baru@pmii.id", "email_verified": true})
+	if user, _, err := svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding); err != nil || user.ID != 7 {
+		t.Errorf("Expected linked identity to resolve user 7, got %v / %v", user, err)
+	}
+}
+
+// TestOIDCLogin_StateNotBoundToBrowser menguji callback ditolak jika cookie state dari browser lain atau tidak ada
+func TestOIDCLogin_StateNotBoundToBrowser(t *testing.T) {
+	server := newMockOIDCServer(t)
+	userRepo := &MockUserRepositoryForUserService{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return &domain.User{ID: 7, Email: email, IsActive: true}, nil
+		},
+	}
+	svc, _, auth := newTestOIDCService(server, oidc.Config{}, userRepo)
+
+	// Penyerang memulai login di browsernya sendiri, lalu mengirim code + state ke korban
+	attacker, _ := svc.BeginLogin(context.Background(), "google")
+	code := server.authorize(t, attacker.AuthorizationURL, jwt.MapClaims{"email_verified": true})
+	victim, _ := svc.BeginLogin(context.Background(), "google")
+
+	for _, binding := range []string{"", victim.StateBinding} {
+		if _, _, err := svc.CompleteLogin(context.Background(), "google", code, attacker.State, binding); !errors.Is(err, ErrOIDCInvalidState) {
+			t.Errorf("Expected ErrOIDCInvalidState for binding %q, got %v", binding, err)
+		}
+	}
+	if auth.loggedIn != nil {
+		t.Error("Expected no login")
+	}
+}
+
+// TestOIDCLogin_DatabaseError menguji error database tidak dianggap akun belum terdaftar atau memicu provisioning
+func TestOIDCLogin_DatabaseError(t *testing.T) {
+	server := newMockOIDCServer(t)
+	userRepo := &MockUserRepositoryForUserService{
+		FindByEmailFunc: func(email string) (*domain.User, error) { return nil, errors.New("connection refused") },
+		CreateFunc: func(user *domain.User) error {
+			t.Error("Expected no user to be provisioned on database error")
+			return nil
+		},
+	}
+	svc, _, _ := newTestOIDCService(server, oidc.Config{AutoProvision: true}, userRepo)
+
+	authorization, _ := svc.BeginLogin(context.Background(), "google")
+	code := server.authorize(t, authorization.AuthorizationURL, jwt.MapClaims{"email_verified": true})
+
+	if _, _, err := svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding); !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("Expected ErrOIDCLoginFailed, got %v", err)
+	}
+}
+
+// TestOIDCLogin_Rejected menguji penolakan: email belum verifikasi, domain, akun belum terdaftar, dan token tidak valid
+func TestOIDCLogin_Rejected(t *testing.T) {
+	server := newMockOIDCServer(t)
+	userRepo := &MockUserRepositoryForUserService{
+		FindByEmailFunc: func(email string) (*domain.User, error) { return nil, gorm.ErrRecordNotFound },
+	}
+
+	tests := []struct {
+		name     string
+		config   oidc.Config
+		claims   jwt.MapClaims
+		expected error
+	}{
+		{name: "email not verified", claims: jwt.MapClaims{"email_verified": false}, expected: ErrOIDCEmailNotVerified},
+		{name: "domain not allowed", config: oidc.Config{AllowedDomains: []string{"pmii.or.id"}}, claims: jwt.MapClaims{"email_verified": true}, expected: ErrOIDCDomainNotAllowed},
+		{name: "not registered", claims: jwt.MapClaims{"email_verified": true}, expected: ErrOIDCAccountNotFound},
+		{name: "wrong audience", claims: jwt.MapClaims{"email_verified": true, "aud": "other-client"}, expected: ErrOIDCTokenInvalid},
+		{name: "nonce mismatch", claims: jwt.MapClaims{"email_verified": true, "nonce": "forged"}, expected: ErrOIDCTokenInvalid},
+		{name: "expired", claims: jwt.MapClaims{"email_verified": true, "exp": time.Now().Add(-time.Hour).Unix()}, expected: ErrOIDCTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, auth := newTestOIDCService(server, tt.config, userRepo)
+
+			authorization, err := svc.BeginLogin(context.Background(), "google")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			code := server.authorize(t, authorization.AuthorizationURL, tt.claims)
+
+			_, _, err = svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if auth.loggedIn != nil {
+				t.Error("Expected no login")
+			}
+		})
+	}
+}
+
+// TestOIDCLogin_AutoProvision menguji pembuatan akun author otomatis untuk domain yang diizinkan
+func TestOIDCLogin_AutoProvision(t *testing.T) {
+	server := newMockOIDCServer(t)
+
+	var created *domain.User
+	userRepo := &MockUserRepositoryForUserService{
+		FindByEmailFunc: func(email string) (*domain.User, error) { return nil, gorm.ErrRecordNotFound },
+		CreateFunc: func(user *domain.User) error {
+			user.ID = 42
+			created = user
+			return nil
+		},
+	}
+	svc, _, _ := newTestOIDCService(server, oidc.Config{AutoProvision: true, AllowedDomains: []string{"pmii.id"}}, userRepo)
+
+	authorization, _ := svc.BeginLogin(context.Background(), "google")
+	code := server.authorize(t, authorization.AuthorizationURL, jwt.MapClaims{"email_verified": true, "name": "Kader Baru", "hd": "pmii.id"})
+
+	user, _, err := svc.CompleteLogin(context.Background(), "google", code, authorization.State, authorization.StateBinding)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created == nil || user.ID != 42 || created.Role != domain.RoleAuthorID || created.FullName != "Kader Baru" {
+		t.Errorf("Expected author account to be provisioned, got %+v", created)
+	}
+	if created.PasswordHash == "" {
+		t.Error("Expected provisioned user to have an unusable random password hash")
+	}
+}
+
+// TestOIDCLogin_UnknownProvider menguji provider yang tidak dikonfigurasi
+func TestOIDCLogin_UnknownProvider(t *testing.T) {
+	svc := NewOIDCService(nil, newMockOIDCRepository(), &MockUserRepositoryForUserService{}, &fakeExternalAuthService{}, &MockActivityLogRepoForAuth{})
+
+	if _, err := svc.BeginLogin(context.Background(), "github"); !errors.Is(err, ErrOIDCProviderNotFound) {
+		t.Errorf("Expected ErrOIDCProviderNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// passwordResetTokenTTL masa berlaku link reset password
+const passwordResetTokenTTL = time.Hour
+
+// MailerService interface untuk pengiriman email
+type MailerService interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// PasswordResetService interface untuk business logic reset password mandiri
+type PasswordResetService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type passwordResetService struct {
+	userRepo        repository.UserRepository
+	resetRepo       repository.PasswordResetRepository
+	sessionRepo     repository.SessionRepository
+	activityLogRepo repository.ActivityLogRepository
+	mailer          MailerService
+	frontendURL     string
+}
+
+// NewPasswordResetService constructor untuk PasswordResetService
+func NewPasswordResetService(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, sessionRepo repository.SessionRepository, activityLogRepo repository.ActivityLogRepository, mailer MailerService, frontendURL string) PasswordResetService {
+	return &passwordResetService{
+		userRepo:        userRepo,
+		resetRepo:       resetRepo,
+		sessionRepo:     sessionRepo,
+		activityLogRepo: activityLogRepo,
+		mailer:          mailer,
+		frontendURL:     strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// ForgotPassword membuat token reset dan mengirimkan link reset ke email user
+// Email yang tidak terdaftar/tidak aktif diabaikan tanpa error agar tidak membocorkan data akun
+func (s *passwordResetService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || !user.IsActive {
+		return nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return ErrResetTokenCreateFailed
+	}
+
+	// Hanya link terbaru yang berlaku
+	if err := s.resetRepo.InvalidateForUser(user.ID); err != nil {
+		return ErrResetTokenCreateFailed
+	}
+
+	resetToken := &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+	if ipAddress := utils.GetIPAddress(ctx); ipAddress != "" {
+		resetToken.IPAddress = &ipAddress
+	}
+
+	if err := s.resetRepo.Create(resetToken); err != nil {
+		return ErrResetTokenCreateFailed
+	}
+
+	// Kirim email di background agar waktu respons tidak membedakan email terdaftar/tidak
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, token)
+	go s.sendResetEmail(user, link)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Permintaan reset password melalui email",
+		TargetID:    &user.ID,
+	})
+
+	return nil
+}
+
+// ResetPassword memakai token reset untuk mengganti password dan mencabut semua session
+func (s *passwordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	resetToken, err := s.resetRepo.FindByTokenHash(utils.HashToken(token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	if resetToken.UsedAt != nil || !time.Now().Before(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(resetToken.UserID)
+	if err != nil || !user.IsActive {
+		return ErrInvalidResetToken
+	}
+
+	// Tandai terpakai lebih dulu agar token tidak bisa dipakai dua kali secara paralel
+	consumed, err := s.resetRepo.MarkUsed(resetToken.ID)
+	if err != nil || !consumed {
+		return ErrInvalidResetToken
+	}
+
+	newHash, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return ErrPasswordProcessing
+	}
+
+	user.PasswordHash = newHash
+	if err := s.userRepo.Update(user); err != nil {
+		return ErrUserUpdateFailed
+	}
+
+	// Semua session dan token lama tidak berlaku lagi
+	_ = s.sessionRepo.RevokeAllForUser(user.ID)
+	revokeAllUserTokens(user.ID)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      user.ID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Password direset melalui link email",
+		TargetID:    &user.ID,
+	})
+
+	return nil
+}
+
+// sendResetEmail mengirim email berisi link reset password
+func (s *passwordResetService) sendResetEmail(user *domain.User, link string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	body := fmt.Sprintf(`Halo %s,
+
+Kami menerima permintaan untuk mereset password akun Anda. Buka link berikut untuk membuat password baru:
+
+%s
+
+Link ini berlaku selama %d menit dan hanya dapat digunakan satu kali.
+Jika Anda tidak meminta reset password, abaikan email ini.`, user.FullName, link, int(passwordResetTokenTTL.Minutes()))
+
+	if err := s.mailer.Send(ctx, user.Email, "Reset Password Akun PMII", body); err != nil {
+		fmt.Printf("[WARN] sendResetEmail: failed to send reset email to user %d: %v\n", user.ID, err)
+	}
+}
+
+// Password reset service errors
+var (
+	ErrInvalidResetToken      = errors.New("token reset password tidak valid atau kadaluarsa")
+	ErrResetTokenCreateFailed = errors.New("gagal membuat token reset password")
+)
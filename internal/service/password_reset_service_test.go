@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// MockPasswordResetRepository adalah mock untuk PasswordResetRepository
+type MockPasswordResetRepository struct {
+	CreateFunc            func(token *domain.PasswordResetToken) error
+	FindByTokenHashFunc   func(hash string) (*domain.PasswordResetToken, error)
+	MarkUsedFunc          func(id int) (bool, error)
+	InvalidateForUserFunc func(userID int) error
+}
+
+func (m *MockPasswordResetRepository) Create(token *domain.PasswordResetToken) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(token)
+	}
+	return nil
+}
+
+func (m *MockPasswordResetRepository) FindByTokenHash(hash string) (*domain.PasswordResetToken, error) {
+	if m.FindByTokenHashFunc != nil {
+		return m.FindByTokenHashFunc(hash)
+	}
+	return nil, errors.New("mock not configured")
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(id int) (bool, error) {
+	if m.MarkUsedFunc != nil {
+		return m.MarkUsedFunc(id)
+	}
+	return true, nil
+}
+
+func (m *MockPasswordResetRepository) InvalidateForUser(userID int) error {
+	if m.InvalidateForUserFunc != nil {
+		return m.InvalidateForUserFunc(userID)
+	}
+	return nil
+}
+
+// MockMailer adalah mock untuk MailerService, email terkirim diteruskan ke channel
+type MockMailer struct {
+	Sent chan string
+}
+
+func (m *MockMailer) Send(ctx context.Context, to, subject, body string) error {
+	if m.Sent != nil {
+		m.Sent <- body
+	}
+	return nil
+}
+
+// TestForgotPassword_UnknownEmail menguji email tidak terdaftar tidak menghasilkan error maupun token
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+	userRepo := &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+	resetRepo := &MockPasswordResetRepository{
+		CreateFunc: func(token *domain.PasswordResetToken) error {
+			t.Error("Expected no reset token to be created")
+			return nil
+		},
+	}
+
+	svc := NewPasswordResetService(userRepo, resetRepo, &MockSessionRepository{}, &MockActivityLogRepoForAuth{}, &MockMailer{}, "http://localhost:3000")
+	if err := svc.ForgotPassword(context.Background(), "unknown@example.com"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// TestForgotPassword_SendsHashedToken menguji token disimpan dalam bentuk hash dan link dikirim via email
+func TestForgotPassword_SendsHashedToken(t *testing.T) {
+	userRepo := &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) {
+			return &domain.User{ID: 3, Email: email, FullName: "Test", IsActive: true}, nil
+		},
+	}
+
+	var stored *domain.PasswordResetToken
+	resetRepo := &MockPasswordResetRepository{
+		CreateFunc: func(token *domain.PasswordResetToken) error {
+			stored = token
+			return nil
+		},
+	}
+	mailer := &MockMailer{Sent: make(chan string, 1)}
+
+	svc := NewPasswordResetService(userRepo, resetRepo, &MockSessionRepository{}, &MockActivityLogRepoForAuth{}, mailer, "http://localhost:3000/")
+	if err := svc.ForgotPassword(context.Background(), "test@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var body string
+	select {
+	case body = <-mailer.Sent:
+	case <-time.After(time.Second):
+		t.Fatal("Expected reset email to be sent")
+	}
+
+	prefix := "http://localhost:3000/reset-password?token="
+	idx := strings.Index(body, prefix)
+	if idx < 0 {
+		t.Fatalf("Expected reset link in email body, got %q", body)
+	}
+	token := strings.Fields(body[idx+len(prefix):])[0]
+
+	if stored == nil || stored.TokenHash != utils.HashToken(token) {
+		t.Error("Expected emailed token to be stored hashed")
+	}
+}
+
+// TestResetPassword_UsedToken menguji token yang sudah dipakai ditolak
+func TestResetPassword_UsedToken(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+	resetRepo := &MockPasswordResetRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.PasswordResetToken, error) {
+			return &domain.PasswordResetToken{ID: 1, UserID: 3, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil
+		},
+	}
+
+	svc := NewPasswordResetService(&MockUserRepository{}, resetRepo, &MockSessionRepository{}, &MockActivityLogRepoForAuth{}, &MockMailer{}, "")
+	err := svc.ResetPassword(context.Background(), "token", "newpass123!")
+
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("Expected ErrInvalidResetToken, got %v", err)
+	}
+}
+
+// TestResetPassword_Success menguji reset password mengganti hash dan mencabut semua session
+func TestResetPassword_Success(t *testing.T) {
+	resetRepo := &MockPasswordResetRepository{
+		FindByTokenHashFunc: func(hash string) (*domain.PasswordResetToken, error) {
+			return &domain.PasswordResetToken{ID: 1, UserID: 3, ExpiresAt: time.Now().Add(time.Hour)}, nil
+		},
+	}
+
+	updated := false
+	userRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, PasswordHash: "oldhash", IsActive: true}, nil
+		},
+		UpdateFunc: func(user *domain.User) error {
+			updated = user.PasswordHash != "oldhash"
+			return nil
+		},
+	}
+
+	revokedUserID := 0
+	sessionRepo := &MockSessionRepository{
+		RevokeAllFunc: func(userID int) error {
+			revokedUserID = userID
+			return nil
+		},
+	}
+
+	svc := NewPasswordResetService(userRepo, resetRepo, sessionRepo, &MockActivityLogRepoForAuth{}, &MockMailer{}, "")
+	if err := svc.ResetPassword(context.Background(), "token", "newpass123!"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !updated {
+		t.Error("Expected password hash to be updated")
+	}
+	if revokedUserID != 3 {
+		t.Errorf("Expected sessions of user 3 to be revoked, got %d", revokedUserID)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"math"
 	"strings"
 	"time"
@@ -11,25 +12,39 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
 )
 
 type PostService interface {
 	GetAllPosts(page, limit int, search string) ([]responses.PostResponse, int, int64, error)
+	GetMyPosts(ctx context.Context, page, limit int, search string) ([]responses.PostResponse, int, int64, error)
 	CreatePost(ctx context.Context, req requests.PostCreateRequest) (responses.PostResponse, error)
 	UpdatePost(ctx context.Context, id string, req requests.PostUpdateRequest) (responses.PostResponse, error)
 	DeletePost(ctx context.Context, id string) error
 	GetPostDetail(id string, ip, ua string) (responses.PostResponse, error)
+	GetDrafts(page, limit int, search string) ([]responses.PostResponse, int, int64, error)
+	GetDraft(id string) (responses.PostResponse, error)
+	TransferOwnership(ctx context.Context, id string, newOwnerID int) (responses.PostResponse, error)
 }
 
 type postService struct {
-	repo            repository.PostRepository
-	activityLogRepo repository.ActivityLogRepository
+	repo          repository.PostRepository
+	userRepo      repository.UserRepository
+	webhooks      WebhookPublisher
+	visitorHasher VisitorHasher
+	geoLocator    GeoLocator
 }
 
-func NewPostService(repo repository.PostRepository, activityLogRepo repository.ActivityLogRepository) PostService {
+// webhooks boleh nil jika event post tidak perlu dikirim ke webhook
+// visitorHasher dipakai agar post_views hanya menyimpan hash IP, bukan IP mentah
+// geoLocator boleh nil jika geolokasi tidak diaktifkan
+func NewPostService(repo repository.PostRepository, userRepo repository.UserRepository, webhooks WebhookPublisher, visitorHasher VisitorHasher, geoLocator GeoLocator) PostService {
 	return &postService{
-		repo:            repo,
-		activityLogRepo: activityLogRepo,
+		repo:          repo,
+		userRepo:      userRepo,
+		webhooks:      orNoopWebhookPublisher(webhooks),
+		visitorHasher: visitorHasher,
+		geoLocator:    orNoopGeoLocator(geoLocator),
 	}
 }
 
@@ -49,8 +64,78 @@ func (s *postService) GetAllPosts(page, limit int, search string) ([]responses.P
 	return responses.FromDomainListToPostResponse(posts), lastPage, total, nil
 }
 
+// GetMyPosts mengambil semua post milik user yang sedang login, termasuk draft
+func (s *postService) GetMyPosts(ctx context.Context, page, limit int, search string) ([]responses.PostResponse, int, int64, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, 0, 0, ErrPostAuthorRequired
+	}
+
+	// Set default values
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	posts, total, err := s.repo.FindAllByUser(userID, offset, limit, search)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return responses.FromDomainListToPostResponse(posts), lastPage, total, nil
+}
+
+// GetDrafts mengambil draft dari semua penulis untuk editor dan integrasi (API key news:read)
+func (s *postService) GetDrafts(page, limit int, search string) ([]responses.PostResponse, int, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	posts, total, err := s.repo.FindAllByStatus(domain.PostStatusDraft, offset, limit, search)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	return responses.FromDomainListToPostResponse(posts), lastPage, total, nil
+}
+
+// GetDraft mengambil satu draft tanpa mencatat view, post yang sudah terbit dianggap tidak ditemukan
+func (s *postService) GetDraft(id string) (responses.PostResponse, error) {
+	post, err := s.findPost(id)
+	if err != nil {
+		return responses.PostResponse{}, err
+	}
+	if post.Status != domain.PostStatusDraft {
+		return responses.PostResponse{}, ErrPostNotFound
+	}
+	return responses.FromDomainToPostResponse(post), nil
+}
+
 // 2. CREATE POST
 func (s *postService) CreatePost(ctx context.Context, req requests.PostCreateRequest) (responses.PostResponse, error) {
+	// Post selalu dimiliki user yang membuatnya
+	userID, ok := utils.GetUserID(ctx)
+	if !ok || userID == 0 {
+		return responses.PostResponse{}, ErrPostAuthorRequired
+	}
+
 	var featuredImage *string
 
 	// Logika Upload Gambar ke Cloudinary
@@ -71,12 +156,6 @@ func (s *postService) CreatePost(ctx context.Context, req requests.PostCreateReq
 		excerptText = excerptText[:150] + "..."
 	}
 
-	// Get user ID from context
-	userID, _ := utils.GetUserID(ctx)
-	if userID == 0 {
-		userID = 1 // Default Admin if not in context
-	}
-
 	publishedTime := time.Now()
 	post := domain.Post{
 		Title:         req.Title,
@@ -90,20 +169,15 @@ func (s *postService) CreatePost(ctx context.Context, req requests.PostCreateReq
 		Tags:          s.processTags(req.Tags), // Logika Many-to-Many Tags
 	}
 
-	if err := s.repo.Create(&post); err != nil {
+	if err := s.repo.WithContext(ctx).Create(&post); err != nil {
 		return responses.PostResponse{}, err
 	}
 
 	// Reload data untuk mendapatkan relasi Category & Tags lengkap
 	updatedPost, _ := s.repo.FindByID(post.ID)
 
-	// Log activity - Create Post
-	s.logActivity(ctx, domain.ActionCreate, domain.ModulePost, "Membuat post baru: "+post.Title, nil, map[string]any{
-		"id":          post.ID,
-		"title":       post.Title,
-		"slug":        post.Slug,
-		"category_id": post.CategoryID,
-	}, &post.ID)
+	// Post baru berstatus draft secara default, event hanya dikirim jika post langsung terbit
+	s.publishPostEvent(ctx, false, updatedPost)
 
 	return responses.FromDomainToPostResponse(updatedPost), nil
 }
@@ -116,17 +190,25 @@ func (s *postService) GetPostDetail(id string, ip, ua string) (responses.PostRes
 		return responses.PostResponse{}, err
 	}
 
-	// 2. Logika Anti-Spam: Cek apakah IP sudah melihat dalam 24 jam terakhir
+	// 2. Logika Anti-Spam: Cek apakah visitor sudah melihat dalam 24 jam terakhir
+	// IP tidak disimpan, hanya hash dengan salt harian sehingga pengecekan berlaku sampai salt berganti
+	visitorHash, err := s.visitorHasher.HashIP(ip)
+	if err != nil {
+		// View tidak dicatat, detail berita tetap dikirim
+		return responses.FromDomainToPostResponse(post), nil
+	}
+
 	since := time.Now().Add(-24 * time.Hour)
-	hasViewed, _ := s.repo.HasViewed(post.ID, ip, since)
+	hasViewed, _ := s.repo.HasViewed(post.ID, visitorHash, since)
 
 	if !hasViewed {
 		newView := domain.PostView{
-			PostID:    post.ID,
-			IPAddress: &ip,
-			UserAgent: &ua,
-			ViewedAt:  time.Now(),
+			PostID:      post.ID,
+			VisitorHash: &visitorHash,
+			UserAgent:   &ua,
+			ViewedAt:    time.Now(),
 		}
+		newView.Country, newView.Region, newView.City = locateIP(s.geoLocator, ip)
 
 		// Simpan view baru ke database
 		if errAdd := s.repo.AddView(&newView); errAdd == nil {
@@ -144,17 +226,15 @@ func (s *postService) GetPostDetail(id string, ip, ua string) (responses.PostRes
 // 4. UPDATE POST
 func (s *postService) UpdatePost(ctx context.Context, id string, req requests.PostUpdateRequest) (responses.PostResponse, error) {
 	// Cari data eksisting
-	post, err := s.repo.FindBySlugOrID(id)
+	post, err := s.findPost(id)
 	if err != nil {
 		return responses.PostResponse{}, err
 	}
 
-	// Store old values for audit
-	oldValues := map[string]any{
-		"title":       post.Title,
-		"slug":        post.Slug,
-		"category_id": post.CategoryID,
+	if err := s.authorizeOwner(ctx, post); err != nil {
+		return responses.PostResponse{}, err
 	}
+	wasPublished := post.Status == domain.PostStatusPublished
 
 	// Update field jika dikirim
 	if req.Title != "" {
@@ -191,42 +271,123 @@ func (s *postService) UpdatePost(ctx context.Context, id string, req requests.Po
 		}
 	}
 
-	if err := s.repo.Update(&post); err != nil {
+	if err := s.repo.WithContext(ctx).Update(&post); err != nil {
 		return responses.PostResponse{}, err
 	}
 
 	// Reload untuk response DTO terbaru
 	updatedPost, _ := s.repo.FindByID(post.ID)
-
-	// Log activity - Update Post
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModulePost, "Mengupdate post: "+post.Title, oldValues, map[string]any{
-		"id":          post.ID,
-		"title":       post.Title,
-		"slug":        post.Slug,
-		"category_id": post.CategoryID,
-	}, &post.ID)
+	s.publishPostEvent(ctx, wasPublished, updatedPost)
 
 	return responses.FromDomainToPostResponse(updatedPost), nil
 }
 
 // 5. DELETE POST
 func (s *postService) DeletePost(ctx context.Context, id string) error {
-	post, err := s.repo.FindBySlugOrID(id)
+	post, err := s.findPost(id)
 	if err != nil {
 		return err
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModulePost, "Menghapus post: "+post.Title, map[string]any{
-		"id":          post.ID,
-		"title":       post.Title,
-		"slug":        post.Slug,
-		"category_id": post.CategoryID,
-	}, nil, &post.ID)
+	if err := s.authorizeOwner(ctx, post); err != nil {
+		return err
+	}
+
+	if err := s.repo.WithContext(ctx).Delete(&post, false); err != nil { // Soft delete sesuai domain.go
+		return err
+	}
+
+	// Draft tidak pernah dikirim ke subscriber, jadi penghapusannya juga tidak
+	if post.Status == domain.PostStatusPublished {
+		s.webhooks.Publish(ctx, domain.WebhookEventPostDeleted, map[string]any{"id": post.ID, "slug": post.Slug, "title": post.Title})
+	}
+	return nil
+}
+
+// publishPostEvent mengirim event webhook untuk post yang terbit, draft dan arsip tidak dikirim ke subscriber
+// post.published hanya dikirim saat status berubah menjadi published, perubahan berikutnya dikirim sebagai post.updated
+func (s *postService) publishPostEvent(ctx context.Context, wasPublished bool, post domain.Post) {
+	if post.Status != domain.PostStatusPublished {
+		return
+	}
+
+	event := domain.WebhookEventPostUpdated
+	if !wasPublished {
+		event = domain.WebhookEventPostPublished
+	}
+	s.webhooks.Publish(ctx, event, responses.FromDomainToPostResponse(post))
+}
+
+// 6. TRANSFER OWNERSHIP (Admin)
+func (s *postService) TransferOwnership(ctx context.Context, id string, newOwnerID int) (responses.PostResponse, error) {
+	post, err := s.findPost(id)
+	if err != nil {
+		return responses.PostResponse{}, err
+	}
+
+	// Hanya role dengan posts.manage_any, pemilik post pun tidak bisa memindahkannya sendiri
+	if !utils.HasPermission(utils.GetUserRole(ctx), domain.PermPostsManageAny) {
+		return responses.PostResponse{}, ErrPostForbidden
+	}
+
+	newOwner, err := s.userRepo.FindByID(newOwnerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return responses.PostResponse{}, ErrUserNotFound
+	}
+	if err != nil {
+		return responses.PostResponse{}, err
+	}
+	if !newOwner.IsActive {
+		return responses.PostResponse{}, ErrPostOwnerInactive
+	}
+
+	oldOwnerID := post.UserID
+	if oldOwnerID != newOwner.ID {
+		if err := s.repo.WithContext(ctx).UpdateOwner(post.ID, newOwner.ID); err != nil {
+			return responses.PostResponse{}, ErrPostUpdateFailed
+		}
+	}
+
+	updatedPost, err := s.repo.FindByID(post.ID)
+	if err != nil {
+		return responses.PostResponse{}, err
+	}
+
+	return responses.FromDomainToPostResponse(updatedPost), nil
+}
+
+// findPost mencari post berdasarkan ID/slug dan memetakan record not found ke ErrPostNotFound
+func (s *postService) findPost(id string) (domain.Post, error) {
+	post, err := s.repo.FindBySlugOrID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Post{}, ErrPostNotFound
+	}
+	return post, err
+}
 
-	return s.repo.Delete(&post, false) // Soft delete sesuai domain.go
+// authorizeOwner memastikan user yang login adalah pemilik post,
+// kecuali role-nya memiliki permission posts.manage_any (admin)
+func (s *postService) authorizeOwner(ctx context.Context, post domain.Post) error {
+	if utils.HasPermission(utils.GetUserRole(ctx), domain.PermPostsManageAny) {
+		return nil
+	}
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok || userID != post.UserID {
+		return ErrPostForbidden
+	}
+	return nil
 }
 
+// Post service errors
+var (
+	ErrPostNotFound       = errors.New("berita tidak ditemukan")
+	ErrPostForbidden      = errors.New("anda hanya dapat mengubah atau menghapus berita milik sendiri")
+	ErrPostAuthorRequired = errors.New("penulis berita tidak diketahui")
+	ErrPostOwnerInactive  = errors.New("pemilik baru harus user yang aktif")
+	ErrPostUpdateFailed   = errors.New("gagal memperbarui berita")
+)
+
 // HELPER: Proses String Tags menjadi Domain Entities (FirstOrCreate)
 func (s *postService) processTags(tagsInput string) []domain.Tag {
 	var tags []domain.Tag
@@ -249,37 +410,3 @@ func (s *postService) processTags(tagsInput string) []domain.Tag {
 	}
 	return tags
 }
-
-// logActivity helper untuk mencatat activity log
-func (s *postService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
-}
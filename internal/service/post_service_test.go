@@ -1,7 +1,16 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
 )
 
 func TestCalculatePagination(t *testing.T) {
@@ -28,3 +37,276 @@ func TestCalculatePagination(t *testing.T) {
 		}
 	}
 }
+
+// MockPostRepository adalah mock untuk PostRepository
+type MockPostRepository struct {
+	FindBySlugOrIDFunc func(identifier string) (domain.Post, error)
+	DeleteFunc         func(post *domain.Post, unscoped bool) error
+	UpdateOwnerFunc    func(postID, userID int) error
+	FindAllByUserFunc  func(userID, offset, limit int, search string) ([]domain.Post, int64, error)
+	FindByIDFunc       func(id int) (domain.Post, error)
+}
+
+func (m *MockPostRepository) FindAllByStatus(status domain.PostStatus, offset, limit int, search string) ([]domain.Post, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockPostRepository) FindBySlugOrID(identifier string) (domain.Post, error) {
+	if m.FindBySlugOrIDFunc != nil {
+		return m.FindBySlugOrIDFunc(identifier)
+	}
+	return domain.Post{}, errors.New("mock not configured")
+}
+
+func (m *MockPostRepository) Delete(post *domain.Post, unscoped bool) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(post, unscoped)
+	}
+	return nil
+}
+
+func (m *MockPostRepository) UpdateOwner(postID, userID int) error {
+	if m.UpdateOwnerFunc != nil {
+		return m.UpdateOwnerFunc(postID, userID)
+	}
+	return nil
+}
+
+// Stub methods (interface requirement)
+func (m *MockPostRepository) FindAll(offset, limit int, search string) ([]domain.Post, int64, error) {
+	return nil, 0, nil
+}
+func (m *MockPostRepository) FindAllByUser(userID, offset, limit int, search string) ([]domain.Post, int64, error) {
+	if m.FindAllByUserFunc != nil {
+		return m.FindAllByUserFunc(userID, offset, limit, search)
+	}
+	return nil, 0, nil
+}
+func (m *MockPostRepository) FindByID(id int) (domain.Post, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return domain.Post{ID: id}, nil
+}
+func (m *MockPostRepository) Create(post *domain.Post) error      { return nil }
+func (m *MockPostRepository) Update(post *domain.Post) error      { return nil }
+func (m *MockPostRepository) AddView(view *domain.PostView) error { return nil }
+func (m *MockPostRepository) GetTagBySlug(slug string, name string) (domain.Tag, error) {
+	return domain.Tag{}, nil
+}
+func (m *MockPostRepository) HasViewed(postID int, visitorHash string, since time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockPostRepository) WithContext(ctx context.Context) repository.PostRepository {
+	return m
+}
+
+// initPostPermissions menyiapkan permission store: role 1 (admin) boleh kelola post siapa pun
+func initPostPermissions() {
+	utils.InitPermissionStore(&MockRoleRepository{
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			if roleID == domain.RoleAdminID {
+				return "admin", []string{domain.PermPostsDelete, domain.PermPostsManageAny}, nil
+			}
+			return "author", []string{domain.PermPostsDelete}, nil
+		},
+	})
+}
+
+func ctxAs(userID int, role string) context.Context {
+	return utils.WithUserRole(utils.WithUserID(context.Background(), userID), role)
+}
+
+// TestDeletePost_Ownership menguji author hanya bisa menghapus post miliknya, admin bisa semua
+func TestDeletePost_Ownership(t *testing.T) {
+	initPostPermissions()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected error
+	}{
+		{name: "owner author", ctx: ctxAs(5, "2"), expected: nil},
+		{name: "other author", ctx: ctxAs(6, "2"), expected: ErrPostForbidden},
+		{name: "admin", ctx: ctxAs(1, "1"), expected: nil},
+		{name: "no user", ctx: context.Background(), expected: ErrPostForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			repo := &MockPostRepository{
+				FindBySlugOrIDFunc: func(identifier string) (domain.Post, error) {
+					return domain.Post{ID: 10, UserID: 5, Title: "Post Author"}, nil
+				},
+				DeleteFunc: func(post *domain.Post, unscoped bool) error {
+					deleted = true
+					return nil
+				},
+			}
+
+			svc := NewPostService(repo, &MockUserRepository{}, nil, nil, nil)
+			err := svc.DeletePost(tt.ctx, "10")
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if deleted != (tt.expected == nil) {
+				t.Errorf("Expected deleted=%v, got %v", tt.expected == nil, deleted)
+			}
+		})
+	}
+}
+
+// TestCreatePost_RequiresAuthor menguji post tidak dibuat atas nama admin default jika user tidak ada di context
+func TestCreatePost_RequiresAuthor(t *testing.T) {
+	svc := NewPostService(&MockPostRepository{}, &MockUserRepository{}, nil, nil, nil)
+
+	_, err := svc.CreatePost(context.Background(), requests.PostCreateRequest{Title: "Judul", Content: "Isi", CategoryID: 1})
+	if !errors.Is(err, ErrPostAuthorRequired) {
+		t.Errorf("Expected ErrPostAuthorRequired, got %v", err)
+	}
+}
+
+// TestPostWebhooks_SkipDrafts menguji draft tidak pernah dikirim ke webhook, post.published hanya saat post terbit
+func TestPostWebhooks_SkipDrafts(t *testing.T) {
+	initPostPermissions()
+
+	status := domain.PostStatusDraft
+	repo := &MockPostRepository{
+		FindBySlugOrIDFunc: func(identifier string) (domain.Post, error) {
+			return domain.Post{ID: 10, UserID: 5, Title: "Rahasia", Status: status}, nil
+		},
+		FindByIDFunc: func(id int) (domain.Post, error) {
+			return domain.Post{ID: id, UserID: 5, Title: "Rahasia", Status: status}, nil
+		},
+	}
+	publisher := &recordingWebhookPublisher{}
+	svc := NewPostService(repo, &MockUserRepository{}, publisher, nil, nil)
+	ctx := ctxAs(5, "2")
+
+	if _, err := svc.CreatePost(ctx, requests.PostCreateRequest{Title: "Rahasia", Content: "Isi", CategoryID: 1}); err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	if _, err := svc.UpdatePost(ctx, "10", requests.PostUpdateRequest{Content: "Isi baru"}); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+	if err := svc.DeletePost(ctx, "10"); err != nil {
+		t.Fatalf("DeletePost: %v", err)
+	}
+	if len(publisher.events) != 0 {
+		t.Fatalf("Expected no webhook for draft, got %v", publisher.events)
+	}
+
+	// Draft yang terbit lewat update dikirim sebagai post.published
+	repo.FindByIDFunc = func(id int) (domain.Post, error) {
+		return domain.Post{ID: id, UserID: 5, Status: domain.PostStatusPublished}, nil
+	}
+	if _, err := svc.UpdatePost(ctx, "10", requests.PostUpdateRequest{Content: "Isi baru"}); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+
+	// Post yang sudah terbit dikirim sebagai post.updated
+	status = domain.PostStatusPublished
+	if _, err := svc.UpdatePost(ctx, "10", requests.PostUpdateRequest{Content: "Isi lagi"}); err != nil {
+		t.Fatalf("UpdatePost: %v", err)
+	}
+
+	expected := []string{domain.WebhookEventPostPublished, domain.WebhookEventPostUpdated}
+	if len(publisher.events) != len(expected) || publisher.events[0] != expected[0] || publisher.events[1] != expected[1] {
+		t.Errorf("Expected events %v, got %v", expected, publisher.events)
+	}
+}
+
+// TestTransferOwnership menguji pemindahan kepemilikan post ke user aktif
+func TestTransferOwnership(t *testing.T) {
+	initPostPermissions()
+
+	var movedTo int
+	repo := &MockPostRepository{
+		FindBySlugOrIDFunc: func(identifier string) (domain.Post, error) {
+			return domain.Post{ID: 10, UserID: 5, Title: "Post Author"}, nil
+		},
+		UpdateOwnerFunc: func(postID, userID int) error {
+			movedTo = userID
+			return nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			switch id {
+			case 9:
+				return nil, gorm.ErrRecordNotFound
+			case 99:
+				return nil, errors.New("connection reset")
+			}
+			return &domain.User{ID: id, IsActive: id != 8}, nil
+		},
+	}
+	svc := NewPostService(repo, userRepo, nil, nil, nil)
+
+	if _, err := svc.TransferOwnership(ctxAs(1, "1"), "10", 8); !errors.Is(err, ErrPostOwnerInactive) {
+		t.Errorf("Expected ErrPostOwnerInactive for inactive user, got %v", err)
+	}
+	if _, err := svc.TransferOwnership(ctxAs(1, "1"), "10", 9); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound for missing user, got %v", err)
+	}
+	if _, err := svc.TransferOwnership(ctxAs(1, "1"), "10", 99); err == nil || errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected database error to be returned as is, got %v", err)
+	}
+	if _, err := svc.TransferOwnership(ctxAs(6, "2"), "10", 7); !errors.Is(err, ErrPostForbidden) {
+		t.Errorf("Expected ErrPostForbidden for author, got %v", err)
+	}
+
+	if _, err := svc.TransferOwnership(ctxAs(1, "1"), "10", 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if movedTo != 7 {
+		t.Errorf("Expected post to be moved to user 7, got %d", movedTo)
+	}
+}
+
+// TestGetMyPosts_Pagination menguji page dan limit tidak valid diganti nilai default
+func TestGetMyPosts_Pagination(t *testing.T) {
+	var gotOffset, gotLimit int
+	repo := &MockPostRepository{
+		FindAllByUserFunc: func(userID, offset, limit int, search string) ([]domain.Post, int64, error) {
+			gotOffset, gotLimit = offset, limit
+			return nil, 25, nil
+		},
+	}
+	svc := NewPostService(repo, &MockUserRepository{}, nil, nil, nil)
+
+	_, lastPage, _, err := svc.GetMyPosts(ctxAs(6, "2"), 0, 1000, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotOffset != 0 || gotLimit != 10 || lastPage != 3 {
+		t.Errorf("Expected offset 0, limit 10 and 3 pages, got offset %d limit %d pages %d", gotOffset, gotLimit, lastPage)
+	}
+}
+
+// TestGetDraft menguji detail draft hanya mengembalikan post berstatus draft
+func TestGetDraft(t *testing.T) {
+	repo := &MockPostRepository{
+		FindBySlugOrIDFunc: func(identifier string) (domain.Post, error) {
+			switch identifier {
+			case "1":
+				return domain.Post{ID: 1, Status: domain.PostStatusDraft}, nil
+			case "2":
+				return domain.Post{ID: 2, Status: domain.PostStatusPublished}, nil
+			}
+			return domain.Post{}, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewPostService(repo, &MockUserRepository{}, nil, nil, nil)
+
+	if res, err := svc.GetDraft("1"); err != nil || res.ID != 1 {
+		t.Errorf("Expected draft 1, got %+v / %v", res, err)
+	}
+	for _, id := range []string{"2", "3"} {
+		if _, err := svc.GetDraft(id); !errors.Is(err, ErrPostNotFound) {
+			t.Errorf("Expected ErrPostNotFound for post %s, got %v", id, err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
 // MockPublicMemberRepository adalah mock untuk MemberRepository (public methods)
@@ -68,6 +69,10 @@ func (m *MockPublicMemberRepository) FindActiveByDepartment(department string, p
 	return nil, 0, errors.New("mock not configured")
 }
 
+func (m *MockPublicMemberRepository) WithContext(ctx context.Context) repository.MemberRepository {
+	return m
+}
+
 // MockContactRepository adalah mock untuk ContactRepository
 type MockContactRepository struct {
 	GetFunc    func() (*domain.Contact, error)
@@ -88,6 +93,10 @@ func (m *MockContactRepository) Update(contact *domain.Contact) error {
 	return errors.New("mock not configured")
 }
 
+func (m *MockContactRepository) WithContext(ctx context.Context) repository.ContactRepository {
+	return m
+}
+
 // ==================== GET ABOUT PAGE TESTS ====================
 
 // Test: GetAboutPage berhasil dengan data lengkap
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// roleKeyPattern format key role: huruf kecil, angka dan underscore
+var roleKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// RoleService interface untuk business logic role dan permission
+type RoleService interface {
+	GetAll(ctx context.Context) ([]domain.Role, error)
+	GetPermissions(ctx context.Context) ([]domain.Permission, error)
+	Create(ctx context.Context, req *requests.CreateRoleRequest) (*domain.Role, error)
+	Update(ctx context.Context, id int, req *requests.UpdateRoleRequest) (*domain.Role, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type roleService struct {
+	roleRepo        repository.RoleRepository
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewRoleService constructor untuk RoleService
+func NewRoleService(roleRepo repository.RoleRepository, activityLogRepo repository.ActivityLogRepository) RoleService {
+	return &roleService{
+		roleRepo:        roleRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// GetAll mengambil semua role beserta permission-nya
+func (s *roleService) GetAll(ctx context.Context) ([]domain.Role, error) {
+	roles, err := s.roleRepo.FindAll()
+	if err != nil {
+		return nil, ErrRoleFetchFailed
+	}
+	return roles, nil
+}
+
+// GetPermissions mengambil semua permission yang bisa diberikan ke role
+func (s *roleService) GetPermissions(ctx context.Context) ([]domain.Permission, error) {
+	permissions, err := s.roleRepo.FindAllPermissions()
+	if err != nil {
+		return nil, ErrRoleFetchFailed
+	}
+	return permissions, nil
+}
+
+// Create membuat role baru dengan permission yang dipilih
+func (s *roleService) Create(ctx context.Context, req *requests.CreateRoleRequest) (*domain.Role, error) {
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if !roleKeyPattern.MatchString(key) {
+		return nil, ErrInvalidRoleKey
+	}
+
+	exists, err := s.roleRepo.ExistsByKey(key)
+	if err != nil {
+		return nil, ErrRoleSaveFailed
+	}
+	if exists {
+		return nil, ErrRoleKeyExists
+	}
+
+	permissions, err := s.resolvePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{
+		Key:         key,
+		Name:        req.Name,
+		Permissions: permissions,
+	}
+	if req.Description != "" {
+		role.Description = &req.Description
+	}
+
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, ErrRoleSaveFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionCreate,
+		Module:      domain.ModuleUser,
+		Description: "Membuat role baru: " + role.Name,
+		TargetID:    &role.ID,
+		NewValue: map[string]any{
+			"id":          role.ID,
+			"key":         role.Key,
+			"name":        role.Name,
+			"permissions": role.PermissionKeys(),
+		},
+	})
+
+	return role, nil
+}
+
+// Update mengubah nama, deskripsi dan/atau permission role
+// Role admin bawaan tidak bisa diubah agar selalu ada role dengan akses penuh
+func (s *roleService) Update(ctx context.Context, id int, req *requests.UpdateRoleRequest) (*domain.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if role.ID == domain.RoleAdminID {
+		return nil, ErrSystemRoleLocked
+	}
+
+	oldValues := map[string]any{
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": role.PermissionKeys(),
+	}
+
+	if req.Name != nil {
+		role.Name = *req.Name
+	}
+	if req.Description != nil {
+		role.Description = req.Description
+	}
+	if req.Permissions != nil {
+		permissions, err := s.resolvePermissions(*req.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = permissions
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, ErrRoleSaveFailed
+	}
+
+	// Permission baru langsung berlaku di instance ini, instance lain setelah cache expired
+	utils.InvalidateRolePermissions(role.ID)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleUser,
+		Description: "Mengupdate role: " + role.Name,
+		TargetID:    &role.ID,
+		OldValue:    oldValues,
+		NewValue: map[string]any{
+			"name":        role.Name,
+			"description": role.Description,
+			"permissions": role.PermissionKeys(),
+		},
+	})
+
+	return role, nil
+}
+
+// Delete menghapus role yang bukan bawaan dan tidak sedang dipakai user
+func (s *roleService) Delete(ctx context.Context, id int) error {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if role.IsSystem {
+		return ErrSystemRoleLocked
+	}
+
+	users, err := s.roleRepo.CountUsers(id)
+	if err != nil {
+		return ErrRoleFetchFailed
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return ErrRoleDeleteFailed
+	}
+
+	utils.InvalidateRolePermissions(id)
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		Action:      domain.ActionDelete,
+		Module:      domain.ModuleUser,
+		Description: "Menghapus role: " + role.Name,
+		TargetID:    &role.ID,
+		OldValue: map[string]any{
+			"id":          role.ID,
+			"key":         role.Key,
+			"name":        role.Name,
+			"permissions": role.PermissionKeys(),
+		},
+	})
+
+	return nil
+}
+
+// resolvePermissions mengubah daftar key permission menjadi domain.Permission
+// Key yang tidak dikenal ditolak agar typo tidak diam-diam diabaikan
+func (s *roleService) resolvePermissions(keys []string) ([]domain.Permission, error) {
+	unique := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, key)
+	}
+
+	permissions, err := s.roleRepo.FindPermissionsByKeys(unique)
+	if err != nil {
+		return nil, ErrRoleFetchFailed
+	}
+	if len(permissions) != len(unique) {
+		return nil, ErrUnknownPermission
+	}
+
+	return permissions, nil
+}
+
+// Role service errors
+var (
+	ErrRoleNotFound      = errors.New("role tidak ditemukan")
+	ErrRoleFetchFailed   = errors.New("gagal mengambil data role")
+	ErrRoleSaveFailed    = errors.New("gagal menyimpan role")
+	ErrRoleDeleteFailed  = errors.New("gagal menghapus role")
+	ErrRoleKeyExists     = errors.New("key role sudah digunakan")
+	ErrInvalidRoleKey    = errors.New("key role hanya boleh huruf kecil, angka dan underscore")
+	ErrUnknownPermission = errors.New("permission tidak dikenal")
+	ErrSystemRoleLocked  = errors.New("role bawaan sistem tidak dapat diubah atau dihapus")
+	ErrRoleInUse         = errors.New("role masih digunakan oleh user")
+)
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// MockRoleRepository adalah mock untuk RoleRepository
+type MockRoleRepository struct {
+	FindByIDFunc              func(id int) (*domain.Role, error)
+	ExistsByKeyFunc           func(key string) (bool, error)
+	CreateFunc                func(role *domain.Role) error
+	UpdateFunc                func(role *domain.Role) error
+	DeleteFunc                func(id int) error
+	CountUsersFunc            func(roleID int) (int64, error)
+	FindPermissionsByKeysFunc func(keys []string) ([]domain.Permission, error)
+	GetRolePermissionKeysFunc func(roleID int) (string, []string, error)
+}
+
+func (m *MockRoleRepository) FindByID(id int) (*domain.Role, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return &domain.Role{ID: id}, nil
+}
+
+func (m *MockRoleRepository) ExistsByKey(key string) (bool, error) {
+	if m.ExistsByKeyFunc != nil {
+		return m.ExistsByKeyFunc(key)
+	}
+	return false, nil
+}
+
+func (m *MockRoleRepository) Create(role *domain.Role) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(role)
+	}
+	return nil
+}
+
+func (m *MockRoleRepository) Update(role *domain.Role) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(role)
+	}
+	return nil
+}
+
+func (m *MockRoleRepository) Delete(id int) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockRoleRepository) CountUsers(roleID int) (int64, error) {
+	if m.CountUsersFunc != nil {
+		return m.CountUsersFunc(roleID)
+	}
+	return 0, nil
+}
+
+func (m *MockRoleRepository) FindPermissionsByKeys(keys []string) ([]domain.Permission, error) {
+	if m.FindPermissionsByKeysFunc != nil {
+		return m.FindPermissionsByKeysFunc(keys)
+	}
+	permissions := make([]domain.Permission, 0, len(keys))
+	for i, key := range keys {
+		permissions = append(permissions, domain.Permission{ID: i + 1, Key: key})
+	}
+	return permissions, nil
+}
+
+func (m *MockRoleRepository) GetRolePermissionKeys(roleID int) (string, []string, error) {
+	if m.GetRolePermissionKeysFunc != nil {
+		return m.GetRolePermissionKeysFunc(roleID)
+	}
+	return "", nil, nil
+}
+
+// Stub methods (interface requirement)
+func (m *MockRoleRepository) FindAll() ([]domain.Role, error)                  { return nil, nil }
+func (m *MockRoleRepository) FindAllPermissions() ([]domain.Permission, error) { return nil, nil }
+
+// TestCreateRole_Success menguji role baru dibuat dengan key yang dinormalisasi
+func TestCreateRole_Success(t *testing.T) {
+	var created *domain.Role
+	roleRepo := &MockRoleRepository{
+		CreateFunc: func(role *domain.Role) error {
+			role.ID = 3
+			created = role
+			return nil
+		},
+	}
+
+	svc := NewRoleService(roleRepo, &MockActivityLogRepoForAuth{})
+	role, err := svc.Create(context.Background(), &requests.CreateRoleRequest{
+		Key:         " Editor ",
+		Name:        "Editor",
+		Permissions: []string{domain.PermPostsPublish, domain.PermPostsUpdate, domain.PermPostsPublish},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created == nil || role.Key != "editor" {
+		t.Fatalf("Expected role with key editor to be created, got %+v", role)
+	}
+	if len(role.Permissions) != 2 {
+		t.Errorf("Expected duplicate permission keys to be ignored, got %v", role.PermissionKeys())
+	}
+}
+
+// TestCreateRole_Validation menguji key tidak valid, key duplikat dan permission tidak dikenal ditolak
+func TestCreateRole_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *MockRoleRepository
+		req      requests.CreateRoleRequest
+		expected error
+	}{
+		{
+			name:     "invalid key",
+			repo:     &MockRoleRepository{},
+			req:      requests.CreateRoleRequest{Key: "content-editor", Name: "Editor"},
+			expected: ErrInvalidRoleKey,
+		},
+		{
+			name:     "duplicate key",
+			repo:     &MockRoleRepository{ExistsByKeyFunc: func(key string) (bool, error) { return true, nil }},
+			req:      requests.CreateRoleRequest{Key: "author", Name: "Author"},
+			expected: ErrRoleKeyExists,
+		},
+		{
+			name: "unknown permission",
+			repo: &MockRoleRepository{FindPermissionsByKeysFunc: func(keys []string) ([]domain.Permission, error) {
+				return []domain.Permission{{ID: 1, Key: domain.PermPostsPublish}}, nil
+			}},
+			req:      requests.CreateRoleRequest{Key: "editor", Name: "Editor", Permissions: []string{domain.PermPostsPublish, "posts.publsh"}},
+			expected: ErrUnknownPermission,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewRoleService(tt.repo, &MockActivityLogRepoForAuth{})
+			if _, err := svc.Create(context.Background(), &tt.req); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestUpdateRole_AdminLocked menguji role admin bawaan tidak bisa diubah
+func TestUpdateRole_AdminLocked(t *testing.T) {
+	svc := NewRoleService(&MockRoleRepository{}, &MockActivityLogRepoForAuth{})
+
+	permissions := []string{}
+	_, err := svc.Update(context.Background(), domain.RoleAdminID, &requests.UpdateRoleRequest{Permissions: &permissions})
+	if !errors.Is(err, ErrSystemRoleLocked) {
+		t.Errorf("Expected ErrSystemRoleLocked, got %v", err)
+	}
+}
+
+// TestUpdateRole_InvalidatesPermissionCache menguji permission baru langsung berlaku setelah update
+func TestUpdateRole_InvalidatesPermissionCache(t *testing.T) {
+	granted := []string{domain.PermPostsCreate}
+	roleRepo := &MockRoleRepository{
+		FindByIDFunc: func(id int) (*domain.Role, error) {
+			return &domain.Role{ID: id, Key: "author", Name: "Author", IsSystem: true}, nil
+		},
+		UpdateFunc: func(role *domain.Role) error {
+			granted = role.PermissionKeys()
+			return nil
+		},
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			return "author", granted, nil
+		},
+	}
+	utils.InitPermissionStore(roleRepo)
+
+	if utils.HasPermission("2", domain.PermPostsPublish) {
+		t.Fatal("Expected author to start without posts.publish")
+	}
+
+	svc := NewRoleService(roleRepo, &MockActivityLogRepoForAuth{})
+	permissions := []string{domain.PermPostsCreate, domain.PermPostsPublish}
+	if _, err := svc.Update(context.Background(), domain.RoleAuthorID, &requests.UpdateRoleRequest{Permissions: &permissions}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !utils.HasPermission("2", domain.PermPostsPublish) {
+		t.Error("Expected posts.publish to be granted right after update")
+	}
+}
+
+// TestDeleteRole_Guards menguji role bawaan dan role yang masih dipakai tidak bisa dihapus
+func TestDeleteRole_Guards(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *MockRoleRepository
+		expected error
+	}{
+		{
+			name: "not found",
+			repo: &MockRoleRepository{FindByIDFunc: func(id int) (*domain.Role, error) {
+				return nil, gorm.ErrRecordNotFound
+			}},
+			expected: ErrRoleNotFound,
+		},
+		{
+			name: "system role",
+			repo: &MockRoleRepository{FindByIDFunc: func(id int) (*domain.Role, error) {
+				return &domain.Role{ID: id, Key: "author", IsSystem: true}, nil
+			}},
+			expected: ErrSystemRoleLocked,
+		},
+		{
+			name:     "role in use",
+			repo:     &MockRoleRepository{CountUsersFunc: func(roleID int) (int64, error) { return 2, nil }},
+			expected: ErrRoleInUse,
+		},
+		{
+			name:     "unused custom role",
+			repo:     &MockRoleRepository{},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewRoleService(tt.repo, &MockActivityLogRepoForAuth{})
+			if err := svc.Delete(context.Background(), 3); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestUpdateUser_UnknownRole menguji role user tidak bisa diganti ke role yang tidak ada
+func TestUpdateUser_UnknownRole(t *testing.T) {
+	userRepo := &MockUserRepositoryForUserService{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, FullName: "Test User", Email: "test@example.com", Role: 2, IsActive: true}, nil
+		},
+	}
+	roleRepo := &MockRoleRepository{
+		FindByIDFunc: func(id int) (*domain.Role, error) { return nil, gorm.ErrRecordNotFound },
+	}
+
+	svc := NewUserService(userRepo, roleRepo, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
+	_, err := svc.UpdateUser(context.Background(), 1, &requests.UpdateUserRequest{Role: intPtr(99)}, nil)
+	if !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("Expected ErrRoleNotFound, got %v", err)
+	}
+}
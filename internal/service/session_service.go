@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// SessionService interface untuk business logic manajemen session login
+type SessionService interface {
+	GetActiveSessions(ctx context.Context, userID int) ([]domain.UserSession, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string) (int, error)
+}
+
+type sessionService struct {
+	sessionRepo     repository.SessionRepository
+	userRepo        repository.UserRepository
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewSessionService constructor untuk SessionService
+func NewSessionService(sessionRepo repository.SessionRepository, userRepo repository.UserRepository, activityLogRepo repository.ActivityLogRepository) SessionService {
+	return &sessionService{
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// GetActiveSessions mengambil daftar session aktif milik user
+func (s *sessionService) GetActiveSessions(ctx context.Context, userID int) ([]domain.UserSession, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, ErrSessionFetchFailed
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession mencabut satu session (family) milik user
+func (s *sessionService) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	sessions, err := s.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.FamilyID != sessionID {
+			continue
+		}
+
+		if err := revokeSessions(s.sessionRepo, []domain.UserSession{session}, ""); err != nil {
+			return ErrSessionRevokeFailed
+		}
+
+		recordActivity(ctx, s.activityLogRepo, audit.Event{
+			Action:      domain.ActionLogout,
+			Module:      domain.ModuleAuth,
+			Description: "Mencabut sesi login",
+			TargetID:    &userID,
+			NewValue: map[string]any{
+				"session_id": session.FamilyID,
+				"device":     session.Device,
+				"ip_address": session.IPAddress,
+			},
+		})
+		return nil
+	}
+
+	return ErrSessionNotFound
+}
+
+// RevokeOtherSessions mencabut semua session user kecuali keepSessionID
+// keepSessionID kosong berarti semua session dicabut
+func (s *sessionService) RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string) (int, error) {
+	sessions, err := s.GetActiveSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := revokeSessions(s.sessionRepo, sessions, keepSessionID); err != nil {
+		return 0, ErrSessionRevokeFailed
+	}
+
+	revoked := len(sessions)
+	for _, session := range sessions {
+		if session.FamilyID == keepSessionID {
+			revoked--
+		}
+	}
+
+	if revoked > 0 {
+		recordActivity(ctx, s.activityLogRepo, audit.Event{
+			Action:      domain.ActionLogout,
+			Module:      domain.ModuleAuth,
+			Description: "Mencabut semua sesi login lain",
+			TargetID:    &userID,
+			NewValue: map[string]any{
+				"revoked_sessions": revoked,
+			},
+		})
+	}
+
+	return revoked, nil
+}
+
+// revokeSessions mencabut refresh token dan access token untuk setiap session kecuali keepSessionID
+func revokeSessions(sessionRepo repository.SessionRepository, sessions []domain.UserSession, keepSessionID string) error {
+	for _, session := range sessions {
+		if session.FamilyID == keepSessionID {
+			continue
+		}
+
+		if err := sessionRepo.RevokeFamily(session.FamilyID); err != nil {
+			return err
+		}
+
+		// Access token session ini tetap berlaku sampai expired jika tidak ikut dicabut
+		if err := utils.RevokeSession(session.FamilyID, session.UserID); err != nil {
+			fmt.Printf("[WARN] revokeSessions: failed to revoke access tokens for session %s: %v\n", session.FamilyID, err)
+		}
+	}
+
+	return nil
+}
+
+// Session service errors
+var (
+	ErrSessionNotFound     = errors.New("sesi tidak ditemukan")
+	ErrSessionFetchFailed  = errors.New("gagal mengambil data sesi")
+	ErrSessionRevokeFailed = errors.New("gagal mencabut sesi")
+)
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+)
+
+// newSessionServiceForTest membuat SessionService dengan user yang selalu ditemukan
+func newSessionServiceForTest(sessionRepo *MockSessionRepository) SessionService {
+	userRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return &domain.User{ID: id, IsActive: true}, nil
+		},
+	}
+	return NewSessionService(sessionRepo, userRepo, &MockActivityLogRepoForAuth{})
+}
+
+// TestGetActiveSessions_UserNotFound menguji daftar session untuk user yang tidak ada
+func TestGetActiveSessions_UserNotFound(t *testing.T) {
+	userRepo := &MockUserRepository{
+		FindByIDFunc: func(id int) (*domain.User, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+	svc := NewSessionService(&MockSessionRepository{}, userRepo, &MockActivityLogRepoForAuth{})
+
+	_, err := svc.GetActiveSessions(context.Background(), 99)
+
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+// TestRevokeSession_NotFound menguji pencabutan session yang bukan milik user
+func TestRevokeSession_NotFound(t *testing.T) {
+	sessionRepo := &MockSessionRepository{
+		FindActiveByUserFunc: func(userID int) ([]domain.UserSession, error) {
+			return []domain.UserSession{{ID: 1, UserID: userID, FamilyID: "laptop"}}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			t.Errorf("Expected no family to be revoked, got %s", familyID)
+			return nil
+		},
+	}
+
+	err := newSessionServiceForTest(sessionRepo).RevokeSession(context.Background(), 1, "someone-else")
+
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+// TestRevokeSession_Success menguji pencabutan satu session
+func TestRevokeSession_Success(t *testing.T) {
+	revoked := ""
+	sessionRepo := &MockSessionRepository{
+		FindActiveByUserFunc: func(userID int) ([]domain.UserSession, error) {
+			return []domain.UserSession{
+				{ID: 1, UserID: userID, FamilyID: "laptop"},
+				{ID: 2, UserID: userID, FamilyID: "phone"},
+			}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			revoked = familyID
+			return nil
+		},
+	}
+
+	err := newSessionServiceForTest(sessionRepo).RevokeSession(context.Background(), 1, "phone")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if revoked != "phone" {
+		t.Errorf("Expected phone session to be revoked, got %q", revoked)
+	}
+}
+
+// TestRevokeOtherSessions_KeepsCurrent menguji logout dari semua perangkat lain
+func TestRevokeOtherSessions_KeepsCurrent(t *testing.T) {
+	var revoked []string
+	sessionRepo := &MockSessionRepository{
+		FindActiveByUserFunc: func(userID int) ([]domain.UserSession, error) {
+			return []domain.UserSession{
+				{ID: 1, UserID: userID, FamilyID: "current"},
+				{ID: 2, UserID: userID, FamilyID: "laptop"},
+				{ID: 3, UserID: userID, FamilyID: "phone"},
+			}, nil
+		},
+		RevokeFamilyFunc: func(familyID string) error {
+			revoked = append(revoked, familyID)
+			return nil
+		},
+	}
+
+	count, err := newSessionServiceForTest(sessionRepo).RevokeOtherSessions(context.Background(), 1, "current")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 2 || len(revoked) != 2 {
+		t.Errorf("Expected 2 sessions revoked, got count=%d revoked=%v", count, revoked)
+	}
+	for _, family := range revoked {
+		if family == "current" {
+			t.Error("Expected current session to stay active")
+		}
+	}
+}
@@ -20,13 +20,16 @@ type SiteSettingService interface {
 type siteSettingService struct {
 	siteSettingRepo   repository.SiteSettingRepository
 	cloudinaryService CloudinaryService
+	webhooks          WebhookPublisher
 }
 
 // NewSiteSettingService constructor untuk SiteSettingService
-func NewSiteSettingService(siteSettingRepo repository.SiteSettingRepository, cloudinaryService CloudinaryService) SiteSettingService {
+// webhooks boleh nil jika perubahan pengaturan tidak perlu dikirim ke webhook
+func NewSiteSettingService(siteSettingRepo repository.SiteSettingRepository, cloudinaryService CloudinaryService, webhooks WebhookPublisher) SiteSettingService {
 	return &siteSettingService{
 		siteSettingRepo:   siteSettingRepo,
 		cloudinaryService: cloudinaryService,
+		webhooks:          orNoopWebhookPublisher(webhooks),
 	}
 }
 
@@ -126,9 +129,12 @@ func (s *siteSettingService) Update(ctx context.Context, req requests.UpdateSite
 	if req.GithubURL != nil {
 		setting.GithubURL = req.GithubURL
 	}
+	if req.RequireAdmin2FA != nil {
+		setting.RequireAdmin2FA = *req.RequireAdmin2FA
+	}
 
 	// Save to database
-	if err := s.siteSettingRepo.Update(setting); err != nil {
+	if err := s.siteSettingRepo.WithContext(ctx).Update(setting); err != nil {
 		// Rollback all new uploads
 		if newFavicon != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "settings", *newFavicon)
@@ -153,7 +159,10 @@ func (s *siteSettingService) Update(ctx context.Context, req requests.UpdateSite
 		_ = s.cloudinaryService.DeleteImage(ctx, "settings", *oldLogoBig)
 	}
 
-	return s.toResponseDTO(setting), nil
+	res := s.toResponseDTO(setting)
+	s.webhooks.Publish(ctx, domain.WebhookEventSettingsUpdated, res)
+
+	return res, nil
 }
 
 // toResponseDTO converts domain.SiteSetting to responses.SiteSettingResponse
@@ -183,5 +192,6 @@ func (s *siteSettingService) toResponseDTO(setting *domain.SiteSetting) *respons
 		InstagramURL:    setting.InstagramURL,
 		YoutubeURL:      setting.YoutubeURL,
 		GithubURL:       setting.GithubURL,
+		RequireAdmin2FA: setting.RequireAdmin2FA,
 	}
 }
@@ -9,7 +9,6 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
-	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
 // CloudinaryService interface untuk operasi cloudinary
@@ -37,15 +36,13 @@ type TestimonialService interface {
 type testimonialService struct {
 	testimonialRepo   repository.TestimonialRepository
 	cloudinaryService CloudinaryService
-	activityLogRepo   repository.ActivityLogRepository
 }
 
 // NewTestimonialService constructor untuk TestimonialService
-func NewTestimonialService(testimonialRepo repository.TestimonialRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository) TestimonialService {
+func NewTestimonialService(testimonialRepo repository.TestimonialRepository, cloudinaryService CloudinaryService) TestimonialService {
 	return &testimonialService{
 		testimonialRepo:   testimonialRepo,
 		cloudinaryService: cloudinaryService,
-		activityLogRepo:   activityLogRepo,
 	}
 }
 
@@ -82,7 +79,7 @@ func (s *testimonialService) Create(ctx context.Context, req requests.CreateTest
 	}
 
 	// Save ke database
-	if err := s.testimonialRepo.Create(testimonial); err != nil {
+	if err := s.testimonialRepo.WithContext(ctx).Create(testimonial); err != nil {
 		// Rollback: hapus foto dari Cloudinary jika save gagal
 		if photoFilename != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "testimonials", *photoFilename)
@@ -93,12 +90,6 @@ func (s *testimonialService) Create(ctx context.Context, req requests.CreateTest
 	// Convert to response DTO
 	resp := s.toDetailResponseDTO(testimonial)
 
-	// Log activity - Create Testimonial
-	s.logActivity(ctx, domain.ActionCreate, domain.ModuleTestimoni, "Membuat testimonial baru: "+testimonial.Name, nil, map[string]any{
-		"id":   testimonial.ID,
-		"name": testimonial.Name,
-	}, &testimonial.ID)
-
 	return resp, nil
 }
 
@@ -163,17 +154,6 @@ func (s *testimonialService) Update(ctx context.Context, id int, req requests.Up
 	// Simpan foto lama untuk rollback
 	oldPhotoURI := testimonial.PhotoURI
 
-	// Store old values for audit log
-	oldValues := map[string]any{
-		"id":           testimonial.ID,
-		"name":         testimonial.Name,
-		"organization": testimonial.Organization,
-		"position":     testimonial.Position,
-		"content":      testimonial.Content,
-		"photo_uri":    testimonial.PhotoURI,
-		"is_active":    testimonial.IsActive,
-	}
-
 	// Upload foto baru ke Cloudinary (jika ada)
 	var newPhotoFilename *string
 	if photoFile != nil {
@@ -203,7 +183,7 @@ func (s *testimonialService) Update(ctx context.Context, id int, req requests.Up
 	}
 
 	// Save ke database
-	if err := s.testimonialRepo.Update(testimonial); err != nil {
+	if err := s.testimonialRepo.WithContext(ctx).Update(testimonial); err != nil {
 		// Rollback: hapus foto baru jika update gagal
 		if newPhotoFilename != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "testimonials", *newPhotoFilename)
@@ -216,17 +196,6 @@ func (s *testimonialService) Update(ctx context.Context, id int, req requests.Up
 		_ = s.cloudinaryService.DeleteImage(ctx, "testimonials", *oldPhotoURI)
 	}
 
-	// Log activity - Update Testimonial
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleTestimoni, "Mengupdate testimonial: "+testimonial.Name, oldValues, map[string]any{
-		"id":           testimonial.ID,
-		"name":         testimonial.Name,
-		"organization": testimonial.Organization,
-		"position":     testimonial.Position,
-		"content":      testimonial.Content,
-		"photo_uri":    testimonial.PhotoURI,
-		"is_active":    testimonial.IsActive,
-	}, &testimonial.ID)
-
 	return s.toDetailResponseDTO(testimonial), nil
 }
 
@@ -238,14 +207,8 @@ func (s *testimonialService) Delete(ctx context.Context, id int) error {
 		return errors.New("testimonial tidak ditemukan")
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleTestimoni, "Menghapus testimonial: "+testimonial.Name, map[string]any{
-		"id":   testimonial.ID,
-		"name": testimonial.Name,
-	}, nil, &testimonial.ID)
-
 	// Hapus dari database
-	if err := s.testimonialRepo.Delete(id); err != nil {
+	if err := s.testimonialRepo.WithContext(ctx).Delete(id); err != nil {
 		return errors.New("gagal menghapus testimonial")
 	}
 
@@ -290,37 +253,3 @@ func (s *testimonialService) toDetailResponseDTO(t *domain.Testimonial) *respons
 		IsActive:     t.IsActive,
 	}
 }
-
-// logActivity helper untuk mencatat activity log
-func (s *testimonialService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
-	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
-}
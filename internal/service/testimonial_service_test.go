@@ -8,7 +8,6 @@ import (
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
-	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
@@ -56,6 +55,10 @@ func (m *MockTestimonialRepository) Delete(id int) error {
 	return errors.New("mock not configured")
 }
 
+func (m *MockTestimonialRepository) WithContext(ctx context.Context) repository.TestimonialRepository {
+	return m
+}
+
 // MockCloudinaryService adalah mock untuk Cloudinary Service
 type MockCloudinaryService struct {
 	UploadImageFunc    func(ctx context.Context, folder string, file *multipart.FileHeader) (string, error)
@@ -116,22 +119,6 @@ func (m *MockCloudinaryService) GetDownloadURL(folder string, filename string) s
 	return ""
 }
 
-// MockActivityLogRepositoryForTestimonial adalah mock untuk ActivityLogRepository
-type MockActivityLogRepositoryForTestimonial struct {
-	CreateFunc func(log *domain.ActivityLog) error
-}
-
-func (m *MockActivityLogRepositoryForTestimonial) Create(log *domain.ActivityLog) error {
-	if m.CreateFunc != nil {
-		return m.CreateFunc(log)
-	}
-	return nil
-}
-
-func (m *MockActivityLogRepositoryForTestimonial) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
-	return nil, 0, nil
-}
-
 // ==================== CREATE TESTS ====================
 
 // Test: Upload error harus return error
@@ -143,7 +130,7 @@ func TestCreate_ErrorUploadFailed(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.CreateTestimonialRequest{Name: "Test", Content: "Content"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -174,7 +161,7 @@ func TestCreate_ErrorDatabaseWithRollback(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.CreateTestimonialRequest{Name: "Test", Content: "Content"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -206,7 +193,7 @@ func TestCreate_ValidationEmptyOptionalFields(t *testing.T) {
 		GetImageURLFunc: func(folder string, filename string) string { return "" },
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.CreateTestimonialRequest{
 		Name:         "Test",
 		Content:      "Content",
@@ -234,7 +221,7 @@ func TestGetAll_ValidationDefaultPagination(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	// Test dengan invalid values
 	_, _, _, _, err := service.GetAll(context.Background(), 0, -5, "")
@@ -252,7 +239,7 @@ func TestGetAll_ErrorDatabaseFailed(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	_, _, _, _, err := service.GetAll(context.Background(), 1, 10, "")
 
@@ -283,7 +270,7 @@ func TestGetAll_LogicLastPageCalculation(t *testing.T) {
 		}
 
 		mockCloudinary := &MockCloudinaryService{}
-		service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+		service := NewTestimonialService(mockRepo, mockCloudinary)
 
 		_, _, lastPage, _, err := service.GetAll(context.Background(), 1, tt.limit, "")
 
@@ -307,7 +294,7 @@ func TestGetByID_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	_, err := service.GetByID(context.Background(), 999)
 
@@ -355,7 +342,7 @@ func TestUpdate_LogicDeleteOldPhotoAfterSuccess(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.UpdateTestimonialRequest{Name: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "new.jpg"}
 
@@ -383,7 +370,7 @@ func TestUpdate_ErrorUploadFailed(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.UpdateTestimonialRequest{Name: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "photo.jpg"}
 
@@ -424,7 +411,7 @@ func TestUpdate_ErrorDatabaseWithRollback(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 	req := requests.UpdateTestimonialRequest{Name: "Updated"}
 	mockFile := &multipart.FileHeader{Filename: "new.jpg"}
 
@@ -450,7 +437,7 @@ func TestUpdate_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	_, err := service.Update(context.Background(), 999, requests.UpdateTestimonialRequest{}, nil)
 
@@ -485,7 +472,7 @@ func TestDelete_LogicDeleteDatabaseFirst(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	err := service.Delete(context.Background(), 1)
 
@@ -515,7 +502,7 @@ func TestDelete_ErrorDatabaseFailed(t *testing.T) {
 		},
 	}
 
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	err := service.Delete(context.Background(), 1)
 
@@ -536,7 +523,7 @@ func TestDelete_ErrorNotFound(t *testing.T) {
 	}
 
 	mockCloudinary := &MockCloudinaryService{}
-	service := NewTestimonialService(mockRepo, mockCloudinary, &MockActivityLogRepositoryForTestimonial{})
+	service := NewTestimonialService(mockRepo, mockCloudinary)
 
 	err := service.Delete(context.Background(), 999)
 
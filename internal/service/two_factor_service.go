@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/audit"
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// recoveryCodeCount jumlah recovery code yang dibuat setiap kali generate
+const recoveryCodeCount = 10
+
+// defaultTOTPIssuer nama issuer di authenticator app jika site name belum diatur
+const defaultTOTPIssuer = "PMII"
+
+// TwoFactorService interface untuk business logic pengelolaan 2FA oleh user sendiri
+type TwoFactorService interface {
+	GetStatus(ctx context.Context, userID int) (*TwoFactorStatus, error)
+	Enroll(ctx context.Context, userID int) (*TwoFactorEnrollment, error)
+	Confirm(ctx context.Context, userID int, code string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error)
+	Disable(ctx context.Context, userID int, password, code string) error
+}
+
+// TwoFactorStatus ringkasan status 2FA user
+type TwoFactorStatus struct {
+	Enabled                bool
+	EnabledAt              *time.Time
+	Required               bool // 2FA wajib untuk user ini (admin + pengaturan situs)
+	RecoveryCodesRemaining int64
+}
+
+// TwoFactorEnrollment berisi secret TOTP dan URI otpauth:// (payload QR code)
+type TwoFactorEnrollment struct {
+	Secret     string
+	OTPAuthURI string
+}
+
+type twoFactorService struct {
+	userRepo        repository.UserRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	siteSettingRepo repository.SiteSettingRepository
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewTwoFactorService constructor untuk TwoFactorService
+func NewTwoFactorService(userRepo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository, siteSettingRepo repository.SiteSettingRepository, activityLogRepo repository.ActivityLogRepository) TwoFactorService {
+	return &twoFactorService{
+		userRepo:        userRepo,
+		twoFactorRepo:   twoFactorRepo,
+		siteSettingRepo: siteSettingRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// GetStatus mengambil status 2FA user
+func (s *twoFactorService) GetStatus(ctx context.Context, userID int) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, ErrTwoFactorFetchFailed
+	}
+
+	status := &TwoFactorStatus{
+		Required: isTwoFactorRequired(user, s.siteSettingRepo),
+	}
+
+	if twoFactor != nil && twoFactor.IsEnabled() {
+		status.Enabled = true
+		status.EnabledAt = twoFactor.EnabledAt
+
+		remaining, err := s.twoFactorRepo.CountRecoveryCodes(userID)
+		if err != nil {
+			return nil, ErrTwoFactorFetchFailed
+		}
+		status.RecoveryCodesRemaining = remaining
+	}
+
+	return status, nil
+}
+
+// Enroll membuat secret TOTP baru yang harus dikonfirmasi dengan kode pertama
+func (s *twoFactorService) Enroll(ctx context.Context, userID int) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return startTwoFactorEnrollment(s.twoFactorRepo, s.siteSettingRepo, user)
+}
+
+// Confirm mengaktifkan 2FA setelah kode dari authenticator app terverifikasi
+// Mengembalikan recovery code plaintext yang hanya ditampilkan sekali
+func (s *twoFactorService) Confirm(ctx context.Context, userID int, code string) ([]string, error) {
+	codes, err := confirmTwoFactorEnrollment(s.twoFactorRepo, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      userID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Mengaktifkan autentikasi dua faktor (2FA)",
+		TargetID:    &userID,
+	})
+
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes membuat ulang recovery code, kode lama tidak berlaku lagi
+// Membutuhkan kode TOTP yang valid agar tidak bisa dilakukan hanya dengan access token
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) ([]string, error) {
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, ErrTwoFactorFetchFailed
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	if !verifyTOTPCode(s.twoFactorRepo, twoFactor, code) {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      userID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Membuat ulang recovery code 2FA",
+		TargetID:    &userID,
+	})
+
+	return codes, nil
+}
+
+// Disable menonaktifkan 2FA, membutuhkan password dan kode TOTP/recovery code
+// Admin tidak bisa menonaktifkan 2FA jika pengaturan situs mewajibkannya
+func (s *twoFactorService) Disable(ctx context.Context, userID int, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if isTwoFactorRequired(user, s.siteSettingRepo) {
+		return ErrTwoFactorRequiredForAdmin
+	}
+
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return ErrInvalidPasswordConfirmation
+	}
+
+	twoFactor, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return ErrTwoFactorFetchFailed
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if !verifyTwoFactorCode(s.twoFactorRepo, twoFactor, code) {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if err := s.twoFactorRepo.Disable(userID); err != nil {
+		return ErrTwoFactorUpdateFailed
+	}
+
+	recordActivity(ctx, s.activityLogRepo, audit.Event{
+		UserID:      userID,
+		Action:      domain.ActionUpdate,
+		Module:      domain.ModuleAuth,
+		Description: "Menonaktifkan autentikasi dua faktor (2FA)",
+		TargetID:    &userID,
+	})
+
+	return nil
+}
+
+// startTwoFactorEnrollment membuat dan menyimpan secret TOTP baru untuk user
+func startTwoFactorEnrollment(twoFactorRepo repository.TwoFactorRepository, siteSettingRepo repository.SiteSettingRepository, user *domain.User) (*TwoFactorEnrollment, error) {
+	existing, err := twoFactorRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, ErrTwoFactorFetchFailed
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+
+	if err := twoFactorRepo.SavePending(user.ID, secret); err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPProvisioningURI(secret, totpIssuer(siteSettingRepo), user.Email),
+	}, nil
+}
+
+// confirmTwoFactorEnrollment memverifikasi kode pertama dari secret pending lalu mengaktifkan 2FA
+func confirmTwoFactorEnrollment(twoFactorRepo repository.TwoFactorRepository, userID int, code string) ([]string, error) {
+	twoFactor, err := twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, ErrTwoFactorFetchFailed
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if twoFactor.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+
+	enabled, err := twoFactorRepo.Enable(userID, step, hashes)
+	if err != nil {
+		return nil, ErrTwoFactorUpdateFailed
+	}
+	if !enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return codes, nil
+}
+
+// verifyTwoFactorCode menerima kode TOTP 6 digit atau recovery code
+func verifyTwoFactorCode(twoFactorRepo repository.TwoFactorRepository, twoFactor *domain.UserTwoFactor, code string) bool {
+	if verifyTOTPCode(twoFactorRepo, twoFactor, code) {
+		return true
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false
+	}
+
+	used, err := twoFactorRepo.UseRecoveryCode(twoFactor.UserID, utils.HashToken(normalized))
+	return err == nil && used
+}
+
+// verifyTOTPCode memvalidasi kode TOTP dan menolak kode yang sudah pernah dipakai (replay)
+func verifyTOTPCode(twoFactorRepo repository.TwoFactorRepository, twoFactor *domain.UserTwoFactor, code string) bool {
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return false
+	}
+
+	updated, err := twoFactorRepo.UpdateLastStep(twoFactor.UserID, step)
+	return err == nil && updated
+}
+
+// generateRecoveryCodes membuat recovery code plaintext (format xxxxx-xxxxx) beserta hash-nya
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.GenerateRandomToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, utils.HashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode menyamakan format input recovery code (tanpa strip/spasi, huruf kecil)
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// isTwoFactorRequired mengecek apakah user wajib 2FA: role-nya memiliki permission tingkat admin
+// (domain.AdminPermissions) dan pengaturan situs mewajibkan 2FA untuk admin
+func isTwoFactorRequired(user *domain.User, siteSettingRepo repository.SiteSettingRepository) bool {
+	role := strconv.Itoa(user.Role)
+	for _, permission := range domain.AdminPermissions {
+		if utils.HasPermission(role, permission) {
+			return isAdminTwoFactorRequired(siteSettingRepo)
+		}
+	}
+	return false
+}
+
+// isAdminTwoFactorRequired membaca pengaturan situs apakah 2FA wajib untuk semua admin
+func isAdminTwoFactorRequired(siteSettingRepo repository.SiteSettingRepository) bool {
+	setting, err := siteSettingRepo.Get()
+	if err != nil {
+		return false
+	}
+	return setting.RequireAdmin2FA
+}
+
+// totpIssuer nama issuer yang tampil di authenticator app
+func totpIssuer(siteSettingRepo repository.SiteSettingRepository) string {
+	setting, err := siteSettingRepo.Get()
+	if err != nil || setting.SiteName == nil || strings.TrimSpace(*setting.SiteName) == "" {
+		return defaultTOTPIssuer
+	}
+	return strings.TrimSpace(*setting.SiteName)
+}
+
+// Two-factor service errors
+var (
+	ErrTwoFactorFetchFailed        = errors.New("gagal mengambil data 2FA")
+	ErrTwoFactorUpdateFailed       = errors.New("gagal menyimpan data 2FA")
+	ErrTwoFactorAlreadyEnabled     = errors.New("2FA sudah aktif")
+	ErrTwoFactorNotEnabled         = errors.New("2FA belum aktif")
+	ErrTwoFactorNotEnrolled        = errors.New("belum ada proses aktivasi 2FA, silakan mulai enrollment terlebih dahulu")
+	ErrTwoFactorRequiredForAdmin   = errors.New("2FA wajib untuk akun admin dan tidak dapat dinonaktifkan")
+	ErrInvalidTwoFactorCode        = errors.New("kode 2FA tidak valid")
+	ErrInvalidPasswordConfirmation = errors.New("password salah")
+)
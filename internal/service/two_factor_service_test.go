@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+)
+
+// MockTwoFactorRepository adalah mock untuk TwoFactorRepository
+type MockTwoFactorRepository struct {
+	FindByUserIDFunc    func(userID int) (*domain.UserTwoFactor, error)
+	SavePendingFunc     func(userID int, secret string) error
+	EnableFunc          func(userID int, step int64, codeHashes []string) (bool, error)
+	UpdateLastStepFunc  func(userID int, step int64) (bool, error)
+	DisableFunc         func(userID int) error
+	UseRecoveryCodeFunc func(userID int, codeHash string) (bool, error)
+}
+
+func (m *MockTwoFactorRepository) FindByUserID(userID int) (*domain.UserTwoFactor, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTwoFactorRepository) SavePending(userID int, secret string) error {
+	if m.SavePendingFunc != nil {
+		return m.SavePendingFunc(userID, secret)
+	}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) Enable(userID int, step int64, codeHashes []string) (bool, error) {
+	if m.EnableFunc != nil {
+		return m.EnableFunc(userID, step, codeHashes)
+	}
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) UpdateLastStep(userID int, step int64) (bool, error) {
+	if m.UpdateLastStepFunc != nil {
+		return m.UpdateLastStepFunc(userID, step)
+	}
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) Disable(userID int) error {
+	if m.DisableFunc != nil {
+		return m.DisableFunc(userID)
+	}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	if m.UseRecoveryCodeFunc != nil {
+		return m.UseRecoveryCodeFunc(userID, codeHash)
+	}
+	return false, nil
+}
+
+// Stub methods (interface requirement)
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	return nil
+}
+func (m *MockTwoFactorRepository) CountRecoveryCodes(userID int) (int64, error) { return 0, nil }
+
+// MockSiteSettingRepository adalah mock untuk SiteSettingRepository
+type MockSiteSettingRepository struct {
+	Setting *domain.SiteSetting
+}
+
+func (m *MockSiteSettingRepository) Get() (*domain.SiteSetting, error) {
+	if m.Setting != nil {
+		return m.Setting, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *MockSiteSettingRepository) Update(setting *domain.SiteSetting) error { return nil }
+
+func (m *MockSiteSettingRepository) WithContext(ctx context.Context) repository.SiteSettingRepository {
+	return m
+}
+
+// MockRevocationBackend adalah backend revocation in-memory untuk test challenge token
+type MockRevocationBackend struct {
+	mu      sync.Mutex
+	revoked map[string]bool
+}
+
+func (m *MockRevocationBackend) SaveRevokedToken(jti string, userID int, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.revoked == nil {
+		m.revoked = make(map[string]bool)
+	}
+	m.revoked[jti] = true
+	return nil
+}
+
+func (m *MockRevocationBackend) IsTokenRevoked(jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.revoked[jti], nil
+}
+
+func (m *MockRevocationBackend) DeleteExpiredTokens() error { return nil }
+func (m *MockRevocationBackend) GetTokensValidAfter(userID int) (*time.Time, error) {
+	return nil, nil
+}
+func (m *MockRevocationBackend) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	return nil
+}
+
+// adminHashedPassword adalah bcrypt hash dari "admin123"
+const adminHashedPassword = "$2a$10$CaI1bA6w2H0LKVGF./iweOteBj/rAkkpx3QUO/dDK5.dRP6BDeB8a"
+
+func newTwoFactorTestUserRepo() *MockUserRepository {
+	user := &domain.User{ID: 1, Email: "admin@example.com", PasswordHash: adminHashedPassword, Role: 1, IsActive: true}
+	return &MockUserRepository{
+		FindByEmailFunc: func(email string) (*domain.User, error) { return user, nil },
+		FindByIDFunc:    func(id int) (*domain.User, error) { return user, nil },
+	}
+}
+
+// initTwoFactorPermissions menyiapkan permission store: role 1 (admin) dan role 5 (role custom dengan
+// permission admin) wajib 2FA, role 2 (author) tidak
+func initTwoFactorPermissions() {
+	utils.InitPermissionStore(&MockRoleRepository{
+		GetRolePermissionKeysFunc: func(roleID int) (string, []string, error) {
+			switch roleID {
+			case domain.RoleAdminID:
+				return "admin", []string{domain.PermUsersManage, domain.PermRolesManage}, nil
+			case 5:
+				return "sekretariat", []string{domain.PermSecurityManage}, nil
+			}
+			return "author", []string{domain.PermPostsCreate}, nil
+		},
+	})
+}
+
+func enabledTwoFactor(secret string) *domain.UserTwoFactor {
+	enabledAt := time.Now().Add(-time.Hour)
+	return &domain.UserTwoFactor{UserID: 1, Secret: secret, EnabledAt: &enabledAt}
+}
+
+// TestLogin_TwoFactorEnabled menguji login user dengan 2FA aktif mengembalikan challenge, bukan token
+func TestLogin_TwoFactorEnabled(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	secret, _ := utils.GenerateTOTPSecret()
+
+	sessionCreated := false
+	sessionRepo := &MockSessionRepository{
+		CreateFunc: func(session *domain.UserSession) error {
+			sessionCreated = true
+			return nil
+		},
+	}
+	twoFactorRepo := &MockTwoFactorRepository{
+		FindByUserIDFunc: func(userID int) (*domain.UserTwoFactor, error) { return enabledTwoFactor(secret), nil },
+	}
+
+	authService := NewAuthService(newTwoFactorTestUserRepo(), sessionRepo, twoFactorRepo, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, tokens, err := authService.Login(context.Background(), "admin@example.com", "admin123")
+
+	var challenge *TwoFactorChallengeError
+	if !errors.As(err, &challenge) {
+		t.Fatalf("Expected TwoFactorChallengeError, got %v", err)
+	}
+	if challenge.SetupRequired {
+		t.Error("Expected verify challenge, got setup challenge")
+	}
+	if tokens != nil || sessionCreated {
+		t.Error("Expected no tokens or session before 2FA verification")
+	}
+
+	claims, err := utils.ValidateJWT(challenge.ChallengeToken)
+	if err != nil {
+		t.Fatalf("Expected valid challenge token, got %v", err)
+	}
+	if claims.Purpose != utils.PurposeTwoFactorVerify {
+		t.Errorf("Expected purpose %s, got %s", utils.PurposeTwoFactorVerify, claims.Purpose)
+	}
+}
+
+// TestLogin_AdminTwoFactorRequired menguji admin tanpa 2FA diarahkan ke setup jika 2FA diwajibkan
+func TestLogin_AdminTwoFactorRequired(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	initTwoFactorPermissions()
+
+	siteSettingRepo := &MockSiteSettingRepository{Setting: &domain.SiteSetting{ID: 1, RequireAdmin2FA: true}}
+
+	authService := NewAuthService(newTwoFactorTestUserRepo(), &MockSessionRepository{}, &MockTwoFactorRepository{}, siteSettingRepo, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, _, err := authService.Login(context.Background(), "admin@example.com", "admin123")
+
+	var challenge *TwoFactorChallengeError
+	if !errors.As(err, &challenge) {
+		t.Fatalf("Expected TwoFactorChallengeError, got %v", err)
+	}
+	if !challenge.SetupRequired {
+		t.Error("Expected setup challenge for admin without 2FA")
+	}
+}
+
+// TestLogin_TwoFactorRequiredByPermission menguji kewajiban 2FA mengikuti permission role, bukan role ID admin
+func TestLogin_TwoFactorRequiredByPermission(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	initTwoFactorPermissions()
+
+	siteSettingRepo := &MockSiteSettingRepository{Setting: &domain.SiteSetting{ID: 1, RequireAdmin2FA: true}}
+
+	for role, wantSetup := range map[int]bool{5: true, domain.RoleAuthorID: false} {
+		user := &domain.User{ID: 9, Email: "staff@example.com", PasswordHash: adminHashedPassword, Role: role, IsActive: true}
+		userRepo := &MockUserRepository{
+			FindByEmailFunc: func(email string) (*domain.User, error) { return user, nil },
+		}
+
+		authService := NewAuthService(userRepo, &MockSessionRepository{}, &MockTwoFactorRepository{}, siteSettingRepo, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+		_, _, err := authService.Login(context.Background(), user.Email, "admin123")
+
+		var challenge *TwoFactorChallengeError
+		gotSetup := errors.As(err, &challenge) && challenge.SetupRequired
+		if gotSetup != wantSetup {
+			t.Errorf("role %d: expected setup challenge %t, got err %v", role, wantSetup, err)
+		}
+	}
+}
+
+// TestVerifyTwoFactor_Success menguji kode TOTP valid menyelesaikan login dan challenge tidak bisa dipakai ulang
+func TestVerifyTwoFactor_Success(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	utils.InitRevocationStore(&MockRevocationBackend{})
+	secret, _ := utils.GenerateTOTPSecret()
+
+	twoFactorRepo := &MockTwoFactorRepository{
+		FindByUserIDFunc: func(userID int) (*domain.UserTwoFactor, error) { return enabledTwoFactor(secret), nil },
+	}
+
+	authService := NewAuthService(newTwoFactorTestUserRepo(), &MockSessionRepository{}, twoFactorRepo, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	_, _, err := authService.Login(context.Background(), "admin@example.com", "admin123")
+
+	var challenge *TwoFactorChallengeError
+	if !errors.As(err, &challenge) {
+		t.Fatalf("Expected TwoFactorChallengeError, got %v", err)
+	}
+
+	code, _ := utils.GenerateTOTPCode(secret, time.Now())
+	user, tokens, err := authService.VerifyTwoFactor(context.Background(), challenge.ChallengeToken, code)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user == nil || tokens == nil || tokens.AccessToken == "" {
+		t.Fatal("Expected user and tokens after 2FA verification")
+	}
+
+	// Challenge token sudah dipakai
+	if _, _, err := authService.VerifyTwoFactor(context.Background(), challenge.ChallengeToken, code); !errors.Is(err, ErrInvalidChallengeToken) {
+		t.Errorf("Expected ErrInvalidChallengeToken on reuse, got %v", err)
+	}
+}
+
+// TestVerifyTwoFactor_RecoveryCode menguji recovery code dipakai sebagai pengganti kode TOTP
+func TestVerifyTwoFactor_RecoveryCode(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	utils.InitRevocationStore(&MockRevocationBackend{})
+	secret, _ := utils.GenerateTOTPSecret()
+
+	var usedHash string
+	twoFactorRepo := &MockTwoFactorRepository{
+		FindByUserIDFunc: func(userID int) (*domain.UserTwoFactor, error) { return enabledTwoFactor(secret), nil },
+		UseRecoveryCodeFunc: func(userID int, codeHash string) (bool, error) {
+			usedHash = codeHash
+			return codeHash == utils.HashToken("abcde12345"), nil
+		},
+	}
+
+	authService := NewAuthService(newTwoFactorTestUserRepo(), &MockSessionRepository{}, twoFactorRepo, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	challengeToken, _ := utils.GenerateChallengeToken(1, "1", utils.PurposeTwoFactorVerify)
+
+	if _, _, err := authService.VerifyTwoFactor(context.Background(), challengeToken, "ABCDE-12345"); err != nil {
+		t.Fatalf("Expected recovery code to be accepted, got %v", err)
+	}
+	if usedHash != utils.HashToken("abcde12345") {
+		t.Error("Expected recovery code to be normalized and hashed")
+	}
+}
+
+// TestVerifyTwoFactor_ReplayedCode menguji kode TOTP yang sudah dipakai ditolak
+func TestVerifyTwoFactor_ReplayedCode(t *testing.T) {
+	utils.InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	utils.InitRevocationStore(&MockRevocationBackend{})
+	secret, _ := utils.GenerateTOTPSecret()
+
+	twoFactorRepo := &MockTwoFactorRepository{
+		FindByUserIDFunc:   func(userID int) (*domain.UserTwoFactor, error) { return enabledTwoFactor(secret), nil },
+		UpdateLastStepFunc: func(userID int, step int64) (bool, error) { return false, nil },
+	}
+
+	authService := NewAuthService(newTwoFactorTestUserRepo(), &MockSessionRepository{}, twoFactorRepo, &MockSiteSettingRepository{}, &MockAccountLockoutService{}, &MockActivityLogRepoForAuth{})
+	challengeToken, _ := utils.GenerateChallengeToken(1, "1", utils.PurposeTwoFactorVerify)
+
+	code, _ := utils.GenerateTOTPCode(secret, time.Now())
+	if _, _, err := authService.VerifyTwoFactor(context.Background(), challengeToken, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+}
+
+// TestTwoFactorConfirm_Success menguji konfirmasi enrollment mengaktifkan 2FA dan membuat recovery code
+func TestTwoFactorConfirm_Success(t *testing.T) {
+	secret, _ := utils.GenerateTOTPSecret()
+
+	var storedHashes []string
+	twoFactorRepo := &MockTwoFactorRepository{
+		FindByUserIDFunc: func(userID int) (*domain.UserTwoFactor, error) {
+			return &domain.UserTwoFactor{UserID: 1, Secret: secret}, nil
+		},
+		EnableFunc: func(userID int, step int64, codeHashes []string) (bool, error) {
+			storedHashes = codeHashes
+			return true, nil
+		},
+	}
+
+	svc := NewTwoFactorService(newTwoFactorTestUserRepo(), twoFactorRepo, &MockSiteSettingRepository{}, &MockActivityLogRepoForAuth{})
+
+	if _, err := svc.Confirm(context.Background(), 1, "000000x"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+
+	code, _ := utils.GenerateTOTPCode(secret, time.Now())
+	codes, err := svc.Confirm(context.Background(), 1, code)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(storedHashes) != recoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d (stored %d)", recoveryCodeCount, len(codes), len(storedHashes))
+	}
+	if storedHashes[0] != utils.HashToken(normalizeRecoveryCode(codes[0])) {
+		t.Error("Expected recovery codes to be stored hashed")
+	}
+}
+
+// TestTwoFactorDisable_RequiredForAdmin menguji admin tidak bisa menonaktifkan 2FA saat diwajibkan
+func TestTwoFactorDisable_RequiredForAdmin(t *testing.T) {
+	initTwoFactorPermissions()
+	disabled := false
+	twoFactorRepo := &MockTwoFactorRepository{
+		DisableFunc: func(userID int) error {
+			disabled = true
+			return nil
+		},
+	}
+	siteSettingRepo := &MockSiteSettingRepository{Setting: &domain.SiteSetting{ID: 1, RequireAdmin2FA: true}}
+
+	svc := NewTwoFactorService(newTwoFactorTestUserRepo(), twoFactorRepo, siteSettingRepo, &MockActivityLogRepoForAuth{})
+	err := svc.Disable(context.Background(), 1, "admin123", "123456")
+
+	if !errors.Is(err, ErrTwoFactorRequiredForAdmin) {
+		t.Errorf("Expected ErrTwoFactorRequiredForAdmin, got %v", err)
+	}
+	if disabled {
+		t.Error("Expected 2FA to stay enabled")
+	}
+}
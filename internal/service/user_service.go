@@ -9,6 +9,7 @@ import (
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/logger"
 	"github.com/garuda-labs-1/pmii-be/pkg/utils"
 )
 
@@ -23,16 +24,18 @@ type UserService interface {
 
 type userService struct {
 	userRepo          repository.UserRepository
+	roleRepo          repository.RoleRepository
+	apiKeyRepo        repository.APIKeyRepository
 	cloudinaryService CloudinaryService
-	activityLogRepo   repository.ActivityLogRepository
 }
 
 // NewUserService constructor untuk UserService
-func NewUserService(userRepo repository.UserRepository, cloudinaryService CloudinaryService, activityLogRepo repository.ActivityLogRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, roleRepo repository.RoleRepository, apiKeyRepo repository.APIKeyRepository, cloudinaryService CloudinaryService) UserService {
 	return &userService{
 		userRepo:          userRepo,
+		roleRepo:          roleRepo,
+		apiKeyRepo:        apiKeyRepo,
 		cloudinaryService: cloudinaryService,
-		activityLogRepo:   activityLogRepo,
 	}
 }
 
@@ -83,12 +86,10 @@ func (s *userService) GetUserByID(ctx context.Context, id int) (*domain.User, er
 	return user, nil
 }
 
-// CreateUser membuat user baru
+// CreateUser membuat user baru dengan password dari admin (InvitationService agar user mengisi password sendiri)
 func (s *userService) CreateUser(ctx context.Context, req *requests.CreateUserRequest, photoFile *multipart.FileHeader) (*domain.User, error) {
 	// Validasi password harus kombinasi huruf dan angka
-	hasLetter := regexp.MustCompile(`[a-zA-Z]`).MatchString(req.Password)
-	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(req.Password)
-	if !hasLetter || !hasNumber {
+	if !isValidPassword(req.Password) {
 		return nil, ErrInvalidPassword
 	}
 
@@ -125,7 +126,7 @@ func (s *userService) CreateUser(ctx context.Context, req *requests.CreateUserRe
 	}
 
 	// Simpan ke database
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.WithContext(ctx).Create(user); err != nil {
 		// Rollback: hapus foto dari Cloudinary jika save gagal
 		if photoFileName != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "users/avatars", *photoFileName)
@@ -139,15 +140,6 @@ func (s *userService) CreateUser(ctx context.Context, req *requests.CreateUserRe
 		user.PhotoURI = &fullPhotoURL
 	}
 
-	// Log activity - Create User
-	s.logActivity(ctx, domain.ActionCreate, domain.ModuleUser, "Membuat user baru: "+user.FullName, nil, map[string]any{
-		"id":        user.ID,
-		"full_name": user.FullName,
-		"email":     user.Email,
-		"role":      user.Role,
-		"is_active": user.IsActive,
-	}, &user.ID)
-
 	return user, nil
 }
 
@@ -161,14 +153,11 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 
 	oldPhoto := user.PhotoURI
 
-	// Store old values for audit log
-	oldValues := map[string]any{
-		"id":        user.ID,
-		"full_name": user.FullName,
-		"email":     user.Email,
-		"role":      user.Role,
-		"is_active": user.IsActive,
-		"photo_uri": user.PhotoURI,
+	// Role baru harus ada di tabel roles
+	if req.Role != nil && *req.Role != user.Role {
+		if _, err := s.roleRepo.FindByID(*req.Role); err != nil {
+			return nil, ErrRoleNotFound
+		}
 	}
 
 	// Upload photo baru ke cloudinary (jika ada)
@@ -189,11 +178,13 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 		}
 	}
 
+	// Token lama harus dicabut jika password diganti, role diubah atau user dinonaktifkan
+	revokeTokens := false
+	deactivated := false
+
 	// Jika password diisi, validasi dan hash
 	if req.Password != nil && *req.Password != "" {
-		hasLetter := regexp.MustCompile(`[a-zA-Z]`).MatchString(*req.Password)
-		hasNumber := regexp.MustCompile(`[0-9]`).MatchString(*req.Password)
-		if !hasLetter || !hasNumber {
+		if !isValidPassword(*req.Password) {
 			return nil, ErrInvalidPassword
 		}
 
@@ -202,6 +193,7 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 			return nil, ErrPasswordProcessing
 		}
 		user.PasswordHash = hashedPassword
+		revokeTokens = true
 	}
 
 	// Update fields (hanya field yang ada di request)
@@ -211,10 +203,15 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 	if req.Email != nil {
 		user.Email = *req.Email
 	}
-	if req.Role != nil {
+	if req.Role != nil && *req.Role != user.Role {
 		user.Role = *req.Role
+		revokeTokens = true
 	}
 	if req.IsActive != nil {
+		if user.IsActive && !*req.IsActive {
+			revokeTokens = true
+			deactivated = true
+		}
 		user.IsActive = *req.IsActive
 	}
 
@@ -222,7 +219,7 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 		user.PhotoURI = newPhotoFileName
 	}
 	// Simpan ke database
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.WithContext(ctx).Update(user); err != nil {
 		// hapus photo baru jika update gagal (dan ada foto baru)
 		if newPhotoFileName != nil {
 			_ = s.cloudinaryService.DeleteImage(ctx, "users/avatars", *newPhotoFileName)
@@ -230,6 +227,13 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 		return nil, ErrUserUpdateFailed
 	}
 
+	if revokeTokens {
+		revokeAllUserTokens(user.ID)
+	}
+	if deactivated {
+		s.revokeAPIKeys(user.ID)
+	}
+
 	// hapus photo lama (hanya jika ada foto baru yang diupload)
 	if newPhotoFileName != nil && oldPhoto != nil {
 		_ = s.cloudinaryService.DeleteImage(ctx, "users/avatars", *oldPhoto)
@@ -244,46 +248,37 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *requests.Upda
 		s.resolvePhotoURL(user)
 	}
 
-	// Log activity - Update User (with old values tracking)
-	s.logActivity(ctx, domain.ActionUpdate, domain.ModuleUser, "Mengupdate user: "+user.FullName, oldValues, map[string]any{
-		"id":        user.ID,
-		"full_name": user.FullName,
-		"email":     user.Email,
-		"role":      user.Role,
-		"is_active": user.IsActive,
-		"photo_uri": user.PhotoURI,
-	}, &user.ID)
-
 	return user, nil
 }
 
 // DeleteUser menghapus user berdasarkan ID (soft delete)
 func (s *userService) DeleteUser(ctx context.Context, id int) error {
-	// Cek apakah user ada dan ambil info foto
-	user, err := s.userRepo.FindByID(id)
-	if err != nil {
+	// Cek apakah user ada
+	if _, err := s.userRepo.FindByID(id); err != nil {
 		return ErrUserNotFound
 	}
 
-	// Log activity sebelum delete
-	s.logActivity(ctx, domain.ActionDelete, domain.ModuleUser, "Menghapus user: "+user.FullName, map[string]any{
-		"id":        user.ID,
-		"full_name": user.FullName,
-		"email":     user.Email,
-		"role":      user.Role,
-		"is_active": user.IsActive,
-	}, nil, &user.ID)
-
 	// Hapus user (soft delete via GORM)
-	if err := s.userRepo.Delete(id); err != nil {
+	if err := s.userRepo.WithContext(ctx).Delete(id); err != nil {
 		return ErrUserDeleteFailed
 	}
 
+	// User terhapus tidak boleh lagi memakai token yang masih berlaku
+	revokeAllUserTokens(id)
+	s.revokeAPIKeys(id)
+
 	// Foto tidak dihapus dari cloudinary karena soft delete
 
 	return nil
 }
 
+// isValidPassword cek password kombinasi huruf dan angka
+func isValidPassword(password string) bool {
+	hasLetter := regexp.MustCompile(`[a-zA-Z]`).MatchString(password)
+	hasNumber := regexp.MustCompile(`[0-9]`).MatchString(password)
+	return hasLetter && hasNumber
+}
+
 // User service errors
 var (
 	ErrUserNotFound       = errors.New("user tidak ditemukan")
@@ -298,36 +293,9 @@ var (
 	ErrUserFetchFailed    = errors.New("gagal mengambil data user")
 )
 
-// logActivity helper untuk mencatat activity log
-func (s *userService) logActivity(ctx context.Context, actionType domain.ActivityActionType, module domain.ActivityModuleType, description string, oldValue, newValue map[string]any, targetID *int) {
-	userID, ok := utils.GetUserID(ctx)
-	if !ok {
-		return // Skip if no user in context
+// revokeAPIKeys mencabut semua API key yang dibuat user, karena key bertindak atas namanya
+func (s *userService) revokeAPIKeys(userID int) {
+	if _, err := s.apiKeyRepo.RevokeAllByCreator(userID); err != nil {
+		logger.Error.Printf("gagal mencabut API key user %d: %v", userID, err)
 	}
-
-	ipAddress := utils.GetIPAddress(ctx)
-	userAgent := utils.GetUserAgent(ctx)
-
-	var ipPtr, uaPtr *string
-	if ipAddress != "" {
-		ipPtr = &ipAddress
-	}
-	if userAgent != "" {
-		uaPtr = &userAgent
-	}
-
-	log := &domain.ActivityLog{
-		UserID:      userID,
-		ActionType:  actionType,
-		Module:      module,
-		Description: &description,
-		TargetID:    targetID,
-		OldValue:    oldValue,
-		NewValue:    newValue,
-		IPAddress:   ipPtr,
-		UserAgent:   uaPtr,
-	}
-
-	// Ignore error - logging should not affect main operation
-	_ = s.activityLogRepo.Create(log)
 }
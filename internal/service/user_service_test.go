@@ -8,7 +8,6 @@ import (
 
 	"github.com/garuda-labs-1/pmii-be/internal/domain"
 	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
-	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
 	"github.com/garuda-labs-1/pmii-be/internal/repository"
 )
 
@@ -64,6 +63,10 @@ func (m *MockUserRepositoryForUserService) Delete(id int) error {
 	return nil
 }
 
+func (m *MockUserRepositoryForUserService) WithContext(ctx context.Context) repository.UserRepository {
+	return m
+}
+
 // MockCloudinaryService adalah mock untuk CloudinaryService (untuk testing UserService)
 type MockCloudinaryServiceForUserService struct {
 	UploadImageFunc    func(ctx context.Context, folder string, file *multipart.FileHeader) (string, error)
@@ -124,22 +127,6 @@ func (m *MockCloudinaryServiceForUserService) GetDownloadURL(folder string, file
 	return ""
 }
 
-// MockActivityLogRepoForUser adalah mock untuk ActivityLogRepository
-type MockActivityLogRepoForUser struct {
-	CreateFunc func(log *domain.ActivityLog) error
-}
-
-func (m *MockActivityLogRepoForUser) Create(log *domain.ActivityLog) error {
-	if m.CreateFunc != nil {
-		return m.CreateFunc(log)
-	}
-	return nil
-}
-
-func (m *MockActivityLogRepoForUser) GetActivityLogs(offset, limit int, filter repository.ActivityLogFilter) ([]responses.ActivityLogResponse, int64, error) {
-	return nil, 0, nil
-}
-
 // Helper functions untuk membuat pointer dari value
 func strPtr(s string) *string { return &s }
 func intPtr(i int) *int       { return &i }
@@ -216,7 +203,7 @@ func TestGetAllUsers(t *testing.T) {
 					return tt.mockUsers, tt.mockTotal, tt.mockErr
 				},
 			}
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			users, currentPage, lastPage, total, err := service.GetAllUsers(context.Background(), tt.page, tt.limit)
 
 			if tt.expectedErr != nil {
@@ -257,7 +244,7 @@ func TestGetAllUsers_DefaultValues(t *testing.T) {
 			return []domain.User{}, 0, nil
 		},
 	}
-	service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 	service.GetAllUsers(context.Background(), 0, 0) // Pass invalid values to test defaults
 }
 
@@ -307,7 +294,7 @@ func TestGetUserByID(t *testing.T) {
 					return nil, errors.New("not found")
 				},
 			}
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			user, err := service.GetUserByID(context.Background(), tt.userID)
 
 			if tt.expectedErr != nil {
@@ -351,7 +338,7 @@ func TestCreateUser_PasswordValidation(t *testing.T) {
 				FindByEmailFunc: func(email string) (*domain.User, error) { return nil, errors.New("not found") },
 				CreateFunc:      func(user *domain.User) error { user.ID = 1; return nil },
 			}
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			req := &requests.CreateUserRequest{
 				FullName: "Test User",
 				Email:    "test@example.com",
@@ -426,7 +413,7 @@ func TestCreateUser_ErrorCases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo, mockCloudinary := tt.setupMock()
-			service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 			req := &requests.CreateUserRequest{
 				FullName: "Test User",
 				Email:    "test@example.com",
@@ -461,7 +448,7 @@ func TestCreateUser_WithPhotoFile(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 	req := &requests.CreateUserRequest{FullName: "Test User", Email: "test@example.com", Password: "password123"}
 	user, err := service.CreateUser(context.Background(), req, &multipart.FileHeader{Filename: "test.jpg"})
 
@@ -499,7 +486,7 @@ func TestCreateUser_RollbackOnDBError(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 	req := &requests.CreateUserRequest{FullName: "Test User", Email: "test@example.com", Password: "password123"}
 	user, err := service.CreateUser(context.Background(), req, &multipart.FileHeader{Filename: "test.jpg"})
 
@@ -530,7 +517,7 @@ func TestUpdateUser_Success(t *testing.T) {
 		FindByEmailFunc: func(email string) (*domain.User, error) { return nil, errors.New("not found") },
 		UpdateFunc:      func(user *domain.User) error { return nil },
 	}
-	service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 	req := &requests.UpdateUserRequest{
 		FullName: strPtr("New Name"),
 		Email:    strPtr("new@example.com"),
@@ -547,6 +534,30 @@ func TestUpdateUser_Success(t *testing.T) {
 	}
 }
 
+// TestUpdateUser_DeactivateRevokesAPIKeys menguji API key milik user dicabut saat user dinonaktifkan
+func TestUpdateUser_DeactivateRevokesAPIKeys(t *testing.T) {
+	existingUser := &domain.User{ID: 1, FullName: "Old Name", Email: "old@example.com", PasswordHash: "oldhash", Role: 1, IsActive: true}
+
+	mockRepo := &MockUserRepositoryForUserService{
+		FindByIDFunc: func(id int) (*domain.User, error) { return existingUser, nil },
+		UpdateFunc:   func(user *domain.User) error { return nil },
+	}
+	apiKeyRepo := newMockAPIKeyRepository()
+	apiKeyRepo.Create(&domain.APIKey{Prefix: "aaaa", CreatedBy: 1})
+	apiKeyRepo.Create(&domain.APIKey{Prefix: "bbbb", CreatedBy: 2})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, apiKeyRepo, &MockCloudinaryServiceForUserService{})
+
+	if _, err := service.UpdateUser(context.Background(), 1, &requests.UpdateUserRequest{IsActive: boolPtr(false)}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if apiKeyRepo.keys["aaaa"].RevokedAt == nil {
+		t.Error("expected API key of deactivated user to be revoked")
+	}
+	if apiKeyRepo.keys["bbbb"].RevokedAt != nil {
+		t.Error("expected API key of other user to stay active")
+	}
+}
+
 // TestUpdateUser_PasswordValidation menguji validasi password saat update
 func TestUpdateUser_PasswordValidation(t *testing.T) {
 	existingUser := &domain.User{ID: 1, FullName: "Test User", Email: "test@example.com", PasswordHash: "oldhash", Role: 2, IsActive: true}
@@ -572,7 +583,7 @@ func TestUpdateUser_PasswordValidation(t *testing.T) {
 				},
 				UpdateFunc: func(user *domain.User) error { return nil },
 			}
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			req := &requests.UpdateUserRequest{
 				FullName: strPtr(existingUser.FullName),
 				Email:    strPtr(existingUser.Email),
@@ -671,7 +682,7 @@ func TestUpdateUser_ErrorCases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo, mockCloudinary := tt.setupMock()
-			service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 			req := &requests.UpdateUserRequest{
 				FullName: strPtr("Updated Name"),
 				Email:    strPtr("user2@example.com"),
@@ -698,7 +709,7 @@ func TestUpdateUser_SameEmailAllowed(t *testing.T) {
 		FindByIDFunc: func(id int) (*domain.User, error) { return existingUser, nil },
 		UpdateFunc:   func(user *domain.User) error { return nil },
 	}
-	service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 	req := &requests.UpdateUserRequest{
 		FullName: strPtr("User One Updated"),
 		Email:    strPtr("user1@example.com"), // Email sama
@@ -756,7 +767,7 @@ func TestUpdateUser_PartialUpdate(t *testing.T) {
 				},
 				UpdateFunc: func(user *domain.User) error { return nil },
 			}
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			user, err := service.UpdateUser(context.Background(), 1, tt.request, nil)
 
 			if err != nil {
@@ -793,7 +804,7 @@ func TestUpdateUser_WithNewPhotoFile(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 	req := &requests.UpdateUserRequest{FullName: strPtr("Test User"), Email: strPtr("test@example.com"), Role: intPtr(2), IsActive: boolPtr(true)}
 	user, err := service.UpdateUser(context.Background(), 1, req, &multipart.FileHeader{Filename: "new-photo.jpg"})
 
@@ -863,7 +874,7 @@ func TestDeleteUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := tt.setupMock()
-			service := NewUserService(mockRepo, &MockCloudinaryServiceForUserService{}, &MockActivityLogRepoForUser{})
+			service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), &MockCloudinaryServiceForUserService{})
 			err := service.DeleteUser(context.Background(), tt.userID)
 
 			if tt.expectedErr != nil {
@@ -898,7 +909,7 @@ func TestDeleteUser_NoPhotoCleanupOnSoftDelete(t *testing.T) {
 		},
 	}
 
-	service := NewUserService(mockRepo, mockCloudinary, &MockActivityLogRepoForUser{})
+	service := NewUserService(mockRepo, &MockRoleRepository{}, newMockAPIKeyRepository(), mockCloudinary)
 	err := service.DeleteUser(context.Background(), 1)
 
 	if err != nil {
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/responses"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+)
+
+// visitorSaltSize panjang salt harian (byte)
+const visitorSaltSize = 32
+
+// VisitorHasher menghasilkan pengganti IP visitor yang hanya bisa dicocokkan pada hari yang sama
+type VisitorHasher interface {
+	HashIP(ip string) (string, error)
+}
+
+// VisitorPrivacyService interface untuk anonimisasi dan retensi data visitor analytics
+type VisitorPrivacyService interface {
+	VisitorHasher
+
+	// Purge mengosongkan hash IP, user agent, provinsi dan kota yang melewati masa retensi
+	// serta menghapus salt hari sebelumnya sehingga hash yang tersisa tidak bisa dibalik
+	Purge() (*VisitorPurgeResult, error)
+
+	// GetDataProcessingNotice keterangan pemrosesan data untuk halaman kebijakan privasi
+	GetDataProcessingNotice() responses.DataProcessingNoticeResponse
+}
+
+// VisitorPurgeResult jumlah baris yang dianonimkan dalam satu purge
+type VisitorPurgeResult struct {
+	Visitors  int64
+	PostViews int64
+	Salts     int64
+}
+
+type visitorPrivacyService struct {
+	visitorRepo   repository.VisitorRepository
+	retentionDays int
+
+	mu      sync.Mutex
+	saltDay time.Time
+	salt    []byte
+
+	now func() time.Time
+}
+
+// NewVisitorPrivacyService constructor untuk VisitorPrivacyService
+func NewVisitorPrivacyService(visitorRepo repository.VisitorRepository, retentionDays int) VisitorPrivacyService {
+	return &visitorPrivacyService{
+		visitorRepo:   visitorRepo,
+		retentionDays: retentionDays,
+		now:           time.Now,
+	}
+}
+
+// HashIP menghitung HMAC-SHA256 IP dengan salt hari ini (hex, 64 karakter)
+// Hash IP yang sama selalu sama dalam satu hari sehingga hitungan unique visitor tetap benar
+func (s *visitorPrivacyService) HashIP(ip string) (string, error) {
+	salt, err := s.dailySalt()
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// dailySalt mengambil salt hari ini dari cache, atau dari database saat hari berganti
+// Salt disimpan di database agar semua replica memakai salt yang sama
+func (s *visitorPrivacyService) dailySalt() ([]byte, error) {
+	today := startOfDay(s.now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.salt != nil && s.saltDay.Equal(today) {
+		return s.salt, nil
+	}
+
+	candidate := make([]byte, visitorSaltSize)
+	if _, err := rand.Read(candidate); err != nil {
+		return nil, fmt.Errorf("generate visitor salt: %w", err)
+	}
+	salt, err := s.visitorRepo.GetOrCreateSalt(today, candidate)
+	if err != nil {
+		return nil, fmt.Errorf("load visitor salt: %w", err)
+	}
+
+	s.saltDay = today
+	s.salt = salt
+	return salt, nil
+}
+
+// Purge menjalankan retensi data mentah visitor, jumlah baris tetap sehingga dashboard tidak berubah
+func (s *visitorPrivacyService) Purge() (*VisitorPurgeResult, error) {
+	now := s.now()
+
+	visitors, postViews, err := s.visitorRepo.AnonymizeBefore(now.AddDate(0, 0, -s.retentionDays))
+	if err != nil {
+		return nil, fmt.Errorf("anonymize visitor data: %w", err)
+	}
+	salts, err := s.visitorRepo.DeleteSaltsBefore(startOfDay(now))
+	if err != nil {
+		return nil, fmt.Errorf("delete visitor salts: %w", err)
+	}
+
+	return &VisitorPurgeResult{Visitors: visitors, PostViews: postViews, Salts: salts}, nil
+}
+
+// GetDataProcessingNotice menyusun keterangan sesuai konfigurasi retensi yang berlaku
+func (s *visitorPrivacyService) GetDataProcessingNotice() responses.DataProcessingNoticeResponse {
+	retention := fmt.Sprintf("%d hari, setelah itu dikosongkan", s.retentionDays)
+	// Baris kunjungan tidak dihapus, hanya kolom yang bisa mengidentifikasi pengunjung yang dikosongkan
+	keptPerVisit := "Disimpan per kunjungan tanpa batas waktu, tidak lagi terhubung ke hash IP setelah masa retensi"
+
+	return responses.DataProcessingNoticeResponse{
+		Summary: "Kami mencatat kunjungan untuk statistik jumlah pengunjung dan pembaca berita. Alamat IP tidak pernah disimpan.",
+		Items: []responses.DataProcessingItem{
+			{
+				Data:      "Hash alamat IP",
+				Purpose:   "Menghitung pengunjung unik per hari dan mencegah satu pembaca dihitung berulang kali",
+				Storage:   "HMAC-SHA256 dengan salt acak harian, alamat IP asli dibuang",
+				Retention: retention,
+			},
+			{
+				Data:      "Jenis browser, sistem operasi dan perangkat",
+				Purpose:   "Statistik perangkat pengunjung",
+				Storage:   "Hanya kategori (mis. Chrome, Android, mobile), user agent lengkap tidak disimpan",
+				Retention: keptPerVisit,
+			},
+			{
+				Data:      "User agent pembaca berita",
+				Purpose:   "Membedakan pembaca saat menghitung jumlah view berita",
+				Storage:   "Disimpan apa adanya bersama catatan view",
+				Retention: retention,
+			},
+			{
+				Data:      "Domain situs perujuk dan parameter UTM kampanye",
+				Purpose:   "Mengetahui asal pengunjung dan efektivitas kampanye",
+				Storage:   "Hanya domain perujuk (tanpa path dan query) dan nilai utm_source, utm_medium, utm_campaign",
+				Retention: keptPerVisit,
+			},
+			{
+				Data:      "Perkiraan lokasi (negara, provinsi dan kota)",
+				Purpose:   "Statistik sebaran pengunjung per wilayah",
+				Storage:   "Dicari dari database geolokasi lokal di server, alamat IP tidak dikirim ke pihak lain",
+				Retention: fmt.Sprintf("Provinsi dan kota %d hari, setelah itu dikosongkan; negara disimpan per kunjungan tanpa batas waktu", s.retentionDays),
+			},
+			{
+				Data:      "Waktu kunjungan dan berita yang dibaca",
+				Purpose:   "Statistik jumlah pengunjung dan berita terpopuler di dashboard",
+				Storage:   "Tanpa data yang mengidentifikasi pengunjung setelah masa retensi",
+				Retention: keptPerVisit,
+			},
+		},
+		IPHashing:      "HMAC-SHA256(salt harian, alamat IP)",
+		SaltRotation:   "Salt baru dibuat setiap hari dan salt hari sebelumnya dihapus, sehingga hash tidak bisa dikembalikan ke alamat IP maupun dicocokkan antar hari",
+		RetentionDays:  s.retentionDays,
+		ThirdParties:   []string{},
+		TrackingCookie: false,
+	}
+}
+
+// startOfDay mengembalikan pukul 00:00 pada hari t (zona waktu server, sama dengan DATE() di query statistik)
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+)
+
+// MockVisitorRepository adalah mock untuk VisitorRepository, salt disimpan di map per hari
+type MockVisitorRepository struct {
+	Salts          map[string][]byte
+	SaltCalls      int
+	SaltErr        error
+	AnonymizedAt   time.Time
+	SaltsDeletedAt time.Time
+	Visits         []domain.Visitor
+	Sources        []domain.Visitor
+}
+
+func (m *MockVisitorRepository) RecordVisit(visitor *domain.Visitor) error {
+	m.Visits = append(m.Visits, *visitor)
+	return nil
+}
+func (m *MockVisitorRepository) RecordSource(visitor *domain.Visitor) error {
+	m.Sources = append(m.Sources, *visitor)
+	return nil
+}
+func (m *MockVisitorRepository) HasVisitedToday(visitorHash string) (bool, error) { return false, nil }
+func (m *MockVisitorRepository) GetUniqueVisitors(startDate, endDate time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *MockVisitorRepository) GetDailyVisitors(startDate, endDate time.Time) ([]repository.DailyVisitors, error) {
+	return nil, nil
+}
+
+func (m *MockVisitorRepository) GetOrCreateSalt(day time.Time, candidate []byte) ([]byte, error) {
+	m.SaltCalls++
+	if m.SaltErr != nil {
+		return nil, m.SaltErr
+	}
+	key := day.Format("2006-01-02")
+	if _, ok := m.Salts[key]; !ok {
+		m.Salts[key] = candidate
+	}
+	return m.Salts[key], nil
+}
+
+func (m *MockVisitorRepository) DeleteSaltsBefore(day time.Time) (int64, error) {
+	m.SaltsDeletedAt = day
+	return 1, nil
+}
+
+func (m *MockVisitorRepository) AnonymizeBefore(before time.Time) (int64, int64, error) {
+	m.AnonymizedAt = before
+	return 3, 7, nil
+}
+
+func newTestVisitorPrivacyService(repo *MockVisitorRepository, now *time.Time) *visitorPrivacyService {
+	svc := NewVisitorPrivacyService(repo, 30).(*visitorPrivacyService)
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+// TestVisitorHashIP_DailySalt menguji hash stabil dalam satu hari dan berubah saat salt harian berganti
+func TestVisitorHashIP_DailySalt(t *testing.T) {
+	repo := &MockVisitorRepository{Salts: map[string][]byte{}}
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	svc := newTestVisitorPrivacyService(repo, &now)
+
+	morning, err := svc.HashIP("203.0.113.7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(morning) != 64 || strings.Contains(morning, "203.0.113.7") {
+		t.Errorf("expected 64 character hex hash, got %q", morning)
+	}
+
+	now = now.Add(14 * time.Hour)
+	evening, _ := svc.HashIP("203.0.113.7")
+	other, _ := svc.HashIP("203.0.113.8")
+	if evening != morning {
+		t.Error("expected the same IP to hash identically within a day")
+	}
+	if other == morning {
+		t.Error("expected different IPs to produce different hashes")
+	}
+	if repo.SaltCalls != 1 {
+		t.Errorf("expected salt to be loaded once per day, loaded %d times", repo.SaltCalls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	nextDay, _ := svc.HashIP("203.0.113.7")
+	if nextDay == morning {
+		t.Error("expected a new salt (and hash) on the next day")
+	}
+	if len(repo.Salts) != 2 {
+		t.Errorf("expected 2 daily salts, got %d", len(repo.Salts))
+	}
+}
+
+// TestVisitorHashIP_SaltError menguji error repository diteruskan (kunjungan tidak dicatat)
+func TestVisitorHashIP_SaltError(t *testing.T) {
+	repo := &MockVisitorRepository{Salts: map[string][]byte{}, SaltErr: errors.New("db down")}
+	now := time.Now()
+	svc := newTestVisitorPrivacyService(repo, &now)
+
+	if _, err := svc.HashIP("203.0.113.7"); err == nil {
+		t.Fatal("expected error when salt cannot be loaded")
+	}
+
+	// Salt gagal tidak di-cache, percobaan berikutnya mengambil ulang
+	repo.SaltErr = nil
+	if _, err := svc.HashIP("203.0.113.7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.SaltCalls != 2 {
+		t.Errorf("expected salt to be reloaded after error, loaded %d times", repo.SaltCalls)
+	}
+}
+
+// TestVisitorPurge_RetentionWindow menguji batas retensi dan penghapusan salt hari sebelumnya
+func TestVisitorPurge_RetentionWindow(t *testing.T) {
+	repo := &MockVisitorRepository{Salts: map[string][]byte{}}
+	now := time.Date(2026, 3, 10, 9, 30, 0, 0, time.Local)
+	svc := newTestVisitorPrivacyService(repo, &now)
+
+	result, err := svc.Purge()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Visitors != 3 || result.PostViews != 7 || result.Salts != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if want := time.Date(2026, 2, 8, 9, 30, 0, 0, time.Local); !repo.AnonymizedAt.Equal(want) {
+		t.Errorf("expected data before %s to be anonymized, got %s", want, repo.AnonymizedAt)
+	}
+	if want := time.Date(2026, 3, 10, 0, 0, 0, 0, time.Local); !repo.SaltsDeletedAt.Equal(want) {
+		t.Errorf("expected salts before %s to be deleted, got %s", want, repo.SaltsDeletedAt)
+	}
+}
+
+// TestVisitorDataProcessingNotice menguji keterangan privasi mengikuti konfigurasi retensi
+func TestVisitorDataProcessingNotice(t *testing.T) {
+	svc := NewVisitorPrivacyService(&MockVisitorRepository{}, 14)
+
+	notice := svc.GetDataProcessingNotice()
+	if notice.RetentionDays != 14 || notice.TrackingCookie || len(notice.Items) == 0 {
+		t.Errorf("unexpected notice: %+v", notice)
+	}
+	if !strings.Contains(notice.Items[0].Retention, "14 hari") {
+		t.Errorf("expected retention in notice items, got %q", notice.Items[0].Retention)
+	}
+	// Data disimpan per kunjungan, bukan agregat; lokasi detail ikut dikosongkan setelah masa retensi
+	for _, item := range notice.Items {
+		if strings.Contains(item.Retention, "agregat") {
+			t.Errorf("notice must not claim per-visit data is aggregated: %+v", item)
+		}
+		if strings.Contains(item.Data, "lokasi") && !strings.Contains(item.Retention, "14 hari") {
+			t.Errorf("expected region and city retention in location item, got %q", item.Retention)
+		}
+	}
+}
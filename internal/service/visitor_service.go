@@ -0,0 +1,167 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/geoip"
+	"github.com/garuda-labs-1/pmii-be/pkg/useragent"
+)
+
+// Batas panjang kolom sumber kunjungan
+const (
+	maxReferrerHostLength = 255
+	maxUTMValueLength     = 100
+	maxGeoNameLength      = 100
+)
+
+// GeoLocator mencari lokasi perkiraan IP dari database geolokasi offline (geoip.Reader)
+type GeoLocator interface {
+	Locate(ip string) (geoip.Location, bool)
+}
+
+// noopGeoLocator dipakai saat database geolokasi tidak diatur, lokasi selalu kosong
+type noopGeoLocator struct{}
+
+func (noopGeoLocator) Locate(ip string) (geoip.Location, bool) { return geoip.Location{}, false }
+
+// orNoopGeoLocator mengganti locator nil dengan noopGeoLocator
+func orNoopGeoLocator(locator GeoLocator) GeoLocator {
+	if locator == nil {
+		return noopGeoLocator{}
+	}
+	return locator
+}
+
+// locateIP mengembalikan kolom country, region dan city (nil jika tidak diketahui)
+func locateIP(locator GeoLocator, ip string) (country, region, city *string) {
+	location, ok := locator.Locate(ip)
+	if !ok {
+		return nil, nil, nil
+	}
+	if len(location.CountryCode) == 2 {
+		code := strings.ToUpper(location.CountryCode)
+		country = &code
+	}
+	return country, geoName(location.Region), geoName(location.City)
+}
+
+func geoName(name string) *string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	if runes := []rune(name); len(runes) > maxGeoNameLength {
+		name = string(runes[:maxGeoNameLength])
+	}
+	return &name
+}
+
+// VisitorService interface untuk pencatatan kunjungan visitor analytics
+type VisitorService interface {
+	// RecordVisit mencatat kunjungan (1 visitor = 1 record per hari) tanpa sumber
+	RecordVisit(ip, userAgent string) error
+
+	// RecordBeacon mencatat kunjungan beserta referrer dan parameter UTM dari beacon frontend
+	RecordBeacon(ip, userAgent string, req requests.VisitorBeaconRequest) error
+}
+
+type visitorService struct {
+	visitorRepo   repository.VisitorRepository
+	visitorHasher VisitorHasher
+	geoLocator    GeoLocator
+	siteHosts     map[string]bool
+}
+
+// NewVisitorService constructor untuk VisitorService
+// geoLocator boleh nil jika geolokasi tidak diaktifkan
+// siteOrigins origin frontend sendiri (ALLOWED_ORIGINS), referrer dari host ini dianggap navigasi internal
+func NewVisitorService(visitorRepo repository.VisitorRepository, visitorHasher VisitorHasher, geoLocator GeoLocator, siteOrigins []string) VisitorService {
+	siteHosts := make(map[string]bool)
+	for _, origin := range siteOrigins {
+		if host := referrerHost(strings.TrimSpace(origin)); host != "" {
+			siteHosts[host] = true
+		}
+	}
+
+	return &visitorService{
+		visitorRepo:   visitorRepo,
+		visitorHasher: visitorHasher,
+		geoLocator:    orNoopGeoLocator(geoLocator),
+		siteHosts:     siteHosts,
+	}
+}
+
+// RecordVisit mencatat kunjungan dari request API biasa
+func (s *visitorService) RecordVisit(ip, userAgent string) error {
+	visitor, err := s.newVisitor(ip, userAgent)
+	if err != nil {
+		return err
+	}
+	return s.visitorRepo.RecordVisit(visitor)
+}
+
+// RecordBeacon mencatat kunjungan dengan sumbernya, hanya domain referrer yang disimpan (bukan URL lengkap)
+func (s *visitorService) RecordBeacon(ip, userAgent string, req requests.VisitorBeaconRequest) error {
+	visitor, err := s.newVisitor(ip, userAgent)
+	if err != nil {
+		return err
+	}
+
+	if host := referrerHost(req.Referrer); host != "" && !s.siteHosts[host] {
+		visitor.ReferrerHost = &host
+	}
+	visitor.UTMSource = utmValue(req.UTMSource)
+	visitor.UTMMedium = utmValue(req.UTMMedium)
+	visitor.UTMCampaign = utmValue(req.UTMCampaign)
+
+	return s.visitorRepo.RecordSource(visitor)
+}
+
+// newVisitor menyiapkan record visitor dengan hash IP, klasifikasi user agent dan lokasi perkiraan
+func (s *visitorService) newVisitor(ip, userAgent string) (*domain.Visitor, error) {
+	visitorHash, err := s.visitorHasher.HashIP(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	agent := useragent.Parse(userAgent)
+	visitor := &domain.Visitor{
+		VisitorHash: &visitorHash,
+		Browser:     &agent.Browser,
+		OS:          &agent.OS,
+		DeviceType:  &agent.Device,
+	}
+	visitor.Country, visitor.Region, visitor.City = locateIP(s.geoLocator, ip)
+	return visitor, nil
+}
+
+// referrerHost mengambil host dari URL referrer (huruf kecil, tanpa "www.")
+// URL tidak valid atau bukan http/https menghasilkan string kosong
+func referrerHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if len(host) > maxReferrerHostLength {
+		return ""
+	}
+	return host
+}
+
+// utmValue menormalkan nilai UTM agar "Instagram" dan "instagram " terhitung sama
+func utmValue(value string) *string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil
+	}
+	if runes := []rune(value); len(runes) > maxUTMValueLength {
+		value = string(runes[:maxUTMValueLength])
+	}
+	return &value
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/pkg/geoip"
+)
+
+// stubVisitorHasher hash tetap tanpa salt database
+type stubVisitorHasher struct{}
+
+func (stubVisitorHasher) HashIP(ip string) (string, error) { return "hash-" + ip, nil }
+
+// stubGeoLocator lokasi tetap per IP, pengganti database MMDB
+type stubGeoLocator map[string]geoip.Location
+
+func (s stubGeoLocator) Locate(ip string) (geoip.Location, bool) {
+	location, ok := s[ip]
+	return location, ok
+}
+
+const testChromeAndroidUA = "Mozilla/5.0 (Linux; Android 13; SM-A546E) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+
+// TestVisitorRecordVisit_ParsesUserAgent menguji user agent disimpan sebagai browser, OS dan device
+func TestVisitorRecordVisit_ParsesUserAgent(t *testing.T) {
+	repo := &MockVisitorRepository{}
+	svc := NewVisitorService(repo, stubVisitorHasher{}, nil, nil)
+
+	if err := svc.RecordVisit("203.0.113.7", testChromeAndroidUA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.Visits) != 1 {
+		t.Fatalf("expected 1 visit, got %d", len(repo.Visits))
+	}
+
+	visit := repo.Visits[0]
+	if *visit.VisitorHash != "hash-203.0.113.7" || *visit.Browser != "Chrome" || *visit.OS != "Android" || *visit.DeviceType != "mobile" {
+		t.Errorf("unexpected visit: hash=%s browser=%s os=%s device=%s", *visit.VisitorHash, *visit.Browser, *visit.OS, *visit.DeviceType)
+	}
+	if visit.ReferrerHost != nil || visit.UTMCampaign != nil {
+		t.Error("expected no traffic source for a plain visit")
+	}
+	if visit.Country != nil || visit.Region != nil || visit.City != nil {
+		t.Error("expected no location without a geolocation database")
+	}
+}
+
+// TestVisitorRecordVisit_Geolocation menguji lokasi perkiraan disimpan saat database geolokasi tersedia
+func TestVisitorRecordVisit_Geolocation(t *testing.T) {
+	repo := &MockVisitorRepository{}
+	locator := stubGeoLocator{
+		"203.0.113.7":  {CountryCode: "id", Country: "Indonesia", Region: "Jawa Timur", City: "Surabaya"},
+		"198.51.100.1": {CountryCode: "ID", Country: "Indonesia"},
+	}
+	svc := NewVisitorService(repo, stubVisitorHasher{}, locator, nil)
+
+	for _, ip := range []string{"203.0.113.7", "198.51.100.1", "192.0.2.1"} {
+		if err := svc.RecordVisit(ip, testChromeAndroidUA); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	full := repo.Visits[0]
+	if full.Country == nil || *full.Country != "ID" || *full.Region != "Jawa Timur" || *full.City != "Surabaya" {
+		t.Errorf("unexpected location: %v %v %v", full.Country, full.Region, full.City)
+	}
+
+	countryOnly := repo.Visits[1]
+	if countryOnly.Country == nil || *countryOnly.Country != "ID" || countryOnly.Region != nil || countryOnly.City != nil {
+		t.Errorf("expected country only, got %v %v %v", countryOnly.Country, countryOnly.Region, countryOnly.City)
+	}
+
+	if unknown := repo.Visits[2]; unknown.Country != nil || unknown.Region != nil || unknown.City != nil {
+		t.Error("expected no location for an IP missing from the database")
+	}
+}
+
+// TestVisitorRecordBeacon_TrafficSource menguji normalisasi referrer dan parameter UTM
+func TestVisitorRecordBeacon_TrafficSource(t *testing.T) {
+	repo := &MockVisitorRepository{}
+	svc := NewVisitorService(repo, stubVisitorHasher{}, nil, []string{"https://pmii.id", " http://localhost:3000"})
+
+	err := svc.RecordBeacon("203.0.113.7", testChromeAndroidUA, requests.VisitorBeaconRequest{
+		Referrer:    "https://www.Google.com/search?q=pmii+jakarta",
+		UTMSource:   " Instagram ",
+		UTMCampaign: "Harlah-PMII",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	source := repo.Sources[0]
+	if source.ReferrerHost == nil || *source.ReferrerHost != "google.com" {
+		t.Errorf("expected referrer host google.com, got %v", source.ReferrerHost)
+	}
+	if *source.UTMSource != "instagram" || *source.UTMCampaign != "harlah-pmii" || source.UTMMedium != nil {
+		t.Errorf("unexpected UTM values: %v %v %v", source.UTMSource, source.UTMMedium, source.UTMCampaign)
+	}
+	if *source.DeviceType != "mobile" {
+		t.Errorf("expected parsed device, got %v", *source.DeviceType)
+	}
+
+	// Navigasi internal dan referrer yang bukan URL http tidak dihitung sebagai perujuk
+	for _, referrer := range []string{"https://pmii.id/berita/harlah", "http://localhost:3000/", "android-app://com.google.android.gm", "bukan url"} {
+		_ = svc.RecordBeacon("203.0.113.7", testChromeAndroidUA, requests.VisitorBeaconRequest{Referrer: referrer})
+		if last := repo.Sources[len(repo.Sources)-1]; last.ReferrerHost != nil {
+			t.Errorf("expected referrer %q to be ignored, got %s", referrer, *last.ReferrerHost)
+		}
+	}
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/utils"
+	"github.com/garuda-labs-1/pmii-be/pkg/webhook"
+)
+
+// Pengaturan pengiriman webhook
+const (
+	webhookSecretPrefix   = "whsec_"
+	webhookSecretBytes    = 24
+	webhookClaimBatchSize = 20
+	webhookErrorBodyLimit = 1024 // Potongan body response yang disimpan saat pengiriman gagal
+	webhookUserAgent      = "PMII-Webhook/1.0"
+)
+
+// Jeda retry: 1m, 2m, 4m, ... dibatasi 6 jam
+var (
+	webhookRetryBaseDelay = time.Minute
+	webhookRetryMaxDelay  = 6 * time.Hour
+)
+
+// WebhookPublisher dipakai service lain untuk mengirim event ke webhook
+type WebhookPublisher interface {
+	// Publish mengantrikan event untuk semua webhook aktif yang berlangganan
+	// Dipanggil setelah perubahan tersimpan, kegagalan hanya dicatat ke log dan tidak menggagalkan operasi utama
+	Publish(ctx context.Context, event string, data any)
+}
+
+// noopWebhookPublisher dipakai jika service dibuat tanpa publisher (mis. di test)
+type noopWebhookPublisher struct{}
+
+func (noopWebhookPublisher) Publish(ctx context.Context, event string, data any) {}
+
+// orNoopWebhookPublisher mengganti publisher nil dengan noop
+func orNoopWebhookPublisher(publisher WebhookPublisher) WebhookPublisher {
+	if publisher == nil {
+		return noopWebhookPublisher{}
+	}
+	return publisher
+}
+
+// WebhookPolicy pengaturan pengiriman webhook dari config
+type WebhookPolicy struct {
+	MaxAttempts         int
+	AllowPrivateTargets bool
+}
+
+// WebhookService interface untuk manajemen webhook dan pengiriman event
+type WebhookService interface {
+	WebhookPublisher
+
+	GetAll(ctx context.Context) ([]domain.Webhook, error)
+	// Create mengembalikan webhook beserta secret plaintext (hanya ditampilkan sekali)
+	Create(ctx context.Context, req *requests.CreateWebhookRequest) (*domain.Webhook, error)
+	// Update mengembalikan secret baru jika RotateSecret, string kosong jika secret tidak dirotasi
+	Update(ctx context.Context, id int, req *requests.UpdateWebhookRequest) (*domain.Webhook, string, error)
+	Delete(ctx context.Context, id int) error
+	// Test mengantrikan event webhook.test ke satu webhook
+	Test(ctx context.Context, id int) (*domain.WebhookDelivery, error)
+
+	GetDeliveries(ctx context.Context, webhookID, page, limit int) ([]domain.WebhookDelivery, int, int64, error)
+	// Replay mengirim ulang payload yang sama (event id sama) sebagai delivery baru
+	Replay(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error)
+
+	// ProcessDue mengirim delivery yang sudah jatuh tempo, mengembalikan jumlah delivery yang diproses
+	ProcessDue(ctx context.Context) (int, error)
+	// Run menjalankan worker pengiriman sampai ctx selesai
+	Run(ctx context.Context, pollInterval time.Duration)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	policy      WebhookPolicy
+
+	wake chan struct{}
+	now  func() time.Time
+}
+
+// NewWebhookService constructor untuk WebhookService
+func NewWebhookService(webhookRepo repository.WebhookRepository, client *http.Client, policy WebhookPolicy) WebhookService {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client:      client,
+		policy:      policy,
+		wake:        make(chan struct{}, 1),
+		now:         time.Now,
+	}
+}
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook tidak ditemukan")
+	ErrWebhookDeliveryNotFound = errors.New("log pengiriman webhook tidak ditemukan")
+	ErrInvalidWebhookURL       = errors.New("URL webhook harus http(s) dengan host yang valid")
+	ErrWebhookPrivateURL       = errors.New("URL webhook tidak boleh mengarah ke alamat privat atau internal")
+	ErrUnknownWebhookEvent     = errors.New("event webhook tidak dikenal")
+	ErrWebhookInactive         = errors.New("webhook tidak aktif")
+	ErrWebhookSaveFailed       = errors.New("gagal menyimpan webhook")
+	ErrWebhookFetchFailed      = errors.New("gagal mengambil data webhook")
+)
+
+// webhookEnvelope adalah body JSON yang dikirim ke penerima
+type webhookEnvelope struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// GetAll mengambil semua webhook
+func (s *webhookService) GetAll(ctx context.Context) ([]domain.Webhook, error) {
+	hooks, err := s.webhookRepo.FindAll()
+	if err != nil {
+		return nil, ErrWebhookFetchFailed
+	}
+	return hooks, nil
+}
+
+// Create mendaftarkan webhook baru, secret dibuat otomatis jika tidak diisi
+func (s *webhookService) Create(ctx context.Context, req *requests.CreateWebhookRequest) (*domain.Webhook, error) {
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, ErrWebhookSaveFailed
+		}
+	}
+
+	hook := &domain.Webhook{
+		Name:     req.Name,
+		URL:      req.URL,
+		Secret:   secret,
+		Events:   events,
+		IsActive: req.IsActive == nil || *req.IsActive,
+	}
+	if userID, ok := utils.GetUserID(ctx); ok {
+		hook.CreatedBy = &userID
+	}
+
+	if err := s.webhookRepo.WithContext(ctx).Create(hook); err != nil {
+		return nil, ErrWebhookSaveFailed
+	}
+	return hook, nil
+}
+
+// Update mengubah webhook, perubahan URL/secret langsung dipakai untuk retry yang masih pending
+func (s *webhookService) Update(ctx context.Context, id int, req *requests.UpdateWebhookRequest) (*domain.Webhook, string, error) {
+	hook, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, "", ErrWebhookNotFound
+	}
+
+	if req.Name != nil {
+		hook.Name = *req.Name
+	}
+	if req.URL != nil {
+		if err := s.validateURL(*req.URL); err != nil {
+			return nil, "", err
+		}
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, "", err
+		}
+		hook.Events = events
+	}
+	if req.IsActive != nil {
+		hook.IsActive = *req.IsActive
+	}
+
+	var newSecret string
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+	if req.RotateSecret {
+		if newSecret, err = generateWebhookSecret(); err != nil {
+			return nil, "", ErrWebhookSaveFailed
+		}
+		hook.Secret = newSecret
+	}
+
+	if err := s.webhookRepo.WithContext(ctx).Update(hook); err != nil {
+		return nil, "", ErrWebhookSaveFailed
+	}
+	return hook, newSecret, nil
+}
+
+// Delete menghapus webhook beserta log pengirimannya
+func (s *webhookService) Delete(ctx context.Context, id int) error {
+	hook, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+	if err := s.webhookRepo.WithContext(ctx).Delete(hook); err != nil {
+		return ErrWebhookSaveFailed
+	}
+	return nil
+}
+
+// Test mengantrikan event webhook.test, webhook tidak perlu berlangganan event ini
+func (s *webhookService) Test(ctx context.Context, id int) (*domain.WebhookDelivery, error) {
+	hook, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	if !hook.IsActive {
+		return nil, ErrWebhookInactive
+	}
+
+	deliveries, err := s.enqueue(domain.WebhookEventWebhookTest, map[string]any{
+		"webhookId": hook.ID,
+		"message":   "Tes webhook dari PMII",
+	}, []domain.Webhook{*hook})
+	if err != nil {
+		return nil, ErrWebhookSaveFailed
+	}
+	return &deliveries[0], nil
+}
+
+// GetDeliveries mengambil log pengiriman satu webhook dengan pagination
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookID, page, limit int) ([]domain.WebhookDelivery, int, int64, error) {
+	if _, err := s.webhookRepo.FindByID(webhookID); err != nil {
+		return nil, 0, 0, ErrWebhookNotFound
+	}
+
+	deliveries, total, err := s.webhookRepo.FindDeliveries(webhookID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, 0, ErrWebhookFetchFailed
+	}
+
+	lastPage := int(math.Ceil(float64(total) / float64(limit)))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	return deliveries, lastPage, total, nil
+}
+
+// Replay membuat delivery baru dengan payload dan event id yang sama, ditandatangani ulang saat dikirim
+func (s *webhookService) Replay(ctx context.Context, deliveryID int64) (*domain.WebhookDelivery, error) {
+	original, err := s.webhookRepo.FindDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if original.Webhook == nil || !original.Webhook.IsActive {
+		return nil, ErrWebhookInactive
+	}
+
+	now := s.now()
+	replay := domain.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		ReplayOf:      &original.ID,
+	}
+	deliveries := []domain.WebhookDelivery{replay}
+	if err := s.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		return nil, ErrWebhookSaveFailed
+	}
+	s.notify()
+	return &deliveries[0], nil
+}
+
+// Publish mengantrikan event, pengiriman dilakukan worker secara asinkron
+func (s *webhookService) Publish(ctx context.Context, event string, data any) {
+	hooks, err := s.webhookRepo.FindActiveByEvent(event)
+	if err != nil {
+		log.Printf("[WARN] webhook: gagal mengambil langganan event %s: %v", event, err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	if _, err := s.enqueue(event, data, hooks); err != nil {
+		log.Printf("[WARN] webhook: gagal mengantrikan event %s: %v", event, err)
+	}
+}
+
+// enqueue membuat satu delivery per webhook dengan payload yang sama lalu membangunkan worker
+func (s *webhookService) enqueue(event string, data any, hooks []domain.Webhook) ([]domain.WebhookDelivery, error) {
+	eventID, err := utils.GenerateRandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+	eventID = "evt_" + eventID
+
+	now := s.now()
+	payload, err := json.Marshal(webhookEnvelope{ID: eventID, Event: event, CreatedAt: now.UTC(), Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]domain.WebhookDelivery, len(hooks))
+	for i, hook := range hooks {
+		deliveries[i] = domain.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       eventID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return deliveries, nil
+}
+
+// notify membangunkan worker tanpa menunggu jika worker sedang sibuk
+func (s *webhookService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run memproses antrian setiap pollInterval dan segera setelah ada event baru
+// Antrian disimpan di database sehingga retry tetap berjalan setelah restart
+func (s *webhookService) Run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			processed, err := s.ProcessDue(ctx)
+			if err != nil {
+				log.Printf("[WARN] webhook: gagal memproses antrian: %v", err)
+			}
+			if processed < webhookClaimBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// ProcessDue mengirim satu batch delivery yang jatuh tempo secara paralel
+func (s *webhookService) ProcessDue(ctx context.Context) (int, error) {
+	// Lease lebih lama dari timeout request agar delivery tidak diambil worker lain selama masih dikirim
+	lease := s.client.Timeout + time.Minute
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(s.now(), webhookClaimBatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(delivery *domain.WebhookDelivery) {
+			defer wg.Done()
+			s.deliver(ctx, delivery)
+			if err := s.webhookRepo.SaveDeliveryResult(delivery); err != nil {
+				log.Printf("[WARN] webhook: gagal menyimpan hasil delivery %d: %v", delivery.ID, err)
+			}
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliver melakukan satu percobaan kirim dan memperbarui status delivery
+func (s *webhookService) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	if delivery.Webhook == nil || !delivery.Webhook.IsActive {
+		s.fail(delivery, nil, "webhook tidak aktif", true)
+		return
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		s.fail(delivery, nil, err.Error(), true)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhook.HeaderEvent, delivery.Event)
+	req.Header.Set(webhook.HeaderEventID, delivery.EventID)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.SignatureHeader(delivery.Webhook.Secret, s.now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.fail(delivery, nil, err.Error(), false)
+		return
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorBodyLimit))
+
+	status := resp.StatusCode
+	if status >= 200 && status < 300 {
+		now := s.now()
+		delivery.Attempts++
+		delivery.Status = domain.WebhookDeliverySuccess
+		delivery.ResponseStatus = &status
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	message := fmt.Sprintf("HTTP %d", status)
+	if len(snippet) > 0 {
+		message += ": " + string(bytes.ToValidUTF8(snippet, nil))
+	}
+	s.fail(delivery, &status, message, false)
+}
+
+// fail mencatat percobaan gagal dan menjadwalkan retry dengan backoff eksponensial
+// permanent = true untuk kegagalan yang tidak akan berhasil jika diulang (webhook nonaktif, URL rusak)
+func (s *webhookService) fail(delivery *domain.WebhookDelivery, status *int, message string, permanent bool) {
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	delivery.LastError = &message
+
+	if permanent || delivery.Attempts >= s.policy.MaxAttempts {
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := s.now().Add(webhookRetryDelay(delivery.Attempts))
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.NextAttemptAt = &next
+}
+
+// webhookRetryDelay jeda sebelum percobaan ke-(attempts+1)
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// validateURL memetakan error validasi URL ke error service
+func (s *webhookService) validateURL(rawURL string) error {
+	err := webhook.ValidateURL(rawURL, s.policy.AllowPrivateTargets)
+	switch {
+	case errors.Is(err, webhook.ErrPrivateAddress):
+		return ErrWebhookPrivateURL
+	case err != nil:
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookEvents memvalidasi event dan menghapus duplikat
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	valid := make(map[string]bool, len(domain.WebhookEvents))
+	for _, event := range domain.WebhookEvents {
+		valid[event] = true
+	}
+
+	result := make([]string, 0, len(events))
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		if !valid[event] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownWebhookEvent, event)
+		}
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+	if len(result) == 0 {
+		return nil, ErrUnknownWebhookEvent
+	}
+	return result, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret, err := utils.GenerateRandomToken(webhookSecretBytes)
+	if err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + secret, nil
+}
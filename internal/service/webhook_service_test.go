@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/garuda-labs-1/pmii-be/internal/domain"
+	"github.com/garuda-labs-1/pmii-be/internal/dto/requests"
+	"github.com/garuda-labs-1/pmii-be/internal/repository"
+	"github.com/garuda-labs-1/pmii-be/pkg/webhook"
+)
+
+// MockWebhookRepository menyimpan webhook dan delivery di memory
+type MockWebhookRepository struct {
+	mu         sync.Mutex
+	Webhooks   []domain.Webhook
+	Deliveries []domain.WebhookDelivery
+}
+
+func (m *MockWebhookRepository) Create(hook *domain.Webhook) error {
+	hook.ID = len(m.Webhooks) + 1
+	m.Webhooks = append(m.Webhooks, *hook)
+	return nil
+}
+
+func (m *MockWebhookRepository) FindAll() ([]domain.Webhook, error) {
+	return m.Webhooks, nil
+}
+
+func (m *MockWebhookRepository) FindByID(id int) (*domain.Webhook, error) {
+	for i := range m.Webhooks {
+		if m.Webhooks[i].ID == id {
+			hook := m.Webhooks[i]
+			return &hook, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *MockWebhookRepository) Update(hook *domain.Webhook) error {
+	for i := range m.Webhooks {
+		if m.Webhooks[i].ID == hook.ID {
+			m.Webhooks[i] = *hook
+		}
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) Delete(hook *domain.Webhook) error {
+	return nil
+}
+
+func (m *MockWebhookRepository) FindActiveByEvent(event string) ([]domain.Webhook, error) {
+	var result []domain.Webhook
+	for _, hook := range m.Webhooks {
+		if hook.IsActive && hook.Subscribes(event) {
+			result = append(result, hook)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockWebhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range deliveries {
+		deliveries[i].ID = int64(len(m.Deliveries) + 1)
+		m.Deliveries = append(m.Deliveries, deliveries[i])
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(now time.Time, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []domain.WebhookDelivery
+	for i := range m.Deliveries {
+		delivery := &m.Deliveries[i]
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) || len(result) == limit {
+			continue
+		}
+		leased := now.Add(lease)
+		delivery.NextAttemptAt = &leased
+
+		claimed := *delivery
+		claimed.Webhook, _ = m.FindByID(delivery.WebhookID)
+		result = append(result, claimed)
+	}
+	return result, nil
+}
+
+func (m *MockWebhookRepository) SaveDeliveryResult(delivery *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.Deliveries {
+		if m.Deliveries[i].ID == delivery.ID {
+			saved := *delivery
+			saved.Webhook = nil
+			m.Deliveries[i] = saved
+		}
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) FindDeliveries(webhookID, offset, limit int) ([]domain.WebhookDelivery, int64, error) {
+	var result []domain.WebhookDelivery
+	for _, delivery := range m.Deliveries {
+		if delivery.WebhookID == webhookID {
+			result = append(result, delivery)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *MockWebhookRepository) FindDeliveryByID(id int64) (*domain.WebhookDelivery, error) {
+	for i := range m.Deliveries {
+		if m.Deliveries[i].ID == id {
+			delivery := m.Deliveries[i]
+			delivery.Webhook, _ = m.FindByID(delivery.WebhookID)
+			return &delivery, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *MockWebhookRepository) WithContext(ctx context.Context) repository.WebhookRepository {
+	return m
+}
+
+// recordingWebhookPublisher mencatat event yang dikirim service konten
+type recordingWebhookPublisher struct {
+	events []string
+	data   []any
+}
+
+func (p *recordingWebhookPublisher) Publish(ctx context.Context, event string, data any) {
+	p.events = append(p.events, event)
+	p.data = append(p.data, data)
+}
+
+func newTestWebhookService(repo *MockWebhookRepository, maxAttempts int) *webhookService {
+	svc := NewWebhookService(repo, webhook.NewHTTPClient(5*time.Second, true), WebhookPolicy{MaxAttempts: maxAttempts, AllowPrivateTargets: true})
+	return svc.(*webhookService)
+}
+
+// TestWebhookCreate_Validation menguji validasi event, URL dan pembuatan secret otomatis
+func TestWebhookCreate_Validation(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	svc := NewWebhookService(repo, http.DefaultClient, WebhookPolicy{MaxAttempts: 3})
+	ctx := context.Background()
+
+	hook, err := svc.Create(ctx, &requests.CreateWebhookRequest{
+		Name:   "Bot Telegram",
+		URL:    "https://bot.pmii.id/hooks",
+		Events: []string{domain.WebhookEventPostPublished, domain.WebhookEventPostPublished},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hook.Secret) < 32 || hook.Secret[:6] != "whsec_" || !hook.IsActive || len(hook.Events) != 1 {
+		t.Errorf("unexpected webhook: %+v", hook)
+	}
+
+	_, err = svc.Create(ctx, &requests.CreateWebhookRequest{Name: "Bot", URL: "https://bot.pmii.id", Events: []string{"post.unknown"}})
+	if !errors.Is(err, ErrUnknownWebhookEvent) {
+		t.Errorf("expected ErrUnknownWebhookEvent, got %v", err)
+	}
+	_, err = svc.Create(ctx, &requests.CreateWebhookRequest{Name: "Bot", URL: "http://127.0.0.1:9000", Events: []string{domain.WebhookEventPostPublished}})
+	if !errors.Is(err, ErrWebhookPrivateURL) {
+		t.Errorf("expected ErrWebhookPrivateURL, got %v", err)
+	}
+	_, err = svc.Create(ctx, &requests.CreateWebhookRequest{Name: "Bot", URL: "javascript:alert(1)", Events: []string{domain.WebhookEventPostPublished}})
+	if !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("expected ErrInvalidWebhookURL, got %v", err)
+	}
+}
+
+// TestWebhookPublish_DeliversSignedPayload menguji event hanya dikirim ke webhook yang berlangganan dengan signature valid
+func TestWebhookPublish_DeliversSignedPayload(t *testing.T) {
+	var received []*http.Request
+	var bodies [][]byte
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &MockWebhookRepository{Webhooks: []domain.Webhook{
+		{ID: 1, URL: server.URL, Secret: "whsec_satu", Events: []string{domain.WebhookEventDocumentCreated}, IsActive: true},
+		{ID: 2, URL: server.URL, Secret: "whsec_dua", Events: []string{domain.WebhookEventPostPublished}, IsActive: true},
+		{ID: 3, URL: server.URL, Secret: "whsec_tiga", Events: []string{domain.WebhookEventDocumentCreated}, IsActive: false},
+	}}
+	svc := newTestWebhookService(repo, 3)
+
+	svc.Publish(context.Background(), domain.WebhookEventDocumentCreated, map[string]any{"id": 9, "name": "AD/ART"})
+	if len(repo.Deliveries) != 1 || repo.Deliveries[0].WebhookID != 1 {
+		t.Fatalf("expected 1 delivery for webhook 1, got %+v", repo.Deliveries)
+	}
+
+	processed, err := svc.ProcessDue(context.Background())
+	if err != nil || processed != 1 {
+		t.Fatalf("expected 1 processed delivery, got %d, %v", processed, err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(received))
+	}
+
+	req := received[0]
+	if err := webhook.Verify("whsec_satu", req.Header.Get(webhook.HeaderSignature), bodies[0], time.Now(), 5*time.Minute); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+	if req.Header.Get(webhook.HeaderEvent) != domain.WebhookEventDocumentCreated || req.Header.Get(webhook.HeaderEventID) != repo.Deliveries[0].EventID {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+
+	var envelope map[string]any
+	if err := json.Unmarshal(bodies[0], &envelope); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if envelope["event"] != domain.WebhookEventDocumentCreated || envelope["data"].(map[string]any)["name"] != "AD/ART" {
+		t.Errorf("unexpected payload: %s", bodies[0])
+	}
+
+	delivery := repo.Deliveries[0]
+	if delivery.Status != domain.WebhookDeliverySuccess || delivery.Attempts != 1 || delivery.DeliveredAt == nil || *delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("unexpected delivery result: %+v", delivery)
+	}
+}
+
+// TestWebhookDelivery_RetryWithBackoff menguji retry dengan backoff eksponensial sampai batas percobaan
+func TestWebhookDelivery_RetryWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bot sedang maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	repo := &MockWebhookRepository{Webhooks: []domain.Webhook{
+		{ID: 1, URL: server.URL, Secret: "whsec_satu", Events: []string{domain.WebhookEventMemberUpdated}, IsActive: true},
+	}}
+	svc := newTestWebhookService(repo, 3)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	svc.Publish(context.Background(), domain.WebhookEventMemberUpdated, map[string]any{"id": 1})
+
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		if _, err := svc.ProcessDue(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		delivery := repo.Deliveries[0]
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: unexpected delivery %+v", attempt+1, delivery)
+		}
+		if delivery.NextAttemptAt == nil || !delivery.NextAttemptAt.Equal(now.Add(wantDelay)) {
+			t.Errorf("attempt %d: expected retry after %s, got %v", attempt+1, wantDelay, delivery.NextAttemptAt)
+		}
+		if delivery.LastError == nil || *delivery.LastError != "HTTP 503: bot sedang maintenance\n" {
+			t.Errorf("unexpected last error: %v", delivery.LastError)
+		}
+
+		// Belum jatuh tempo, tidak dikirim lagi
+		if processed, _ := svc.ProcessDue(context.Background()); processed != 0 {
+			t.Errorf("expected delivery not to be retried before its backoff, processed %d", processed)
+		}
+		now = *delivery.NextAttemptAt
+	}
+
+	if _, err := svc.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery := repo.Deliveries[0]; delivery.Status != domain.WebhookDeliveryFailed || delivery.Attempts != 3 || delivery.NextAttemptAt != nil {
+		t.Errorf("expected failed delivery after max attempts, got %+v", delivery)
+	}
+}
+
+// TestWebhookReplay menguji replay memakai payload dan event id yang sama
+func TestWebhookReplay(t *testing.T) {
+	repo := &MockWebhookRepository{Webhooks: []domain.Webhook{
+		{ID: 1, URL: "https://bot.pmii.id", Secret: "whsec_satu", Events: []string{domain.WebhookEventSettingsUpdated}, IsActive: true},
+	}}
+	svc := newTestWebhookService(repo, 3)
+	svc.Publish(context.Background(), domain.WebhookEventSettingsUpdated, map[string]any{"siteName": "PMII"})
+	repo.Deliveries[0].Status = domain.WebhookDeliveryFailed
+
+	replay, err := svc.Replay(context.Background(), repo.Deliveries[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original := repo.Deliveries[0]
+	if replay.ID == original.ID || replay.EventID != original.EventID || replay.Payload != original.Payload || replay.ReplayOf == nil || *replay.ReplayOf != original.ID {
+		t.Errorf("unexpected replay: %+v", replay)
+	}
+	if replay.Status != domain.WebhookDeliveryPending || replay.NextAttemptAt == nil {
+		t.Errorf("expected replay to be queued, got %+v", replay)
+	}
+
+	if _, err := svc.Replay(context.Background(), 99); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("expected ErrWebhookDeliveryNotFound, got %v", err)
+	}
+	repo.Webhooks[0].IsActive = false
+	if _, err := svc.Replay(context.Background(), original.ID); !errors.Is(err, ErrWebhookInactive) {
+		t.Errorf("expected ErrWebhookInactive, got %v", err)
+	}
+}
+
+// TestWebhookRetryDelay menguji jeda retry berlipat dua dan dibatasi maksimum
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 5: 16 * time.Minute, 9: 256 * time.Minute, 12: 6 * time.Hour}
+	for attempts, want := range tests {
+		if got := webhookRetryDelay(attempts); got != want {
+			t.Errorf("webhookRetryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
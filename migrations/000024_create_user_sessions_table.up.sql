@@ -0,0 +1,21 @@
+-- Refresh token sessions (satu row per refresh token, dikelompokkan per family)
+CREATE TABLE "user_sessions" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" INT NOT NULL,
+  "family_id" varchar(64) NOT NULL,
+  "refresh_token_hash" varchar(64) UNIQUE NOT NULL,
+  "device" varchar(100),
+  "ip_address" varchar(45),
+  "user_agent" text,
+  "expires_at" timestamp NOT NULL,
+  "last_used_at" timestamp,
+  "rotated_at" timestamp,
+  "revoked_at" timestamp,
+  "created_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "user_sessions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "user_sessions" ("user_id");
+CREATE INDEX ON "user_sessions" ("family_id");
+CREATE INDEX ON "user_sessions" ("expires_at");
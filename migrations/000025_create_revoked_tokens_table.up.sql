@@ -0,0 +1,12 @@
+-- Token revocation persisten (menggantikan blacklist in-memory)
+CREATE TABLE "revoked_tokens" (
+  "jti" varchar(64) PRIMARY KEY,
+  "user_id" INT NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "revoked_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX ON "revoked_tokens" ("expires_at");
+
+-- Watermark per user: token yang terbit sebelum waktu ini tidak valid
+ALTER TABLE "users" ADD COLUMN "tokens_valid_after" timestamp;
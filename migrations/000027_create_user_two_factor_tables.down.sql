@@ -0,0 +1,3 @@
+ALTER TABLE "site_settings" DROP COLUMN IF EXISTS "require_admin_2fa";
+DROP TABLE IF EXISTS "user_recovery_codes";
+DROP TABLE IF EXISTS "user_two_factors";
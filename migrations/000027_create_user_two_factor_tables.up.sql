@@ -0,0 +1,28 @@
+-- TOTP two-factor authentication (RFC 6238)
+-- Row dibuat saat enrollment, enabled_at terisi setelah kode pertama dikonfirmasi
+CREATE TABLE "user_two_factors" (
+  "user_id" INT PRIMARY KEY,
+  "secret" varchar(64) NOT NULL,
+  "enabled_at" timestamp,
+  "last_used_step" BIGINT,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "user_two_factors" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Recovery code sekali pakai, hanya hash SHA-256 yang disimpan
+CREATE TABLE "user_recovery_codes" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" INT NOT NULL,
+  "code_hash" varchar(64) NOT NULL,
+  "used_at" timestamp,
+  "created_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "user_recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "user_recovery_codes" ("user_id");
+
+-- Wajibkan 2FA untuk semua admin
+ALTER TABLE "site_settings" ADD COLUMN IF NOT EXISTS "require_admin_2fa" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- Add 'login_failed' value to activity_action_type enum
+ALTER TYPE activity_action_type ADD VALUE IF NOT EXISTS 'login_failed';
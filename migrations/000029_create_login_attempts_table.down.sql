@@ -0,0 +1,4 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locked_until";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "last_failed_login_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "failed_login_count";
+DROP TABLE IF EXISTS "login_attempts";
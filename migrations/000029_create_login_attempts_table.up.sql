@@ -0,0 +1,19 @@
+-- Riwayat percobaan login (per akun dan per IP) untuk deteksi brute-force
+CREATE TABLE "login_attempts" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "email" varchar(100) NOT NULL,
+  "user_id" INT,
+  "ip_address" varchar(45),
+  "success" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "login_attempts" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "login_attempts" ("ip_address", "created_at");
+CREATE INDEX ON "login_attempts" ("email", "created_at");
+
+-- Status lockout per akun
+ALTER TABLE "users" ADD COLUMN "failed_login_count" INT NOT NULL DEFAULT 0;
+ALTER TABLE "users" ADD COLUMN "last_failed_login_at" timestamp;
+ALTER TABLE "users" ADD COLUMN "locked_until" timestamp;
@@ -0,0 +1,62 @@
+-- Role dan permission berbasis database menggantikan role hard-coded (1=admin, 2=author)
+CREATE TABLE "roles" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "key" varchar(50) UNIQUE NOT NULL,
+  "name" varchar(100) NOT NULL,
+  "description" text,
+  "is_system" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "permissions" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "key" varchar(100) UNIQUE NOT NULL,
+  "description" text,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE TABLE "role_permissions" (
+  "role_id" INT NOT NULL,
+  "permission_id" INT NOT NULL,
+  PRIMARY KEY ("role_id", "permission_id")
+);
+
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("role_id") REFERENCES "roles" ("id") ON DELETE CASCADE;
+ALTER TABLE "role_permissions" ADD FOREIGN KEY ("permission_id") REFERENCES "permissions" ("id") ON DELETE CASCADE;
+
+-- Role bawaan memakai ID yang sama dengan nilai users.role yang sudah ada
+INSERT INTO "roles" ("id", "key", "name", "description", "is_system") VALUES
+  (1, 'admin', 'Admin', 'Akses penuh ke seluruh fitur admin', true),
+  (2, 'author', 'Author', 'Menulis dan mengelola postingan', true);
+
+ALTER TABLE "roles" ALTER COLUMN "id" RESTART WITH 3;
+
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('dashboard.view', 'Melihat dashboard'),
+  ('activity_logs.view', 'Melihat activity log'),
+  ('posts.create', 'Membuat postingan'),
+  ('posts.update', 'Mengubah postingan'),
+  ('posts.delete', 'Menghapus postingan'),
+  ('posts.publish', 'Mempublikasikan postingan'),
+  ('categories.manage', 'Mengelola kategori'),
+  ('tags.manage', 'Mengelola tag'),
+  ('testimonials.manage', 'Mengelola testimoni'),
+  ('members.manage', 'Mengelola anggota pengurus'),
+  ('about.manage', 'Mengelola halaman tentang'),
+  ('contact.manage', 'Mengelola kontak'),
+  ('settings.manage', 'Mengelola pengaturan situs'),
+  ('documents.manage', 'Mengelola dokumen'),
+  ('ads.manage', 'Mengelola iklan'),
+  ('users.manage', 'Mengelola user dan session'),
+  ('roles.manage', 'Mengelola role dan permission');
+
+-- Admin mendapat semua permission, author hanya permission postingan
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions";
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 2, "id" FROM "permissions"
+WHERE "key" IN ('dashboard.view', 'posts.create', 'posts.update', 'posts.delete', 'posts.publish');
+
+ALTER TABLE "users" ADD FOREIGN KEY ("role") REFERENCES "roles" ("id");
@@ -0,0 +1 @@
+DELETE FROM "permissions" WHERE "key" = 'posts.manage_any';
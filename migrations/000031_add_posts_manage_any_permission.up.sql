@@ -0,0 +1,7 @@
+-- Author hanya boleh mengubah/menghapus post miliknya sendiri,
+-- role dengan posts.manage_any boleh mengelola post siapa pun
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('posts.manage_any', 'Mengubah, menghapus dan memindahkan kepemilikan postingan milik siapa pun');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions" WHERE "key" = 'posts.manage_any';
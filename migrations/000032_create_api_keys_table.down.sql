@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE "key" = 'api_keys.manage';
+ALTER TABLE "activity_logs" DROP COLUMN IF EXISTS "api_key_id";
+DROP TABLE IF EXISTS "api_keys";
@@ -0,0 +1,29 @@
+-- API key untuk integrasi machine-to-machine (partner site, aplikasi mobile internal)
+-- Key hanya disimpan dalam bentuk hash SHA-256, prefix dipakai untuk lookup dan identifikasi
+CREATE TABLE "api_keys" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "name" varchar(100) NOT NULL,
+  "prefix" varchar(16) UNIQUE NOT NULL,
+  "key_hash" varchar(64) UNIQUE NOT NULL,
+  "scopes" jsonb NOT NULL DEFAULT '[]',
+  "allowed_ips" jsonb NOT NULL DEFAULT '[]',
+  "created_by" INT NOT NULL,
+  "expires_at" timestamp,
+  "last_used_at" timestamp,
+  "last_used_ip" varchar(45),
+  "revoked_at" timestamp,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id");
+
+-- Aksi yang dilakukan dengan API key dicatat bersama key yang dipakai
+ALTER TABLE "activity_logs" ADD COLUMN "api_key_id" INT;
+ALTER TABLE "activity_logs" ADD FOREIGN KEY ("api_key_id") REFERENCES "api_keys" ("id") ON DELETE SET NULL;
+
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('api_keys.manage', 'Mengelola API key integrasi');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions" WHERE "key" = 'api_keys.manage';
@@ -0,0 +1,29 @@
+-- State login OpenID Connect (authorization code + PKCE), sekali pakai dan berumur pendek
+-- State disimpan dalam bentuk hash SHA-256, nonce dan code verifier dipakai saat callback
+CREATE TABLE "oidc_login_states" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "provider" varchar(50) NOT NULL,
+  "state_hash" varchar(64) UNIQUE NOT NULL,
+  "nonce" varchar(64) NOT NULL,
+  "code_verifier" varchar(128) NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX ON "oidc_login_states" ("expires_at");
+
+-- Identitas eksternal (provider + subject) yang terhubung ke akun user
+CREATE TABLE "user_identities" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "user_id" INT NOT NULL,
+  "provider" varchar(50) NOT NULL,
+  "subject" varchar(255) NOT NULL,
+  "email" varchar(100) NOT NULL,
+  "last_login_at" timestamp,
+  "created_at" timestamp DEFAULT (now()),
+  UNIQUE ("provider", "subject")
+);
+
+ALTER TABLE "user_identities" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE INDEX ON "user_identities" ("user_id");
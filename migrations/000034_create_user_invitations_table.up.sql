@@ -0,0 +1,25 @@
+-- Undangan user baru: admin mengisi email dan role, user mengisi password dan profil sendiri
+-- Token undangan hanya disimpan dalam bentuk hash SHA-256, berlaku sampai expires_at dan sekali pakai
+CREATE TABLE "user_invitations" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "email" varchar(100) NOT NULL,
+  "role_id" INT NOT NULL,
+  "token_hash" varchar(64) UNIQUE NOT NULL,
+  "invited_by" INT NOT NULL,
+  "expires_at" timestamp NOT NULL,
+  "last_sent_at" timestamp NOT NULL DEFAULT (now()),
+  "send_count" INT NOT NULL DEFAULT 1,
+  "accepted_at" timestamp,
+  "accepted_user_id" INT,
+  "revoked_at" timestamp,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "user_invitations" ADD FOREIGN KEY ("role_id") REFERENCES "roles" ("id");
+ALTER TABLE "user_invitations" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("id");
+ALTER TABLE "user_invitations" ADD FOREIGN KEY ("accepted_user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+-- Hanya satu undangan terbuka per email
+CREATE UNIQUE INDEX "user_invitations_open_email_idx" ON "user_invitations" (lower("email"))
+  WHERE "accepted_at" IS NULL AND "revoked_at" IS NULL;
@@ -0,0 +1,5 @@
+-- Note: PostgreSQL doesn't support removing enum values directly,
+-- nilai 'impersonate' pada activity_action_type dibiarkan
+DROP INDEX IF EXISTS "idx_activity_logs_impersonator_id";
+ALTER TABLE "activity_logs" DROP COLUMN IF EXISTS "impersonator_id";
+DELETE FROM "permissions" WHERE "key" = 'users.impersonate';
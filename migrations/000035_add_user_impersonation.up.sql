@@ -0,0 +1,14 @@
+-- Admin dapat login sebagai user lain untuk membantu troubleshooting
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('users.impersonate', 'Masuk sebagai user lain (impersonasi) untuk membantu troubleshooting');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions" WHERE "key" = 'users.impersonate';
+
+-- Aksi selama impersonasi dicatat atas nama user yang diimpersonasi dan admin yang sebenarnya
+ALTER TABLE "activity_logs" ADD COLUMN "impersonator_id" INT;
+ALTER TABLE "activity_logs" ADD FOREIGN KEY ("impersonator_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+CREATE INDEX "idx_activity_logs_impersonator_id" ON "activity_logs" ("impersonator_id") WHERE "impersonator_id" IS NOT NULL;
+
+-- Add 'impersonate' value to activity_action_type enum
+ALTER TYPE activity_action_type ADD VALUE IF NOT EXISTS 'impersonate';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "csp_reports";
@@ -0,0 +1,23 @@
+-- Laporan pelanggaran Content Security Policy dari browser (POST /v1/csp-report)
+-- Dipakai untuk menyetel policy dengan mode report-only sebelum di-enforce
+CREATE TABLE "csp_reports" (
+  "id" BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "document_uri" text,
+  "referrer" text,
+  "violated_directive" varchar(255),
+  "effective_directive" varchar(255),
+  "blocked_uri" text,
+  "source_file" text,
+  "line_number" INT,
+  "column_number" INT,
+  "status_code" INT,
+  "sample" text,
+  "disposition" varchar(20),
+  "original_policy" text,
+  "ip_address" varchar(45),
+  "user_agent" text,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX "idx_csp_reports_created_at" ON "csp_reports" ("created_at");
+CREATE INDEX "idx_csp_reports_effective_directive" ON "csp_reports" ("effective_directive");
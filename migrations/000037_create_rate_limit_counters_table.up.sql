@@ -0,0 +1,11 @@
+-- Counter rate limit bersama antar instance (RATE_LIMIT_STORE=postgres)
+-- UNLOGGED: data sementara, tidak perlu WAL dan boleh hilang saat crash
+CREATE UNLOGGED TABLE "rate_limit_counters" (
+  "key" varchar(255) NOT NULL,
+  "window_start" timestamp NOT NULL,
+  "count" INT NOT NULL DEFAULT 1,
+  "expires_at" timestamp NOT NULL,
+  PRIMARY KEY ("key", "window_start")
+);
+
+CREATE INDEX ON "rate_limit_counters" ("expires_at");
@@ -0,0 +1,4 @@
+-- Note: PostgreSQL doesn't support removing enum values directly,
+-- nilai 'access_denied' dan 'security' dibiarkan
+DELETE FROM "permissions" WHERE "key" = 'security.manage';
+DROP TABLE IF EXISTS "admin_ip_allowlist";
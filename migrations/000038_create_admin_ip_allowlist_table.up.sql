@@ -0,0 +1,20 @@
+-- Allowlist IP/CIDR untuk /v1/admin, kosong = admin API bisa diakses dari mana saja
+CREATE TABLE "admin_ip_allowlist" (
+  "id" SERIAL PRIMARY KEY,
+  "cidr" varchar(64) UNIQUE NOT NULL,
+  "description" varchar(255),
+  "created_by" INT,
+  "created_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "admin_ip_allowlist" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('security.manage', 'Kelola pengaturan keamanan (allowlist IP admin)');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions" WHERE "key" = 'security.manage';
+
+-- Akses admin yang diblokir dicatat dengan action 'access_denied' di module 'security'
+ALTER TYPE activity_action_type ADD VALUE IF NOT EXISTS 'access_denied';
+ALTER TYPE activity_module_type ADD VALUE IF NOT EXISTS 'security';
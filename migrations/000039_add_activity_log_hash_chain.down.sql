@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "activity_log_checkpoints";
+
+ALTER TABLE "activity_logs" DROP CONSTRAINT IF EXISTS "activity_logs_impersonator_id_fkey";
+ALTER TABLE "activity_logs" ADD CONSTRAINT "activity_logs_impersonator_id_fkey" FOREIGN KEY ("impersonator_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+ALTER TABLE "activity_logs" DROP CONSTRAINT IF EXISTS "activity_logs_api_key_id_fkey";
+ALTER TABLE "activity_logs" ADD CONSTRAINT "activity_logs_api_key_id_fkey" FOREIGN KEY ("api_key_id") REFERENCES "api_keys" ("id") ON DELETE SET NULL;
+
+DROP INDEX IF EXISTS "idx_activity_logs_chain_head";
+ALTER TABLE "activity_logs" DROP COLUMN IF EXISTS "entry_hash";
+ALTER TABLE "activity_logs" DROP COLUMN IF EXISTS "prev_hash";
@@ -0,0 +1,25 @@
+-- Hash chain untuk activity log (tamper-evident)
+-- entry_hash = SHA-256 dari isi kanonik entry + prev_hash (entry_hash entry sebelumnya)
+-- Entry lama (sebelum migrasi ini) dibiarkan NULL dan tidak termasuk chain
+ALTER TABLE "activity_logs" ADD COLUMN "prev_hash" varchar(64);
+ALTER TABLE "activity_logs" ADD COLUMN "entry_hash" varchar(64);
+CREATE INDEX "idx_activity_logs_chain_head" ON "activity_logs" ("id") WHERE "entry_hash" IS NOT NULL;
+
+-- Kolom yang ikut di-hash tidak boleh berubah diam-diam lewat ON DELETE SET NULL
+ALTER TABLE "activity_logs" DROP CONSTRAINT IF EXISTS "activity_logs_api_key_id_fkey";
+ALTER TABLE "activity_logs" ADD CONSTRAINT "activity_logs_api_key_id_fkey" FOREIGN KEY ("api_key_id") REFERENCES "api_keys" ("id");
+ALTER TABLE "activity_logs" DROP CONSTRAINT IF EXISTS "activity_logs_impersonator_id_fkey";
+ALTER TABLE "activity_logs" ADD CONSTRAINT "activity_logs_impersonator_id_fkey" FOREIGN KEY ("impersonator_id") REFERENCES "users" ("id");
+
+-- Checkpoint bertanda tangan (Ed25519) atas head chain, bisa diekspor dan disimpan di luar database
+CREATE TABLE "activity_log_checkpoints" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "last_log_id" INT NOT NULL,
+  "entry_hash" varchar(64) NOT NULL,
+  "entry_count" BIGINT NOT NULL,
+  "key_id" varchar(64) NOT NULL,
+  "signature" text NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX "idx_activity_log_checkpoints_last_log_id" ON "activity_log_checkpoints" ("last_log_id");
@@ -0,0 +1,15 @@
+-- Note: PostgreSQL doesn't support removing enum values directly,
+-- nilai 'archive' dan 'activity_logs' dibiarkan
+
+-- Entry tanpa user (job sistem, akses admin yang diblokir) adalah bagian dari hash chain dan tidak boleh dihapus,
+-- rollback dibatalkan sampai entry tersebut diarsipkan atau ditangani manual
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM "activity_logs" WHERE "user_id" IS NULL) THEN
+    RAISE EXCEPTION 'activity_logs berisi entry dengan user_id NULL, arsipkan entry tersebut sebelum rollback';
+  END IF;
+END $$;
+
+ALTER TABLE "activity_logs" ALTER COLUMN "user_id" SET NOT NULL;
+
+DROP TABLE IF EXISTS "activity_log_archives";
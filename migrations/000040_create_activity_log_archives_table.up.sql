@@ -0,0 +1,26 @@
+-- Retensi activity log: entry lama diarsipkan ke file NDJSON gzip (disk atau Cloudinary) lalu dihapus dari tabel
+-- Arsip selalu berupa prefix berdasarkan id, last_entry_hash menjadi titik awal hash chain entry yang tersisa
+CREATE TABLE "activity_log_archives" (
+  "id" INT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  "storage" varchar(20) NOT NULL,
+  "location" text NOT NULL,
+  "from_log_id" INT NOT NULL,
+  "to_log_id" INT NOT NULL,
+  "entry_count" BIGINT NOT NULL,
+  "chained_count" BIGINT NOT NULL,
+  "last_entry_hash" varchar(64),
+  "cutoff" timestamp NOT NULL,
+  "sha256" varchar(64) NOT NULL,
+  "size_bytes" BIGINT NOT NULL,
+  "created_by" INT,
+  "created_at" timestamp DEFAULT (now())
+);
+
+CREATE INDEX "idx_activity_log_archives_to_log_id" ON "activity_log_archives" ("to_log_id");
+ALTER TABLE "activity_log_archives" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+-- Job arsip terjadwal tidak punya user, entry-nya disimpan dengan user_id NULL
+ALTER TABLE "activity_logs" ALTER COLUMN "user_id" DROP NOT NULL;
+
+ALTER TYPE activity_action_type ADD VALUE IF NOT EXISTS 'archive';
+ALTER TYPE activity_module_type ADD VALUE IF NOT EXISTS 'activity_logs';
@@ -0,0 +1,5 @@
+-- Note: PostgreSQL doesn't support removing enum values directly,
+-- nilai 'webhooks' dibiarkan
+DELETE FROM "permissions" WHERE "key" = 'webhooks.manage';
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhooks";
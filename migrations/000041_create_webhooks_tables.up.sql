@@ -0,0 +1,48 @@
+-- Webhook keluar: admin mendaftarkan URL penerima beserta event yang ingin diterima
+-- Secret disimpan plaintext karena dibutuhkan untuk menandatangani payload (HMAC)
+CREATE TABLE "webhooks" (
+  "id" SERIAL PRIMARY KEY,
+  "name" varchar(100) NOT NULL,
+  "url" text NOT NULL,
+  "secret" varchar(128) NOT NULL,
+  "events" jsonb NOT NULL DEFAULT '[]',
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_by" INT,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "webhooks" ADD FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+-- Satu baris per event per webhook, sekaligus antrian retry dan log pengiriman
+CREATE TABLE "webhook_deliveries" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "webhook_id" INT NOT NULL,
+  "event_id" varchar(64) NOT NULL,
+  "event" varchar(50) NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar(20) NOT NULL DEFAULT 'pending',
+  "attempts" INT NOT NULL DEFAULT 0,
+  "response_status" INT,
+  "last_error" text,
+  "next_attempt_at" timestamp,
+  "delivered_at" timestamp,
+  "replay_of" BIGINT,
+  "created_at" timestamp DEFAULT (now()),
+  "updated_at" timestamp DEFAULT (now())
+);
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("replay_of") REFERENCES "webhook_deliveries" ("id") ON DELETE SET NULL;
+
+CREATE INDEX "idx_webhook_deliveries_due" ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+CREATE INDEX "idx_webhook_deliveries_webhook" ON "webhook_deliveries" ("webhook_id", "id" DESC);
+
+INSERT INTO "permissions" ("key", "description") VALUES
+  ('webhooks.manage', 'Kelola webhook keluar dan log pengirimannya');
+
+INSERT INTO "role_permissions" ("role_id", "permission_id")
+SELECT 1, "id" FROM "permissions" WHERE "key" = 'webhooks.manage';
+
+-- Perubahan webhook dicatat plugin audit di module 'webhooks'
+ALTER TYPE activity_module_type ADD VALUE IF NOT EXISTS 'webhooks';
@@ -0,0 +1,8 @@
+-- Note: IP asli tidak bisa dikembalikan, kolom hanya diberi nama semula
+ALTER TABLE "post_views" RENAME COLUMN "visitor_hash" TO "ip_address";
+
+UPDATE "visitors" SET "visitor_hash" = '' WHERE "visitor_hash" IS NULL;
+ALTER TABLE "visitors" ALTER COLUMN "visitor_hash" SET NOT NULL;
+ALTER TABLE "visitors" RENAME COLUMN "visitor_hash" TO "ip_address";
+
+DROP TABLE IF EXISTS "visitor_salts";
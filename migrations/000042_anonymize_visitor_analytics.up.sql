@@ -0,0 +1,24 @@
+-- Salt acak per hari untuk hash IP visitor. Salt hari sebelumnya dihapus oleh job purge
+-- sehingga hash lama tidak bisa dicocokkan ulang (brute force ruang IPv4) ke IP asli
+CREATE TABLE "visitor_salts" (
+  "day" date PRIMARY KEY,
+  "salt" bytea NOT NULL,
+  "created_at" timestamp DEFAULT (now())
+);
+
+-- IP mentah diganti hash (HMAC-SHA256 hex, 64 karakter), index lama ikut pindah ke kolom baru
+ALTER TABLE "visitors" RENAME COLUMN "ip_address" TO "visitor_hash";
+ALTER TABLE "visitors" ALTER COLUMN "visitor_hash" TYPE varchar(64);
+ALTER TABLE "visitors" ALTER COLUMN "visitor_hash" DROP NOT NULL;
+
+ALTER TABLE "post_views" RENAME COLUMN "ip_address" TO "visitor_hash";
+ALTER TABLE "post_views" ALTER COLUMN "visitor_hash" TYPE varchar(64);
+
+-- Data lama di-hash dengan salt sekali pakai yang tidak disimpan, jumlah baris (dashboard) tidak berubah
+WITH s AS (SELECT gen_random_uuid()::text AS salt)
+UPDATE "visitors" SET "visitor_hash" = encode(sha256(convert_to(s.salt || DATE("visited_at")::text || "visitor_hash", 'UTF8')), 'hex')
+FROM s WHERE "visitor_hash" IS NOT NULL;
+
+WITH s AS (SELECT gen_random_uuid()::text AS salt)
+UPDATE "post_views" SET "visitor_hash" = encode(sha256(convert_to(s.salt || DATE("viewed_at")::text || "visitor_hash", 'UTF8')), 'hex')
+FROM s WHERE "visitor_hash" IS NOT NULL;
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_visitors_utm_campaign;
+DROP INDEX IF EXISTS idx_visitors_referrer_host;
+
+-- Note: user agent lengkap tidak bisa dikembalikan, platform diisi dari klasifikasi
+ALTER TABLE "visitors" ADD COLUMN "platform" varchar(255);
+UPDATE "visitors" SET "platform" = concat_ws(' / ', "browser", "os", "device_type") WHERE "device_type" IS NOT NULL;
+
+ALTER TABLE "visitors" DROP COLUMN "device_type";
+ALTER TABLE "visitors" DROP COLUMN "os";
+ALTER TABLE "visitors" DROP COLUMN "browser";
+ALTER TABLE "visitors" DROP COLUMN "utm_campaign";
+ALTER TABLE "visitors" DROP COLUMN "utm_medium";
+ALTER TABLE "visitors" DROP COLUMN "utm_source";
+ALTER TABLE "visitors" DROP COLUMN "referrer_host";
@@ -0,0 +1,46 @@
+-- Sumber kunjungan (dari beacon frontend) dan klasifikasi user agent pengganti kolom platform
+ALTER TABLE "visitors" ADD COLUMN "referrer_host" varchar(255);
+ALTER TABLE "visitors" ADD COLUMN "utm_source" varchar(100);
+ALTER TABLE "visitors" ADD COLUMN "utm_medium" varchar(100);
+ALTER TABLE "visitors" ADD COLUMN "utm_campaign" varchar(100);
+ALTER TABLE "visitors" ADD COLUMN "browser" varchar(50);
+ALTER TABLE "visitors" ADD COLUMN "os" varchar(50);
+ALTER TABLE "visitors" ADD COLUMN "device_type" varchar(20);
+
+-- Klasifikasi data lama dengan aturan yang sama seperti pkg/useragent (disederhanakan)
+UPDATE "visitors" SET
+  "device_type" = CASE
+    WHEN "platform" ~* '(bot|crawl|spider|slurp|preview|facebookexternalhit|headless|curl/|wget/|python-requests|go-http-client|okhttp|java/|postman)' THEN 'bot'
+    WHEN "platform" LIKE '%iPad%' OR "platform" LIKE '%Tablet%' OR ("platform" LIKE '%Android%' AND "platform" NOT LIKE '%Mobile%') THEN 'tablet'
+    WHEN "platform" LIKE '%Mobi%' OR "platform" LIKE '%iPhone%' OR "platform" LIKE '%iPod%' THEN 'mobile'
+    ELSE 'desktop'
+  END,
+  "browser" = CASE
+    WHEN "platform" ~ '(Edg/|EdgA/|EdgiOS/|Edge/)' THEN 'Edge'
+    WHEN "platform" ~ '(OPR/|Opera)' THEN 'Opera'
+    WHEN "platform" LIKE '%SamsungBrowser/%' THEN 'Samsung Internet'
+    WHEN "platform" LIKE '%UCBrowser/%' THEN 'UC Browser'
+    WHEN "platform" ~ '(Firefox/|FxiOS/)' THEN 'Firefox'
+    WHEN "platform" ~ '(CriOS/|Chrome/)' THEN 'Chrome'
+    WHEN "platform" LIKE '%Version/%' THEN 'Safari'
+    WHEN "platform" ~ '(MSIE |Trident/)' THEN 'Internet Explorer'
+    ELSE 'Other'
+  END,
+  "os" = CASE
+    WHEN "platform" LIKE '%Windows%' THEN 'Windows'
+    WHEN "platform" ~ '(iPhone|iPad|iPod)' THEN 'iOS'
+    WHEN "platform" LIKE '%Android%' THEN 'Android'
+    WHEN "platform" LIKE '%CrOS%' THEN 'ChromeOS'
+    WHEN "platform" ~ '(Macintosh|Mac OS X)' THEN 'macOS'
+    WHEN "platform" LIKE '%Linux%' THEN 'Linux'
+    ELSE 'Other'
+  END
+WHERE "platform" IS NOT NULL AND "platform" <> '';
+
+UPDATE "visitors" SET "browser" = 'Other' WHERE "device_type" = 'bot';
+
+-- User agent lengkap tidak disimpan lagi
+ALTER TABLE "visitors" DROP COLUMN "platform";
+
+CREATE INDEX idx_visitors_referrer_host ON "visitors" ("referrer_host", "visited_at") WHERE "referrer_host" IS NOT NULL;
+CREATE INDEX idx_visitors_utm_campaign ON "visitors" ("utm_campaign", "visited_at") WHERE "utm_campaign" IS NOT NULL;
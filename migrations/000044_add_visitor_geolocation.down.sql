@@ -0,0 +1,7 @@
+ALTER TABLE "post_views" DROP COLUMN "city";
+ALTER TABLE "post_views" DROP COLUMN "region";
+ALTER TABLE "post_views" DROP COLUMN "country";
+
+ALTER TABLE "visitors" DROP COLUMN "city";
+ALTER TABLE "visitors" DROP COLUMN "region";
+ALTER TABLE "visitors" DROP COLUMN "country";
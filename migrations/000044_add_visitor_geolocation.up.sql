@@ -0,0 +1,9 @@
+-- Lokasi perkiraan dari database geolokasi offline (MMDB), diisi saat kunjungan/view dicatat
+-- Kosong jika ANALYTICS_GEOIP_DATABASE tidak diatur atau IP tidak ditemukan
+ALTER TABLE "visitors" ADD COLUMN "country" varchar(2);
+ALTER TABLE "visitors" ADD COLUMN "region" varchar(100);
+ALTER TABLE "visitors" ADD COLUMN "city" varchar(100);
+
+ALTER TABLE "post_views" ADD COLUMN "country" varchar(2);
+ALTER TABLE "post_views" ADD COLUMN "region" varchar(100);
+ALTER TABLE "post_views" ADD COLUMN "city" varchar(100);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "login_attempts_created_at_idx";
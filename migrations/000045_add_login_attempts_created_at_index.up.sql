@@ -0,0 +1,2 @@
+-- Dipakai purge retensi login_attempts (DELETE ... WHERE created_at < ?)
+CREATE INDEX "login_attempts_created_at_idx" ON "login_attempts" ("created_at");
@@ -0,0 +1,90 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Storage menyimpan file arsip ke disk lokal atau object storage
+type Storage interface {
+	// Name adalah jenis storage yang dicatat bersama arsip (disk, cloudinary)
+	Name() string
+
+	// Save menyimpan isi src dengan nama file name, mengembalikan lokasi file (path atau public ID)
+	Save(ctx context.Context, name string, src io.Reader) (string, error)
+}
+
+// DiskStorage menyimpan arsip di direktori lokal
+type DiskStorage struct {
+	dir string
+}
+
+// NewDiskStorage constructor untuk DiskStorage
+func NewDiskStorage(dir string) *DiskStorage {
+	return &DiskStorage{dir: dir}
+}
+
+// Name implements Storage
+func (s *DiskStorage) Name() string {
+	return "disk"
+}
+
+// Save menulis ke file sementara lalu rename, sehingga tidak ada file arsip yang setengah jadi
+func (s *DiskStorage) Save(ctx context.Context, name string, src io.Reader) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "."+name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to save archive file: %w", err)
+	}
+	return path, nil
+}
+
+// CloudinaryUploader adalah bagian dari cloudinary.Service yang dipakai untuk arsip
+type CloudinaryUploader interface {
+	UploadPrivateRaw(ctx context.Context, folder string, name string, src io.Reader) (string, error)
+}
+
+// CloudinaryStorage menyimpan arsip sebagai raw file private di Cloudinary
+type CloudinaryStorage struct {
+	uploader CloudinaryUploader
+	folder   string
+}
+
+// NewCloudinaryStorage constructor untuk CloudinaryStorage
+func NewCloudinaryStorage(uploader CloudinaryUploader, folder string) *CloudinaryStorage {
+	return &CloudinaryStorage{uploader: uploader, folder: folder}
+}
+
+// Name implements Storage
+func (s *CloudinaryStorage) Name() string {
+	return "cloudinary"
+}
+
+// Save mengunggah arsip sebagai asset private
+func (s *CloudinaryStorage) Save(ctx context.Context, name string, src io.Reader) (string, error) {
+	return s.uploader.UploadPrivateRaw(ctx, s.folder, name, src)
+}
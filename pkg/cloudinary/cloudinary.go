@@ -3,6 +3,7 @@ package cloudinary
 import (
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 )
 
@@ -237,6 +239,30 @@ func (s *Service) UploadFromPathWithOverwrite(ctx context.Context, folder string
 	return filename, nil
 }
 
+// UploadPrivateRaw uploads a raw file (e.g. archive) as a private asset
+// Private assets are not reachable from public delivery URLs, only through signed API access
+// folder: target folder in Cloudinary (e.g., "archives/activity-logs")
+// name: public ID including extension (e.g., "activity-logs-1-500.ndjson.gz")
+// Returns: public ID of the uploaded asset, error
+func (s *Service) UploadPrivateRaw(ctx context.Context, folder string, name string, src io.Reader) (string, error) {
+	result, err := s.cld.Upload.Upload(ctx, src, uploader.UploadParams{
+		AssetFolder:    fmt.Sprintf("uploads/%s", folder),
+		PublicID:       name,
+		UseFilename:    boolPtr(false),
+		UniqueFilename: boolPtr(false),
+		ResourceType:   string(ResourceTypeRaw),
+		Type:           api.Private,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to Cloudinary: %w", err)
+	}
+	if result.Error.Message != "" {
+		return "", fmt.Errorf("failed to upload to Cloudinary: %s", result.Error.Message)
+	}
+
+	return result.PublicID, nil
+}
+
 // boolPtr returns a pointer to a bool value
 func boolPtr(b bool) *bool {
 	return &b
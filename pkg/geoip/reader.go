@@ -0,0 +1,87 @@
+// Package geoip membaca database geolokasi IP format MMDB (MaxMind GeoLite2/GeoIP2 City, DB-IP Lite)
+// langsung dari file lokal, tanpa request jaringan
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// ErrInvalidDatabase dikembalikan jika file bukan MMDB atau strukturnya rusak
+var ErrInvalidDatabase = errors.New("geoip: file bukan database MMDB yang valid")
+
+// Location hasil lookup, nama dalam bahasa Inggris sesuai isi database
+type Location struct {
+	CountryCode string // ISO 3166-1 alpha-2, mis. ID
+	Country     string
+	Region      string // Subdivisi pertama (provinsi)
+	City        string
+}
+
+// geoRecord field record format GeoIP2 City yang dibaca, field lain dilewati decoder
+type geoRecord struct {
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// Reader database MMDB, aman dipakai bersamaan
+type Reader struct {
+	db *maxminddb.Reader
+}
+
+// Open membuka file MMDB dari path
+func Open(path string) (*Reader, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("geoip: %w", err)
+	}
+	return &Reader{db: db}, nil
+}
+
+// New membaca database MMDB dari isi file
+func New(buf []byte) (*Reader, error) {
+	db, err := maxminddb.FromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDatabase, err)
+	}
+	return &Reader{db: db}, nil
+}
+
+// DatabaseType jenis database dari metadata, mis. GeoLite2-City
+func (r *Reader) DatabaseType() string {
+	return r.db.Metadata.DatabaseType
+}
+
+// Locate mencari lokasi IP, false jika IP tidak valid atau tidak ada di database (mis. IP privat)
+func (r *Reader) Locate(ip string) (Location, bool) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return Location{}, false
+	}
+
+	var record geoRecord
+	if err := r.db.Lookup(addr, &record); err != nil {
+		return Location{}, false
+	}
+
+	location := Location{
+		CountryCode: record.Country.ISOCode,
+		Country:     record.Country.Names["en"],
+		City:        record.City.Names["en"],
+	}
+	if len(record.Subdivisions) > 0 {
+		location.Region = record.Subdivisions[0].Names["en"]
+	}
+	return location, location != Location{}
+}
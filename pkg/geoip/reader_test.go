@@ -0,0 +1,250 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// Tipe data section MMDB yang dipakai encoder test
+const (
+	typeString = 2
+	typeDouble = 3
+	typeMap    = 7
+	typeUint64 = 9
+	typeArray  = 11
+)
+
+// metadataMarker menandai awal metadata di bagian akhir file MMDB
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// testNetwork satu network beserta record-nya di database uji
+type testNetwork struct {
+	prefix string
+	record map[string]any
+}
+
+func cityRecord(code, country, region, city string) map[string]any {
+	return map[string]any{
+		"country":      map[string]any{"iso_code": code, "names": map[string]any{"en": country}},
+		"subdivisions": []any{map[string]any{"iso_code": "JK", "names": map[string]any{"en": region}}},
+		"city":         map[string]any{"geoname_id": uint64(1642911), "names": map[string]any{"en": city, "id": city}},
+		"location":     map[string]any{"latitude": -6.2146, "longitude": 106.8451},
+	}
+}
+
+// buildTestMMDB menulis database MMDB kecil sesuai spesifikasi format MaxMind DB
+func buildTestMMDB(t *testing.T, ipVersion, recordSize int, networks []testNetwork) []byte {
+	t.Helper()
+
+	// Trie: nilai >= 0 = index node, -1 = kosong, <= -2 = index data
+	nodes := [][2]int{{-1, -1}}
+	for i, network := range networks {
+		prefix := netip.MustParsePrefix(network.prefix)
+		var bits []byte
+		if ipVersion == 6 {
+			ip := prefix.Addr().As16()
+			if prefix.Addr().Is4() {
+				ip = [16]byte{}
+				v4 := prefix.Addr().As4()
+				copy(ip[12:], v4[:])
+			}
+			bits = ip[:]
+		} else {
+			ip := prefix.Addr().As4()
+			bits = ip[:]
+		}
+		length := prefix.Bits()
+		if ipVersion == 6 && prefix.Addr().Is4() {
+			length += 96
+		}
+
+		node := 0
+		for b := 0; b < length; b++ {
+			bit := (bits[b/8] >> (7 - b%8)) & 1
+			if b == length-1 {
+				nodes[node][bit] = -2 - i
+				break
+			}
+			if nodes[node][bit] < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+	}
+
+	var data []byte
+	offsets := make([]int, len(networks))
+	for i, network := range networks {
+		offsets[i] = len(data)
+		data = append(data, encodeTestValue(network.record)...)
+	}
+
+	nodeCount := len(nodes)
+	recordValue := func(v int) uint32 {
+		switch {
+		case v >= 0:
+			return uint32(v)
+		case v == -1:
+			return uint32(nodeCount)
+		default:
+			return uint32(nodeCount + 16 + offsets[-2-v])
+		}
+	}
+
+	var tree []byte
+	for _, node := range nodes {
+		left, right := recordValue(node[0]), recordValue(node[1])
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			tree = append(tree, byte(left>>16), byte(left>>8), byte(left), byte((left>>20)&0xF0|(right>>24)&0x0F), byte(right>>16), byte(right>>8), byte(right))
+		default:
+			tree = binary.BigEndian.AppendUint32(tree, left)
+			tree = binary.BigEndian.AppendUint32(tree, right)
+		}
+	}
+
+	buf := append(tree, make([]byte, 16)...)
+	buf = append(buf, data...)
+	buf = append(buf, metadataMarker...)
+	buf = append(buf, encodeTestValue(map[string]any{
+		"node_count":                  uint64(nodeCount),
+		"record_size":                 uint64(recordSize),
+		"ip_version":                  uint64(ipVersion),
+		"database_type":               "Test-City",
+		"languages":                   []any{"en"},
+		"binary_format_major_version": uint64(2),
+		"binary_format_minor_version": uint64(0),
+		"build_epoch":                 uint64(1767225600),
+	})...)
+	return buf
+}
+
+// encodeTestValue encoder data section untuk tipe yang dipakai di test
+func encodeTestValue(value any) []byte {
+	header := func(kind, size int) []byte {
+		var out []byte
+		if kind <= 7 {
+			out = []byte{byte(kind << 5)}
+		} else {
+			out = []byte{0, byte(kind - 7)}
+		}
+		switch {
+		case size < 29:
+			out[0] |= byte(size)
+		case size < 285:
+			out[0] |= 29
+			out = append(out, byte(size-29))
+		default:
+			out[0] |= 30
+			out = append(out, byte((size-285)>>8), byte(size-285))
+		}
+		return out
+	}
+
+	switch v := value.(type) {
+	case string:
+		return append(header(typeString, len(v)), v...)
+	case uint64:
+		var raw []byte
+		for x := v; x > 0; x >>= 8 {
+			raw = append([]byte{byte(x)}, raw...)
+		}
+		return append(header(typeUint64, len(raw)), raw...)
+	case float64:
+		return binary.BigEndian.AppendUint64(header(typeDouble, 8), math.Float64bits(v))
+	case []any:
+		out := header(typeArray, len(v))
+		for _, item := range v {
+			out = append(out, encodeTestValue(item)...)
+		}
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out := header(typeMap, len(v))
+		for _, key := range keys {
+			out = append(out, encodeTestValue(key)...)
+			out = append(out, encodeTestValue(v[key])...)
+		}
+		return out
+	}
+	panic("unsupported test value")
+}
+
+func TestLocate(t *testing.T) {
+	networks := []testNetwork{
+		{"103.47.132.0/22", cityRecord("ID", "Indonesia", "Jakarta", "Jakarta")},
+		{"36.72.0.0/13", cityRecord("ID", "Indonesia", "Central Java", "Semarang")},
+		{"2001:db8::/32", cityRecord("SG", "Singapore", "Central Singapore", "Singapore")},
+	}
+
+	for _, tc := range []struct {
+		ipVersion, recordSize int
+	}{{6, 24}, {6, 28}, {6, 32}, {4, 24}} {
+		nets := networks
+		if tc.ipVersion == 4 {
+			nets = networks[:2]
+		}
+		reader, err := New(buildTestMMDB(t, tc.ipVersion, tc.recordSize, nets))
+		if err != nil {
+			t.Fatalf("ipv%d/%d: unexpected error: %v", tc.ipVersion, tc.recordSize, err)
+		}
+		if reader.DatabaseType() != "Test-City" {
+			t.Errorf("unexpected database type %q", reader.DatabaseType())
+		}
+
+		location, ok := reader.Locate("36.74.10.20")
+		want := Location{CountryCode: "ID", Country: "Indonesia", Region: "Central Java", City: "Semarang"}
+		if !ok || location != want {
+			t.Errorf("ipv%d/%d: Locate = %+v, %v, want %+v", tc.ipVersion, tc.recordSize, location, ok, want)
+		}
+		if location, ok := reader.Locate("::ffff:103.47.133.1"); !ok || location.City != "Jakarta" {
+			t.Errorf("ipv%d/%d: expected IPv4-mapped address to resolve to Jakarta, got %+v", tc.ipVersion, tc.recordSize, location)
+		}
+		if tc.ipVersion == 6 {
+			if location, ok := reader.Locate("2001:db8::1"); !ok || location.CountryCode != "SG" {
+				t.Errorf("ipv%d/%d: expected IPv6 address to resolve to SG, got %+v", tc.ipVersion, tc.recordSize, location)
+			}
+		}
+
+		for _, ip := range []string{"10.0.0.1", "127.0.0.1", "2001:db9::1", "bukan-ip", ""} {
+			if location, ok := reader.Locate(ip); ok {
+				t.Errorf("ipv%d/%d: expected %q not to resolve, got %+v", tc.ipVersion, tc.recordSize, ip, location)
+			}
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	if err := os.WriteFile(path, buildTestMMDB(t, 6, 28, []testNetwork{{"103.47.132.0/22", cityRecord("ID", "Indonesia", "Jakarta", "Jakarta")}}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reader, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := reader.Locate("103.47.132.9"); !ok {
+		t.Error("expected lookup from opened file to succeed")
+	}
+
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.mmdb")); err == nil {
+		t.Error("expected error for missing file")
+	}
+	if _, err := New([]byte("bukan database")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("expected ErrInvalidDatabase, got %v", err)
+	}
+}
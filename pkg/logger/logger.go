@@ -6,9 +6,10 @@ import (
 )
 
 // Logger simple wrapper untuk logging
+// Sudah bisa dipakai sebelum Init (mis. di unit test) dengan format yang sama
 var (
-	Info  *log.Logger
-	Error *log.Logger
+	Info  = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
+	Error = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 )
 
 // Init inisialisasi logger
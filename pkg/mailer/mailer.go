@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+// Config holds SMTP configuration
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// LogBody ikut mencatat isi email saat SMTP tidak diatur
+	// Hanya untuk development: isi email bisa berisi token reset password atau undangan
+	LogBody bool
+}
+
+// Service handles sending email via SMTP
+type Service struct {
+	config Config
+}
+
+// NewService creates a new mailer service instance
+// Jika Host kosong, email tidak dikirim dan hanya penerima & subject yang dicatat ke log
+func NewService(config Config) *Service {
+	return &Service{config: config}
+}
+
+// Send sends a plain text email to a single recipient
+func (s *Service) Send(ctx context.Context, to, subject, body string) error {
+	if s.config.Host == "" {
+		if s.config.LogBody {
+			log.Printf("📧 [MAIL DISABLED] to=%s subject=%q\n%s", to, subject, body)
+		} else {
+			log.Printf("📧 [MAIL DISABLED] to=%s subject=%q", to, subject)
+		}
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	msg := strings.Join([]string{
+		"From: " + s.config.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%s", s.config.Host, s.config.Port)
+	if err := smtp.SendMail(addr, auth, s.config.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
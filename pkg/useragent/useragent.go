@@ -0,0 +1,106 @@
+// Package useragent mengelompokkan header User-Agent ke browser, sistem operasi dan jenis perangkat
+// untuk statistik pengunjung, sehingga user agent lengkap tidak perlu disimpan
+package useragent
+
+import "strings"
+
+// Jenis perangkat
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Other dipakai untuk browser atau sistem operasi yang tidak dikenali
+const Other = "Other"
+
+// Info hasil klasifikasi satu user agent
+type Info struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+// botMarkers penanda crawler dan HTTP client non-browser (huruf kecil)
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "preview", "facebookexternalhit", "headless",
+	"curl/", "wget/", "python-requests", "go-http-client", "okhttp", "java/", "postman",
+}
+
+// rule memetakan penanda di user agent ke satu nama
+type rule struct {
+	markers []string
+	name    string
+}
+
+// browserRules dicek berurutan karena hampir semua browser ikut mencantumkan "Chrome" dan "Safari"
+var browserRules = []rule{
+	{[]string{"Edg/", "EdgA/", "EdgiOS/", "Edge/"}, "Edge"},
+	{[]string{"OPR/", "Opera"}, "Opera"},
+	{[]string{"SamsungBrowser/"}, "Samsung Internet"},
+	{[]string{"UCBrowser/"}, "UC Browser"},
+	{[]string{"Firefox/", "FxiOS/"}, "Firefox"},
+	{[]string{"CriOS/", "Chrome/"}, "Chrome"},
+	{[]string{"Version/"}, "Safari"},
+	{[]string{"MSIE ", "Trident/"}, "Internet Explorer"},
+}
+
+// osRules dicek berurutan, Android sebelum Linux dan iOS sebelum macOS
+var osRules = []rule{
+	{[]string{"Windows"}, "Windows"},
+	{[]string{"iPhone", "iPad", "iPod"}, "iOS"},
+	{[]string{"Android"}, "Android"},
+	{[]string{"CrOS"}, "ChromeOS"},
+	{[]string{"Macintosh", "Mac OS X"}, "macOS"},
+	{[]string{"Linux"}, "Linux"},
+}
+
+// Parse mengelompokkan user agent, string kosong menghasilkan device unknown
+func Parse(ua string) Info {
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return Info{Browser: Other, OS: Other, Device: DeviceUnknown}
+	}
+
+	info := Info{
+		Browser: match(ua, browserRules),
+		OS:      match(ua, osRules),
+		Device:  device(ua),
+	}
+	if info.Device == DeviceBot {
+		info.Browser = Other
+	}
+	return info
+}
+
+func match(ua string, rules []rule) string {
+	for _, rule := range rules {
+		for _, marker := range rule.markers {
+			if strings.Contains(ua, marker) {
+				return rule.name
+			}
+		}
+	}
+	return Other
+}
+
+func device(ua string) string {
+	lower := strings.ToLower(ua)
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			return DeviceBot
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"):
+		return DeviceMobile
+	default:
+		return DeviceDesktop
+	}
+}
@@ -12,6 +12,14 @@ const (
 	ContextKeyIPAddress contextKey = "ip_address"
 	// ContextKeyUserAgent is the key for the client user agent in context
 	ContextKeyUserAgent contextKey = "user_agent"
+	// ContextKeySessionID is the key for the current session (JWT sid claim) in context
+	ContextKeySessionID contextKey = "session_id"
+	// ContextKeyUserRole is the key for the authenticated user's role ID (JWT role claim) in context
+	ContextKeyUserRole contextKey = "user_role"
+	// ContextKeyAPIKeyID is the key for the API key used to authenticate the request in context
+	ContextKeyAPIKeyID contextKey = "api_key_id"
+	// ContextKeyImpersonatorID is the key for the admin impersonating the authenticated user in context
+	ContextKeyImpersonatorID contextKey = "impersonator_id"
 )
 
 // WithUserID adds user ID to context
@@ -19,6 +27,26 @@ func WithUserID(ctx context.Context, userID int) context.Context {
 	return context.WithValue(ctx, ContextKeyUserID, userID)
 }
 
+// WithSessionID adds the current session ID to context
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, ContextKeySessionID, sessionID)
+}
+
+// WithUserRole adds the authenticated user's role ID to context
+func WithUserRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, ContextKeyUserRole, role)
+}
+
+// WithAPIKeyID adds the ID of the API key used to authenticate the request to context
+func WithAPIKeyID(ctx context.Context, apiKeyID int) context.Context {
+	return context.WithValue(ctx, ContextKeyAPIKeyID, apiKeyID)
+}
+
+// WithImpersonatorID adds the ID of the admin impersonating the authenticated user to context
+func WithImpersonatorID(ctx context.Context, impersonatorID int) context.Context {
+	return context.WithValue(ctx, ContextKeyImpersonatorID, impersonatorID)
+}
+
 // WithRequestInfo adds IP address and user agent to context
 func WithRequestInfo(ctx context.Context, ipAddress, userAgent string) context.Context {
 	ctx = context.WithValue(ctx, ContextKeyIPAddress, ipAddress)
@@ -47,3 +75,35 @@ func GetUserAgent(ctx context.Context) string {
 	}
 	return ""
 }
+
+// GetSessionID retrieves the current session ID from context
+func GetSessionID(ctx context.Context) string {
+	if sid, ok := ctx.Value(ContextKeySessionID).(string); ok {
+		return sid
+	}
+	return ""
+}
+
+// GetUserRole retrieves the authenticated user's role ID from context
+func GetUserRole(ctx context.Context) string {
+	if role, ok := ctx.Value(ContextKeyUserRole).(string); ok {
+		return role
+	}
+	return ""
+}
+
+// GetAPIKeyID retrieves the API key ID from context, nil if the request was not made with an API key
+func GetAPIKeyID(ctx context.Context) *int {
+	if id, ok := ctx.Value(ContextKeyAPIKeyID).(int); ok {
+		return &id
+	}
+	return nil
+}
+
+// GetImpersonatorID retrieves the impersonating admin's user ID from context, nil if the request is not impersonated
+func GetImpersonatorID(ctx context.Context) *int {
+	if id, ok := ctx.Value(ContextKeyImpersonatorID).(int); ok {
+		return &id
+	}
+	return nil
+}
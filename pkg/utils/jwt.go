@@ -9,67 +9,163 @@ import (
 
 // Claims struktur untuk JWT payload
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Role   string `json:"role"`
+	UserID    int    `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`     // Family ID dari user_sessions
+	Purpose   string `json:"purpose,omitempty"` // Kosong untuk access token, diisi untuk challenge token
+	// ImpersonatorID diisi dengan ID admin yang sebenarnya jika token adalah token impersonasi
+	ImpersonatorID int `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Purpose challenge token untuk login dua langkah (2FA)
+const (
+	PurposeTwoFactorVerify = "2fa_verify" // User sudah mengaktifkan 2FA, tinggal input kode
+	PurposeTwoFactorSetup  = "2fa_setup"  // Admin wajib 2FA tapi belum enrollment
+)
+
+// challengeTokenTTL masa berlaku challenge token setelah password benar
+const challengeTokenTTL = 5 * time.Minute
+
+// impersonationTokenTTL masa berlaku token impersonasi, tidak bisa diperpanjang dengan refresh token
+const impersonationTokenTTL = 15 * time.Minute
+
 var jwtSecret []byte
-var jwtExpirationHours int
+var accessTokenTTL time.Duration
+var refreshTokenTTL time.Duration
 
-// InitJWT inisialisasi JWT secret key dan expiration
+// InitJWT inisialisasi JWT secret key (HS256), masa berlaku access token dan refresh token
 // Harus dipanggil saat aplikasi start dengan config
-func InitJWT(secret string, expirationHours int) {
+// Secret boleh kosong jika key asimetris dimuat dengan InitJWTSigningKeys (HS256 tidak lagi diterima)
+func InitJWT(secret string, accessTTL, refreshTTL time.Duration) {
 	jwtSecret = []byte(secret)
-	jwtExpirationHours = expirationHours
+	accessTokenTTL = accessTTL
+	refreshTokenTTL = refreshTTL
 }
 
-// GenerateJWT membuat JWT token dengan user ID dan role
-// Token valid selama 24 jam
-func GenerateJWT(userID int, role string) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", errors.New("JWT secret not initialized")
-	}
+// AccessTokenTTL mengembalikan masa berlaku access token
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
 
-	// Set expiration time sesuai config (default 24 jam)
-	expirationTime := time.Now().Add(time.Duration(jwtExpirationHours) * time.Hour)
+// RefreshTokenTTL mengembalikan masa berlaku refresh token
+func RefreshTokenTTL() time.Duration {
+	return refreshTokenTTL
+}
+
+// GenerateJWT membuat access token (JWT) dengan user ID, role dan session ID
+// Token berumur pendek sesuai config (default 15 menit), diperpanjang via refresh token
+func GenerateJWT(userID int, role string, sessionID string) (string, error) {
+	// Set expiration time sesuai config
+	expirationTime := time.Now().Add(accessTokenTTL)
+
+	// jti unik per token, dipakai sebagai key revocation saat logout
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
 
 	// Buat claims
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	// Buat token dengan signing method HS256
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	// Sign token dengan key asimetris aktif (RS256/EdDSA), atau HS256 jika belum dikonfigurasi
+	return signJWT(claims)
+}
+
+// ChallengeTokenTTL mengembalikan masa berlaku challenge token
+func ChallengeTokenTTL() time.Duration {
+	return challengeTokenTTL
+}
 
-	// Sign token dengan secret key
-	tokenString, err := token.SignedString(jwtSecret)
+// GenerateChallengeToken membuat token berumur pendek untuk langkah kedua login
+// Token ini ditolak AuthMiddleware sehingga tidak bisa dipakai mengakses API lain
+func GenerateChallengeToken(userID int, role string, purpose string) (string, error) {
+	jti, err := GenerateRandomToken(16)
 	if err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	claims := &Claims{
+		UserID:  userID,
+		Role:    role,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(challengeTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signJWT(claims)
+}
+
+// ImpersonationTokenTTL mengembalikan masa berlaku token impersonasi
+func ImpersonationTokenTTL() time.Duration {
+	return impersonationTokenTTL
+}
+
+// GenerateImpersonationToken membuat access token atas nama user lain untuk admin (impersonasi)
+// Token tidak terikat session (tanpa sid) dan tidak punya refresh token, sehingga berakhir setelah TTL
+// atau saat logout; token juga ikut dicabut jika semua token milik admin dicabut
+func GenerateImpersonationToken(userID int, role string, impersonatorID int) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	claims := &Claims{
+		UserID:         userID,
+		Role:           role,
+		ImpersonatorID: impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(impersonationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signJWT(claims)
+}
+
+// ValidateChallengeToken memvalidasi challenge token dengan purpose tertentu
+// Token yang sudah dipakai (jti dicabut) dianggap tidak valid
+func ValidateChallengeToken(tokenString string, purpose string) (*Claims, error) {
+	claims, err := ValidateJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != purpose {
+		return nil, errors.New("invalid token purpose")
+	}
+
+	if IsTokenRevoked(claims) {
+		return nil, errors.New("token has been revoked")
+	}
+
+	return claims, nil
 }
 
 // ValidateJWT memvalidasi JWT token dan return claims jika valid
+// Menerima token RS256/EdDSA dari key verifikasi yang terdaftar (berdasarkan kid)
+// dan token HS256 lama selama JWT_SECRET masih diset (masa migrasi)
 func ValidateJWT(tokenString string) (*Claims, error) {
-	if len(jwtSecret) == 0 {
+	if len(jwtSecret) == 0 && len(jwtVerificationKeys) == 0 {
 		return nil, errors.New("JWT secret not initialized")
 	}
 
-	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validasi signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return jwtSecret, nil
-	})
+	// Parse token, key dipilih oleh jwtKeyFunc sesuai algoritma dan kid
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, jwtKeyFunc,
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits ukuran minimal RSA key untuk RS256
+const minRSAKeyBits = 2048
+
+// jwtKey public key untuk verifikasi JWT asimetris, diidentifikasi dengan kid
+type jwtKey struct {
+	kid    string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// jwtSigningKey private key aktif untuk menandatangani JWT baru
+type jwtSigningKey struct {
+	jwtKey
+	private crypto.Signer
+}
+
+var jwtSigner *jwtSigningKey
+var jwtVerificationKeys map[string]*jwtKey
+
+// JWK satu public key dalam format JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve (Ed25519)
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKSet kumpulan public key yang dipublikasikan di /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// InitJWTSigningKeys memuat private key (RSA/Ed25519, PEM) untuk menandatangani JWT
+// dan public key tambahan yang masih diterima selama rotasi key
+// Jika signingKeyFile kosong, token tetap ditandatangani dengan HS256 (JWT_SECRET)
+func InitJWTSigningKeys(signingKeyFile string, verificationKeyFiles []string) error {
+	jwtSigner = nil
+	jwtVerificationKeys = make(map[string]*jwtKey)
+
+	if signingKeyFile != "" {
+		signer, err := loadSigningKey(signingKeyFile)
+		if err != nil {
+			return err
+		}
+		jwtSigner = signer
+		jwtVerificationKeys[signer.kid] = &signer.jwtKey
+	}
+
+	for _, file := range verificationKeyFiles {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		key, err := loadVerificationKey(file)
+		if err != nil {
+			return err
+		}
+		jwtVerificationKeys[key.kid] = key
+	}
+
+	return nil
+}
+
+// SigningKeyID mengembalikan kid key yang dipakai untuk menandatangani token baru (kosong jika HS256)
+func SigningKeyID() string {
+	if jwtSigner == nil {
+		return ""
+	}
+	return jwtSigner.kid
+}
+
+// JWKS mengembalikan semua public key verifikasi dalam format JWK Set
+// Secret HS256 tidak pernah dipublikasikan
+func JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(jwtVerificationKeys))}
+	for _, key := range jwtVerificationKeys {
+		set.Keys = append(set.Keys, key.jwk())
+	}
+	return set
+}
+
+// jwk mengubah public key ke format JWK
+func (k *jwtKey) jwk() JWK {
+	result := JWK{Kid: k.kid, Use: "sig", Alg: k.method.Alg()}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		result.Kty = "RSA"
+		result.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		result.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		result.Kty = "OKP"
+		result.Crv = "Ed25519"
+		result.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return result
+}
+
+// signJWT menandatangani claims dengan key asimetris aktif (header kid), fallback ke HS256
+func signJWT(claims *Claims) (string, error) {
+	if jwtSigner != nil {
+		token := jwt.NewWithClaims(jwtSigner.method, claims)
+		token.Header["kid"] = jwtSigner.kid
+		return token.SignedString(jwtSigner.private)
+	}
+
+	if len(jwtSecret) == 0 {
+		return "", errors.New("JWT secret not initialized")
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+}
+
+// jwtKeyFunc memilih key verifikasi berdasarkan algoritma dan kid di header token
+func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		// HS256 tetap diterima selama masa migrasi, selama JWT_SECRET masih diset
+		if len(jwtSecret) == 0 {
+			return nil, errors.New("HS256 tokens are no longer accepted")
+		}
+		return jwtSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := jwtVerificationKeys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+	return key.public, nil
+}
+
+// loadSigningKey membaca private key RSA atau Ed25519 dari file PEM
+func loadSigningKey(path string) (*jwtSigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT signing key: %w", err)
+	}
+
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		key, err := newJWTKey(&private.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return &jwtSigningKey{jwtKey: *key, private: private}, nil
+	}
+
+	if private, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		signer, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.New("unsupported JWT signing key type")
+		}
+		key, err := newJWTKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		return &jwtSigningKey{jwtKey: *key, private: signer}, nil
+	}
+
+	return nil, fmt.Errorf("JWT signing key %s must be an RSA or Ed25519 private key in PEM format", path)
+}
+
+// loadVerificationKey membaca public key RSA atau Ed25519 dari file PEM
+func loadVerificationKey(path string) (*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT verification key: %w", err)
+	}
+
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return newJWTKey(public)
+	}
+	if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return newJWTKey(public)
+	}
+
+	return nil, fmt.Errorf("JWT verification key %s must be an RSA or Ed25519 public key in PEM format", path)
+}
+
+// newJWTKey menentukan algoritma dari tipe key dan menghitung kid (RFC 7638 thumbprint)
+func newJWTKey(public crypto.PublicKey) (*jwtKey, error) {
+	key := &jwtKey{public: public}
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("unsupported JWT key type")
+	}
+
+	kid, err := jwkThumbprint(key.jwk())
+	if err != nil {
+		return nil, err
+	}
+	key.kid = kid
+	return key, nil
+}
+
+// jwkThumbprint menghitung thumbprint SHA-256 dari member wajib JWK (RFC 7638)
+// sehingga kid stabil untuk key yang sama di semua instance
+func jwkThumbprint(k JWK) (string, error) {
+	var members any
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	default:
+		return "", errors.New("unsupported JWK type")
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
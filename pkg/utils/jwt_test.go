@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// writeTestKeys membuat private key RSA dan Ed25519 beserta public key RSA
+func writeTestKeys(t *testing.T) (rsaPrivate, rsaPublic, edPrivate string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate = writePEM(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	pubDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	rsaPublic = writePEM(t, dir, "rsa.pub.pem", "PUBLIC KEY", pubDER)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	edPrivate = writePEM(t, dir, "ed25519.pem", "PRIVATE KEY", edDER)
+
+	return rsaPrivate, rsaPublic, edPrivate
+}
+
+func resetJWTKeys(t *testing.T) {
+	t.Cleanup(func() {
+		InitJWT("", 0, 0)
+		_ = InitJWTSigningKeys("", nil)
+	})
+}
+
+// TestJWT_AsymmetricRotation menguji token RS256 → EdDSA dengan rotasi key dan token HS256 lama
+func TestJWT_AsymmetricRotation(t *testing.T) {
+	resetJWTKeys(t)
+	rsaPrivate, rsaPublic, edPrivate := writeTestKeys(t)
+
+	InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+	_ = InitJWTSigningKeys("", nil)
+	hsToken, err := GenerateJWT(1, "1", "sid")
+	if err != nil {
+		t.Fatalf("Expected HS256 token, got %v", err)
+	}
+
+	// Tahap 1: mulai tanda tangan dengan RSA, token HS256 tetap diterima
+	if err := InitJWTSigningKeys(rsaPrivate, nil); err != nil {
+		t.Fatalf("Expected RSA key to load, got %v", err)
+	}
+	rsaKid := SigningKeyID()
+	rsaToken, err := GenerateJWT(1, "1", "sid")
+	if err != nil {
+		t.Fatalf("Expected RS256 token, got %v", err)
+	}
+	parsed, _, _ := jwt.NewParser().ParseUnverified(rsaToken, &Claims{})
+	if parsed.Method.Alg() != "RS256" || parsed.Header["kid"] != rsaKid {
+		t.Errorf("Expected RS256 with kid %s, got %s / %v", rsaKid, parsed.Method.Alg(), parsed.Header["kid"])
+	}
+	for _, token := range []string{hsToken, rsaToken} {
+		if _, err := ValidateJWT(token); err != nil {
+			t.Errorf("Expected token to be valid, got %v", err)
+		}
+	}
+
+	// Tahap 2: rotasi ke Ed25519, public key RSA lama masih diterima
+	if err := InitJWTSigningKeys(edPrivate, []string{rsaPublic}); err != nil {
+		t.Fatalf("Expected Ed25519 key to load, got %v", err)
+	}
+	edToken, _ := GenerateJWT(1, "1", "sid")
+	for _, token := range []string{rsaToken, edToken} {
+		if _, err := ValidateJWT(token); err != nil {
+			t.Errorf("Expected token to be valid during rotation, got %v", err)
+		}
+	}
+
+	jwks := JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected 2 keys in JWKS, got %d", len(jwks.Keys))
+	}
+	for _, k := range jwks.Keys {
+		if k.Kid == "" || (k.Kty != "RSA" && k.Kty != "OKP") {
+			t.Errorf("Unexpected JWK %+v", k)
+		}
+	}
+
+	// Tahap 3: key RSA lama dihapus dan JWT_SECRET dikosongkan, token lama ditolak
+	InitJWT("", 15*time.Minute, 24*time.Hour)
+	_ = InitJWTSigningKeys(edPrivate, nil)
+	for _, token := range []string{hsToken, rsaToken} {
+		if _, err := ValidateJWT(token); err == nil {
+			t.Error("Expected token from retired key to be rejected")
+		}
+	}
+	if _, err := ValidateJWT(edToken); err != nil {
+		t.Errorf("Expected EdDSA token to remain valid, got %v", err)
+	}
+}
+
+// TestJWT_RejectsAlgorithmMismatch menguji token dengan kid valid tapi algoritma lain ditolak
+func TestJWT_RejectsAlgorithmMismatch(t *testing.T) {
+	resetJWTKeys(t)
+	rsaPrivate, _, _ := writeTestKeys(t)
+
+	InitJWT("", 15*time.Minute, 24*time.Hour)
+	if err := InitJWTSigningKeys(rsaPrivate, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Token "none" dan HS256 dengan kid key RSA tidak boleh diterima
+	claims := &Claims{UserID: 1, Role: "1", RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}}
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	none.Header["kid"] = SigningKeyID()
+	noneToken, _ := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hs.Header["kid"] = SigningKeyID()
+	hsToken, _ := hs.SignedString([]byte("guess"))
+
+	for _, token := range []string{noneToken, hsToken} {
+		if _, err := ValidateJWT(token); err == nil {
+			t.Error("Expected forged token to be rejected")
+		}
+	}
+}
+
+// watermarkBackend adalah backend revocation in-memory yang hanya menyimpan watermark per user
+type watermarkBackend struct {
+	validAfter map[int]time.Time
+}
+
+func (b *watermarkBackend) SaveRevokedToken(jti string, userID int, expiresAt time.Time) error {
+	return nil
+}
+func (b *watermarkBackend) IsTokenRevoked(jti string) (bool, error) { return false, nil }
+func (b *watermarkBackend) DeleteExpiredTokens() error              { return nil }
+func (b *watermarkBackend) GetTokensValidAfter(userID int) (*time.Time, error) {
+	if t, ok := b.validAfter[userID]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+func (b *watermarkBackend) SetTokensValidAfter(userID int, validAfter time.Time) error {
+	b.validAfter[userID] = validAfter
+	return nil
+}
+
+func TestImpersonationToken_RevokedWithImpersonator(t *testing.T) {
+	resetJWTKeys(t)
+	InitJWT("test-secret", 15*time.Minute, 24*time.Hour)
+
+	tests := []struct {
+		name        string
+		revokedUser int
+	}{
+		{"user yang diimpersonasi", 2},
+		{"admin yang melakukan impersonasi", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &watermarkBackend{validAfter: map[int]time.Time{}}
+			InitRevocationStore(backend)
+
+			token, err := GenerateImpersonationToken(2, "2", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := ValidateJWT(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if IsTokenRevoked(claims) {
+				t.Fatal("token impersonasi baru tidak boleh dianggap dicabut")
+			}
+
+			// Watermark di masa depan: semua token user yang sudah terbit tidak valid
+			// (store dibuat ulang untuk mengosongkan cache watermark)
+			InitRevocationStore(backend)
+			backend.validAfter[tt.revokedUser] = time.Now().Add(2 * time.Second)
+			if !IsTokenRevoked(claims) {
+				t.Errorf("token impersonasi harus dicabut jika token %s dicabut", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// permissionCacheTTL lama permission role disimpan di cache lokal
+// Perubahan role dari instance lain paling lambat terlihat setelah durasi ini
+const permissionCacheTTL = time.Minute
+
+// PermissionBackend adalah sumber data role dan permission (Postgres)
+// Diimplementasikan oleh repository sehingga semua instance berbagi data yang sama
+type PermissionBackend interface {
+	GetRolePermissionKeys(roleID int) (string, []string, error)
+}
+
+// roleEntry menyimpan cache key role dan permission yang dimilikinya
+type roleEntry struct {
+	key         string
+	permissions map[string]struct{}
+	cachedUntil time.Time
+}
+
+// PermissionStore menyimpan cache permission per role
+// JWT hanya membawa role ID, permission di-resolve lewat store ini
+type PermissionStore struct {
+	backend PermissionBackend
+	roles   map[int]roleEntry
+	mu      sync.RWMutex
+}
+
+var permissionStore *PermissionStore
+
+// InitPermissionStore inisialisasi permission store dengan backend persisten
+func InitPermissionStore(backend PermissionBackend) {
+	permissionStore = &PermissionStore{
+		backend: backend,
+		roles:   make(map[int]roleEntry),
+	}
+}
+
+// HasPermission mengecek apakah role (nilai claim user_role) memiliki permission tertentu
+// Role tidak dikenal dan kegagalan backend dianggap tidak punya akses (fail closed)
+func HasPermission(role string, permission string) bool {
+	roleID, err := strconv.Atoi(role)
+	if err != nil || permissionStore == nil {
+		return false
+	}
+
+	entry, err := permissionStore.lookup(roleID)
+	if err != nil {
+		return false
+	}
+
+	_, ok := entry.permissions[permission]
+	return ok
+}
+
+// GetRoleKey mengambil key role (mis. "admin") berdasarkan ID
+func GetRoleKey(roleID int) (string, bool) {
+	if permissionStore == nil {
+		return "", false
+	}
+
+	entry, err := permissionStore.lookup(roleID)
+	if err != nil {
+		return "", false
+	}
+	return entry.key, true
+}
+
+// InvalidateRolePermissions menghapus cache role setelah role diubah atau dihapus
+func InvalidateRolePermissions(roleID int) {
+	if permissionStore == nil {
+		return
+	}
+
+	permissionStore.mu.Lock()
+	defer permissionStore.mu.Unlock()
+	delete(permissionStore.roles, roleID)
+}
+
+// lookup cek cache lokal dulu, lalu backend
+func (s *PermissionStore) lookup(roleID int) (roleEntry, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	entry, ok := s.roles[roleID]
+	s.mu.RUnlock()
+
+	if ok && now.Before(entry.cachedUntil) {
+		return entry, nil
+	}
+
+	key, permissions, err := s.backend.GetRolePermissionKeys(roleID)
+	if err != nil {
+		return roleEntry{}, err
+	}
+	if key == "" {
+		return roleEntry{}, errors.New("role not found")
+	}
+
+	entry = roleEntry{
+		key:         key,
+		permissions: make(map[string]struct{}, len(permissions)),
+		cachedUntil: now.Add(permissionCacheTTL),
+	}
+	for _, permission := range permissions {
+		entry.permissions[permission] = struct{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles[roleID] = entry
+
+	return entry, nil
+}
@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// revocationCacheTTL lama hasil "tidak dicabut" dan watermark user disimpan di cache lokal
+// Di instance yang mencabut, cache langsung diperbarui sehingga revocation berlaku seketika.
+// Revocation dari instance lain paling lambat terlihat setelah durasi ini (maksimal 2 detik),
+// sebagai ganti satu query backend per request.
+const revocationCacheTTL = 2 * time.Second
+
+// revokedCacheTTL lama hasil "dicabut" disimpan di cache lokal; token yang dicabut tidak pernah
+// kembali valid, jadi cache ini boleh jauh lebih lama
+const revokedCacheTTL = 10 * time.Minute
+
+// RevocationBackend adalah penyimpanan persisten token revocation (Postgres)
+// Diimplementasikan oleh repository sehingga semua instance berbagi data yang sama
+type RevocationBackend interface {
+	SaveRevokedToken(jti string, userID int, expiresAt time.Time) error
+	IsTokenRevoked(jti string) (bool, error)
+	DeleteExpiredTokens() error
+	GetTokensValidAfter(userID int) (*time.Time, error)
+	SetTokensValidAfter(userID int, validAfter time.Time) error
+}
+
+// watermarkEntry menyimpan cache batas "token sebelum waktu ini tidak valid" per user
+type watermarkEntry struct {
+	validAfter  *time.Time
+	cachedUntil time.Time
+}
+
+// TokenRevocationStore menyimpan token yang sudah dicabut (by jti) dan watermark per user
+// Data utama ada di backend, map di bawah hanya cache
+type TokenRevocationStore struct {
+	backend    RevocationBackend
+	revoked    map[string]time.Time // jti -> expiry token
+	notRevoked map[string]time.Time // jti -> cache berlaku sampai
+	watermarks map[int]watermarkEntry
+	mu         sync.RWMutex
+}
+
+var revocationStore *TokenRevocationStore
+
+// InitRevocationStore inisialisasi token revocation store dengan backend persisten
+func InitRevocationStore(backend RevocationBackend) {
+	revocationStore = &TokenRevocationStore{
+		backend:    backend,
+		revoked:    make(map[string]time.Time),
+		notRevoked: make(map[string]time.Time),
+		watermarks: make(map[int]watermarkEntry),
+	}
+
+	// Cleanup expired tokens setiap 1 jam
+	go revocationStore.cleanupExpired()
+}
+
+// RevokeToken mencabut satu token berdasarkan jti sampai token tersebut expired
+func RevokeToken(jti string, userID int, expiresAt time.Time) error {
+	if revocationStore == nil {
+		return errors.New("token revocation store not initialized")
+	}
+	if jti == "" {
+		return errors.New("token has no jti")
+	}
+
+	if err := revocationStore.backend.SaveRevokedToken(jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	revocationStore.mu.Lock()
+	defer revocationStore.mu.Unlock()
+	revocationStore.revoked[jti] = expiresAt
+	delete(revocationStore.notRevoked, jti)
+
+	return nil
+}
+
+// RevokeSession mencabut semua access token milik satu session (sid) yang masih berlaku
+// Disimpan di tabel yang sama dengan jti, berlaku selama umur maksimal access token
+func RevokeSession(sessionID string, userID int) error {
+	if sessionID == "" {
+		return errors.New("empty session id")
+	}
+	return RevokeToken(sessionID, userID, time.Now().Add(AccessTokenTTL()))
+}
+
+// RevokeAllUserTokens membuat semua token user yang terbit sebelum saat ini tidak valid
+// Dipakai saat user dinonaktifkan, dihapus, atau password diganti
+func RevokeAllUserTokens(userID int) error {
+	if revocationStore == nil {
+		return errors.New("token revocation store not initialized")
+	}
+
+	now := time.Now()
+	if err := revocationStore.backend.SetTokensValidAfter(userID, now); err != nil {
+		return err
+	}
+
+	revocationStore.mu.Lock()
+	defer revocationStore.mu.Unlock()
+	revocationStore.watermarks[userID] = watermarkEntry{
+		validAfter:  &now,
+		cachedUntil: now.Add(revocationCacheTTL),
+	}
+
+	return nil
+}
+
+// IsTokenRevoked mengecek apakah token sudah dicabut (by jti) atau terbit sebelum watermark user
+// Token tanpa jti dan kegagalan backend dianggap dicabut (fail closed)
+func IsTokenRevoked(claims *Claims) bool {
+	if revocationStore == nil || claims.ID == "" {
+		return true
+	}
+
+	revoked, err := revocationStore.isJTIRevoked(claims.ID)
+	if err != nil || revoked {
+		return true
+	}
+
+	// Session (sid) yang dicabut lewat manajemen session ikut mematikan access token-nya
+	if claims.SessionID != "" {
+		revoked, err := revocationStore.isJTIRevoked(claims.SessionID)
+		if err != nil || revoked {
+			return true
+		}
+	}
+
+	if revocationStore.issuedBeforeWatermark(claims.UserID, claims) {
+		return true
+	}
+
+	// Token impersonasi ikut dicabut jika token admin yang melakukan impersonasi dicabut
+	// (admin dinonaktifkan, ganti password, dsb.)
+	if claims.ImpersonatorID != 0 && revocationStore.issuedBeforeWatermark(claims.ImpersonatorID, claims) {
+		return true
+	}
+
+	return false
+}
+
+// issuedBeforeWatermark mengecek apakah token terbit sebelum watermark user (gagal baca dianggap dicabut)
+func (s *TokenRevocationStore) issuedBeforeWatermark(userID int, claims *Claims) bool {
+	validAfter, err := s.tokensValidAfter(userID)
+	if err != nil {
+		return true
+	}
+
+	// iat JWT berpresisi detik, jadi watermark dibulatkan ke bawah
+	return validAfter != nil && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(validAfter.Truncate(time.Second))
+}
+
+// isJTIRevoked cek cache lokal dulu, lalu backend
+func (s *TokenRevocationStore) isJTIRevoked(jti string) (bool, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	_, revoked := s.revoked[jti]
+	cachedUntil, checked := s.notRevoked[jti]
+	s.mu.RUnlock()
+
+	if revoked {
+		return true, nil
+	}
+	if checked && now.Before(cachedUntil) {
+		return false, nil
+	}
+
+	revoked, err := s.backend.IsTokenRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if revoked {
+		// Expiry persis tidak diketahui di sini; cleanup berikutnya akan membersihkan
+		s.revoked[jti] = now.Add(revokedCacheTTL)
+	} else {
+		s.notRevoked[jti] = now.Add(revocationCacheTTL)
+	}
+
+	return revoked, nil
+}
+
+// tokensValidAfter mengambil watermark user dari cache atau backend
+func (s *TokenRevocationStore) tokensValidAfter(userID int) (*time.Time, error) {
+	now := time.Now()
+
+	s.mu.RLock()
+	entry, ok := s.watermarks[userID]
+	s.mu.RUnlock()
+
+	if ok && now.Before(entry.cachedUntil) {
+		return entry.validAfter, nil
+	}
+
+	validAfter, err := s.backend.GetTokensValidAfter(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watermarks[userID] = watermarkEntry{
+		validAfter:  validAfter,
+		cachedUntil: now.Add(revocationCacheTTL),
+	}
+
+	return validAfter, nil
+}
+
+// cleanupExpired menghapus token yang sudah expired dari backend dan cache
+func (s *TokenRevocationStore) cleanupExpired() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_ = s.backend.DeleteExpiredTokens()
+
+		s.mu.Lock()
+		now := time.Now()
+		for jti, expiresAt := range s.revoked {
+			if now.After(expiresAt) {
+				delete(s.revoked, jti)
+			}
+		}
+		for jti, cachedUntil := range s.notRevoked {
+			if now.After(cachedUntil) {
+				delete(s.notRevoked, jti)
+			}
+		}
+		for userID, entry := range s.watermarks {
+			if now.After(entry.cachedUntil) {
+				delete(s.watermarks, userID)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Nama cookie dan header untuk mode auth cookie (SPA admin)
+const (
+	SessionCookieName = "pmii_session" // Access token (HttpOnly)
+	RefreshCookieName = "pmii_refresh" // Refresh token (HttpOnly, hanya dikirim ke endpoint auth)
+	CSRFCookieName    = "pmii_csrf"    // Token CSRF double-submit, dibaca SPA lalu dikirim ulang di header
+	CSRFHeaderName    = "X-CSRF-Token"
+)
+
+// OIDCStateCookieName cookie yang mengikat state login SSO ke browser yang memulai login
+const OIDCStateCookieName = "pmii_oidc_state"
+
+// oidcStateCookiePath membatasi cookie state SSO hanya terkirim ke /v1/auth/oidc/*
+const oidcStateCookiePath = "/v1/auth/oidc"
+
+// refreshCookiePath membatasi refresh token hanya terkirim ke /v1/auth/* (refresh & logout)
+const refreshCookiePath = "/v1/auth"
+
+// SessionCookieConfig konfigurasi cookie session, diatur per environment
+type SessionCookieConfig struct {
+	Enabled  bool
+	Domain   string // Kosong = host API saja; isi parent domain (mis. pmii.id) agar SPA bisa membaca cookie CSRF
+	Secure   bool
+	SameSite http.SameSite
+}
+
+var sessionCookieConfig SessionCookieConfig
+
+// InitSessionCookies inisialisasi mode auth cookie
+// Harus dipanggil saat aplikasi start dengan config
+func InitSessionCookies(cfg SessionCookieConfig) {
+	sessionCookieConfig = cfg
+}
+
+// SessionCookiesEnabled mengecek apakah mode auth cookie diaktifkan
+func SessionCookiesEnabled() bool {
+	return sessionCookieConfig.Enabled
+}
+
+// ParseSameSite mengubah nilai config (lax, strict, none) ke http.SameSite
+func ParseSameSite(value string) (http.SameSite, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("invalid SameSite value %q (use lax, strict or none)", value)
+	}
+}
+
+// NewSessionCookies membuat cookie access token, refresh token dan token CSRF setelah login/refresh
+func NewSessionCookies(accessToken, refreshToken, csrfToken string) []*http.Cookie {
+	return []*http.Cookie{
+		newSessionCookie(SessionCookieName, accessToken, "/", accessTokenTTL, true),
+		newSessionCookie(RefreshCookieName, refreshToken, refreshCookiePath, refreshTokenTTL, true),
+		newSessionCookie(CSRFCookieName, csrfToken, "/", refreshTokenTTL, false),
+	}
+}
+
+// ClearSessionCookies membuat cookie kadaluarsa untuk menghapus session di browser (logout)
+func ClearSessionCookies() []*http.Cookie {
+	cookies := NewSessionCookies("", "", "")
+	for _, cookie := range cookies {
+		cookie.MaxAge = -1
+		cookie.Expires = time.Unix(0, 0)
+	}
+	return cookies
+}
+
+// NewOIDCStateCookie membuat cookie HttpOnly berisi hash state SSO, dicocokkan saat callback (cegah login CSRF)
+// Dipasang di mode bearer maupun cookie karena login SSO selalu berjalan di browser
+func NewOIDCStateCookie(stateHash string, ttl time.Duration) *http.Cookie {
+	cookie := newSessionCookie(OIDCStateCookieName, stateHash, oidcStateCookiePath, ttl, true)
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	return cookie
+}
+
+// ClearOIDCStateCookie membuat cookie state SSO kadaluarsa setelah callback
+func ClearOIDCStateCookie() *http.Cookie {
+	cookie := NewOIDCStateCookie("", 0)
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
+	return cookie
+}
+
+// ValidCSRFToken membandingkan token CSRF dari cookie dan header (double-submit) dalam waktu konstan
+func ValidCSRFToken(cookieValue, headerValue string) bool {
+	if cookieValue == "" || headerValue == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookieValue), []byte(headerValue)) == 1
+}
+
+func newSessionCookie(name, value, path string, ttl time.Duration, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   sessionCookieConfig.Domain,
+		MaxAge:   int(ttl.Seconds()),
+		Expires:  time.Now().Add(ttl),
+		Secure:   sessionCookieConfig.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sessionCookieConfig.SameSite,
+	}
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL     = errors.New("webhook: URL harus http(s) dengan host yang valid")
+	ErrPrivateAddress = errors.New("webhook: URL mengarah ke alamat privat atau internal")
+)
+
+// ValidateURL memeriksa URL webhook saat disimpan
+// Host berupa IP langsung dicek di sini, hostname dicek lagi saat koneksi dibuat (lihat NewHTTPClient)
+func ValidateURL(rawURL string, allowPrivate bool) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidURL
+	}
+	if allowPrivate {
+		return nil
+	}
+	if parsed.Hostname() == "localhost" {
+		return ErrPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(parsed.Hostname()); err == nil && !isPublicAddr(addr) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// NewHTTPClient membuat client untuk mengirim webhook
+// Tanpa allowPrivate, koneksi ke IP privat/loopback/link-local ditolak setelah DNS di-resolve
+// sehingga URL webhook tidak bisa dipakai untuk menjangkau layanan internal (SSRF), termasuk lewat redirect
+func NewHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, addr)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // Proxy akan membuat pengecekan alamat tujuan di dialer tidak berlaku
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace (CGNAT, RFC 6598) tidak termasuk IsPrivate tapi tetap bukan alamat publik
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header yang dikirim bersama setiap payload webhook
+const (
+	HeaderSignature = "X-PMII-Signature" // t=<unix>,v1=<hex hmac>
+	HeaderEvent     = "X-PMII-Event"
+	HeaderEventID   = "X-PMII-Event-ID" // Sama untuk pengiriman ulang, dipakai penerima untuk deduplikasi
+	HeaderDelivery  = "X-PMII-Delivery"
+)
+
+var (
+	ErrInvalidSignatureHeader = errors.New("webhook: invalid signature header")
+	ErrSignatureMismatch      = errors.New("webhook: signature mismatch")
+	ErrSignatureExpired       = errors.New("webhook: signature timestamp outside tolerance")
+)
+
+// Sign menghitung HMAC-SHA256 dari "<timestamp>.<body>"
+// Timestamp ikut ditandatangani agar penerima bisa menolak payload lama yang dikirim ulang pihak lain
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader membuat nilai header X-PMII-Signature
+func SignatureHeader(secret string, timestamp time.Time, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), Sign(secret, timestamp, body))
+}
+
+// Verify memeriksa header X-PMII-Signature di sisi penerima (dipakai juga di test)
+// tolerance 0 = timestamp tidak dicek
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignatureHeader
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignatureHeader
+			}
+			timestamp = parsed
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == 0 || signature == "" {
+		return ErrInvalidSignatureHeader
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if tolerance > 0 && (now.Sub(signedAt) > tolerance || signedAt.Sub(now) > tolerance) {
+		return ErrSignatureExpired
+	}
+
+	expected := Sign(secret, signedAt, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}